@@ -5,7 +5,20 @@ import (
 )
 
 const (
-	UserAdminRoleID int = 1
+	UserAdminRoleID     int = 1
+	UserBartenderRoleID int = 2
+	UserBarbackRoleID   int = 3
+	UserSalesRoleID     int = 4
+
+	ViewLeadsCapability     string = "ViewLeads"
+	EditQuotesCapability    string = "EditQuotes"
+	SendInvoicesCapability  string = "SendInvoices"
+	ManageUsersCapability   string = "ManageUsers"
+	ManageEventsCapability  string = "ManageEvents"
+	ViewOwnEventsCapability string = "ViewOwnEvents"
+	ManagePayrollCapability string = "ManagePayroll"
+	ViewReportsCapability   string = "ViewReports"
+	ManageJobsCapability    string = "ManageJobs"
 
 	DavidUserID int = 1
 
@@ -19,6 +32,12 @@ const (
 	EventSourceCRM           string = "crm"
 
 	AssumedBaseHoursForPerPersonPricing float64 = 4.00
+	MinimumBillableHours                float64 = 3.00
+	MinimumQuoteTotal                   float64 = 500.00
+	WeekendSurchargeRate                float64 = 0.10
+	HolidaySurchargeRate                float64 = 0.20
+	IncludedTravelMiles                 float64 = 25.00
+	TravelFeePerMile                    float64 = 1.50
 
 	DefaultCurrency          string  = "USD"
 	DefaultLeadValue         float64 = 150.00
@@ -30,35 +49,141 @@ const (
 	RemainingInvoiceTypeID int = 2
 	FullInvoiceTypeID      int = 3
 
+	PendingEventStaffStatusID  int = 1
+	AcceptedEventStaffStatusID int = 2
+	DeclinedEventStaffStatusID int = 3
+
 	OpenInvoiceStatusID int = 1
 	VoidInvoiceStatusID int = 2
 	PaidInvoiceStatusID int = 3
 
-	AlcoholServiceTypeID         int = 1
-	BarRentalServiceTypeID       int = 2
-	CoolerRentalServiceTypeID    int = 3
-	BartendingAddOnServiceTypeID int = 4
-	BartendingServiceTypeID      int = 5
-	ExtraServiceTypeID           int = 8
+	AlcoholServiceTypeID           int = 1
+	BarRentalServiceTypeID         int = 2
+	CoolerRentalServiceTypeID      int = 3
+	BartendingAddOnServiceTypeID   int = 4
+	BartendingServiceTypeID        int = 5
+	ExtraServiceTypeID             int = 8
+	PricingAdjustmentServiceTypeID int = 9
+
+	PerPersonUnitTypeID int = 1
+	PerHourUnitTypeID   int = 2
+	FlatUnitTypeID      int = 3
+	RatioUnitTypeID     int = 4
+	AdHocUnitTypeID     int = 5
+	FixedUnitTypeID     int = 6
+
+	WeekendSurchargeService       string = "Weekend Surcharge"
+	HolidaySurchargeService       string = "Holiday Surcharge"
+	TravelFeeService              string = "Travel Fee"
+	MinimumOrderAdjustmentService string = "Minimum Order Adjustment"
+
+	PercentageDiscountType string = "percentage"
+	FixedDiscountType      string = "fixed"
 
 	CupsStrawsNapkinsServiceID int = 14
 
 	NoInterestLeadInterestID int = 4
 
+	PendingJobStatus   string = "pending"
+	RunningJobStatus   string = "running"
+	CompletedJobStatus string = "completed"
+	DeadJobStatus      string = "dead"
+
+	CheckLeadSpreadsheetsJob      string = "check_lead_spreadsheets"
+	ArchiveUnresponsiveLeadsJob   string = "archive_unresponsive_leads"
+	UnreadMessagesNotificationJob string = "unread_messages_notification"
+	PhoneCallTranscriptionJob     string = "phone_call_transcription"
+	SMSSequenceJob                string = "sms_sequence"
+	SendScheduledMessageJob       string = "send_scheduled_message"
+	VoicemailTranscriptionJob     string = "voicemail_transcription"
+
+	DefaultJobMaxAttempts int = 5
+
+	ActiveSMSSequenceEnrollmentStatus    string = "active"
+	CompletedSMSSequenceEnrollmentStatus string = "completed"
+	StoppedSMSSequenceEnrollmentStatus   string = "stopped"
+
+	RepliedSMSSequenceStopReason  string = "replied"
+	BookedSMSSequenceStopReason   string = "booked"
+	OptedOutSMSSequenceStopReason string = "opted_out"
+
+	PendingScheduledMessageStatus   string = "pending"
+	SentScheduledMessageStatus      string = "sent"
+	CancelledScheduledMessageStatus string = "cancelled"
+	FailedScheduledMessageStatus    string = "failed"
+
+	OpenConversationStatus    string = "open"
+	SnoozedConversationStatus string = "snoozed"
+	ClosedConversationStatus  string = "closed"
+
+	InboundMessageCRMEvent string = "inbound_message"
+	MessageReadCRMEvent    string = "message_read"
+	CallStatusCRMEvent     string = "call_status"
+	PaymentCRMEvent        string = "payment"
+	QuoteAcceptedCRMEvent  string = "quote_accepted"
+
+	SimultaneousRingStrategy string = "simultaneous"
+	SequentialRingStrategy   string = "sequential"
+
+	EnglishCallLanguage string = "en"
+	SpanishCallLanguage string = "es"
+
+	DeliveredMessageStatus   string = "delivered"
+	UndeliveredMessageStatus string = "undelivered"
+	FailedMessageStatus      string = "failed"
+
+	PendingTranscriptionStatus    string = "pending"
+	ProcessingTranscriptionStatus string = "processing"
+	CompletedTranscriptionStatus  string = "completed"
+
+	// Automated texts created between these local hours wait for the next morning
+	QuietHoursStartHour int = 21
+	QuietHoursEndHour   int = 9
+
+	KeywordSMSConsentSource  string = "keyword"
+	LeadFormSMSConsentSource string = "lead_form"
+
+	OptOutSMSConsentAction string = "opt_out"
+	OptInSMSConsentAction  string = "opt_in"
+	HelpSMSConsentAction   string = "help"
+
+	SMSHelpMessage  string = "YD Cocktails: reply to this message or call this number and we'll help you out. Msg & data rates may apply. Reply STOP to unsubscribe."
+	SMSOptInMessage string = "YD Cocktails: you're subscribed to text messages again. Msg & data rates may apply. Reply HELP for help, STOP to unsubscribe."
+
 	NewLeadStatusID      int = 1
 	ArchivedLeadStatusID int = 7
 
 	InitialContactActionID int = 1
 	FirstFollowUpActionID  int = 3
+	CallBackActionID       int = 5
+
+	VoicemailCallStatus string = "voicemail"
+
+	LiquorIngredientCategory  string = "Liquor"
+	MixerIngredientCategory   string = "Mixer"
+	GarnishIngredientCategory string = "Garnish"
+
+	DefaultDrinksPerGuestPerHour float64 = 1
+	LiquorBottleOunces           float64 = 25.36 // 750 ml
+	MixerBottleOunces            float64 = 33.81 // 1 L
+	IcePoundsPerGuestPerHour     float64 = 0.5
+	IceBagPounds                 float64 = 20
 
 	SocialMediaAdsMedium  = "paid"
 	SocialMediaAdsChannel = "social"
 
-	SessionName                    = "yd_vending_sessions"
-	LeadsPerPage                   = 10
-	TwilioCallbackWebhook          = "/call/inbound/end"
-	TwilioRecordingCallbackWebhook = "/call/inbound/recording-callback"
-	TwilioAmdCallbackWebhook       = "/call/inbound/amd"
+	PhoneLeadSource = "phone"
+	SMSLeadSource   = "sms"
+
+	SessionName                     = "yd_vending_sessions"
+	LeadsPerPage                    = 10
+	TwilioCallbackWebhook           = "/call/inbound/end"
+	TwilioRecordingCallbackWebhook  = "/call/inbound/recording-callback"
+	TwilioAmdCallbackWebhook        = "/call/inbound/amd"
+	TwilioSMSStatusCallbackWebhook  = "/sms/status"
+	TwilioCallMenuWebhook           = "/call/inbound/menu"
+	TwilioVoicemailWebhook          = "/call/inbound/voicemail"
+	TwilioVoicemailRecordingWebhook = "/call/inbound/voicemail/recording"
 )
 
 var (
@@ -108,7 +233,12 @@ var (
 	NotificationSubscribers       []string
 	FacebookLeadsSpreadsheetID    string
 	FacebookLeadsSpreadsheetRange string
+	AdSpendSpreadsheetID          string
+	AdSpendSpreadsheetRange       string
 	OpenAIApiKey                  string
+	RunMigrations                 bool
+	ProvidersMode                 string
+	ProvidersDir                  string
 )
 
 func Init() {
@@ -157,14 +287,28 @@ func Init() {
 	StripeWebhookSecret = os.Getenv("STRIPE_WEBHOOK_SECRET")
 	FacebookLeadsSpreadsheetID = os.Getenv("FACEBOOK_LEADS_SPREADSHEET_ID")
 	FacebookLeadsSpreadsheetRange = os.Getenv("FACEBOOK_LEADS_SPREADSHEET_RANGE")
+	AdSpendSpreadsheetID = os.Getenv("AD_SPEND_SPREADSHEET_ID")
+	AdSpendSpreadsheetRange = os.Getenv("AD_SPEND_SPREADSHEET_RANGE")
 	OpenAIApiKey = os.Getenv("OPEN_AI_API_KEY")
+	RunMigrations = os.Getenv("RUN_MIGRATIONS") == "1"
+	ProvidersMode = os.Getenv("PROVIDERS")
+	ProvidersDir = os.Getenv("PROVIDERS_DIR")
+	if ProvidersDir == "" {
+		ProvidersDir = LOCAL_FILES_DIR + "providers/"
+	}
 
 	NotificationSubscribers = []string{DavidPhoneNumber, YovaPhoneNumber}
 }
 
+var DiscountTypes = []string{PercentageDiscountType, FixedDiscountType}
+
+// Recipe cards list ingredients in this order
+var IngredientCategories = []string{LiquorIngredientCategory, MixerIngredientCategory, GarnishIngredientCategory}
+
 var TEMPLATES_DIR = "./templates/"
 var LOCAL_FILES_DIR = "./local_files/"
 var WEBSITE_TEMPLATES_DIR = TEMPLATES_DIR + "website/"
 var CRM_TEMPLATES_DIR = TEMPLATES_DIR + "crm/"
 var PARTIAL_TEMPLATES_DIR = TEMPLATES_DIR + "partials/"
+var STAFF_TEMPLATES_DIR = TEMPLATES_DIR + "staff/"
 var EXTERNAL_TEMPLATES_DIR = TEMPLATES_DIR + "external/"
@@ -70,6 +70,13 @@ func Connect() (*sql.DB, error) {
 	}
 
 	DB = db
+
+	if constants.RunMigrations {
+		if err := MigrateUp(); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %v", err)
+		}
+	}
+
 	return db, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
@@ -49,20 +50,37 @@ func CheckIsTokenUsed(decryptedToken string) (bool, error) {
 }
 
 func CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error) {
+	leadID, _, err := createLeadAndMarketing(quoteForm, false)
+	return leadID, err
+}
+
+// GetOrCreateLeadFromPhoneNumber creates a lead unless its phone number already belongs to one, in which case
+// that lead is returned instead. The insert yields to the unique phone_number constraint rather than racing it.
+func GetOrCreateLeadFromPhoneNumber(quoteForm types.QuoteForm) (int, bool, error) {
+	return createLeadAndMarketing(quoteForm, true)
+}
+
+func createLeadAndMarketing(quoteForm types.QuoteForm, skipExistingPhoneNumber bool) (int, bool, error) {
 	var leadID int
 	tx, err := DB.Begin()
 	if err != nil {
-		return leadID, fmt.Errorf("error starting transaction: %w", err)
+		return leadID, false, fmt.Errorf("error starting transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	leadStmt, err := tx.Prepare(`
+	onConflict := ""
+	if skipExistingPhoneNumber {
+		onConflict = "ON CONFLICT (phone_number) DO NOTHING"
+	}
+
+	leadStmt, err := tx.Prepare(fmt.Sprintf(`
 		INSERT INTO lead (full_name, phone_number, created_at, message, opt_in_text_messaging, email, lead_status_id, next_action_id)
 		VALUES ($1, $2, to_timestamp($3)::timestamptz AT TIME ZONE 'America/New_York', $4, $5, $6, $7, $8)
+		%s
 		RETURNING lead_id
-	`)
+	`, onConflict))
 	if err != nil {
-		return leadID, fmt.Errorf("error preparing lead statement: %w", err)
+		return leadID, false, fmt.Errorf("error preparing lead statement: %w", err)
 	}
 	defer leadStmt.Close()
 
@@ -78,16 +96,23 @@ func CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error) {
 		constants.NewLeadStatusID,
 		constants.InitialContactActionID,
 	).Scan(&leadID)
+	if skipExistingPhoneNumber && err == sql.ErrNoRows {
+		err = tx.QueryRow(`SELECT lead_id FROM lead WHERE phone_number = $1`, utils.CreateNullString(quoteForm.PhoneNumber)).Scan(&leadID)
+		if err != nil {
+			return leadID, false, fmt.Errorf("error getting existing lead: %w", err)
+		}
+		return leadID, false, nil
+	}
 	if err != nil {
-		return leadID, fmt.Errorf("error inserting lead: %w", err)
+		return leadID, false, fmt.Errorf("error inserting lead: %w", err)
 	}
 
 	marketingStmt, err := tx.Prepare(`
-		INSERT INTO lead_marketing (lead_id, source, medium, channel, landing_page, keyword, referrer, click_id, campaign_id, ad_campaign, ad_group_id, ad_group_name, ad_set_id, ad_set_name, ad_id, ad_headline, language, user_agent, button_clicked, ip, external_id, google_client_id, csrf_secret, facebook_click_id, facebook_client_id, longitude, latitude, instant_form_lead_id, instant_form_id, instant_form_name)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30)
+		INSERT INTO lead_marketing (lead_id, source, medium, channel, landing_page, keyword, referrer, click_id, campaign_id, ad_campaign, ad_group_id, ad_group_name, ad_set_id, ad_set_name, ad_id, ad_headline, language, user_agent, button_clicked, ip, external_id, google_client_id, csrf_secret, facebook_click_id, facebook_client_id, longitude, latitude, instant_form_lead_id, instant_form_id, instant_form_name, tracking_phone_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31)
 	`)
 	if err != nil {
-		return leadID, fmt.Errorf("error preparing marketing statement: %w", err)
+		return leadID, false, fmt.Errorf("error preparing marketing statement: %w", err)
 	}
 	defer marketingStmt.Close()
 
@@ -122,17 +147,62 @@ func CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error) {
 		utils.CreateNullInt64(quoteForm.InstantFormLeadID),
 		utils.CreateNullInt64(quoteForm.InstantFormID),
 		utils.CreateNullString(quoteForm.InstantFormName),
+		utils.CreateNullString(quoteForm.TrackingPhoneNumber),
 	)
 	if err != nil {
-		return leadID, fmt.Errorf("error inserting marketing data: %w", err)
+		return leadID, false, fmt.Errorf("error inserting marketing data: %w", err)
+	}
+
+	if quoteForm.OptInTextMessaging != nil && quoteForm.PhoneNumber != nil {
+		_, err = tx.Exec(`
+			INSERT INTO sms_consent_event (phone_number, lead_id, is_opted_in, source, date_created)
+			VALUES ($1, $2, $3, $4, to_timestamp($5)::timestamptz AT TIME ZONE 'America/New_York')
+		`, *quoteForm.PhoneNumber, leadID, *quoteForm.OptInTextMessaging, constants.LeadFormSMSConsentSource, time.Now().Unix())
+		if err != nil {
+			return leadID, false, fmt.Errorf("error inserting sms consent event: %w", err)
+		}
+	}
+
+	// New leads start every active follow-up sequence
+	_, err = tx.Exec(`
+		INSERT INTO sms_sequence_enrollment (sms_sequence_id, lead_id, status, date_enrolled)
+		SELECT sms_sequence_id, $1, $2, (NOW() AT TIME ZONE 'America/New_York')
+		FROM sms_sequence
+		WHERE is_active = true
+	`, leadID, constants.ActiveSMSSequenceEnrollmentStatus)
+	if err != nil {
+		return leadID, false, fmt.Errorf("error enrolling lead in sms sequences: %w", err)
+	}
+
+	// Round-robin: the sales user who has gone longest without a new conversation gets this one
+	var assignedUserId sql.NullInt64
+	err = tx.QueryRow(`
+		SELECT u.user_id
+		FROM "user" AS u
+		LEFT JOIN conversation AS c ON c.assigned_user_id = u.user_id
+		WHERE u.user_role_id = $1
+		GROUP BY u.user_id
+		ORDER BY MAX(c.date_assigned) ASC NULLS FIRST, u.user_id ASC
+		LIMIT 1
+	`, constants.UserSalesRoleID).Scan(&assignedUserId)
+	if err != nil && err != sql.ErrNoRows {
+		return leadID, false, fmt.Errorf("error getting next sales user: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO conversation (lead_id, assigned_user_id, status, date_assigned, date_created)
+		VALUES ($1, $2, $3, CASE WHEN $2::INTEGER IS NULL THEN NULL ELSE (NOW() AT TIME ZONE 'America/New_York') END, (NOW() AT TIME ZONE 'America/New_York'))
+	`, leadID, assignedUserId, constants.OpenConversationStatus)
+	if err != nil {
+		return leadID, false, fmt.Errorf("error creating conversation: %w", err)
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return leadID, fmt.Errorf("error committing transaction: %w", err)
+		return leadID, false, fmt.Errorf("error committing transaction: %w", err)
 	}
 
-	return leadID, nil
+	return leadID, true, nil
 }
 
 func MarkCSRFTokenAsUsed(token string) error {
@@ -152,20 +222,58 @@ func MarkCSRFTokenAsUsed(token string) error {
 }
 
 func SaveSMS(msg models.Message) error {
-	stmt, err := DB.Prepare(`
-		INSERT INTO message (external_id, text, date_created, text_from, text_to, is_inbound, is_read)
-		VALUES ($1, $2, to_timestamp($3)::timestamptz AT TIME ZONE 'America/New_York', $4, $5, $6, $7)
-	`)
+	tx, err := DB.Begin()
 	if err != nil {
-		return fmt.Errorf("error preparing statement: %w", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = lockMessageExternalID(tx, msg.ExternalID)
+	if err != nil {
+		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(msg.ExternalID, msg.Text, msg.DateCreated, msg.TextFrom, msg.TextTo, msg.IsInbound, msg.IsRead)
+	var messageId int
+	err = tx.QueryRow(`
+		INSERT INTO message (external_id, text, date_created, text_from, text_to, is_inbound, is_read, status)
+		VALUES ($1, $2, to_timestamp($3)::timestamptz AT TIME ZONE 'America/New_York', $4, $5, $6, $7, $8)
+		RETURNING message_id
+	`, msg.ExternalID, msg.Text, msg.DateCreated, msg.TextFrom, msg.TextTo, msg.IsInbound, msg.IsRead, utils.CreateNullString(&msg.Status)).Scan(&messageId)
 	if err != nil {
 		return fmt.Errorf("error executing statement: %w", err)
 	}
 
+	// A status callback that beat the insert is waiting for this row
+	_, err = tx.Exec(`
+		UPDATE message AS m
+		SET status = p.status, error_code = p.error_code, error_message = p.error_message, date_updated = p.date_updated
+		FROM pending_message_status AS p
+		WHERE m.message_id = $1 AND p.external_id = m.external_id
+	`, messageId)
+	if err != nil {
+		return fmt.Errorf("error applying pending message status: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM pending_message_status WHERE external_id = $1`, msg.ExternalID)
+	if err != nil {
+		return fmt.Errorf("error deleting pending message status: %w", err)
+	}
+
+	for _, media := range msg.Media {
+		_, err = tx.Exec(`
+			INSERT INTO message_media (message_id, file_path, content_type, date_created)
+			VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York')
+		`, messageId, media.FilePath, media.ContentType, msg.DateCreated)
+		if err != nil {
+			return fmt.Errorf("error inserting message media: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -367,6 +475,7 @@ func GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
 		lna.action_date, 
 		lc.date_created AS last_contact_date,
 		MAX(q.event_date) as event_date,
+		COALESCE(lom.status IN ('failed', 'undelivered'), false) AS has_failed_message,
 		COUNT(*) OVER() AS total_rows
 	FROM lead AS l
 	JOIN lead_marketing AS lm ON lm.lead_id = l.lead_id
@@ -380,6 +489,12 @@ func GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
 	) AS lna ON lna.lead_id = l.lead_id
 	LEFT JOIN next_action AS nsa ON nsa.next_action_id = lna.next_action_id
 	LEFT JOIN latest_communication AS lc ON lc.phone_number = l.phone_number
+	LEFT JOIN (
+		SELECT DISTINCT ON (text_to) text_to, status
+		FROM message
+		WHERE is_inbound = false
+		ORDER BY text_to, date_created DESC
+	) AS lom ON lom.text_to = l.phone_number
 	LEFT JOIN quote as q ON q.lead_id = l.lead_id
 	WHERE 
 		(
@@ -423,7 +538,8 @@ func GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
 		nsa.action, 
 		na.action, 
 		lna.action_date, 
-		lc.date_created
+		lc.date_created,
+		lom.status
 	ORDER BY l.created_at DESC
 	LIMIT $1 OFFSET $2;`
 
@@ -466,6 +582,7 @@ func GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
 			&nextActionDate,
 			&lastContactDate,
 			&eventDate,
+			&lead.HasFailedMessage,
 			&totalRows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error scanning row: %w", err)
@@ -569,6 +686,7 @@ func GetLeadDetails(leadID string) (types.LeadDetails, error) {
 	lm.instant_form_id,
 	lm.instant_form_name,
 	lm.referral_lead_id,
+	lm.tracking_phone_number,
 	li.lead_interest_id,
 	ls.lead_status_id,
 	na.next_action_id,
@@ -588,7 +706,7 @@ func GetLeadDetails(leadID string) (types.LeadDetails, error) {
 	var message, externalId, userAgent, clickId, googleClientId, stripeCustomerId sql.NullString
 	var campaignId, instantFormleadId, instantFormId, referralLeadId, leadInterestId, leadStatusId, nextActionId sql.NullInt64
 
-	var buttonClicked, instantFormName sql.NullString
+	var buttonClicked, instantFormName, trackingPhoneNumber sql.NullString
 
 	err := row.Scan(
 		&leadDetails.LeadID,
@@ -617,6 +735,7 @@ func GetLeadDetails(leadID string) (types.LeadDetails, error) {
 		&instantFormId,
 		&instantFormName,
 		&referralLeadId,
+		&trackingPhoneNumber,
 		&leadInterestId,
 		&leadStatusId,
 		&nextActionId,
@@ -657,6 +776,9 @@ func GetLeadDetails(leadID string) (types.LeadDetails, error) {
 	if instantFormName.Valid {
 		leadDetails.InstantFormName = instantFormName.String
 	}
+	if trackingPhoneNumber.Valid {
+		leadDetails.TrackingPhoneNumber = trackingPhoneNumber.String
+	}
 
 	if clickId.Valid {
 		leadDetails.ClickID = clickId.String
@@ -1199,7 +1321,7 @@ func CreateEvent(form types.EventForm) error {
 	query := `
 		INSERT INTO event (
 			bartender_id, lead_id, street_address, city, zip_code,
-			start_time, end_time, date_created, date_paid, amount, tip, guests
+			start_time, end_time, date_created, date_paid, amount, tip, guests, quote_id
 		)
 		VALUES (
 			$1, $2, $3, $4, $5,
@@ -1207,7 +1329,7 @@ func CreateEvent(form types.EventForm) error {
 			to_timestamp($7)::timestamptz AT TIME ZONE 'America/New_York',
 			to_timestamp($8)::timestamptz AT TIME ZONE 'America/New_York',
 			to_timestamp($9)::timestamptz AT TIME ZONE 'America/New_York',
-			$10, $11, $12
+			$10, $11, $12, $13
 		)
 	`
 
@@ -1225,6 +1347,7 @@ func CreateEvent(form types.EventForm) error {
 		utils.CreateNullFloat64(form.Amount),
 		utils.CreateNullFloat64(form.Tip),
 		utils.CreateNullInt(form.Guests),
+		utils.CreateNullInt(form.QuoteID),
 	)
 	if err != nil {
 		return fmt.Errorf("error inserting event data: %w", err)
@@ -1495,10 +1618,11 @@ func CreateLeadQuote(form types.LeadQuoteForm) error {
 			guests, 
 			hours,
 			event_date, 
-			external_id
+			external_id,
+			travel_miles
 		)
 		VALUES (
-			$1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', $5
+			$1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', $5, $6
 		);
 	`
 
@@ -1509,6 +1633,7 @@ func CreateLeadQuote(form types.LeadQuoteForm) error {
 		utils.CreateNullFloat64(form.Hours),
 		utils.CreateNullInt64(form.EventDate),
 		uuid.New().String(),
+		utils.CreateNullFloat64(form.TravelMiles),
 	)
 	if err != nil {
 		return fmt.Errorf("error inserting lead quote data: %w", err)
@@ -1581,7 +1706,8 @@ func GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
 		guests,
 		hours,
 		event_date AT TIME ZONE 'America/New_York' AT TIME ZONE 'UTC',
-		quote_id
+		quote_id,
+		travel_miles
 	FROM quote 
 	WHERE quote_id = $1`
 
@@ -1589,7 +1715,7 @@ func GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
 
 	var leadID, guests sql.NullInt64
 	var eventDate sql.NullTime
-	var hours sql.NullFloat64
+	var hours, travelMiles sql.NullFloat64
 
 	row := DB.QueryRow(query, quoteId)
 
@@ -1599,6 +1725,7 @@ func GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
 		&hours,
 		&eventDate,
 		&quoteDetails.QuoteID,
+		&travelMiles,
 	)
 
 	if err != nil {
@@ -1620,6 +1747,9 @@ func GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
 	if eventDate.Valid {
 		quoteDetails.EventDate = eventDate.Time.Unix()
 	}
+	if travelMiles.Valid {
+		quoteDetails.TravelMiles = travelMiles.Float64
+	}
 
 	return quoteDetails, nil
 }
@@ -1630,7 +1760,8 @@ func UpdateLeadQuote(form types.LeadQuoteForm) error {
 		SET 
 			guests = COALESCE($2, guests),
 			hours = COALESCE($3, hours),
-			event_date = COALESCE(to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', event_date)
+			event_date = COALESCE(to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', event_date),
+			travel_miles = COALESCE($5, travel_miles)
 		WHERE quote_id = $1
 	`
 
@@ -1640,6 +1771,7 @@ func UpdateLeadQuote(form types.LeadQuoteForm) error {
 		utils.CreateNullInt(form.Guests),
 		utils.CreateNullFloat64(form.Hours),
 		utils.CreateNullInt64(form.EventDate),
+		utils.CreateNullFloat64(form.TravelMiles),
 	)
 	if err != nil {
 		return fmt.Errorf("error updating lead quote data: %w", err)
@@ -1769,7 +1901,7 @@ func GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails
 		l.phone_number,
 		l.email,
 		i.url AS deposit_invoice_url,
-		SUM(qs.units * qs.price_per_unit::NUMERIC) * it.amount_percentage AS deposit_amount,
+		SUM(qs.units * qs.price_per_unit::NUMERIC) * (SELECT amount_percentage FROM invoice_type WHERE invoice_type_id = $3) AS deposit_amount,
 		0.00 AS remaining_amount,
 		(
 			SELECT i2.url
@@ -1795,20 +1927,19 @@ func GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails
 		) AS is_deposit_paid
 	FROM quote AS q
 	JOIN lead AS l ON q.lead_id = l.lead_id
-	JOIN invoice AS i ON i.quote_id = q.quote_id
-	JOIN invoice_type AS it ON it.invoice_type_id = i.invoice_type_id AND it.invoice_type_id = $3
+	LEFT JOIN invoice AS i ON i.quote_id = q.quote_id AND i.invoice_type_id = $3
 	LEFT JOIN quote_service qs ON qs.quote_id = q.quote_id
 	WHERE q.external_id = $1
 	GROUP BY q.quote_id, guests, hours, event_date, 
-			l.full_name, l.phone_number, l.email, i.url, it.amount_percentage, i.date_created
-	ORDER BY i.date_created DESC
+			l.full_name, l.phone_number, l.email, i.url, i.date_created
+	ORDER BY i.date_created DESC NULLS LAST
 	LIMIT 1;`
 
 	var quoteDetails types.ExternalQuoteDetails
 
 	var guests sql.NullInt64
 	var eventDate sql.NullTime
-	var email sql.NullString
+	var email, depositInvoiceURL, fullInvoiceURL, remainingInvoiceURL sql.NullString
 	var amount, depositAmount, remainingAmount, hours sql.NullFloat64
 
 	row := DB.QueryRow(query, externalQuoteId, constants.OpenInvoiceStatusID, constants.DepositInvoiceTypeID, constants.FullInvoiceTypeID, constants.RemainingInvoiceTypeID, constants.PaidInvoiceStatusID)
@@ -1822,11 +1953,11 @@ func GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails
 		&quoteDetails.FullName,
 		&quoteDetails.PhoneNumber,
 		&email,
-		&quoteDetails.DepositInvoiceURL,
+		&depositInvoiceURL,
 		&depositAmount,
 		&remainingAmount,
-		&quoteDetails.FullInvoiceURL,
-		&quoteDetails.RemainingInvoiceURL,
+		&fullInvoiceURL,
+		&remainingInvoiceURL,
 		&quoteDetails.IsDepositPaid,
 	)
 
@@ -1851,6 +1982,15 @@ func GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails
 	if email.Valid {
 		quoteDetails.Email = email.String
 	}
+	if depositInvoiceURL.Valid {
+		quoteDetails.DepositInvoiceURL = depositInvoiceURL.String
+	}
+	if fullInvoiceURL.Valid {
+		quoteDetails.FullInvoiceURL = fullInvoiceURL.String
+	}
+	if remainingInvoiceURL.Valid {
+		quoteDetails.RemainingInvoiceURL = remainingInvoiceURL.String
+	}
 	if amount.Valid {
 		quoteDetails.Amount = amount.Float64
 	}
@@ -2201,7 +2341,7 @@ func GetServicesList(pageNum int) ([]models.Service, int, error) {
 
 	offset := (pageNum - 1) * int(constants.LeadsPerPage)
 
-	rows, err := DB.Query(`SELECT service_id, service, suggested_price::NUMERIC, service_type_id, guest_ratio, unit_type_id, COUNT(*) OVER() AS total_rows
+	rows, err := DB.Query(`SELECT service_id, service, suggested_price::NUMERIC, service_type_id, guest_ratio, unit_type_id, COALESCE(cost_basis::NUMERIC, 0), COUNT(*) OVER() AS total_rows
 			FROM "service"
 			ORDER BY service_id ASC
 			OFFSET $1
@@ -2216,7 +2356,7 @@ func GetServicesList(pageNum int) ([]models.Service, int, error) {
 		var suggestedPrice sql.NullFloat64
 		var guestRatio, unitTypeId sql.NullInt32
 
-		err := rows.Scan(&service.ServiceID, &service.Service, &suggestedPrice, &service.ServiceTypeID, &guestRatio, &unitTypeId, &totalRows)
+		err := rows.Scan(&service.ServiceID, &service.Service, &suggestedPrice, &service.ServiceTypeID, &guestRatio, &unitTypeId, &service.CostBasis, &totalRows)
 		if err != nil {
 			return services, totalRows, fmt.Errorf("error scanning row: %w", err)
 		}
@@ -2242,7 +2382,7 @@ func GetServicesList(pageNum int) ([]models.Service, int, error) {
 func GetServices() ([]models.Service, error) {
 	var services []models.Service
 
-	rows, err := DB.Query(`SELECT service_id, service, suggested_price::NUMERIC, service_type_id, guest_ratio, unit_type_id FROM "service";`)
+	rows, err := DB.Query(`SELECT service_id, service, suggested_price::NUMERIC, service_type_id, guest_ratio, unit_type_id, COALESCE(cost_basis::NUMERIC, 0) FROM "service";`)
 	if err != nil {
 		return services, fmt.Errorf("error executing query: %w", err)
 	}
@@ -2252,7 +2392,7 @@ func GetServices() ([]models.Service, error) {
 		var service models.Service
 		var suggestedPrice sql.NullFloat64
 		var guestRatio sql.NullInt32
-		err := rows.Scan(&service.ServiceID, &service.Service, &suggestedPrice, &service.ServiceTypeID, &guestRatio, &service.UnitTypeID)
+		err := rows.Scan(&service.ServiceID, &service.Service, &suggestedPrice, &service.ServiceTypeID, &guestRatio, &service.UnitTypeID, &service.CostBasis)
 		if err != nil {
 			return services, fmt.Errorf("error scanning row: %w", err)
 		}
@@ -2274,14 +2414,14 @@ func GetServices() ([]models.Service, error) {
 
 func CreateService(form types.ServiceForm) error {
 	stmt, err := DB.Prepare(`
-		INSERT INTO service (service, suggested_price, service_type_id, guest_ratio) VALUES ($1, $2, $3, $4)
+		INSERT INTO service (service, suggested_price, service_type_id, guest_ratio, cost_basis, unit_type_id) VALUES ($1, $2, $3, $4, COALESCE($5, 0), $6)
 	`)
 	if err != nil {
 		return fmt.Errorf("error preparing statement: %w", err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(utils.CreateNullString(form.Service), utils.CreateNullFloat64(form.SuggestedPrice), utils.CreateNullInt(form.ServiceTypeID), utils.CreateNullInt(form.GuestRatio))
+	_, err = stmt.Exec(utils.CreateNullString(form.Service), utils.CreateNullFloat64(form.SuggestedPrice), utils.CreateNullInt(form.ServiceTypeID), utils.CreateNullInt(form.GuestRatio), utils.CreateNullFloat64(form.CostBasis), utils.CreateNullInt(form.UnitTypeID))
 	if err != nil {
 		return fmt.Errorf("error executing statement: %w", err)
 	}
@@ -2296,7 +2436,8 @@ func UpdateService(form types.ServiceForm) error {
 		suggested_price = COALESCE($2, suggested_price),
 		service_type_id = COALESCE($3, service_type_id),
 		guest_ratio = $4,
-		unit_type_id = COALESCE($5, unit_type_id)
+		unit_type_id = COALESCE($5, unit_type_id),
+		cost_basis = COALESCE($7, cost_basis)
 		WHERE service_id = $6
 	`)
 	if err != nil {
@@ -2304,7 +2445,7 @@ func UpdateService(form types.ServiceForm) error {
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(utils.CreateNullString(form.Service), utils.CreateNullFloat64(form.SuggestedPrice), utils.CreateNullInt(form.ServiceTypeID), utils.CreateNullInt(form.GuestRatio), utils.CreateNullInt(form.UnitTypeID), utils.CreateNullInt(form.ServiceID))
+	_, err = stmt.Exec(utils.CreateNullString(form.Service), utils.CreateNullFloat64(form.SuggestedPrice), utils.CreateNullInt(form.ServiceTypeID), utils.CreateNullInt(form.GuestRatio), utils.CreateNullInt(form.UnitTypeID), utils.CreateNullInt(form.ServiceID), utils.CreateNullFloat64(form.CostBasis))
 	if err != nil {
 		return fmt.Errorf("error executing statement: %w", err)
 	}
@@ -2321,7 +2462,10 @@ func GetQuoteServices(quoteId int) ([]types.QuoteServiceList, error) {
 		qs.units, 
 		qs.price_per_unit::NUMERIC, 
 		(qs.price_per_unit::NUMERIC * qs.units),
-		qs.quote_service_id
+		qs.quote_service_id,
+		COALESCE(qs.pricing_rule, ''),
+		COALESCE(qs.package_tier, ''),
+		qs.is_optional
 	FROM quote_service AS qs
 	JOIN service AS s ON qs.service_id = s.service_id
 	WHERE qs.quote_id = $1;`
@@ -2341,7 +2485,10 @@ func GetQuoteServices(quoteId int) ([]types.QuoteServiceList, error) {
 			&service.Units,
 			&service.PricePerUnit,
 			&service.Total,
-			&service.QuoteServiceID)
+			&service.QuoteServiceID,
+			&service.PricingRule,
+			&service.PackageTier,
+			&service.IsOptional)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
@@ -2370,7 +2517,7 @@ func DeleteQuoteService(id int) error {
 
 func CreateQuoteService(form types.QuoteServiceForm) error {
 	stmt, err := DB.Prepare(`
-		INSERT INTO quote_service (service_id, quote_id, units, price_per_unit) VALUES ($1, $2, $3, $4)
+		INSERT INTO quote_service (service_id, quote_id, units, price_per_unit, pricing_rule, package_tier, is_optional) VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`)
 	if err != nil {
 		return fmt.Errorf("error preparing statement: %w", err)
@@ -2382,6 +2529,9 @@ func CreateQuoteService(form types.QuoteServiceForm) error {
 		utils.CreateNullInt(form.QuoteID),
 		utils.CreateNullFloat64(form.Units),
 		utils.CreateNullFloat64(form.PricePerUnit),
+		utils.CreateNullString(form.PricingRule),
+		utils.CreateNullString(form.PackageTier),
+		utils.CreateNullBoolDefaultFalse(form.IsOptional),
 	)
 	if err != nil {
 		return fmt.Errorf("error executing statement: %w", err)
@@ -2393,8 +2543,8 @@ func CreateQuoteService(form types.QuoteServiceForm) error {
 func CreateQuoteServicesMany(tx *sql.Tx, services []types.QuoteServiceForm) error {
 	// Prepare the SQL statement for batch insert
 	stmt, err := tx.Prepare(`
-		INSERT INTO quote_service (service_id, quote_id, units, price_per_unit) 
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO quote_service (service_id, quote_id, units, price_per_unit, pricing_rule) 
+		VALUES ($1, $2, $3, $4, $5)
 	`)
 	if err != nil {
 		return fmt.Errorf("error preparing statement: %w", err)
@@ -2408,6 +2558,7 @@ func CreateQuoteServicesMany(tx *sql.Tx, services []types.QuoteServiceForm) erro
 			utils.CreateNullInt(service.QuoteID),
 			utils.CreateNullFloat64(service.Units),
 			utils.CreateNullFloat64(service.PricePerUnit),
+			utils.CreateNullString(service.PricingRule),
 		)
 		if err != nil {
 			return fmt.Errorf("error inserting quote service: %w", err)
@@ -2421,7 +2572,8 @@ func UpdateQuoteService(form types.QuoteServiceForm) error {
 	stmt, err := DB.Prepare(`
 		UPDATE quote_service
 		SET price_per_unit = COALESCE($1, price_per_unit),
-		units = COALESCE($2, units)
+		units = COALESCE($2, units),
+		pricing_rule = COALESCE($4, pricing_rule)
 		WHERE quote_service_id = $3
 	`)
 	if err != nil {
@@ -2433,6 +2585,7 @@ func UpdateQuoteService(form types.QuoteServiceForm) error {
 		utils.CreateNullFloat64(form.PricePerUnit),
 		utils.CreateNullFloat64(form.Units),
 		utils.CreateNullInt(form.QuoteServiceID),
+		utils.CreateNullString(form.PricingRule),
 	)
 	if err != nil {
 		return fmt.Errorf("error executing statement: %w", err)
@@ -2489,7 +2642,10 @@ func GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
 	m.is_inbound,
 	m.message_id,
 	l.lead_id,
-	m.is_read
+	m.is_read,
+	COALESCE(m.status, ''),
+	COALESCE(m.error_code, ''),
+	COALESCE(m.error_message, '')
 	FROM "message" AS m
 	JOIN "lead" AS l ON l.phone_number IN (m.text_from, m.text_to)
 	JOIN "user" AS u  ON u.phone_number IN (m.text_from, m.text_to)
@@ -2503,6 +2659,7 @@ func GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
 	}
 	defer rows.Close()
 
+	var messageTimes []int64
 	for rows.Next() {
 		var dateCreated time.Time
 
@@ -2516,6 +2673,9 @@ func GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
 			&message.MessageID,
 			&message.LeadID,
 			&message.IsRead,
+			&message.Status,
+			&message.ErrorCode,
+			&message.ErrorMessage,
 		)
 		if err != nil {
 			fmt.Printf("%+v\n", err)
@@ -2523,14 +2683,131 @@ func GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
 		}
 
 		message.DateCreated = utils.FormatTimestampWithOptions(dateCreated.Unix(), nil)
+		message.IsFailed = isFailedMessageStatus(message.Status)
 		messages = append(messages, message)
+		messageTimes = append(messageTimes, dateCreated.Unix())
 	}
 
 	if err = rows.Err(); err != nil {
 		return messages, err
 	}
 
-	return messages, nil
+	media, err := getMessageMediaByLeadID(leadId)
+	if err != nil {
+		return messages, err
+	}
+
+	for i := range messages {
+		messages[i].Media = media[messages[i].MessageID]
+	}
+
+	notes, noteTimes, err := getConversationNotesByLeadID(leadId)
+	if err != nil {
+		return messages, err
+	}
+
+	return mergeConversationNotes(messages, messageTimes, notes, noteTimes), nil
+}
+
+func getMessageMediaByLeadID(leadId int) (map[int][]types.FrontendMessageMedia, error) {
+	media := make(map[int][]types.FrontendMessageMedia)
+
+	rows, err := DB.Query(`SELECT mm.message_id, mm.message_media_id, mm.content_type
+	FROM message_media AS mm
+	JOIN "message" AS m ON m.message_id = mm.message_id
+	JOIN "lead" AS l ON l.phone_number IN (m.text_from, m.text_to)
+	WHERE l.lead_id = $1
+	ORDER BY mm.message_media_id ASC;`, leadId)
+	if err != nil {
+		return media, fmt.Errorf("error querying message media: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageId, messageMediaId int
+		var contentType string
+
+		if err := rows.Scan(&messageId, &messageMediaId, &contentType); err != nil {
+			return media, fmt.Errorf("error scanning message media: %w", err)
+		}
+
+		media[messageId] = append(media[messageId], newFrontendMessageMedia(messageMediaId, contentType))
+	}
+
+	if err = rows.Err(); err != nil {
+		return media, err
+	}
+
+	return media, nil
+}
+
+func getConversationNotesByLeadID(leadId int) ([]types.FrontendMessage, []int64, error) {
+	var notes []types.FrontendMessage
+	var noteTimes []int64
+
+	rows, err := DB.Query(`SELECT CONCAT(u.first_name, ' ', u.last_name), cn.note, cn.date_created
+	FROM conversation_note AS cn
+	JOIN conversation AS c ON c.conversation_id = cn.conversation_id
+	LEFT JOIN "user" AS u ON u.user_id = cn.user_id
+	WHERE c.lead_id = $1
+	ORDER BY cn.date_created ASC;`, leadId)
+	if err != nil {
+		return notes, noteTimes, fmt.Errorf("error querying conversation notes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dateCreated time.Time
+		note := types.FrontendMessage{
+			LeadID: leadId,
+			IsRead: true,
+			IsNote: true,
+		}
+
+		if err := rows.Scan(&note.UserName, &note.Message, &dateCreated); err != nil {
+			return notes, noteTimes, fmt.Errorf("error scanning conversation note: %w", err)
+		}
+
+		note.DateCreated = utils.FormatTimestampWithOptions(dateCreated.Unix(), nil)
+		notes = append(notes, note)
+		noteTimes = append(noteTimes, dateCreated.Unix())
+	}
+
+	if err = rows.Err(); err != nil {
+		return notes, noteTimes, err
+	}
+
+	return notes, noteTimes, nil
+}
+
+// mergeConversationNotes interleaves internal notes with the texts by time. Both lists must already be sorted.
+func mergeConversationNotes(messages []types.FrontendMessage, messageTimes []int64, notes []types.FrontendMessage, noteTimes []int64) []types.FrontendMessage {
+	if len(notes) == 0 {
+		return messages
+	}
+
+	merged := make([]types.FrontendMessage, 0, len(messages)+len(notes))
+	i, j := 0, 0
+	for i < len(messages) || j < len(notes) {
+		if j == len(notes) || (i < len(messages) && messageTimes[i] <= noteTimes[j]) {
+			merged = append(merged, messages[i])
+			i++
+			continue
+		}
+		merged = append(merged, notes[j])
+		j++
+	}
+
+	return merged
+}
+
+// newFrontendMessageMedia links to the CRM route that signs the private media URL, so only logged in users can open it.
+func newFrontendMessageMedia(messageMediaId int, contentType string) types.FrontendMessageMedia {
+	return types.FrontendMessageMedia{
+		URL:         fmt.Sprintf("/crm/message/media/%d", messageMediaId),
+		ContentType: contentType,
+		IsImage:     strings.HasPrefix(contentType, "image/"),
+	}
 }
 
 func CreateLeadNote(note models.LeadNote) error {
@@ -2596,7 +2873,7 @@ func GetLeadNotesByLeadID(leadId int) ([]types.FrontendNote, error) {
 	return notes, nil
 }
 
-func GetLeadsWithMessages() ([]types.LeadsWithMessages, error) {
+func GetLeadsWithMessages(filters types.ConversationFilters) ([]types.LeadsWithMessages, error) {
 	var messages []types.LeadsWithMessages
 
 	rows, err := DB.Query(`
@@ -2610,10 +2887,18 @@ func GetLeadsWithMessages() ([]types.LeadsWithMessages, error) {
 				MAX(m.message_id) AS latest_message_id,
 				MAX(m.date_created) AS latest_message_time,
 				l.lead_status_id,
-				l.lead_interest_id
+				l.lead_interest_id,
+				c.assigned_user_id,
+				CONCAT(au.first_name, ' ', au.last_name) AS assigned_user_name,
+				CASE
+					WHEN c.status = 'snoozed' AND c.snoozed_until <= (NOW() AT TIME ZONE 'America/New_York') THEN 'open'
+					ELSE COALESCE(c.status, 'open')
+				END AS conversation_status
 			FROM "lead" AS l
 			LEFT JOIN "message" AS m ON l.phone_number IN (m.text_from, m.text_to)
-			GROUP BY l.lead_id, l.full_name, l.lead_status_id, l.lead_interest_id
+			LEFT JOIN conversation AS c ON c.lead_id = l.lead_id
+			LEFT JOIN "user" AS au ON au.user_id = c.assigned_user_id
+			GROUP BY l.lead_id, l.full_name, l.lead_status_id, l.lead_interest_id, c.assigned_user_id, au.first_name, au.last_name, c.status, c.snoozed_until
 		),
 		temp_distinct_leads AS (
 			SELECT DISTINCT ON (t.lead_id)
@@ -2625,7 +2910,10 @@ func GetLeadsWithMessages() ([]types.LeadsWithMessages, error) {
 				t.latest_message_id,
 				t.lead_status_id,
 				t.lead_interest_id,
-				t.latest_message_time
+				t.latest_message_time,
+				t.assigned_user_id,
+				t.assigned_user_name,
+				t.conversation_status
 			FROM temp_leads AS t
 			ORDER BY t.lead_id, t.latest_message_id DESC NULLS LAST
 		)
@@ -2633,18 +2921,27 @@ func GetLeadsWithMessages() ([]types.LeadsWithMessages, error) {
 			lead_id, 
 			full_name, 
 			unread_messages,
-			phone_number
+			phone_number,
+			assigned_user_name,
+			conversation_status
 		FROM temp_distinct_leads
 		WHERE 
-			(unread_messages > 0) OR (lead_status_id != $1 OR lead_interest_id != $2)
+			((unread_messages > 0) OR (lead_status_id != $1 OR lead_interest_id != $2)
 
 			OR (lead_status_id = $1 AND latest_message_time > CURRENT_DATE - INTERVAL '7 days')
 
-			OR (lead_interest_id = $2 AND latest_message_time > CURRENT_DATE - INTERVAL '7 days')
+			OR (lead_interest_id = $2 AND latest_message_time > CURRENT_DATE - INTERVAL '7 days'))
+
+			AND ($3::TEXT IS NULL OR conversation_status = $3::TEXT)
+			AND ($4::INTEGER IS NULL OR assigned_user_id = $4::INTEGER)
+			AND (NOT $5::BOOLEAN OR assigned_user_id IS NULL)
 		ORDER BY 
 			CASE WHEN unread_messages > 0 THEN 0 ELSE 1 END, latest_unread_message_id DESC NULLS LAST,
 			latest_message_id DESC NULLS LAST, lead_id DESC;
-	`, constants.ArchivedLeadStatusID, constants.NoInterestLeadInterestID)
+	`, constants.ArchivedLeadStatusID, constants.NoInterestLeadInterestID,
+		utils.CreateNullString(filters.Status),
+		utils.CreateNullInt(filters.AssignedUserID),
+		filters.Unassigned)
 	if err != nil {
 		return messages, fmt.Errorf("error executing query: %v", err)
 	}
@@ -2657,11 +2954,14 @@ func GetLeadsWithMessages() ([]types.LeadsWithMessages, error) {
 			&message.LeadName,
 			&message.UnreadMessages,
 			&message.LeadPhoneNumber,
+			&message.AssignedUserName,
+			&message.Status,
 		)
 		if err != nil {
 			return messages, fmt.Errorf("error scanning row: %v", err)
 		}
 
+		message.AssignedUserName = strings.TrimSpace(message.AssignedUserName)
 		messages = append(messages, message)
 	}
 
@@ -2747,6 +3047,12 @@ func UpdateCallRecordingURL(callSid string, recordingURL string) error {
 }
 
 func CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO phone_call_transcription (
 			phone_call_id, 
@@ -2759,7 +3065,7 @@ func CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) e
 		);
 	`
 
-	_, err := DB.Exec(
+	_, err = tx.Exec(
 		query,
 		transcription.PhoneCallID,
 		transcription.Text,
@@ -2770,22 +3076,35 @@ func CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) e
 		return fmt.Errorf("error inserting phone call transcription: %w", err)
 	}
 
+	_, err = tx.Exec(`UPDATE phone_call SET transcription_status = $2 WHERE phone_call_id = $1`, transcription.PhoneCallID, constants.CompletedTranscriptionStatus)
+	if err != nil {
+		return fmt.Errorf("error completing phone call transcription: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
 
+// A transcription that has been claimed this long is assumed to have died with its worker
+const phoneCallTranscriptionClaimTimeout = time.Hour
+
 func GetPhoneCallsWithoutTranscription() ([]models.PhoneCall, error) {
 	var phoneCalls []models.PhoneCall
 
 	query := `
 		SELECT p.phone_call_id, p.recording_url, p.call_from, p.call_to, p.is_inbound
 		FROM phone_call AS p
-		WHERE NOT EXISTS (
-			SELECT 1 FROM phone_call_transcription AS t 
-			WHERE t.phone_call_id = p.phone_call_id
-		) AND p.recording_url IS NOT NULL
+		WHERE p.recording_url IS NOT NULL AND (
+			p.transcription_status = $1
+			OR (p.transcription_status = $2 AND p.date_transcription_claimed < (NOW() AT TIME ZONE 'America/New_York') - make_interval(secs => $3))
+		)
 	`
 
-	rows, err := DB.Query(query)
+	rows, err := DB.Query(query, constants.PendingTranscriptionStatus, constants.ProcessingTranscriptionStatus, phoneCallTranscriptionClaimTimeout.Seconds())
 	if err != nil {
 		return phoneCalls, fmt.Errorf("error executing query: %w", err)
 	}
@@ -2807,6 +3126,41 @@ func GetPhoneCallsWithoutTranscription() ([]models.PhoneCall, error) {
 	return phoneCalls, nil
 }
 
+// ClaimPhoneCallTranscription reports whether this caller won the call, so the voicemail job and the sweep never both transcribe it.
+func ClaimPhoneCallTranscription(phoneCallId int) (bool, error) {
+	var claimedId int
+	err := DB.QueryRow(`
+		UPDATE phone_call
+		SET transcription_status = $2, date_transcription_claimed = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE phone_call_id = $1 AND recording_url IS NOT NULL AND (
+			transcription_status = $3
+			OR (transcription_status = $2 AND date_transcription_claimed < (NOW() AT TIME ZONE 'America/New_York') - make_interval(secs => $4))
+		)
+		RETURNING phone_call_id
+	`, phoneCallId, constants.ProcessingTranscriptionStatus, constants.PendingTranscriptionStatus, phoneCallTranscriptionClaimTimeout.Seconds()).Scan(&claimedId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error claiming phone call transcription: %w", err)
+	}
+
+	return true, nil
+}
+
+func ReleasePhoneCallTranscription(phoneCallId int) error {
+	_, err := DB.Exec(`
+		UPDATE phone_call
+		SET transcription_status = $2, date_transcription_claimed = NULL
+		WHERE phone_call_id = $1 AND transcription_status = $3
+	`, phoneCallId, constants.PendingTranscriptionStatus, constants.ProcessingTranscriptionStatus)
+	if err != nil {
+		return fmt.Errorf("error releasing phone call transcription: %w", err)
+	}
+
+	return nil
+}
+
 func CreateLeadNextAction(leadNextAction types.LeadNextActionForm) error {
 	query := `
 		INSERT INTO lead_next_action (
@@ -3214,43 +3568,164 @@ func GetPaginatedEventList(pageNum int) ([]types.EventListView, int, error) {
 	return events, totalRows, nil
 }
 
-func GetServiceTypes() ([]models.ServiceType, error) {
-	var serviceTypes []models.ServiceType
+func GetPaginatedEventListByUser(pageNum, userId int) ([]types.EventListView, int, error) {
+	var events []types.EventListView
+	var totalRows int
 
-	rows, err := DB.Query(`SELECT service_type_id, service_type FROM "service_type"`)
+	offset := (pageNum - 1) * int(constants.LeadsPerPage)
+
+	rows, err := DB.Query(`
+		SELECT 
+		e.event_id,
+		e.lead_id,
+		COALESCE(e.amount::NUMERIC, 0) + COALESCE(e.tip::NUMERIC, 0) AS revenue,
+		l.full_name,
+		CONCAT(b.first_name, ' ', b.last_name) AS bartender,
+		e.guests,
+		e.start_time,
+		e.end_time,
+		EXISTS (
+			SELECT 1 FROM invoice AS inv
+			WHERE inv.quote_id = q.quote_id
+			AND inv.invoice_type_id = $3
+		) 
+		AND NOT EXISTS (
+			SELECT 1 FROM invoice AS inv
+			WHERE inv.quote_id = q.quote_id
+			AND inv.invoice_type_id IN ($4, $5)
+			AND inv.invoice_status_id = $6
+		) AS is_deposit_paid,
+		q.quote_id,
+		COUNT(*) OVER() AS total_rows
+	FROM event AS e
+	JOIN lead AS l ON l.lead_id = e.lead_id
+	LEFT JOIN "user" AS b ON b.user_id = e.bartender_id
+	LEFT JOIN quote AS q ON q.lead_id = l.lead_id
+	WHERE e.bartender_id = $7
+	OR EXISTS (SELECT 1 FROM event_staff AS es WHERE es.event_id = e.event_id AND es.user_id = $7)
+	ORDER BY e.start_time DESC
+	OFFSET $1
+	LIMIT $2;`, offset, constants.LeadsPerPage, constants.DepositInvoiceTypeID, constants.FullInvoiceTypeID, constants.RemainingInvoiceTypeID, constants.PaidInvoiceStatusID, userId)
 	if err != nil {
-		return serviceTypes, fmt.Errorf("error executing query: %w", err)
+		return events, totalRows, fmt.Errorf("error executing query: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var st models.ServiceType
-		err := rows.Scan(&st.ServiceTypeID, &st.Type)
+		var event types.EventListView
+		var eventStart, eventEnd sql.NullTime
+		var guests sql.NullInt64
+		var bartender sql.NullString
+		var shouldSendReminder sql.NullBool
+		var quoteId sql.NullInt32
+
+		err := rows.Scan(
+			&event.EventID,
+			&event.LeadID,
+			&event.Amount,
+			&event.LeadName,
+			&bartender,
+			&guests,
+			&eventStart,
+			&eventEnd,
+			&shouldSendReminder,
+			&quoteId,
+			&totalRows,
+		)
 		if err != nil {
-			return serviceTypes, fmt.Errorf("error scanning row: %w", err)
+			return events, totalRows, fmt.Errorf("error scanning row: %w", err)
 		}
-		serviceTypes = append(serviceTypes, st)
+
+		if bartender.Valid {
+			event.Bartender = bartender.String
+		}
+
+		if quoteId.Valid {
+			event.QuoteID = int(quoteId.Int32)
+		}
+
+		if shouldSendReminder.Valid {
+			event.ShouldSendReminder = shouldSendReminder.Bool
+		}
+
+		if eventStart.Valid && eventEnd.Valid {
+			event.EventTime = fmt.Sprintf(
+				"%s - %s",
+				utils.FormatTimestampWithOptions(eventStart.Time.Unix(), nil),
+				utils.FormatTimestampWithOptions(eventEnd.Time.Unix(), nil),
+			)
+		}
+
+		if guests.Valid {
+			event.Guests = int(guests.Int64)
+		}
+
+		events = append(events, event)
 	}
 
 	if err := rows.Err(); err != nil {
-		return serviceTypes, fmt.Errorf("error iterating rows: %w", err)
+		return events, totalRows, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	return serviceTypes, nil
+	return events, totalRows, nil
 }
 
-func CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.QuoteServiceForm) (int, string, error) {
-	var quoteId int
-	quoteExternalId := uuid.New().String()
+func IsUserAssignedToEvent(userId, eventId int) (bool, error) {
+	var isAssigned bool
 
-	// Start a new transaction
-	tx, err := DB.Begin()
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM event AS e
+			WHERE e.event_id = $2
+			AND (
+				e.bartender_id = $1
+				OR EXISTS (SELECT 1 FROM event_staff AS es WHERE es.event_id = e.event_id AND es.user_id = $1)
+			)
+		)`, userId, eventId).Scan(&isAssigned)
 	if err != nil {
-		return quoteId, quoteExternalId, fmt.Errorf("error starting transaction: %w", err)
+		return isAssigned, fmt.Errorf("error checking event assignment: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
+
+	return isAssigned, nil
+}
+
+func GetServiceTypes() ([]models.ServiceType, error) {
+	var serviceTypes []models.ServiceType
+
+	rows, err := DB.Query(`SELECT service_type_id, service_type FROM "service_type"`)
+	if err != nil {
+		return serviceTypes, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var st models.ServiceType
+		err := rows.Scan(&st.ServiceTypeID, &st.Type)
+		if err != nil {
+			return serviceTypes, fmt.Errorf("error scanning row: %w", err)
+		}
+		serviceTypes = append(serviceTypes, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return serviceTypes, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return serviceTypes, nil
+}
+
+func CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.QuoteServiceForm) (int, string, error) {
+	var quoteId int
+	quoteExternalId := uuid.New().String()
+
+	// Start a new transaction
+	tx, err := DB.Begin()
+	if err != nil {
+		return quoteId, quoteExternalId, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
 		} else {
 			tx.Commit()
 		}
@@ -3258,8 +3733,8 @@ func CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.Quo
 
 	// Insert into quote table
 	query := `
-		INSERT INTO quote (lead_id, guests, hours, event_date, external_id)
-		VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', $5)
+		INSERT INTO quote (lead_id, guests, hours, event_date, external_id, travel_miles)
+		VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', $5, $6)
 		RETURNING quote_id;
 	`
 
@@ -3270,6 +3745,7 @@ func CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.Quo
 		utils.CreateNullFloat64(quickQuote.Hours),
 		utils.CreateNullInt64(quickQuote.EventDate),
 		quoteExternalId,
+		utils.CreateNullFloat64(quickQuote.TravelMiles),
 	).Scan(&quoteId)
 
 	if err != nil {
@@ -3289,6 +3765,34 @@ func CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.Quo
 	return quoteId, quoteExternalId, tx.Commit()
 }
 
+func ReplaceQuoteAdjustments(quoteId int, adjustments []types.QuoteServiceForm) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM quote_service AS qs
+		USING service AS s
+		WHERE s.service_id = qs.service_id AND qs.quote_id = $1 AND s.service_type_id = $2
+	`, quoteId, constants.PricingAdjustmentServiceTypeID)
+	if err != nil {
+		return fmt.Errorf("error deleting quote adjustments: %w", err)
+	}
+
+	for i := range adjustments {
+		adjustments[i].QuoteID = &quoteId
+	}
+
+	err = CreateQuoteServicesMany(tx, adjustments)
+	if err != nil {
+		return fmt.Errorf("error inserting quote adjustments: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 func ArchivedLeadsWithLastContactOverTwoWeeks() error {
 	query := `
 		WITH latest_communication AS (
@@ -3327,10 +3831,11 @@ func ArchivedLeadsWithLastContactOverTwoWeeks() error {
 func GetEventStaff(eventId int) ([]types.EventStaffList, error) {
 	var eventStaffList []types.EventStaffList
 
-	query := `SELECT s.event_staff_id, u.first_name, u.last_name, r.role
+	query := `SELECT s.event_staff_id, u.first_name, u.last_name, r.role, COALESCE(s.hourly_rate::NUMERIC, 0), ss.status
 	FROM "user" AS u
 	JOIN user_role AS r ON r.user_role_id = u.user_role_id
-	JOIN event_staff AS s ON s.user_id = u.user_id AND s.event_id = $1;`
+	JOIN event_staff AS s ON s.user_id = u.user_id AND s.event_id = $1
+	JOIN event_staff_status AS ss ON ss.event_staff_status_id = s.event_staff_status_id;`
 	rows, err := DB.Query(query, eventId)
 	if err != nil {
 		return nil, fmt.Errorf("error executing query: %w", err)
@@ -3344,6 +3849,8 @@ func GetEventStaff(eventId int) ([]types.EventStaffList, error) {
 			&eventStaff.FirstName,
 			&eventStaff.LastName,
 			&eventStaff.Role,
+			&eventStaff.HourlyRate,
+			&eventStaff.Status,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
@@ -3394,8 +3901,8 @@ func GetUserRoles() ([]models.UserRole, error) {
 
 func CreateEventStaff(form types.EventStaffForm) error {
 	query := `
-		INSERT INTO event_staff (event_id, user_id, user_role_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO event_staff (event_id, user_id, user_role_id, hourly_rate)
+		VALUES ($1, $2, $3, $4)
 	`
 
 	_, err := DB.Exec(
@@ -3403,6 +3910,7 @@ func CreateEventStaff(form types.EventStaffForm) error {
 		utils.CreateNullInt(form.EventID),
 		utils.CreateNullInt(form.UserID),
 		utils.CreateNullInt(form.UserRoleID),
+		utils.CreateNullFloat64(form.HourlyRate),
 	)
 	if err != nil {
 		return fmt.Errorf("error inserting event data: %w", err)
@@ -3411,6 +3919,231 @@ func CreateEventStaff(form types.EventStaffForm) error {
 	return nil
 }
 
+func GetStaffAssignments(userId int) ([]types.StaffAssignment, error) {
+	var assignments []types.StaffAssignment
+
+	query := `SELECT s.event_staff_id,
+		e.event_id,
+		COALESCE(er.role, r.role, ''),
+		e.street_address,
+		e.city,
+		e.zip_code,
+		e.start_time,
+		e.end_time,
+		e.guests,
+		COALESCE(s.hourly_rate::NUMERIC, 0),
+		COALESCE(EXTRACT(EPOCH FROM (e.end_time - e.start_time)) / 3600, 0),
+		s.event_staff_status_id,
+		ss.status,
+		e.start_time > (NOW() AT TIME ZONE 'America/New_York')
+	FROM event_staff AS s
+	JOIN event AS e ON e.event_id = s.event_id
+	JOIN event_staff_status AS ss ON ss.event_staff_status_id = s.event_staff_status_id
+	LEFT JOIN event_role AS er ON er.event_role_id = s.event_role_id
+	LEFT JOIN user_role AS r ON r.user_role_id = s.user_role_id
+	WHERE s.user_id = $1
+	ORDER BY e.start_time DESC;`
+
+	rows, err := DB.Query(query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assignment types.StaffAssignment
+		var streetAddress, city, zipCode sql.NullString
+		var eventStart, eventEnd sql.NullTime
+		var guests sql.NullInt64
+		var isUpcoming sql.NullBool
+
+		if err := rows.Scan(
+			&assignment.EventStaffID,
+			&assignment.EventID,
+			&assignment.Role,
+			&streetAddress,
+			&city,
+			&zipCode,
+			&eventStart,
+			&eventEnd,
+			&guests,
+			&assignment.HourlyRate,
+			&assignment.Hours,
+			&assignment.EventStaffStatusID,
+			&assignment.Status,
+			&isUpcoming,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if streetAddress.Valid {
+			assignment.StreetAddress = streetAddress.String
+		}
+
+		if city.Valid {
+			assignment.City = city.String
+		}
+
+		if zipCode.Valid {
+			assignment.ZipCode = zipCode.String
+		}
+
+		if eventStart.Valid && eventEnd.Valid {
+			assignment.EventTime = fmt.Sprintf(
+				"%s - %s",
+				utils.FormatTimestampWithOptions(eventStart.Time.Unix(), nil),
+				utils.FormatTimestampWithOptions(eventEnd.Time.Unix(), nil),
+			)
+		}
+
+		if guests.Valid {
+			assignment.Guests = int(guests.Int64)
+		}
+
+		if isUpcoming.Valid {
+			assignment.IsUpcoming = isUpcoming.Bool
+		}
+
+		assignment.ExpectedPay = assignment.Hours * assignment.HourlyRate
+
+		assignments = append(assignments, assignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// UpdateEventStaffStatus only touches assignments belonging to userId so staff cannot respond for each other.
+func UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId int) error {
+	query := `
+		UPDATE event_staff
+		SET event_staff_status_id = $3, date_responded = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE event_staff_id = $1 AND user_id = $2
+	`
+
+	result, err := DB.Exec(query, eventStaffId, userId, eventStaffStatusId)
+	if err != nil {
+		return fmt.Errorf("error updating event staff status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no event staff assignment found with id %d for user %d", eventStaffId, userId)
+	}
+
+	return nil
+}
+
+func GetPayrollLines(start, end int64) ([]types.PayrollLine, error) {
+	var lines []types.PayrollLine
+
+	query := `SELECT s.event_staff_id,
+		e.event_id,
+		u.user_id,
+		CONCAT(u.first_name, ' ', u.last_name),
+		COALESCE(er.role, r.role, ''),
+		e.start_time,
+		COALESCE(EXTRACT(EPOCH FROM (e.end_time - e.start_time)) / 3600, 0),
+		COALESCE(s.hourly_rate::NUMERIC, 0),
+		COALESCE(e.tip::NUMERIC, 0) / COUNT(*) OVER (PARTITION BY e.event_id),
+		s.date_paid,
+		COALESCE(s.amount_paid::NUMERIC, 0)
+	FROM event_staff AS s
+	JOIN event AS e ON e.event_id = s.event_id
+	JOIN "user" AS u ON u.user_id = s.user_id
+	LEFT JOIN event_role AS er ON er.event_role_id = s.event_role_id
+	LEFT JOIN user_role AS r ON r.user_role_id = s.user_role_id
+	WHERE s.event_staff_status_id != $3
+	AND e.start_time >= to_timestamp($1)::timestamptz AT TIME ZONE 'America/New_York'
+	AND e.start_time < to_timestamp($2)::timestamptz AT TIME ZONE 'America/New_York'
+	ORDER BY u.first_name, u.last_name, e.start_time;`
+
+	rows, err := DB.Query(query, start, end, constants.DeclinedEventStaffStatusID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line types.PayrollLine
+		var eventStart, datePaid sql.NullTime
+
+		if err := rows.Scan(
+			&line.EventStaffID,
+			&line.EventID,
+			&line.UserID,
+			&line.StaffMember,
+			&line.Role,
+			&eventStart,
+			&line.Hours,
+			&line.HourlyRate,
+			&line.TipShare,
+			&datePaid,
+			&line.AmountPaid,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if eventStart.Valid {
+			line.EventDate = utils.FormatTimestampWithOptions(eventStart.Time.Unix(), nil)
+		}
+
+		if datePaid.Valid {
+			line.IsPaid = true
+			line.DatePaid = utils.FormatTimestampWithOptions(datePaid.Time.Unix(), nil)
+		}
+
+		line.Wages = line.Hours * line.HourlyRate
+		line.Total = line.Wages + line.TipShare
+
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return lines, nil
+}
+
+// MarkPayrollLinesPaid records what each line paid out so later rate or tip edits don't rewrite history.
+func MarkPayrollLinesPaid(lines []types.PayrollLine) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		UPDATE event_staff
+		SET date_paid = (NOW() AT TIME ZONE 'America/New_York'), amount_paid = $2
+		WHERE event_staff_id = $1 AND date_paid IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, line := range lines {
+		if _, err := stmt.Exec(line.EventStaffID, line.Total); err != nil {
+			return fmt.Errorf("error marking payroll line %d as paid: %w", line.EventStaffID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
 func DeleteEventStaff(id int) error {
 	sqlStatement := `
         DELETE FROM event_staff WHERE event_staff_id = $1
@@ -3426,7 +4159,7 @@ func DeleteEventStaff(id int) error {
 func GetEventCocktails(eventId int) ([]types.EventCocktailList, error) {
 	var eventCocktailList []types.EventCocktailList
 
-	query := `SELECT ec.event_cocktail_id, c.name
+	query := `SELECT ec.event_cocktail_id, c.cocktail_id, c.name
 	FROM cocktail AS c
 	JOIN event_cocktail AS ec ON ec.cocktail_id = c.cocktail_id AND ec.event_id = $1;`
 	rows, err := DB.Query(query, eventId)
@@ -3439,6 +4172,7 @@ func GetEventCocktails(eventId int) ([]types.EventCocktailList, error) {
 		var eventCocktail types.EventCocktailList
 		if err := rows.Scan(
 			&eventCocktail.EventCocktailID,
+			&eventCocktail.CocktailID,
 			&eventCocktail.Name,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning row: %w", err)
@@ -3777,32 +4511,36 @@ func DeleteCocktail(id int) error {
 func UpdateCocktail(form types.CocktailForm) error {
 	query := `
 		UPDATE cocktail
-		SET name = COALESCE($2, name)
-		WHERE user_id = $1;
+		SET name = COALESCE($2, name),
+			instructions = COALESCE($3, instructions)
+		WHERE cocktail_id = $1;
 	`
 
 	_, err := DB.Exec(
 		query,
 		utils.CreateNullInt(form.CocktailID),
 		utils.CreateNullString(form.Name),
+		utils.CreateNullString(form.Instructions),
 	)
 	if err != nil {
-		return fmt.Errorf("error updating user: %w", err)
+		return fmt.Errorf("error updating cocktail: %w", err)
 	}
 
 	return nil
 }
 
 func GetCocktailDetails(cocktailId string) (models.Cocktail, error) {
-	query := `SELECT cocktail_id, name FROM cocktail WHERE cocktail_id = $1`
+	query := `SELECT cocktail_id, name, instructions FROM cocktail WHERE cocktail_id = $1`
 
 	var cocktailDetails models.Cocktail
+	var instructions sql.NullString
 
 	row := DB.QueryRow(query, cocktailId)
 
 	err := row.Scan(
 		&cocktailDetails.CocktailID,
 		&cocktailDetails.Name,
+		&instructions,
 	)
 
 	if err != nil {
@@ -3812,30 +4550,2106 @@ func GetCocktailDetails(cocktailId string) (models.Cocktail, error) {
 		return cocktailDetails, fmt.Errorf("error scanning row: %w", err)
 	}
 
+	if instructions.Valid {
+		cocktailDetails.Instructions = instructions.String
+	}
+
 	return cocktailDetails, nil
 }
 
-func GetUnitTypes() ([]models.UnitType, error) {
-	var unitTypes []models.UnitType
+func GetIngredients() ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
 
-	rows, err := DB.Query(`SELECT unit_type_id, type FROM "unit_type"`)
+	rows, err := DB.Query(`SELECT ingredient_id, name, category FROM ingredient ORDER BY category, name`)
 	if err != nil {
-		return unitTypes, fmt.Errorf("error executing query: %w", err)
+		return ingredients, fmt.Errorf("error executing query: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var ut models.UnitType
-		err := rows.Scan(&ut.UnitTypeID, &ut.Type)
+		var ingredient models.Ingredient
+		var category sql.NullString
+
+		err := rows.Scan(&ingredient.IngredientID, &ingredient.Name, &category)
 		if err != nil {
-			return unitTypes, fmt.Errorf("error scanning row: %w", err)
+			return ingredients, fmt.Errorf("error scanning row: %w", err)
 		}
-		unitTypes = append(unitTypes, ut)
+
+		if category.Valid {
+			ingredient.Category = category.String
+		}
+
+		ingredients = append(ingredients, ingredient)
 	}
 
 	if err := rows.Err(); err != nil {
-		return unitTypes, fmt.Errorf("error iterating rows: %w", err)
+		return ingredients, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	return unitTypes, nil
+	return ingredients, nil
+}
+
+func CreateIngredient(form types.IngredientForm) error {
+	query := `INSERT INTO ingredient (name, category) VALUES ($1, $2)`
+
+	_, err := DB.Exec(query, utils.CreateNullString(form.Name), utils.CreateNullString(form.Category))
+	if err != nil {
+		return fmt.Errorf("error inserting ingredient: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteIngredient(id int) error {
+	_, err := DB.Exec(`DELETE FROM ingredient WHERE ingredient_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting ingredient: %w", err)
+	}
+
+	return nil
+}
+
+func GetUnits() ([]models.Unit, error) {
+	var units []models.Unit
+
+	rows, err := DB.Query(`SELECT unit_id, name, abbreviation FROM unit ORDER BY name`)
+	if err != nil {
+		return units, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var unit models.Unit
+		var abbreviation sql.NullString
+
+		err := rows.Scan(&unit.UnitID, &unit.Name, &abbreviation)
+		if err != nil {
+			return units, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if abbreviation.Valid {
+			unit.Abbreviation = abbreviation.String
+		}
+
+		units = append(units, unit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return units, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return units, nil
+}
+
+func CreateUnit(form types.UnitForm) error {
+	query := `INSERT INTO unit (name, abbreviation) VALUES ($1, $2)`
+
+	_, err := DB.Exec(query, utils.CreateNullString(form.Name), utils.CreateNullString(form.Abbreviation))
+	if err != nil {
+		return fmt.Errorf("error inserting unit: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteUnit(id int) error {
+	_, err := DB.Exec(`DELETE FROM unit WHERE unit_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting unit: %w", err)
+	}
+
+	return nil
+}
+
+func GetCocktailIngredients(cocktailId int) ([]types.CocktailIngredientList, error) {
+	var cocktailIngredients []types.CocktailIngredientList
+
+	query := `
+		SELECT i.ingredient_id, i.name, i.category, u.unit_id, COALESCE(NULLIF(u.abbreviation, ''), u.name), ci.amount::FLOAT
+		FROM cocktail_ingredient AS ci
+		JOIN ingredient AS i ON i.ingredient_id = ci.ingredient_id
+		JOIN unit AS u ON u.unit_id = ci.unit_id
+		WHERE ci.cocktail_id = $1
+		ORDER BY ci.amount DESC, i.name
+	`
+
+	rows, err := DB.Query(query, cocktailId)
+	if err != nil {
+		return cocktailIngredients, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cocktailIngredient types.CocktailIngredientList
+		var category sql.NullString
+
+		err := rows.Scan(
+			&cocktailIngredient.IngredientID,
+			&cocktailIngredient.Ingredient,
+			&category,
+			&cocktailIngredient.UnitID,
+			&cocktailIngredient.Unit,
+			&cocktailIngredient.Amount,
+		)
+		if err != nil {
+			return cocktailIngredients, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if category.Valid {
+			cocktailIngredient.Category = category.String
+		}
+
+		cocktailIngredients = append(cocktailIngredients, cocktailIngredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return cocktailIngredients, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return cocktailIngredients, nil
+}
+
+// SetCocktailIngredient adds an ingredient line to a recipe, or replaces the amount when the ingredient is already in it.
+func SetCocktailIngredient(form types.CocktailIngredientForm) error {
+	query := `
+		INSERT INTO cocktail_ingredient (cocktail_id, ingredient_id, unit_id, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cocktail_id, ingredient_id) DO UPDATE
+		SET unit_id = EXCLUDED.unit_id, amount = EXCLUDED.amount
+	`
+
+	_, err := DB.Exec(
+		query,
+		utils.CreateNullInt(form.CocktailID),
+		utils.CreateNullInt(form.IngredientID),
+		utils.CreateNullInt(form.UnitID),
+		utils.CreateNullFloat64(form.Amount),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving cocktail ingredient: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteCocktailIngredient(cocktailId, ingredientId int) error {
+	_, err := DB.Exec(`DELETE FROM cocktail_ingredient WHERE cocktail_id = $1 AND ingredient_id = $2`, cocktailId, ingredientId)
+	if err != nil {
+		return fmt.Errorf("error deleting cocktail ingredient: %w", err)
+	}
+
+	return nil
+}
+
+func GetUnitTypes() ([]models.UnitType, error) {
+	var unitTypes []models.UnitType
+
+	rows, err := DB.Query(`SELECT unit_type_id, type FROM "unit_type"`)
+	if err != nil {
+		return unitTypes, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ut models.UnitType
+		err := rows.Scan(&ut.UnitTypeID, &ut.Type)
+		if err != nil {
+			return unitTypes, fmt.Errorf("error scanning row: %w", err)
+		}
+		unitTypes = append(unitTypes, ut)
+	}
+
+	if err := rows.Err(); err != nil {
+		return unitTypes, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return unitTypes, nil
+}
+
+// GetEventProfitability falls back to the lead's latest paid quote for events created before event.quote_id existed.
+func GetEventProfitability(start, end int64) ([]types.EventProfitability, error) {
+	var events []types.EventProfitability
+
+	query := `WITH event_quote AS (
+		SELECT e.event_id,
+			COALESCE(e.quote_id, (
+				SELECT i.quote_id
+				FROM invoice AS i
+				JOIN quote AS q ON q.quote_id = i.quote_id
+				WHERE q.lead_id = e.lead_id AND i.invoice_status_id = $3
+				ORDER BY i.date_paid DESC NULLS LAST
+				LIMIT 1
+			)) AS quote_id,
+			COALESCE(e.start_time, e.date_created) AS event_date
+		FROM event AS e
+	)
+	SELECT e.event_id,
+		e.lead_id,
+		l.full_name,
+		eq.event_date,
+		COALESCE((
+			SELECT s.service
+			FROM quote_service AS qs
+			JOIN service AS s ON s.service_id = qs.service_id
+			WHERE qs.quote_id = eq.quote_id AND s.service_type_id = $4
+			ORDER BY qs.units * qs.price_per_unit::NUMERIC DESC
+			LIMIT 1
+		), 'No Package'),
+		COALESCE(NULLIF(lm.source, ''), 'Unknown'),
+		CASE WHEN eq.quote_id IS NULL THEN COALESCE(e.amount::NUMERIC, 0)
+		ELSE COALESCE((SELECT SUM(qs.units * qs.price_per_unit::NUMERIC) FROM quote_service AS qs WHERE qs.quote_id = eq.quote_id), 0)
+		END,
+		CASE WHEN eq.quote_id IS NULL THEN (CASE WHEN e.date_paid IS NOT NULL THEN COALESCE(e.amount::NUMERIC, 0) ELSE 0 END)
+		ELSE COALESCE((SELECT SUM(qs.units * qs.price_per_unit::NUMERIC) FROM quote_service AS qs WHERE qs.quote_id = eq.quote_id), 0) * COALESCE((
+			SELECT SUM(it.amount_percentage)
+			FROM invoice AS i
+			JOIN invoice_type AS it ON it.invoice_type_id = i.invoice_type_id
+			WHERE i.quote_id = eq.quote_id AND i.invoice_status_id = $3
+		), 0)
+		END,
+		COALESCE((
+			SELECT SUM(EXTRACT(EPOCH FROM (e.end_time - e.start_time)) / 3600 * COALESCE(es.hourly_rate::NUMERIC, 0))
+			FROM event_staff AS es
+			WHERE es.event_id = e.event_id AND es.event_staff_status_id != $5
+		), 0),
+		COALESCE((
+			SELECT SUM(qs.units * s.cost_basis::NUMERIC)
+			FROM quote_service AS qs
+			JOIN service AS s ON s.service_id = qs.service_id
+			WHERE qs.quote_id = eq.quote_id
+		), 0)
+	FROM event AS e
+	JOIN event_quote AS eq ON eq.event_id = e.event_id
+	JOIN lead AS l ON l.lead_id = e.lead_id
+	LEFT JOIN lead_marketing AS lm ON lm.lead_id = e.lead_id
+	WHERE eq.event_date >= to_timestamp($1)::timestamptz AT TIME ZONE 'America/New_York'
+	AND eq.event_date < to_timestamp($2)::timestamptz AT TIME ZONE 'America/New_York'
+	ORDER BY eq.event_date ASC;`
+
+	rows, err := DB.Query(query, start, end, constants.PaidInvoiceStatusID, constants.BartendingServiceTypeID, constants.DeclinedEventStaffStatusID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event types.EventProfitability
+		var eventDate sql.NullTime
+
+		if err := rows.Scan(
+			&event.EventID,
+			&event.LeadID,
+			&event.FullName,
+			&eventDate,
+			&event.PackageType,
+			&event.LeadSource,
+			&event.QuotedRevenue,
+			&event.AmountPaid,
+			&event.LaborCost,
+			&event.ServiceCost,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if eventDate.Valid {
+			event.EventDate = utils.FormatTimestampWithOptions(eventDate.Time.Unix(), nil)
+			event.Month = eventDate.Time.Format("January 2006")
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+type marketingGroup struct {
+	leadKey    string
+	leadLabel  string
+	spendKey   string
+	spendLabel string
+}
+
+// Spend joins on the same IDs stored in lead_marketing so renamed campaigns still line up.
+var marketingGroups = map[string]marketingGroup{
+	"source":   {"LOWER(NULLIF(lm.source, ''))", "lm.source", "LOWER(NULLIF(a.source, ''))", "a.source"},
+	"campaign": {"lm.campaign_id::TEXT", "lm.ad_campaign", "a.campaign_id::TEXT", "a.campaign_name"},
+	"ad_set":   {"NULLIF(lm.ad_set_id, 0)::TEXT", "lm.ad_set_name", "NULLIF(a.ad_set_id, 0)::TEXT", "a.ad_set_name"},
+	"keyword":  {"NULLIF(lm.keyword, '')", "lm.keyword", "NULL::TEXT", "NULL::TEXT"},
+}
+
+func GetMarketingAttribution(start, end int64, groupBy string) ([]types.MarketingAttribution, error) {
+	var report []types.MarketingAttribution
+
+	group, ok := marketingGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid marketing group: %s", groupBy)
+	}
+
+	query := fmt.Sprintf(`WITH funnel AS (
+		SELECT %s AS group_key,
+			MAX(NULLIF(%s, '')) AS label,
+			COUNT(*) AS leads,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM quote AS q WHERE q.lead_id = l.lead_id
+			)) AS quoted,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1
+				FROM invoice AS i
+				JOIN quote AS q ON q.quote_id = i.quote_id
+				WHERE q.lead_id = l.lead_id AND i.invoice_status_id = $3 AND i.invoice_type_id IN ($4, $5)
+			)) AS deposit_paid,
+			COUNT(*) FILTER (WHERE ev.completed) AS events_completed,
+			COALESCE(SUM(ev.revenue), 0) AS revenue
+		FROM lead AS l
+		LEFT JOIN lead_marketing AS lm ON lm.lead_id = l.lead_id
+		LEFT JOIN LATERAL (
+			SELECT SUM(e.amount::NUMERIC) AS revenue,
+				BOOL_OR(e.end_time < (NOW() AT TIME ZONE 'America/New_York')) AS completed
+			FROM event AS e
+			WHERE e.lead_id = l.lead_id
+		) AS ev ON TRUE
+		WHERE l.created_at >= to_timestamp($1)::timestamptz AT TIME ZONE 'America/New_York'
+		AND l.created_at < to_timestamp($2)::timestamptz AT TIME ZONE 'America/New_York'
+		GROUP BY group_key
+	),
+	spend AS (
+		SELECT %s AS group_key,
+			MAX(NULLIF(%s, '')) AS label,
+			SUM(a.spend::NUMERIC) AS spend
+		FROM ad_spend AS a
+		WHERE %s IS NOT NULL
+		AND a.date >= (to_timestamp($1)::timestamptz AT TIME ZONE 'America/New_York')::DATE
+		AND a.date < (to_timestamp($2)::timestamptz AT TIME ZONE 'America/New_York')::DATE
+		GROUP BY group_key
+	)
+	SELECT COALESCE(f.label, s.label, f.group_key, s.group_key, 'Unknown'),
+		COALESCE(f.leads, 0),
+		COALESCE(f.quoted, 0),
+		COALESCE(f.deposit_paid, 0),
+		COALESCE(f.events_completed, 0),
+		COALESCE(f.revenue, 0),
+		COALESCE(s.spend, 0)
+	FROM funnel AS f
+	FULL OUTER JOIN spend AS s ON s.group_key = f.group_key
+	ORDER BY COALESCE(f.leads, 0) DESC, COALESCE(s.spend, 0) DESC;`, group.leadKey, group.leadLabel, group.spendKey, group.spendLabel, group.spendKey)
+
+	rows, err := DB.Query(query, start, end, constants.PaidInvoiceStatusID, constants.DepositInvoiceTypeID, constants.FullInvoiceTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row types.MarketingAttribution
+
+		if err := rows.Scan(
+			&row.Label,
+			&row.Leads,
+			&row.Quoted,
+			&row.DepositPaid,
+			&row.EventsCompleted,
+			&row.Revenue,
+			&row.Spend,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		report = append(report, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return report, nil
+}
+
+// ImportAdSpend replaces spend for any date, campaign and ad set already imported so re-running an import is safe.
+func ImportAdSpend(rows []models.AdSpend) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO ad_spend (date, source, campaign_id, campaign_name, ad_set_id, ad_set_name, spend, date_imported)
+		VALUES (
+			(to_timestamp($1)::timestamptz AT TIME ZONE 'America/New_York')::DATE,
+			$2, $3, $4, $5, $6, $7,
+			(NOW() AT TIME ZONE 'America/New_York')
+		)
+		ON CONFLICT (date, campaign_id, ad_set_id) DO UPDATE SET
+			source = EXCLUDED.source,
+			campaign_name = EXCLUDED.campaign_name,
+			ad_set_name = EXCLUDED.ad_set_name,
+			spend = EXCLUDED.spend,
+			date_imported = EXCLUDED.date_imported
+	`)
+	if err != nil {
+		return fmt.Errorf("error preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		_, err = stmt.Exec(
+			row.Date,
+			utils.CreateNullString(&row.Source),
+			row.CampaignID,
+			utils.CreateNullString(&row.CampaignName),
+			row.AdSetID,
+			utils.CreateNullString(&row.AdSetName),
+			row.Spend,
+		)
+		if err != nil {
+			return fmt.Errorf("error executing statement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func EnqueueJob(job models.Job) error {
+	query := `
+		INSERT INTO job (job_type, payload, status, max_attempts, run_at, date_created)
+		VALUES ($1, $2, $3, $4, to_timestamp($5)::timestamptz AT TIME ZONE 'America/New_York', (NOW() AT TIME ZONE 'America/New_York'))
+	`
+
+	_, err := DB.Exec(query, job.JobType, job.Payload, constants.PendingJobStatus, job.MaxAttempts, job.RunAt)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func ScheduleRecurringJob(jobType string, interval, maxAttempts int) error {
+	query := `
+		INSERT INTO job (job_type, status, max_attempts, recurring_interval, run_at, date_created)
+		VALUES ($1, $2, $3, $4, (NOW() AT TIME ZONE 'America/New_York'), (NOW() AT TIME ZONE 'America/New_York'))
+		ON CONFLICT (job_type) WHERE recurring_interval IS NOT NULL DO UPDATE SET
+			max_attempts = EXCLUDED.max_attempts,
+			recurring_interval = EXCLUDED.recurring_interval
+	`
+
+	_, err := DB.Exec(query, jobType, constants.PendingJobStatus, maxAttempts, interval)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimNextJob locks the next due job for workerID and returns nil when there is nothing to run.
+// Jobs left running for over 30 minutes are assumed to belong to a dead worker and are claimed again.
+func ClaimNextJob(workerID string) (*models.Job, error) {
+	query := `
+		WITH next_job AS (
+			SELECT job_id
+			FROM job
+			WHERE (status = $2 AND run_at <= (NOW() AT TIME ZONE 'America/New_York'))
+			OR (status = $3 AND locked_at <= (NOW() AT TIME ZONE 'America/New_York') - INTERVAL '30 minutes')
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE job AS j
+		SET status = $3,
+			attempts = j.attempts + 1,
+			locked_by = $1,
+			locked_at = (NOW() AT TIME ZONE 'America/New_York')
+		FROM next_job
+		WHERE j.job_id = next_job.job_id
+		RETURNING j.job_id, j.job_type, j.payload, j.attempts, j.max_attempts, j.recurring_interval
+	`
+
+	var job models.Job
+	var recurringInterval sql.NullInt64
+
+	err := DB.QueryRow(query, workerID, constants.PendingJobStatus, constants.RunningJobStatus).Scan(
+		&job.JobID,
+		&job.JobType,
+		&job.Payload,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&recurringInterval,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+
+	job.Status = constants.RunningJobStatus
+	job.LockedBy = workerID
+	job.RecurringInterval = int(recurringInterval.Int64)
+
+	return &job, nil
+}
+
+// CompleteJob finishes a one-off job or puts a recurring job back in the queue for its next run.
+func CompleteJob(jobID int) error {
+	query := `
+		UPDATE job
+		SET status = CASE WHEN recurring_interval IS NULL THEN $2 ELSE $3 END,
+			run_at = CASE WHEN recurring_interval IS NULL THEN run_at
+				ELSE (NOW() AT TIME ZONE 'America/New_York') + make_interval(secs => recurring_interval) END,
+			attempts = CASE WHEN recurring_interval IS NULL THEN attempts ELSE 0 END,
+			last_error = NULL,
+			locked_by = NULL,
+			locked_at = NULL,
+			date_completed = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE job_id = $1
+	`
+
+	_, err := DB.Exec(query, jobID, constants.CompletedJobStatus, constants.PendingJobStatus)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func RescheduleJob(jobID int, lastError string, runAt int64) error {
+	query := `
+		UPDATE job
+		SET status = $2,
+			run_at = to_timestamp($3)::timestamptz AT TIME ZONE 'America/New_York',
+			last_error = $4,
+			locked_by = NULL,
+			locked_at = NULL
+		WHERE job_id = $1
+	`
+
+	_, err := DB.Exec(query, jobID, constants.PendingJobStatus, runAt, lastError)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+// DeadLetterJob copies a job that ran out of attempts into job_dead_letter. One-off jobs are marked
+// as dead while recurring jobs start over at their next interval.
+func DeadLetterJob(jobID int, lastError string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO job_dead_letter (job_id, job_type, payload, attempts, last_error, date_failed)
+		SELECT job_id, job_type, payload, attempts, $2, (NOW() AT TIME ZONE 'America/New_York')
+		FROM job
+		WHERE job_id = $1
+	`, jobID, lastError)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE job
+		SET status = CASE WHEN recurring_interval IS NULL THEN $2 ELSE $3 END,
+			run_at = CASE WHEN recurring_interval IS NULL THEN run_at
+				ELSE (NOW() AT TIME ZONE 'America/New_York') + make_interval(secs => recurring_interval) END,
+			attempts = CASE WHEN recurring_interval IS NULL THEN attempts ELSE 0 END,
+			last_error = $4,
+			locked_by = NULL,
+			locked_at = NULL
+		WHERE job_id = $1
+	`, jobID, constants.DeadJobStatus, constants.PendingJobStatus, lastError)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func GetJobList(status string, pageNum int) ([]types.JobList, int, error) {
+	var jobs []types.JobList
+	var totalRows int
+
+	offset := (pageNum - 1) * int(constants.LeadsPerPage)
+
+	rows, err := DB.Query(`SELECT j.job_id, j.job_type, j.status, j.attempts, j.max_attempts, j.recurring_interval,
+			j.run_at, j.last_error, j.locked_by, j.date_completed, COUNT(*) OVER() AS total_rows
+			FROM job AS j
+			WHERE $1 = '' OR j.status = $1
+			ORDER BY j.run_at DESC
+			OFFSET $2
+			LIMIT $3`, status, offset, constants.LeadsPerPage)
+	if err != nil {
+		return jobs, totalRows, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job types.JobList
+		var recurringInterval sql.NullInt64
+		var runAt, dateCompleted sql.NullTime
+		var lastError, lockedBy sql.NullString
+
+		err := rows.Scan(&job.JobID, &job.JobType, &job.Status, &job.Attempts, &job.MaxAttempts, &recurringInterval,
+			&runAt, &lastError, &lockedBy, &dateCompleted, &totalRows)
+		if err != nil {
+			return jobs, totalRows, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		job.RecurringInterval = int(recurringInterval.Int64)
+		job.LastError = lastError.String
+		job.LockedBy = lockedBy.String
+
+		if runAt.Valid {
+			job.RunAt = utils.FormatTimestampWithOptions(runAt.Time.Unix(), nil)
+		}
+
+		if dateCompleted.Valid {
+			job.DateCompleted = utils.FormatTimestampWithOptions(dateCompleted.Time.Unix(), nil)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return jobs, totalRows, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return jobs, totalRows, nil
+}
+
+func GetJobDeadLetters() ([]types.JobDeadLetterList, error) {
+	var deadLetters []types.JobDeadLetterList
+
+	rows, err := DB.Query(`SELECT d.job_dead_letter_id, d.job_id, d.job_type, d.payload, d.attempts, d.last_error, d.date_failed
+			FROM job_dead_letter AS d
+			WHERE d.date_requeued IS NULL
+			ORDER BY d.date_failed DESC
+			LIMIT 50`)
+	if err != nil {
+		return deadLetters, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deadLetter types.JobDeadLetterList
+		var jobID sql.NullInt64
+		var lastError sql.NullString
+		var dateFailed time.Time
+
+		err := rows.Scan(&deadLetter.JobDeadLetterID, &jobID, &deadLetter.JobType, &deadLetter.Payload, &deadLetter.Attempts, &lastError, &dateFailed)
+		if err != nil {
+			return deadLetters, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		deadLetter.JobID = int(jobID.Int64)
+		deadLetter.LastError = lastError.String
+		deadLetter.DateFailed = utils.FormatTimestampWithOptions(dateFailed.Unix(), nil)
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return deadLetters, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// RequeueJobDeadLetter enqueues a fresh one-off copy of the failed job to run immediately.
+func RequeueJobDeadLetter(jobDeadLetterID int) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO job (job_type, payload, status, max_attempts, run_at, date_created)
+		SELECT job_type, payload, $2, $3, (NOW() AT TIME ZONE 'America/New_York'), (NOW() AT TIME ZONE 'America/New_York')
+		FROM job_dead_letter
+		WHERE job_dead_letter_id = $1 AND date_requeued IS NULL
+	`, jobDeadLetterID, constants.PendingJobStatus, constants.DefaultJobMaxAttempts)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("dead letter not found or already requeued")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE job_dead_letter
+		SET date_requeued = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE job_dead_letter_id = $1
+	`, jobDeadLetterID)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func RunJobNow(jobID int) error {
+	query := `
+		UPDATE job
+		SET status = $2,
+			run_at = (NOW() AT TIME ZONE 'America/New_York'),
+			attempts = 0
+		WHERE job_id = $1 AND status IN ($2, $3)
+	`
+
+	result, err := DB.Exec(query, jobID, constants.PendingJobStatus, constants.DeadJobStatus)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("only pending or dead jobs can be run")
+	}
+
+	return nil
+}
+
+func GetSMSSequences() ([]types.SMSSequenceDetails, error) {
+	var sequences []types.SMSSequenceDetails
+
+	rows, err := DB.Query(`
+		SELECT s.sms_sequence_id, s.name, s.is_active, s.archive_on_completion,
+		(SELECT COUNT(*) FROM sms_sequence_enrollment AS e WHERE e.sms_sequence_id = s.sms_sequence_id AND e.status = $1) AS active_enrollments
+		FROM sms_sequence AS s
+		ORDER BY s.sms_sequence_id
+	`, constants.ActiveSMSSequenceEnrollmentStatus)
+	if err != nil {
+		return sequences, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sequence types.SMSSequenceDetails
+
+		err := rows.Scan(&sequence.SMSSequenceID, &sequence.Name, &sequence.IsActive, &sequence.ArchiveOnCompletion, &sequence.ActiveEnrollments)
+		if err != nil {
+			return sequences, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		sequences = append(sequences, sequence)
+	}
+
+	if err := rows.Err(); err != nil {
+		return sequences, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	stepRows, err := DB.Query(`
+		SELECT sms_sequence_step_id, sms_sequence_id, step_number, delay_days, body, is_ai_generated
+		FROM sms_sequence_step
+		ORDER BY sms_sequence_id, step_number
+	`)
+	if err != nil {
+		return sequences, fmt.Errorf("error executing query: %w", err)
+	}
+	defer stepRows.Close()
+
+	for stepRows.Next() {
+		var step types.SMSSequenceStepList
+		var sequenceID int
+
+		err := stepRows.Scan(&step.SMSSequenceStepID, &sequenceID, &step.StepNumber, &step.DelayDays, &step.Body, &step.IsAIGenerated)
+		if err != nil {
+			return sequences, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		for i := range sequences {
+			if sequences[i].SMSSequenceID == sequenceID {
+				sequences[i].Steps = append(sequences[i].Steps, step)
+			}
+		}
+	}
+
+	if err := stepRows.Err(); err != nil {
+		return sequences, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return sequences, nil
+}
+
+func CreateSMSSequence(form types.SMSSequenceForm) error {
+	query := `
+		INSERT INTO sms_sequence (name, is_active, archive_on_completion, date_created)
+		VALUES ($1, $2, $3, (NOW() AT TIME ZONE 'America/New_York'))
+	`
+
+	_, err := DB.Exec(query, utils.CreateNullString(form.Name), utils.CreateNullBoolDefaultFalse(form.IsActive), utils.CreateNullBoolDefaultFalse(form.ArchiveOnCompletion))
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func UpdateSMSSequence(form types.SMSSequenceForm) error {
+	query := `
+		UPDATE sms_sequence
+		SET name = COALESCE($2, name),
+			is_active = COALESCE($3, is_active),
+			archive_on_completion = COALESCE($4, archive_on_completion)
+		WHERE sms_sequence_id = $1
+	`
+
+	_, err := DB.Exec(
+		query,
+		utils.CreateNullInt(form.SMSSequenceID),
+		utils.CreateNullString(form.Name),
+		utils.CreateNullBool(form.IsActive),
+		utils.CreateNullBool(form.ArchiveOnCompletion),
+	)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteSMSSequence(id int) error {
+	_, err := DB.Exec(`DELETE FROM sms_sequence WHERE sms_sequence_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func CreateSMSSequenceStep(form types.SMSSequenceStepForm) error {
+	query := `
+		INSERT INTO sms_sequence_step (sms_sequence_id, step_number, delay_days, body, is_ai_generated)
+		SELECT $1, COALESCE(MAX(step_number), 0) + 1, $2, $3, $4
+		FROM sms_sequence_step
+		WHERE sms_sequence_id = $1
+	`
+
+	_, err := DB.Exec(
+		query,
+		utils.CreateNullInt(form.SMSSequenceID),
+		utils.CreateNullInt(form.DelayDays),
+		utils.CreateNullString(form.Body),
+		utils.CreateNullBoolDefaultFalse(form.IsAIGenerated),
+	)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteSMSSequenceStep(id int) error {
+	_, err := DB.Exec(`DELETE FROM sms_sequence_step WHERE sms_sequence_step_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueSMSSequenceSteps returns the next unsent step for every active enrollment once
+// that step's delay, counted in days from enrollment, has passed.
+func GetDueSMSSequenceSteps() ([]types.DueSMSSequenceStep, error) {
+	var steps []types.DueSMSSequenceStep
+
+	query := `
+		SELECT e.sms_sequence_enrollment_id, e.lead_id, l.full_name, l.phone_number, COALESCE(l.message, ''),
+		st.step_number, st.body, st.is_ai_generated,
+		NOT EXISTS (
+			SELECT 1 FROM sms_sequence_step AS ns
+			WHERE ns.sms_sequence_id = e.sms_sequence_id AND ns.step_number > st.step_number
+		) AS is_last_step,
+		s.archive_on_completion,
+		EXISTS (SELECT 1 FROM event AS ev WHERE ev.lead_id = e.lead_id) AS is_booked
+		FROM sms_sequence_enrollment AS e
+		JOIN sms_sequence AS s ON s.sms_sequence_id = e.sms_sequence_id
+		JOIN lead AS l ON l.lead_id = e.lead_id
+		JOIN LATERAL (
+			SELECT step_number, delay_days, body, is_ai_generated
+			FROM sms_sequence_step
+			WHERE sms_sequence_id = e.sms_sequence_id AND step_number > e.last_step_number
+			ORDER BY step_number
+			LIMIT 1
+		) AS st ON true
+		WHERE e.status = $1 AND s.is_active
+		-- Each step waits from the previous send, the first one from enrollment
+		AND COALESCE(e.date_last_sent, e.date_enrolled) + make_interval(days => st.delay_days) <= (NOW() AT TIME ZONE 'America/New_York')
+		ORDER BY e.date_enrolled
+	`
+
+	rows, err := DB.Query(query, constants.ActiveSMSSequenceEnrollmentStatus)
+	if err != nil {
+		return steps, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var step types.DueSMSSequenceStep
+		var fullName, phoneNumber sql.NullString
+
+		err := rows.Scan(
+			&step.SMSSequenceEnrollmentID,
+			&step.LeadID,
+			&fullName,
+			&phoneNumber,
+			&step.Message,
+			&step.StepNumber,
+			&step.Body,
+			&step.IsAIGenerated,
+			&step.IsLastStep,
+			&step.ArchiveOnCompletion,
+			&step.IsBooked,
+		)
+		if err != nil {
+			return steps, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		step.FullName = fullName.String
+		step.PhoneNumber = phoneNumber.String
+
+		steps = append(steps, step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return steps, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return steps, nil
+}
+
+func MarkSMSSequenceStepSent(enrollmentID, stepNumber int, isLastStep bool) error {
+	query := `
+		UPDATE sms_sequence_enrollment
+		SET last_step_number = $2,
+			status = CASE WHEN $3 THEN $4 ELSE status END,
+			date_last_sent = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE sms_sequence_enrollment_id = $1
+	`
+
+	_, err := DB.Exec(query, enrollmentID, stepNumber, isLastStep, constants.CompletedSMSSequenceEnrollmentStatus)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func StopSMSSequenceEnrollments(leadID int, reason string) error {
+	query := `
+		UPDATE sms_sequence_enrollment
+		SET status = $2,
+			stop_reason = $3,
+			date_stopped = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE lead_id = $1 AND status = $4
+	`
+
+	_, err := DB.Exec(query, leadID, constants.StoppedSMSSequenceEnrollmentStatus, reason, constants.ActiveSMSSequenceEnrollmentStatus)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func CreateSMSConsentEvent(event models.SMSConsentEvent) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var leadId *int
+	if event.LeadID > 0 {
+		leadId = &event.LeadID
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO sms_consent_event (phone_number, lead_id, is_opted_in, source, keyword, message, external_id, date_created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, to_timestamp($8)::timestamptz AT TIME ZONE 'America/New_York')
+	`,
+		event.PhoneNumber,
+		utils.CreateNullInt(leadId),
+		event.IsOptedIn,
+		event.Source,
+		utils.CreateNullString(&event.Keyword),
+		utils.CreateNullString(&event.Message),
+		utils.CreateNullString(&event.ExternalID),
+		event.DateCreated,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting sms consent event: %w", err)
+	}
+
+	// Keep the lead's flag in line with the latest consent so the CRM shows it
+	_, err = tx.Exec(`UPDATE lead SET opt_in_text_messaging = $2 WHERE phone_number = $1`, event.PhoneNumber, event.IsOptedIn)
+	if err != nil {
+		return fmt.Errorf("error updating lead opt in: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func IsPhoneNumberOptedOut(phoneNumber string) (bool, error) {
+	var isOptedOut bool
+
+	query := `
+		SELECT COALESCE((
+			SELECT NOT is_opted_in
+			FROM sms_consent_event
+			WHERE phone_number = $1
+			ORDER BY date_created DESC, sms_consent_event_id DESC
+			LIMIT 1
+		), false)
+	`
+
+	err := DB.QueryRow(query, phoneNumber).Scan(&isOptedOut)
+	if err != nil {
+		return isOptedOut, fmt.Errorf("error scanning row: %w", err)
+	}
+
+	return isOptedOut, nil
+}
+
+func GetSMSConsentEvents(phoneNumber string) ([]types.SMSConsentEventList, error) {
+	var events []types.SMSConsentEventList
+
+	rows, err := DB.Query(`
+		SELECT sms_consent_event_id, is_opted_in, source, COALESCE(keyword, ''), COALESCE(message, ''), date_created
+		FROM sms_consent_event
+		WHERE phone_number = $1
+		ORDER BY date_created DESC, sms_consent_event_id DESC
+	`, phoneNumber)
+	if err != nil {
+		return events, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event types.SMSConsentEventList
+		var dateCreated time.Time
+
+		err := rows.Scan(&event.SMSConsentEventID, &event.IsOptedIn, &event.Source, &event.Keyword, &event.Message, &dateCreated)
+		if err != nil {
+			return events, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		event.DateCreated = utils.FormatTimestampWithOptions(dateCreated.Unix(), nil)
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return events, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func CreateScheduledMessage(msg models.ScheduledMessage) (int, error) {
+	var scheduledMessageId int
+
+	var leadId, userId *int
+	if msg.LeadID > 0 {
+		leadId = &msg.LeadID
+	}
+	if msg.UserID > 0 {
+		userId = &msg.UserID
+	}
+
+	query := `
+		INSERT INTO scheduled_message (lead_id, user_id, text_from, text_to, body, send_at, status, date_created)
+		VALUES ($1, $2, $3, $4, $5, to_timestamp($6)::timestamptz AT TIME ZONE 'America/New_York', $7, to_timestamp($8)::timestamptz AT TIME ZONE 'America/New_York')
+		RETURNING scheduled_message_id
+	`
+
+	err := DB.QueryRow(query,
+		utils.CreateNullInt(leadId),
+		utils.CreateNullInt(userId),
+		msg.TextFrom,
+		msg.TextTo,
+		msg.Body,
+		msg.SendAt,
+		constants.PendingScheduledMessageStatus,
+		msg.DateCreated,
+	).Scan(&scheduledMessageId)
+	if err != nil {
+		return scheduledMessageId, fmt.Errorf("error inserting scheduled message: %w", err)
+	}
+
+	return scheduledMessageId, nil
+}
+
+func GetScheduledMessage(scheduledMessageId int) (models.ScheduledMessage, error) {
+	var msg models.ScheduledMessage
+	var leadId sql.NullInt64
+
+	query := `
+		SELECT scheduled_message_id, lead_id, text_from, text_to, body, status
+		FROM scheduled_message
+		WHERE scheduled_message_id = $1
+	`
+
+	err := DB.QueryRow(query, scheduledMessageId).Scan(&msg.ScheduledMessageID, &leadId, &msg.TextFrom, &msg.TextTo, &msg.Body, &msg.Status)
+	if err != nil {
+		return msg, fmt.Errorf("error scanning row: %w", err)
+	}
+
+	if leadId.Valid {
+		msg.LeadID = int(leadId.Int64)
+	}
+
+	return msg, nil
+}
+
+func GetScheduledMessagesByLeadID(leadId int) ([]types.ScheduledMessageList, error) {
+	var messages []types.ScheduledMessageList
+
+	rows, err := DB.Query(`
+		SELECT sm.scheduled_message_id, sm.lead_id, sm.body, sm.send_at, sm.status, COALESCE(sm.last_error, ''),
+		COALESCE(u.first_name || ' ' || u.last_name, '')
+		FROM scheduled_message AS sm
+		LEFT JOIN "user" AS u ON u.user_id = sm.user_id
+		WHERE sm.lead_id = $1 AND sm.status IN ($2, $3)
+		ORDER BY sm.send_at
+	`, leadId, constants.PendingScheduledMessageStatus, constants.FailedScheduledMessageStatus)
+	if err != nil {
+		return messages, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var msg types.ScheduledMessageList
+		var sendAt time.Time
+
+		err := rows.Scan(&msg.ScheduledMessageID, &msg.LeadID, &msg.Body, &sendAt, &msg.Status, &msg.LastError, &msg.UserName)
+		if err != nil {
+			return messages, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		msg.SendAt = utils.FormatTimestampWithOptions(sendAt.Unix(), nil)
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return messages, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return messages, nil
+}
+
+func MarkScheduledMessageSent(scheduledMessageId int, externalId string) error {
+	query := `
+		UPDATE scheduled_message
+		SET status = $2, external_id = $3, last_error = NULL, date_sent = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE scheduled_message_id = $1
+	`
+
+	_, err := DB.Exec(query, scheduledMessageId, constants.SentScheduledMessageStatus, externalId)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func MarkScheduledMessageFailed(scheduledMessageId int, lastError string) error {
+	query := `
+		UPDATE scheduled_message
+		SET status = $2, last_error = $3
+		WHERE scheduled_message_id = $1
+	`
+
+	_, err := DB.Exec(query, scheduledMessageId, constants.FailedScheduledMessageStatus, lastError)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func CancelScheduledMessage(scheduledMessageId, leadId int) error {
+	query := `
+		UPDATE scheduled_message
+		SET status = $3
+		WHERE scheduled_message_id = $1 AND lead_id = $2 AND status IN ($4, $5)
+	`
+
+	result, err := DB.Exec(query, scheduledMessageId, leadId, constants.CancelledScheduledMessageStatus, constants.PendingScheduledMessageStatus, constants.FailedScheduledMessageStatus)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no pending scheduled message found with id %d", scheduledMessageId)
+	}
+
+	return nil
+}
+
+func isFailedMessageStatus(status string) bool {
+	return status == constants.FailedMessageStatus || status == constants.UndeliveredMessageStatus
+}
+
+func UpdateSMSStatus(externalId, status, errorCode, errorMessage string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = lockMessageExternalID(tx, externalId)
+	if err != nil {
+		return err
+	}
+
+	// Twilio callbacks can arrive out of order, so a final status is never replaced by an earlier one
+	_, err = tx.Exec(`
+		UPDATE message
+		SET status = $2, error_code = $3, error_message = $4, date_updated = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE external_id = $1 AND (status IS NULL OR status NOT IN ('delivered', 'undelivered', 'failed', 'read'))
+	`, externalId, status, utils.CreateNullString(&errorCode), utils.CreateNullString(&errorMessage))
+	if err != nil {
+		return fmt.Errorf("error updating message status: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM message WHERE external_id = $1)`, externalId).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error checking message: %w", err)
+	}
+
+	// The callback can beat SaveSMS, so the status is kept until the message is saved
+	if !exists {
+		_, err = tx.Exec(`
+			INSERT INTO pending_message_status (external_id, status, error_code, error_message, date_updated)
+			VALUES ($1, $2, $3, $4, (NOW() AT TIME ZONE 'America/New_York'))
+			ON CONFLICT (external_id) DO UPDATE
+			SET status = EXCLUDED.status, error_code = EXCLUDED.error_code, error_message = EXCLUDED.error_message, date_updated = EXCLUDED.date_updated
+			WHERE pending_message_status.status NOT IN ('delivered', 'undelivered', 'failed', 'read')
+		`, externalId, status, utils.CreateNullString(&errorCode), utils.CreateNullString(&errorMessage))
+		if err != nil {
+			return fmt.Errorf("error saving pending message status: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockMessageExternalID serializes SaveSMS and status callbacks for the same message SID.
+func lockMessageExternalID(tx *sql.Tx, externalId string) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, externalId)
+	if err != nil {
+		return fmt.Errorf("error locking message: %w", err)
+	}
+
+	return nil
+}
+
+func GetConversation(leadId int) (types.FrontendConversation, error) {
+	conversation := types.FrontendConversation{
+		LeadID: leadId,
+		Status: constants.OpenConversationStatus,
+	}
+
+	var assignedUserId sql.NullInt64
+	var assignedUserName sql.NullString
+	var status sql.NullString
+	var snoozedUntil sql.NullTime
+
+	err := DB.QueryRow(`
+		SELECT c.assigned_user_id, CONCAT(u.first_name, ' ', u.last_name), c.status, c.snoozed_until
+		FROM conversation AS c
+		LEFT JOIN "user" AS u ON u.user_id = c.assigned_user_id
+		WHERE c.lead_id = $1
+	`, leadId).Scan(&assignedUserId, &assignedUserName, &status, &snoozedUntil)
+	if err == sql.ErrNoRows {
+		return conversation, nil
+	}
+	if err != nil {
+		return conversation, fmt.Errorf("error getting conversation: %w", err)
+	}
+
+	if assignedUserId.Valid {
+		conversation.AssignedUserID = int(assignedUserId.Int64)
+		conversation.AssignedUserName = strings.TrimSpace(assignedUserName.String)
+	}
+
+	conversation.Status = status.String
+	if snoozedUntil.Valid {
+		conversation.SnoozedUntil = utils.FormatTimestampWithOptions(snoozedUntil.Time.Unix(), nil)
+
+		if status.String == constants.SnoozedConversationStatus && !snoozedUntil.Time.After(time.Now()) {
+			conversation.Status = constants.OpenConversationStatus
+		}
+	}
+
+	return conversation, nil
+}
+
+func UpdateConversation(leadId int, assignedUserId *int, status string, snoozedUntil *int64) error {
+	// date_assigned only moves when the owner changes so round-robin stays fair
+	_, err := DB.Exec(`
+		INSERT INTO conversation (lead_id, assigned_user_id, status, snoozed_until, date_assigned, date_created)
+		VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', CASE WHEN $2::INTEGER IS NULL THEN NULL ELSE (NOW() AT TIME ZONE 'America/New_York') END, (NOW() AT TIME ZONE 'America/New_York'))
+		ON CONFLICT (lead_id) DO UPDATE SET
+			assigned_user_id = EXCLUDED.assigned_user_id,
+			status = EXCLUDED.status,
+			snoozed_until = EXCLUDED.snoozed_until,
+			date_assigned = CASE
+				WHEN conversation.assigned_user_id IS DISTINCT FROM EXCLUDED.assigned_user_id THEN EXCLUDED.date_assigned
+				ELSE conversation.date_assigned
+			END
+	`, leadId, utils.CreateNullInt(assignedUserId), status, utils.CreateNullInt64(snoozedUntil))
+	if err != nil {
+		return fmt.Errorf("error updating conversation: %w", err)
+	}
+
+	return nil
+}
+
+func ReopenConversation(leadId int) error {
+	_, err := DB.Exec(`
+		INSERT INTO conversation (lead_id, status, date_created)
+		VALUES ($1, $2, (NOW() AT TIME ZONE 'America/New_York'))
+		ON CONFLICT (lead_id) DO UPDATE SET status = EXCLUDED.status, snoozed_until = NULL
+	`, leadId, constants.OpenConversationStatus)
+	if err != nil {
+		return fmt.Errorf("error reopening conversation: %w", err)
+	}
+
+	return nil
+}
+
+func CreateConversationNote(leadId int, note models.ConversationNote) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var conversationId int
+	err = tx.QueryRow(`
+		INSERT INTO conversation (lead_id, status, date_created)
+		VALUES ($1, $2, (NOW() AT TIME ZONE 'America/New_York'))
+		ON CONFLICT (lead_id) DO UPDATE SET lead_id = EXCLUDED.lead_id
+		RETURNING conversation_id
+	`, leadId, constants.OpenConversationStatus).Scan(&conversationId)
+	if err != nil {
+		return fmt.Errorf("error getting conversation: %w", err)
+	}
+
+	var userId *int
+	if note.UserID > 0 {
+		userId = &note.UserID
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO conversation_note (conversation_id, user_id, note, date_created)
+		VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York')
+	`, conversationId, utils.CreateNullInt(userId), note.Note, note.DateCreated)
+	if err != nil {
+		return fmt.Errorf("error inserting conversation note: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func newCallFlowBusinessHours() []types.CallFlowBusinessHoursList {
+	var hours []types.CallFlowBusinessHoursList
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours = append(hours, types.CallFlowBusinessHoursList{
+			DayOfWeek: int(day),
+			DayName:   day.String(),
+		})
+	}
+	return hours
+}
+
+func GetCallFlow() (types.CallFlowDetails, error) {
+	var flow types.CallFlowDetails
+	var greeting sql.NullString
+
+	err := DB.QueryRow(`
+		SELECT call_flow_id, greeting, has_language_menu, ring_strategy, ring_timeout, voicemail_greeting, after_hours_message
+		FROM call_flow
+		ORDER BY call_flow_id
+		LIMIT 1
+	`).Scan(&flow.CallFlowID, &greeting, &flow.HasLanguageMenu, &flow.RingStrategy, &flow.RingTimeout, &flow.VoicemailGreeting, &flow.AfterHoursMessage)
+	if err != nil {
+		return flow, fmt.Errorf("error getting call flow: %w", err)
+	}
+	flow.Greeting = greeting.String
+	flow.BusinessHours = newCallFlowBusinessHours()
+
+	rows, err := DB.Query(`
+		SELECT day_of_week, to_char(open_time, 'HH24:MI'), to_char(close_time, 'HH24:MI')
+		FROM call_flow_business_hours
+		WHERE call_flow_id = $1
+	`, flow.CallFlowID)
+	if err != nil {
+		return flow, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day int
+		var openTime, closeTime string
+
+		err := rows.Scan(&day, &openTime, &closeTime)
+		if err != nil {
+			return flow, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if day >= 0 && day < len(flow.BusinessHours) {
+			flow.BusinessHours[day].OpenTime = openTime
+			flow.BusinessHours[day].CloseTime = closeTime
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return flow, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	recipientRows, err := DB.Query(`
+		SELECT r.call_flow_recipient_id, r.user_id, CONCAT(u.first_name, ' ', u.last_name), u.forward_phone_number, COALESCE(r.language, ''), r.ring_order
+		FROM call_flow_recipient AS r
+		JOIN "user" AS u ON u.user_id = r.user_id
+		WHERE r.call_flow_id = $1
+		ORDER BY r.ring_order, r.call_flow_recipient_id
+	`, flow.CallFlowID)
+	if err != nil {
+		return flow, fmt.Errorf("error executing query: %w", err)
+	}
+	defer recipientRows.Close()
+
+	for recipientRows.Next() {
+		var recipient types.CallFlowRecipientList
+		var phoneNumber sql.NullString
+
+		err := recipientRows.Scan(&recipient.CallFlowRecipientID, &recipient.UserID, &recipient.UserName, &phoneNumber, &recipient.Language, &recipient.RingOrder)
+		if err != nil {
+			return flow, fmt.Errorf("error scanning row: %w", err)
+		}
+		recipient.PhoneNumber = phoneNumber.String
+
+		flow.Recipients = append(flow.Recipients, recipient)
+	}
+
+	if err := recipientRows.Err(); err != nil {
+		return flow, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return flow, nil
+}
+
+func UpdateCallFlow(form types.CallFlowForm) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE call_flow
+		SET greeting = $2,
+			has_language_menu = $3,
+			ring_strategy = $4,
+			ring_timeout = $5,
+			voicemail_greeting = $6,
+			after_hours_message = $7,
+			date_updated = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE call_flow_id = $1
+	`,
+		utils.CreateNullInt(form.CallFlowID),
+		utils.CreateNullString(form.Greeting),
+		utils.CreateNullBoolDefaultFalse(form.HasLanguageMenu),
+		utils.CreateNullString(form.RingStrategy),
+		utils.CreateNullInt(form.RingTimeout),
+		utils.CreateNullString(form.VoicemailGreeting),
+		utils.CreateNullString(form.AfterHoursMessage),
+	)
+	if err != nil {
+		return fmt.Errorf("error updating call flow: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM call_flow_business_hours WHERE call_flow_id = $1`, utils.CreateNullInt(form.CallFlowID))
+	if err != nil {
+		return fmt.Errorf("error clearing business hours: %w", err)
+	}
+
+	for day, hours := range form.BusinessHours {
+		if hours.OpenTime == nil || hours.CloseTime == nil {
+			continue
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO call_flow_business_hours (call_flow_id, day_of_week, open_time, close_time)
+			VALUES ($1, $2, $3::time, $4::time)
+		`, utils.CreateNullInt(form.CallFlowID), day, *hours.OpenTime, *hours.CloseTime)
+		if err != nil {
+			return fmt.Errorf("error inserting business hours: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func CreateCallFlowRecipient(form types.CallFlowRecipientForm) error {
+	query := `
+		INSERT INTO call_flow_recipient (call_flow_id, user_id, language, ring_order)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (call_flow_id, user_id) DO UPDATE SET language = EXCLUDED.language, ring_order = EXCLUDED.ring_order
+	`
+
+	_, err := DB.Exec(
+		query,
+		utils.CreateNullInt(form.CallFlowID),
+		utils.CreateNullInt(form.UserID),
+		utils.CreateNullString(form.Language),
+		utils.CreateNullInt(form.RingOrder),
+	)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func DeleteCallFlowRecipient(id int) error {
+	_, err := DB.Exec(`DELETE FROM call_flow_recipient WHERE call_flow_recipient_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+
+	return nil
+}
+
+func GetDiscountCodes() ([]types.DiscountCodeList, error) {
+	var discountCodes []types.DiscountCodeList
+
+	query := `SELECT dc.discount_code_id,
+		dc.code,
+		dc.discount_type,
+		dc.amount,
+		dc.expires_at AT TIME ZONE 'America/New_York' AT TIME ZONE 'UTC',
+		COALESCE(dc.max_redemptions, 0),
+		(SELECT COUNT(*) FROM quote_discount AS qd WHERE qd.discount_code_id = dc.discount_code_id),
+		dc.is_active,
+		COALESCE((
+			SELECT STRING_AGG(st.service_type, ', ' ORDER BY st.service_type)
+			FROM discount_code_service_type AS dcst
+			JOIN service_type AS st ON st.service_type_id = dcst.service_type_id
+			WHERE dcst.discount_code_id = dc.discount_code_id
+		), '')
+	FROM discount_code AS dc
+	ORDER BY dc.is_active DESC, dc.date_created DESC;`
+
+	rows, err := DB.Query(query)
+	if err != nil {
+		return discountCodes, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var discountCode types.DiscountCodeList
+		var expiresAt sql.NullTime
+
+		err := rows.Scan(
+			&discountCode.DiscountCodeID,
+			&discountCode.Code,
+			&discountCode.DiscountType,
+			&discountCode.Amount,
+			&expiresAt,
+			&discountCode.MaxRedemptions,
+			&discountCode.Redemptions,
+			&discountCode.IsActive,
+			&discountCode.ServiceTypes,
+		)
+		if err != nil {
+			return discountCodes, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if expiresAt.Valid {
+			discountCode.ExpiresAt = utils.FormatTimestampWithOptions(expiresAt.Time.Unix(), &types.TimestampFormatOptions{Format: "Jan 2, 2006", TimeZone: constants.TimeZone})
+		}
+
+		discountCodes = append(discountCodes, discountCode)
+	}
+
+	if err := rows.Err(); err != nil {
+		return discountCodes, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return discountCodes, nil
+}
+
+func GetDiscountCodeByCode(code string) (models.DiscountCode, error) {
+	var discountCode models.DiscountCode
+
+	query := `SELECT discount_code_id,
+		code,
+		discount_type,
+		amount,
+		expires_at AT TIME ZONE 'America/New_York' AT TIME ZONE 'UTC',
+		COALESCE(max_redemptions, 0),
+		is_active,
+		date_created AT TIME ZONE 'America/New_York' AT TIME ZONE 'UTC'
+	FROM discount_code
+	WHERE UPPER(code) = UPPER($1);`
+
+	var expiresAt sql.NullTime
+	var dateCreated time.Time
+
+	err := DB.QueryRow(query, code).Scan(
+		&discountCode.DiscountCodeID,
+		&discountCode.Code,
+		&discountCode.DiscountType,
+		&discountCode.Amount,
+		&expiresAt,
+		&discountCode.MaxRedemptions,
+		&discountCode.IsActive,
+		&dateCreated,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return discountCode, fmt.Errorf("no discount code found: %w", err)
+		}
+		return discountCode, fmt.Errorf("error scanning row: %w", err)
+	}
+
+	if expiresAt.Valid {
+		discountCode.ExpiresAt = expiresAt.Time.Unix()
+	}
+	discountCode.DateCreated = dateCreated.Unix()
+
+	return discountCode, nil
+}
+
+func GetDiscountCodeServiceTypeIDs(discountCodeId int) ([]int, error) {
+	var serviceTypeIds []int
+
+	rows, err := DB.Query(`SELECT service_type_id FROM discount_code_service_type WHERE discount_code_id = $1`, discountCodeId)
+	if err != nil {
+		return serviceTypeIds, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var serviceTypeId int
+		if err := rows.Scan(&serviceTypeId); err != nil {
+			return serviceTypeIds, fmt.Errorf("error scanning row: %w", err)
+		}
+		serviceTypeIds = append(serviceTypeIds, serviceTypeId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return serviceTypeIds, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return serviceTypeIds, nil
+}
+
+func CountDiscountCodeRedemptions(discountCodeId, excludeQuoteId int) (int, error) {
+	var count int
+
+	err := DB.QueryRow(`SELECT COUNT(*) FROM quote_discount WHERE discount_code_id = $1 AND quote_id <> $2`, discountCodeId, excludeQuoteId).Scan(&count)
+	if err != nil {
+		return count, fmt.Errorf("error counting redemptions: %w", err)
+	}
+
+	return count, nil
+}
+
+func CreateDiscountCode(form types.DiscountCodeForm) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var discountCodeId int
+	err = tx.QueryRow(`
+		INSERT INTO discount_code (code, discount_type, amount, expires_at, max_redemptions, is_active, date_created)
+		VALUES ($1, $2, $3, to_timestamp($4)::timestamptz AT TIME ZONE 'America/New_York', $5, TRUE, (NOW() AT TIME ZONE 'America/New_York'))
+		RETURNING discount_code_id
+	`,
+		utils.CreateNullString(form.Code),
+		utils.CreateNullString(form.DiscountType),
+		utils.CreateNullFloat64(form.Amount),
+		utils.CreateNullInt64(form.ExpiresAt),
+		utils.CreateNullInt(form.MaxRedemptions),
+	).Scan(&discountCodeId)
+	if err != nil {
+		return fmt.Errorf("error inserting discount code: %w", err)
+	}
+
+	for _, serviceTypeId := range form.ServiceTypeIDs {
+		_, err = tx.Exec(`
+			INSERT INTO discount_code_service_type (discount_code_id, service_type_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, discountCodeId, serviceTypeId)
+		if err != nil {
+			return fmt.Errorf("error inserting discount code service type: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func DeactivateDiscountCode(discountCodeId int) error {
+	_, err := DB.Exec(`UPDATE discount_code SET is_active = FALSE WHERE discount_code_id = $1`, discountCodeId)
+	if err != nil {
+		return fmt.Errorf("error deactivating discount code: %w", err)
+	}
+
+	return nil
+}
+
+func GetQuoteDiscount(quoteId int) (types.QuoteDiscountDetails, error) {
+	var discount types.QuoteDiscountDetails
+
+	query := `SELECT qd.quote_discount_id,
+		qd.quote_id,
+		qd.discount_code_id,
+		dc.code,
+		dc.discount_type,
+		dc.amount,
+		qd.amount
+	FROM quote_discount AS qd
+	JOIN discount_code AS dc ON dc.discount_code_id = qd.discount_code_id
+	WHERE qd.quote_id = $1;`
+
+	err := DB.QueryRow(query, quoteId).Scan(
+		&discount.QuoteDiscountID,
+		&discount.QuoteID,
+		&discount.DiscountCodeID,
+		&discount.Code,
+		&discount.DiscountType,
+		&discount.Value,
+		&discount.Amount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return discount, nil
+		}
+		return discount, fmt.Errorf("error scanning row: %w", err)
+	}
+
+	return discount, nil
+}
+
+const setQuoteDiscountQuery = `
+	INSERT INTO quote_discount (quote_id, discount_code_id, amount, date_redeemed)
+	VALUES ($1, $2, $3, (NOW() AT TIME ZONE 'America/New_York'))
+	ON CONFLICT (quote_id) DO UPDATE SET
+		amount = EXCLUDED.amount,
+		date_redeemed = CASE WHEN quote_discount.discount_code_id = EXCLUDED.discount_code_id THEN quote_discount.date_redeemed ELSE EXCLUDED.date_redeemed END,
+		discount_code_id = EXCLUDED.discount_code_id
+`
+
+func SetQuoteDiscount(quoteId, discountCodeId int, amount float64) error {
+	_, err := DB.Exec(setQuoteDiscountQuery, quoteId, discountCodeId, amount)
+	if err != nil {
+		return fmt.Errorf("error saving quote discount: %w", err)
+	}
+
+	return nil
+}
+
+// RedeemQuoteDiscount saves the discount unless the code has reached its usage limit. The code stays locked
+// until the discount is saved, so concurrent redemptions are counted against each other.
+func RedeemQuoteDiscount(quoteId, discountCodeId int, amount float64) (bool, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxRedemptions sql.NullInt64
+	err = tx.QueryRow(`SELECT max_redemptions FROM discount_code WHERE discount_code_id = $1 FOR UPDATE`, discountCodeId).Scan(&maxRedemptions)
+	if err != nil {
+		return false, fmt.Errorf("error locking discount code: %w", err)
+	}
+
+	if maxRedemptions.Valid && maxRedemptions.Int64 > 0 {
+		var redemptions int64
+		err = tx.QueryRow(`SELECT COUNT(*) FROM quote_discount WHERE discount_code_id = $1 AND quote_id <> $2`, discountCodeId, quoteId).Scan(&redemptions)
+		if err != nil {
+			return false, fmt.Errorf("error counting redemptions: %w", err)
+		}
+
+		if redemptions >= maxRedemptions.Int64 {
+			return false, nil
+		}
+	}
+
+	_, err = tx.Exec(setQuoteDiscountQuery, quoteId, discountCodeId, amount)
+	if err != nil {
+		return false, fmt.Errorf("error saving quote discount: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func DeleteQuoteDiscount(quoteId int) error {
+	_, err := DB.Exec(`DELETE FROM quote_discount WHERE quote_id = $1`, quoteId)
+	if err != nil {
+		return fmt.Errorf("error deleting quote discount: %w", err)
+	}
+
+	return nil
+}
+
+func GetDiscountRedemptionsBySource() ([]types.DiscountRedemptionReport, error) {
+	var report []types.DiscountRedemptionReport
+
+	query := `SELECT dc.code,
+		COALESCE(NULLIF(lm.source, ''), 'Unknown') AS source,
+		COUNT(qd.quote_discount_id),
+		COALESCE(SUM(qd.amount), 0)
+	FROM quote_discount AS qd
+	JOIN discount_code AS dc ON dc.discount_code_id = qd.discount_code_id
+	JOIN quote AS q ON q.quote_id = qd.quote_id
+	LEFT JOIN lead_marketing AS lm ON lm.lead_id = q.lead_id
+	GROUP BY dc.code, COALESCE(NULLIF(lm.source, ''), 'Unknown')
+	ORDER BY dc.code, COUNT(qd.quote_discount_id) DESC;`
+
+	rows, err := DB.Query(query)
+	if err != nil {
+		return report, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row types.DiscountRedemptionReport
+		if err := rows.Scan(&row.Code, &row.Source, &row.Redemptions, &row.TotalDiscount); err != nil {
+			return report, fmt.Errorf("error scanning row: %w", err)
+		}
+		report = append(report, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return report, nil
+}
+
+func GetQuoteAcceptance(quoteId int) (types.QuoteAcceptanceDetails, error) {
+	var acceptance types.QuoteAcceptanceDetails
+
+	query := `SELECT quote_acceptance_id,
+		signature_name,
+		COALESCE(package_tier, ''),
+		COALESCE(selected_add_ons, ''),
+		ip_address,
+		COALESCE(user_agent, ''),
+		date_accepted AT TIME ZONE 'America/New_York' AT TIME ZONE 'UTC',
+		date_invoiced IS NOT NULL
+	FROM quote_acceptance
+	WHERE quote_id = $1;`
+
+	var dateAccepted time.Time
+
+	err := DB.QueryRow(query, quoteId).Scan(
+		&acceptance.QuoteAcceptanceID,
+		&acceptance.SignatureName,
+		&acceptance.PackageTier,
+		&acceptance.SelectedAddOns,
+		&acceptance.IPAddress,
+		&acceptance.UserAgent,
+		&dateAccepted,
+		&acceptance.IsInvoiced,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return acceptance, nil
+		}
+		return acceptance, fmt.Errorf("error scanning row: %w", err)
+	}
+
+	acceptance.DateAccepted = utils.FormatTimestampWithOptions(dateAccepted.Unix(), &types.TimestampFormatOptions{Format: "01/02/2006 03:04 PM", TimeZone: constants.TimeZone})
+
+	return acceptance, nil
+}
+
+// AcceptQuote keeps the chosen package and add-on lines as regular services, drops the options that weren't
+// chosen and records the signature, all at once so a quote is never left half accepted.
+func AcceptQuote(acceptance models.QuoteAcceptance, keepQuoteServiceIds []int) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, quoteServiceId := range keepQuoteServiceIds {
+		_, err = tx.Exec(`
+			UPDATE quote_service SET package_tier = NULL, is_optional = FALSE
+			WHERE quote_service_id = $1 AND quote_id = $2
+		`, quoteServiceId, acceptance.QuoteID)
+		if err != nil {
+			return fmt.Errorf("error keeping quote option: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM quote_service
+		WHERE quote_id = $1 AND (package_tier IS NOT NULL OR is_optional)
+	`, acceptance.QuoteID)
+	if err != nil {
+		return fmt.Errorf("error deleting quote options: %w", err)
+	}
+
+	// A double submit keeps the first signature and choices
+	var quoteAcceptanceId int
+	err = tx.QueryRow(`
+		INSERT INTO quote_acceptance (quote_id, signature_name, package_tier, selected_add_ons, ip_address, user_agent, date_accepted)
+		VALUES ($1, $2, $3, $4, $5, $6, to_timestamp($7)::timestamptz AT TIME ZONE 'America/New_York')
+		ON CONFLICT (quote_id) DO NOTHING
+		RETURNING quote_acceptance_id
+	`,
+		acceptance.QuoteID,
+		acceptance.SignatureName,
+		utils.CreateNullString(&acceptance.PackageTier),
+		utils.CreateNullString(&acceptance.SelectedAddOns),
+		acceptance.IPAddress,
+		utils.CreateNullString(&acceptance.UserAgent),
+		acceptance.DateAccepted,
+	).Scan(&quoteAcceptanceId)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error inserting quote acceptance: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func MarkQuoteAcceptanceInvoiced(quoteId int, dateInvoiced int64) error {
+	query := `UPDATE quote_acceptance
+	SET date_invoiced = to_timestamp($2)::timestamptz AT TIME ZONE 'America/New_York'
+	WHERE quote_id = $1;`
+
+	_, err := DB.Exec(query, quoteId, dateInvoiced)
+	if err != nil {
+		return fmt.Errorf("error marking quote acceptance invoiced: %w", err)
+	}
+
+	return nil
+}
+
+// An invoicing claim this old is assumed to have died with its request
+const quoteInvoicingClaimTimeout = 10 * time.Minute
+
+// ClaimQuoteAcceptanceInvoicing reports whether this request won the right to invoice the accepted quote.
+func ClaimQuoteAcceptanceInvoicing(quoteId int) (bool, error) {
+	var quoteAcceptanceId int
+	err := DB.QueryRow(`
+		UPDATE quote_acceptance
+		SET date_invoicing_started = (NOW() AT TIME ZONE 'America/New_York')
+		WHERE quote_id = $1 AND date_invoiced IS NULL AND (
+			date_invoicing_started IS NULL
+			OR date_invoicing_started < (NOW() AT TIME ZONE 'America/New_York') - make_interval(secs => $2)
+		)
+		RETURNING quote_acceptance_id
+	`, quoteId, quoteInvoicingClaimTimeout.Seconds()).Scan(&quoteAcceptanceId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error claiming quote invoicing: %w", err)
+	}
+
+	return true, nil
+}
+
+func ReleaseQuoteAcceptanceInvoicing(quoteId int) error {
+	_, err := DB.Exec(`UPDATE quote_acceptance SET date_invoicing_started = NULL WHERE quote_id = $1 AND date_invoiced IS NULL`, quoteId)
+	if err != nil {
+		return fmt.Errorf("error releasing quote invoicing: %w", err)
+	}
+
+	return nil
+}
+
+func GetMessageMedia(messageMediaId int) (models.MessageMedia, error) {
+	var media models.MessageMedia
+
+	query := `SELECT message_media_id, message_id, file_path, content_type
+	FROM message_media
+	WHERE message_media_id = $1`
+
+	err := DB.QueryRow(query, messageMediaId).Scan(&media.MessageMediaID, &media.MessageID, &media.FilePath, &media.ContentType)
+	if err != nil {
+		return media, fmt.Errorf("error scanning message media: %w", err)
+	}
+
+	return media, nil
 }
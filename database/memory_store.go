@@ -0,0 +1,4307 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+	"github.com/davidalvarez305/yd_cocktails/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MemoryStore keeps every table in process memory. It backs all of the store
+// interfaces at once so that handlers and services can run without Postgres.
+type MemoryStore struct {
+	mu     sync.Mutex
+	nextID int
+
+	leads          map[int]*memoryLead
+	leadNotes      []models.LeadNote
+	leadNextAction []models.LeadNextAction
+
+	quotes        map[int]*models.Quote
+	quoteServices map[int]*models.QuoteService
+	services      map[int]*models.Service
+	invoices      map[int]*memoryInvoice
+
+	discountCodes            map[int]*models.DiscountCode
+	discountCodeServiceTypes map[int][]int
+	quoteDiscounts           map[int]*models.QuoteDiscount
+	quoteAcceptances         map[int]*models.QuoteAcceptance
+
+	messages       []models.Message
+	pendingStatus  map[string]models.Message
+	phoneCalls     []models.PhoneCall
+	transcriptions []models.PhoneCallTranscription
+	transcribing   map[int]int64
+	smsConsent     []models.SMSConsentEvent
+	scheduled      map[int]*models.ScheduledMessage
+
+	events         map[int]*models.Event
+	eventStaff     map[int]*models.EventStaff
+	eventCocktails map[int]*models.EventCocktail
+	cocktails      map[int]*models.Cocktail
+
+	ingredients         map[int]*models.Ingredient
+	units               map[int]*models.Unit
+	cocktailIngredients []models.CocktailIngredient
+
+	adSpend []models.AdSpend
+
+	jobs           map[int]*models.Job
+	jobDeadLetters []models.JobDeadLetter
+
+	smsSequences           map[int]*models.SMSSequence
+	smsSequenceSteps       map[int]*models.SMSSequenceStep
+	smsSequenceEnrollments map[int]*models.SMSSequenceEnrollment
+
+	conversations     map[int]*models.Conversation
+	conversationNotes []models.ConversationNote
+
+	callFlow           models.CallFlow
+	callFlowHours      []models.CallFlowBusinessHours
+	callFlowRecipients map[int]*models.CallFlowRecipient
+
+	users      map[int]*models.User
+	sessions   map[string]*models.Session
+	csrfTokens map[string]*models.CSRFToken
+
+	leadStatuses  []models.LeadStatus
+	leadInterests []models.LeadInterest
+	nextActions   []models.NextAction
+	userRoles     []models.UserRole
+	serviceTypes  []models.ServiceType
+	unitTypes     []models.UnitType
+	invoiceTypes  []models.InvoiceType
+
+	eventStaffStatuses []models.EventStaffStatus
+}
+
+type memoryLead struct {
+	models.Lead
+	StripeCustomerID string
+	Marketing        models.LeadMarketing
+}
+
+type memoryInvoice struct {
+	models.Invoice
+	InvoiceStatusID int
+}
+
+// NewMemoryStores returns stores backed by a single in-memory database seeded
+// with the same lookup rows as the initial migration.
+func NewMemoryStores() Stores {
+	m := NewMemoryStore()
+
+	return Stores{
+		Leads:     m,
+		Quotes:    m,
+		Invoices:  m,
+		Messages:  m,
+		Events:    m,
+		Users:     m,
+		Sessions:  m,
+		Reports:   m,
+		Jobs:      m,
+		Sequences: m,
+
+		Conversations: m,
+		CallFlows:     m,
+	}
+}
+
+func NewMemoryStore() *MemoryStore {
+	m := &MemoryStore{
+		leads:          make(map[int]*memoryLead),
+		quotes:         make(map[int]*models.Quote),
+		quoteServices:  make(map[int]*models.QuoteService),
+		services:       make(map[int]*models.Service),
+		invoices:       make(map[int]*memoryInvoice),
+		discountCodes:  make(map[int]*models.DiscountCode),
+		quoteDiscounts: make(map[int]*models.QuoteDiscount),
+
+		discountCodeServiceTypes: make(map[int][]int),
+		quoteAcceptances:         make(map[int]*models.QuoteAcceptance),
+		events:                   make(map[int]*models.Event),
+		eventStaff:               make(map[int]*models.EventStaff),
+		eventCocktails:           make(map[int]*models.EventCocktail),
+		cocktails:                make(map[int]*models.Cocktail),
+		ingredients:              make(map[int]*models.Ingredient),
+		units:                    make(map[int]*models.Unit),
+		users:                    make(map[int]*models.User),
+		sessions:                 make(map[string]*models.Session),
+		csrfTokens:               make(map[string]*models.CSRFToken),
+		jobs:                     make(map[int]*models.Job),
+		scheduled:                make(map[int]*models.ScheduledMessage),
+		pendingStatus:            make(map[string]models.Message),
+		transcribing:             make(map[int]int64),
+		conversations:            make(map[int]*models.Conversation),
+
+		callFlow: models.CallFlow{
+			CallFlowID:        1,
+			RingStrategy:      constants.SimultaneousRingStrategy,
+			RingTimeout:       20,
+			VoicemailGreeting: "Sorry we missed your call. Please leave your name, event date and a short message after the tone and we will call you back.",
+			AfterHoursMessage: "Thanks for calling YD Cocktails. Our office is closed right now.",
+		},
+		callFlowRecipients: make(map[int]*models.CallFlowRecipient),
+
+		smsSequences:           make(map[int]*models.SMSSequence),
+		smsSequenceSteps:       make(map[int]*models.SMSSequenceStep),
+		smsSequenceEnrollments: make(map[int]*models.SMSSequenceEnrollment),
+
+		leadStatuses: []models.LeadStatus{
+			{LeadStatusID: 1, Status: "New"},
+			{LeadStatusID: 2, Status: "Contacted"},
+			{LeadStatusID: 3, Status: "Quoted"},
+			{LeadStatusID: 4, Status: "Negotiating"},
+			{LeadStatusID: 5, Status: "Booked"},
+			{LeadStatusID: 6, Status: "Lost"},
+			{LeadStatusID: 7, Status: "Archived"},
+		},
+		leadInterests: []models.LeadInterest{
+			{LeadInterestID: 1, Interest: "High"},
+			{LeadInterestID: 2, Interest: "Medium"},
+			{LeadInterestID: 3, Interest: "Low"},
+			{LeadInterestID: 4, Interest: "No Interest"},
+		},
+		nextActions: []models.NextAction{
+			{NextActionID: 1, Action: "Initial Contact"},
+			{NextActionID: 2, Action: "Send Quote"},
+			{NextActionID: 3, Action: "First Follow Up"},
+			{NextActionID: 4, Action: "Second Follow Up"},
+			{NextActionID: 5, Action: "Call Back"},
+		},
+		userRoles: []models.UserRole{
+			{UserRoleID: 1, Role: "Admin"},
+			{UserRoleID: 2, Role: "Bartender"},
+			{UserRoleID: 3, Role: "Barback"},
+			{UserRoleID: 4, Role: "Sales"},
+		},
+		serviceTypes: []models.ServiceType{
+			{ServiceTypeID: 1, Type: "Alcohol"},
+			{ServiceTypeID: 2, Type: "Bar Rental"},
+			{ServiceTypeID: 3, Type: "Cooler Rental"},
+			{ServiceTypeID: 4, Type: "Bartending Add-On"},
+			{ServiceTypeID: 5, Type: "Bartending"},
+			{ServiceTypeID: 6, Type: "Glassware"},
+			{ServiceTypeID: 7, Type: "Mixers"},
+			{ServiceTypeID: 8, Type: "Extra"},
+			{ServiceTypeID: constants.PricingAdjustmentServiceTypeID, Type: "Pricing Adjustment"},
+		},
+		unitTypes: []models.UnitType{
+			{UnitTypeID: constants.PerPersonUnitTypeID, Type: "Per Person"},
+			{UnitTypeID: constants.PerHourUnitTypeID, Type: "Per Hour"},
+			{UnitTypeID: constants.FlatUnitTypeID, Type: "Flat"},
+			{UnitTypeID: constants.RatioUnitTypeID, Type: "Ratio"},
+			{UnitTypeID: constants.AdHocUnitTypeID, Type: "Ad Hoc"},
+			{UnitTypeID: constants.FixedUnitTypeID, Type: "Fixed"},
+		},
+		invoiceTypes: []models.InvoiceType{
+			{InvoiceTypeID: constants.DepositInvoiceTypeID, Type: "Deposit", AmountPercentage: 0.25},
+			{InvoiceTypeID: constants.RemainingInvoiceTypeID, Type: "Remaining", AmountPercentage: 0.75},
+			{InvoiceTypeID: constants.FullInvoiceTypeID, Type: "Full", AmountPercentage: 1.00},
+		},
+		eventStaffStatuses: []models.EventStaffStatus{
+			{EventStaffStatusID: constants.PendingEventStaffStatusID, Status: "Pending"},
+			{EventStaffStatusID: constants.AcceptedEventStaffStatusID, Status: "Accepted"},
+			{EventStaffStatusID: constants.DeclinedEventStaffStatusID, Status: "Declined"},
+		},
+	}
+
+	// Units are editable from the CRM, so they take ids from the same sequence as everything else
+	for _, unit := range []models.Unit{
+		{Name: "Ounce", Abbreviation: "oz"},
+		{Name: "Dash", Abbreviation: "dash"},
+		{Name: "Barspoon", Abbreviation: "bsp"},
+		{Name: "Splash", Abbreviation: "splash"},
+		{Name: "Piece", Abbreviation: "pc"},
+		{Name: "Leaf", Abbreviation: "leaf"},
+	} {
+		unit.UnitID = m.id()
+		m.units[unit.UnitID] = &unit
+	}
+
+	// The pricing engine adds surcharges, travel and minimum order top ups as lines against these services
+	for _, name := range []string{
+		constants.WeekendSurchargeService,
+		constants.HolidaySurchargeService,
+		constants.TravelFeeService,
+		constants.MinimumOrderAdjustmentService,
+	} {
+		id := m.id()
+		m.services[id] = &models.Service{
+			ServiceID:     id,
+			ServiceTypeID: constants.PricingAdjustmentServiceTypeID,
+			Service:       name,
+			UnitTypeID:    constants.FixedUnitTypeID,
+		}
+	}
+
+	return m
+}
+
+func (m *MemoryStore) id() int {
+	m.nextID++
+	return m.nextID
+}
+
+func deref[T any](ptr *T) T {
+	var value T
+	if ptr != nil {
+		value = *ptr
+	}
+	return value
+}
+
+func formatMemoryTimestamp(ts int64) string {
+	if ts == 0 {
+		return ""
+	}
+	return utils.FormatTimestampWithOptions(ts, &types.TimestampFormatOptions{
+		Format:   "01/02/2006 03:04 PM",
+		TimeZone: constants.TimeZone,
+	})
+}
+
+func parseMemoryID(id string) (int, error) {
+	value, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q: %w", id, err)
+	}
+	return value, nil
+}
+
+func paginate[T any](rows []T, pageNum int) []T {
+	offset := (pageNum - 1) * int(constants.LeadsPerPage)
+	if offset < 0 || offset >= len(rows) {
+		return nil
+	}
+	end := offset + int(constants.LeadsPerPage)
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[offset:end]
+}
+
+func (m *MemoryStore) leadByPhone(phoneNumber string) *memoryLead {
+	for _, lead := range m.leads {
+		if lead.PhoneNumber == phoneNumber {
+			return lead
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) userByPhone(phoneNumber string) *models.User {
+	for _, user := range m.users {
+		if user.PhoneNumber == phoneNumber {
+			return user
+		}
+	}
+	return nil
+}
+
+// conversation returns the lead's conversation, creating an open one the first time it's needed.
+func (m *MemoryStore) conversation(leadId int) *models.Conversation {
+	conversation, ok := m.conversations[leadId]
+	if !ok {
+		conversation = &models.Conversation{
+			ConversationID: m.id(),
+			LeadID:         leadId,
+			Status:         constants.OpenConversationStatus,
+			DateCreated:    time.Now().Unix(),
+		}
+		m.conversations[leadId] = conversation
+	}
+	return conversation
+}
+
+func (m *MemoryStore) conversationStatus(leadId int) string {
+	conversation, ok := m.conversations[leadId]
+	if !ok {
+		return constants.OpenConversationStatus
+	}
+	if conversation.Status == constants.SnoozedConversationStatus && conversation.SnoozedUntil <= time.Now().Unix() {
+		return constants.OpenConversationStatus
+	}
+	return conversation.Status
+}
+
+func (m *MemoryStore) nextSalesUserID() int {
+	var nextUserId int
+	var nextLastAssigned int64
+	for _, id := range sortedKeys(m.users) {
+		if m.users[id].UserRoleID != constants.UserSalesRoleID {
+			continue
+		}
+
+		var lastAssigned int64
+		for _, conversation := range m.conversations {
+			if conversation.AssignedUserID == id && conversation.DateAssigned > lastAssigned {
+				lastAssigned = conversation.DateAssigned
+			}
+		}
+
+		if nextUserId == 0 || lastAssigned < nextLastAssigned {
+			nextUserId, nextLastAssigned = id, lastAssigned
+		}
+	}
+	return nextUserId
+}
+
+func (m *MemoryStore) userFullName(userId int) string {
+	user, ok := m.users[userId]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+func (m *MemoryStore) quoteTotal(quoteId int) float64 {
+	var total float64
+	for _, qs := range m.quoteServices {
+		if qs.QuoteID == quoteId {
+			total += qs.Units * qs.PricePerUnit
+		}
+	}
+	return total
+}
+
+func (m *MemoryStore) invoicePercentage(invoiceTypeId int) float64 {
+	for _, it := range m.invoiceTypes {
+		if it.InvoiceTypeID == invoiceTypeId {
+			return it.AmountPercentage
+		}
+	}
+	return 0
+}
+
+func (m *MemoryStore) lastContact(phoneNumber string) int64 {
+	var last int64
+	for _, msg := range m.messages {
+		if (msg.TextFrom == phoneNumber || msg.TextTo == phoneNumber) && msg.DateCreated > last {
+			last = msg.DateCreated
+		}
+	}
+	for _, call := range m.phoneCalls {
+		if (call.CallFrom == phoneNumber || call.CallTo == phoneNumber) && call.DateCreated > last {
+			last = call.DateCreated
+		}
+	}
+	return last
+}
+
+func (m *MemoryStore) lastOutboundMessageFailed(phoneNumber string) bool {
+	var last *models.Message
+	for i, msg := range m.messages {
+		if msg.IsInbound || msg.TextTo != phoneNumber {
+			continue
+		}
+		if last == nil || msg.DateCreated >= last.DateCreated {
+			last = &m.messages[i]
+		}
+	}
+	return last != nil && isFailedMessageStatus(last.Status)
+}
+
+func sortedKeys[T any](rows map[int]T) []int {
+	keys := make([]int, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Leads
+
+func (m *MemoryStore) CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.createLead(quoteForm), nil
+}
+
+func (m *MemoryStore) GetOrCreateLeadFromPhoneNumber(quoteForm types.QuoteForm) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if lead := m.leadByPhone(deref(quoteForm.PhoneNumber)); lead != nil {
+		return lead.LeadID, false, nil
+	}
+	return m.createLead(quoteForm), true, nil
+}
+
+func (m *MemoryStore) createLead(quoteForm types.QuoteForm) int {
+	createdAt := time.Now().Unix()
+	if quoteForm.CreatedAt != nil {
+		createdAt = *quoteForm.CreatedAt
+	}
+
+	leadId := m.id()
+	m.leads[leadId] = &memoryLead{
+		Lead: models.Lead{
+			LeadID:             leadId,
+			FullName:           deref(quoteForm.FullName),
+			PhoneNumber:        deref(quoteForm.PhoneNumber),
+			OptInTextMessaging: deref(quoteForm.OptInTextMessaging),
+			CreatedAt:          createdAt,
+			Email:              deref(quoteForm.Email),
+			Message:            deref(quoteForm.Message),
+			NextActionID:       1,
+			LeadStatusID:       constants.NewLeadStatusID,
+		},
+		Marketing: models.LeadMarketing{
+			LeadMarketingID:   int64(m.id()),
+			LeadID:            int64(leadId),
+			Source:            deref(quoteForm.Source),
+			Medium:            deref(quoteForm.Medium),
+			Channel:           deref(quoteForm.Channel),
+			LandingPage:       deref(quoteForm.LandingPage),
+			Longitude:         deref(quoteForm.Longitude),
+			Latitude:          deref(quoteForm.Latitude),
+			Keyword:           deref(quoteForm.Keyword),
+			Referrer:          deref(quoteForm.Referrer),
+			ClickID:           deref(quoteForm.ClickID),
+			CampaignID:        deref(quoteForm.CampaignID),
+			AdCampaign:        deref(quoteForm.AdCampaign),
+			AdGroupID:         deref(quoteForm.AdGroupID),
+			AdGroupName:       deref(quoteForm.AdGroupName),
+			AdSetID:           deref(quoteForm.AdSetID),
+			AdSetName:         deref(quoteForm.AdSetName),
+			AdID:              deref(quoteForm.AdID),
+			AdHeadline:        deref(quoteForm.AdHeadline),
+			Language:          deref(quoteForm.Language),
+			UserAgent:         deref(quoteForm.UserAgent),
+			ButtonClicked:     deref(quoteForm.ButtonClicked),
+			IP:                deref(quoteForm.IP),
+			ExternalID:        deref(quoteForm.ExternalID),
+			GoogleClientID:    deref(quoteForm.GoogleClientID),
+			FacebookClickID:   deref(quoteForm.FacebookClickID),
+			FacebookClientID:  deref(quoteForm.FacebookClientID),
+			CSRFSecret:        deref(quoteForm.CSRFSecret),
+			InstantFormLeadID: deref(quoteForm.InstantFormLeadID),
+			InstantFormID:     deref(quoteForm.InstantFormID),
+			InstantFormName:   deref(quoteForm.InstantFormName),
+			ReferralLeadID:    deref(quoteForm.ReferralLeadID),
+
+			TrackingPhoneNumber: deref(quoteForm.TrackingPhoneNumber),
+		},
+	}
+
+	if quoteForm.OptInTextMessaging != nil && quoteForm.PhoneNumber != nil {
+		m.smsConsent = append(m.smsConsent, models.SMSConsentEvent{
+			SMSConsentEventID: m.id(),
+			PhoneNumber:       *quoteForm.PhoneNumber,
+			LeadID:            leadId,
+			IsOptedIn:         *quoteForm.OptInTextMessaging,
+			Source:            constants.LeadFormSMSConsentSource,
+			DateCreated:       time.Now().Unix(),
+		})
+	}
+
+	for _, id := range sortedKeys(m.smsSequences) {
+		if !m.smsSequences[id].IsActive {
+			continue
+		}
+		enrollmentId := m.id()
+		m.smsSequenceEnrollments[enrollmentId] = &models.SMSSequenceEnrollment{
+			SMSSequenceEnrollmentID: enrollmentId,
+			SMSSequenceID:           id,
+			LeadID:                  leadId,
+			Status:                  constants.ActiveSMSSequenceEnrollmentStatus,
+			DateEnrolled:            time.Now().Unix(),
+		}
+	}
+
+	conversation := m.conversation(leadId)
+	if userId := m.nextSalesUserID(); userId > 0 {
+		conversation.AssignedUserID = userId
+		conversation.DateAssigned = time.Now().Unix()
+	}
+
+	return leadId
+}
+
+func (m *MemoryStore) GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pageNum := 1
+	if params.PageNum != nil {
+		num, err := strconv.Atoi(*params.PageNum)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not convert page num: %w", err)
+		}
+		pageNum = num
+	}
+
+	var leads []types.LeadList
+	for _, id := range sortedKeys(m.leads) {
+		lead := m.leads[id]
+
+		if params.Search != nil {
+			search := strings.ToLower(*params.Search)
+			if !strings.Contains(strings.ToLower(lead.FullName), search) && !strings.Contains(lead.PhoneNumber, search) {
+				continue
+			}
+		} else {
+			if params.LeadStatusID != nil && lead.LeadStatusID != *params.LeadStatusID {
+				continue
+			}
+			if params.LeadStatusID == nil && lead.LeadStatusID == constants.ArchivedLeadStatusID {
+				continue
+			}
+			if params.LeadInterestID != nil && lead.LeadInterestID != *params.LeadInterestID {
+				continue
+			}
+			if params.LeadInterestID == nil && lead.LeadInterestID == constants.NoInterestLeadInterestID {
+				continue
+			}
+			if params.NextActionID != nil && lead.NextActionID != *params.NextActionID {
+				continue
+			}
+		}
+
+		row := types.LeadList{
+			LeadID:           lead.LeadID,
+			FullName:         lead.FullName,
+			PhoneNumber:      lead.PhoneNumber,
+			CreatedAt:        formatMemoryTimestamp(lead.CreatedAt),
+			Language:         lead.Marketing.Language,
+			LastContactDate:  formatMemoryTimestamp(m.lastContact(lead.PhoneNumber)),
+			HasFailedMessage: m.lastOutboundMessageFailed(lead.PhoneNumber),
+		}
+		for _, status := range m.leadStatuses {
+			if status.LeadStatusID == lead.LeadStatusID {
+				row.LeadStatus = status.Status
+			}
+		}
+		for _, interest := range m.leadInterests {
+			if interest.LeadInterestID == lead.LeadInterestID {
+				row.LeadInterest = interest.Interest
+			}
+		}
+		for _, action := range m.nextActions {
+			if action.NextActionID == lead.NextActionID {
+				row.NextAction = action.Action
+			}
+		}
+		leads = append(leads, row)
+	}
+
+	sort.SliceStable(leads, func(i, j int) bool { return leads[i].LeadID > leads[j].LeadID })
+
+	totalRows := len(leads)
+	leads = paginate(leads, pageNum)
+	for i := range leads {
+		leads[i].TotalRows = totalRows
+	}
+
+	return leads, totalRows, nil
+}
+
+func (m *MemoryStore) GetReferrals() ([]types.Referral, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var referrals []types.Referral
+	for _, id := range sortedKeys(m.leads) {
+		referrals = append(referrals, types.Referral{LeadID: id, FullName: m.leads[id].FullName})
+	}
+	return referrals, nil
+}
+
+func (m *MemoryStore) GetLeadDetails(leadID string) (types.LeadDetails, error) {
+	var details types.LeadDetails
+
+	id, err := parseMemoryID(leadID)
+	if err != nil {
+		return details, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[id]
+	if !ok {
+		return details, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+
+	mk := lead.Marketing
+	return types.LeadDetails{
+		LeadID:              lead.LeadID,
+		FullName:            lead.FullName,
+		Email:               lead.Email,
+		PhoneNumber:         lead.PhoneNumber,
+		StripeCustomerID:    lead.StripeCustomerID,
+		CampaignName:        mk.AdCampaign,
+		CampaignID:          mk.CampaignID,
+		Medium:              mk.Medium,
+		Source:              mk.Source,
+		Referrer:            mk.Referrer,
+		LandingPage:         mk.LandingPage,
+		IP:                  mk.IP,
+		Keyword:             mk.Keyword,
+		Channel:             mk.Channel,
+		Language:            mk.Language,
+		Message:             lead.Message,
+		FacebookClickID:     mk.FacebookClickID,
+		FacebookClientID:    mk.FacebookClientID,
+		UserAgent:           mk.UserAgent,
+		ExternalID:          mk.ExternalID,
+		ClickID:             mk.ClickID,
+		GoogleClientID:      mk.GoogleClientID,
+		ButtonClicked:       mk.ButtonClicked,
+		ReferralLeadID:      mk.ReferralLeadID,
+		InstantFormLeadID:   mk.InstantFormLeadID,
+		InstantFormID:       mk.InstantFormID,
+		InstantFormName:     mk.InstantFormName,
+		TrackingPhoneNumber: mk.TrackingPhoneNumber,
+		NextActionID:        lead.NextActionID,
+		LeadInterestID:      lead.LeadInterestID,
+		LeadStatusID:        lead.LeadStatusID,
+	}, nil
+}
+
+func (m *MemoryStore) GetConversionReporting(leadID int) (types.ConversionReporting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var report types.ConversionReporting
+
+	lead, ok := m.leads[leadID]
+	if !ok {
+		return report, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+
+	mk := lead.Marketing
+	report = types.ConversionReporting{
+		LeadID:            lead.LeadID,
+		Email:             lead.Email,
+		PhoneNumber:       lead.PhoneNumber,
+		CampaignName:      mk.AdCampaign,
+		CampaignID:        mk.CampaignID,
+		LandingPage:       mk.LandingPage,
+		IP:                mk.IP,
+		FacebookClickID:   mk.FacebookClickID,
+		FacebookClientID:  mk.FacebookClientID,
+		UserAgent:         mk.UserAgent,
+		ExternalID:        mk.ExternalID,
+		ClickID:           mk.ClickID,
+		GoogleClientID:    mk.GoogleClientID,
+		ReferralLeadID:    mk.ReferralLeadID,
+		InstantFormLeadID: mk.InstantFormLeadID,
+	}
+
+	for _, id := range sortedKeys(m.events) {
+		event := m.events[id]
+		if event.LeadID == leadID {
+			report.EventID = event.EventID
+			report.Revenue = event.Amount
+		}
+	}
+
+	return report, nil
+}
+
+func (m *MemoryStore) GetLeadIDFromPhoneNumber(phoneNumber string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead := m.leadByPhone(phoneNumber)
+	if lead == nil {
+		return 0, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return lead.LeadID, nil
+}
+
+func (m *MemoryStore) GetLeadByStripeCustomerID(stripeCustomerID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, lead := range m.leads {
+		if lead.StripeCustomerID != "" && lead.StripeCustomerID == stripeCustomerID {
+			return lead.LeadID, nil
+		}
+	}
+	return 0, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+}
+
+func (m *MemoryStore) IsPhoneNumberInDB(phoneNumber string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.leadByPhone(phoneNumber) != nil, nil
+}
+
+func (m *MemoryStore) UpdateLead(form types.UpdateLeadForm) error {
+	if form.LeadID == nil {
+		return fmt.Errorf("lead_id cannot be nil")
+	}
+
+	id, err := parseMemoryID(*form.LeadID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[id]
+	if !ok {
+		return fmt.Errorf("lead %d not found", id)
+	}
+
+	if form.FullName != nil {
+		lead.FullName = *form.FullName
+	}
+	if form.PhoneNumber != nil {
+		lead.PhoneNumber = *form.PhoneNumber
+	}
+	if form.Email != nil {
+		lead.Email = *form.Email
+	}
+	if form.StripeCustomerID != nil {
+		lead.StripeCustomerID = *form.StripeCustomerID
+	}
+	if form.LeadInterestID != nil {
+		lead.LeadInterestID = *form.LeadInterestID
+	}
+	if form.LeadStatusID != nil {
+		lead.LeadStatusID = *form.LeadStatusID
+	}
+	if form.NextActionID != nil {
+		lead.NextActionID = *form.NextActionID
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) UpdateLeadMarketing(form types.UpdateLeadMarketingForm) error {
+	if form.LeadID == nil {
+		return fmt.Errorf("lead_id cannot be nil")
+	}
+
+	id, err := parseMemoryID(*form.LeadID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[id]
+	if !ok {
+		return fmt.Errorf("lead %d not found", id)
+	}
+
+	mk := &lead.Marketing
+	if form.CampaignName != nil {
+		mk.AdCampaign = *form.CampaignName
+	}
+	if form.Medium != nil {
+		mk.Medium = *form.Medium
+	}
+	if form.Source != nil {
+		mk.Source = *form.Source
+	}
+	if form.Referrer != nil {
+		mk.Referrer = *form.Referrer
+	}
+	if form.LandingPage != nil {
+		mk.LandingPage = *form.LandingPage
+	}
+	if form.IP != nil {
+		mk.IP = *form.IP
+	}
+	if form.Keyword != nil {
+		mk.Keyword = *form.Keyword
+	}
+	if form.Channel != nil {
+		mk.Channel = *form.Channel
+	}
+	if form.Language != nil {
+		mk.Language = *form.Language
+	}
+	if form.ButtonClicked != nil {
+		mk.ButtonClicked = *form.ButtonClicked
+	}
+	if form.ReferralLeadID != nil {
+		mk.ReferralLeadID = *form.ReferralLeadID
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) UpdateLeadStatus(id, leadStatusId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[id]
+	if !ok {
+		return fmt.Errorf("lead %d not found", id)
+	}
+	lead.LeadStatusID = leadStatusId
+	return nil
+}
+
+func (m *MemoryStore) DeleteLead(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.leads, id)
+	return nil
+}
+
+func (m *MemoryStore) AssignStripeCustomerIDToLead(stripeCustomerId string, leadId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[leadId]
+	if !ok {
+		return fmt.Errorf("failed to assign stripe customer id to lead: lead %d not found", leadId)
+	}
+	lead.StripeCustomerID = stripeCustomerId
+	return nil
+}
+
+func (m *MemoryStore) GetLeadStatusList() ([]models.LeadStatus, error) {
+	return append([]models.LeadStatus(nil), m.leadStatuses...), nil
+}
+
+func (m *MemoryStore) GetLeadInterestList() ([]models.LeadInterest, error) {
+	return append([]models.LeadInterest(nil), m.leadInterests...), nil
+}
+
+func (m *MemoryStore) GetNextActionList() ([]models.NextAction, error) {
+	return append([]models.NextAction(nil), m.nextActions...), nil
+}
+
+func (m *MemoryStore) CreateLeadNote(note models.LeadNote) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	note.LeadNoteID = m.id()
+	m.leadNotes = append(m.leadNotes, note)
+	return nil
+}
+
+func (m *MemoryStore) GetLeadNotesByLeadID(leadId int) ([]types.FrontendNote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var notes []types.FrontendNote
+	for _, note := range m.leadNotes {
+		if note.LeadID != leadId {
+			continue
+		}
+
+		var userName string
+		if user, ok := m.users[note.AddedByUserID]; ok {
+			userName = user.Username
+		}
+
+		notes = append(notes, types.FrontendNote{
+			UserName:  userName,
+			DateAdded: formatMemoryTimestamp(note.DateAdded),
+			Note:      note.Note,
+		})
+	}
+	return notes, nil
+}
+
+func (m *MemoryStore) CreateLeadNextAction(leadNextAction types.LeadNextActionForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leadNextAction = append(m.leadNextAction, models.LeadNextAction{
+		LeadNextActionID: m.id(),
+		NextActionID:     deref(leadNextAction.NextActionID),
+		LeadID:           deref(leadNextAction.LeadID),
+		ActionDate:       deref(leadNextAction.NextActionDate),
+	})
+	return nil
+}
+
+func (m *MemoryStore) GetLeadNextActionsByLeadID(leadId int) ([]types.LeadNextActionList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var actions []types.LeadNextActionList
+	for _, lna := range m.leadNextAction {
+		if lna.LeadID != leadId {
+			continue
+		}
+
+		row := types.LeadNextActionList{
+			LeadNextActionID: lna.LeadNextActionID,
+			NextActionDate:   formatMemoryTimestamp(lna.ActionDate),
+		}
+		for _, action := range m.nextActions {
+			if action.NextActionID == lna.NextActionID {
+				row.NextAction = action.Action
+			}
+		}
+		actions = append(actions, row)
+	}
+	return actions, nil
+}
+
+func (m *MemoryStore) DeleteLeadNextAction(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, lna := range m.leadNextAction {
+		if lna.LeadNextActionID == id {
+			m.leadNextAction = append(m.leadNextAction[:i], m.leadNextAction[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) ArchivedLeadsWithLastContactOverTwoWeeks() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, lead := range m.leads {
+		last := m.lastContact(lead.PhoneNumber)
+		if (last == 0 && lead.CreatedAt <= now.AddDate(0, 0, -7).Unix()) || (last != 0 && last <= now.AddDate(0, 0, -14).Unix()) {
+			lead.LeadStatusID = constants.ArchivedLeadStatusID
+		}
+	}
+	return nil
+}
+
+// Quotes
+
+func (m *MemoryStore) CreateLeadQuote(form types.LeadQuoteForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.quotes[id] = &models.Quote{
+		QuoteID:     id,
+		ExternalID:  uuid.New().String(),
+		LeadID:      deref(form.LeadID),
+		Guests:      deref(form.Guests),
+		Hours:       deref(form.Hours),
+		EventDate:   deref(form.EventDate),
+		TravelMiles: deref(form.TravelMiles),
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetLeadQuotes(leadId int) ([]types.LeadQuoteList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var quotes []types.LeadQuoteList
+	for _, id := range sortedKeys(m.quotes) {
+		quote := m.quotes[id]
+		if quote.LeadID != leadId {
+			continue
+		}
+		quotes = append(quotes, types.LeadQuoteList{
+			LeadID:     quote.LeadID,
+			QuoteID:    quote.QuoteID,
+			ExternalID: quote.ExternalID,
+			Guests:     quote.Guests,
+			EventDate:  formatMemoryTimestamp(quote.EventDate),
+			Amount:     m.quoteTotal(quote.QuoteID),
+		})
+	}
+	return quotes, nil
+}
+
+func (m *MemoryStore) GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
+	id, err := parseMemoryID(quoteId)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quote, ok := m.quotes[id]
+	if !ok {
+		return models.Quote{}, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return *quote, nil
+}
+
+func (m *MemoryStore) UpdateLeadQuote(form types.LeadQuoteForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quote, ok := m.quotes[deref(form.QuoteID)]
+	if !ok {
+		return fmt.Errorf("quote %d not found", deref(form.QuoteID))
+	}
+
+	if form.Guests != nil {
+		quote.Guests = *form.Guests
+	}
+	if form.Hours != nil {
+		quote.Hours = *form.Hours
+	}
+	if form.EventDate != nil {
+		quote.EventDate = *form.EventDate
+	}
+	if form.TravelMiles != nil {
+		quote.TravelMiles = *form.TravelMiles
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteLeadQuote(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.quotes, id)
+	delete(m.quoteDiscounts, id)
+	delete(m.quoteAcceptances, id)
+	for qsId, qs := range m.quoteServices {
+		if qs.QuoteID == id {
+			delete(m.quoteServices, qsId)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.QuoteServiceForm) (int, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quoteId := m.id()
+	externalId := uuid.New().String()
+	m.quotes[quoteId] = &models.Quote{
+		QuoteID:     quoteId,
+		ExternalID:  externalId,
+		LeadID:      deref(quickQuote.LeadID),
+		Guests:      deref(quickQuote.Guests),
+		Hours:       deref(quickQuote.Hours),
+		EventDate:   deref(quickQuote.EventDate),
+		TravelMiles: deref(quickQuote.TravelMiles),
+	}
+
+	for _, form := range quoteServices {
+		id := m.id()
+		m.quoteServices[id] = &models.QuoteService{
+			QuoteServiceID: id,
+			ServiceID:      deref(form.ServiceID),
+			QuoteID:        quoteId,
+			Units:          deref(form.Units),
+			PricePerUnit:   deref(form.PricePerUnit),
+			PricingRule:    deref(form.PricingRule),
+		}
+	}
+
+	return quoteId, externalId, nil
+}
+
+func (m *MemoryStore) ReplaceQuoteAdjustments(quoteId int, adjustments []types.QuoteServiceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, qs := range m.quoteServices {
+		if qs.QuoteID != quoteId {
+			continue
+		}
+		if service, ok := m.services[qs.ServiceID]; ok && service.ServiceTypeID == constants.PricingAdjustmentServiceTypeID {
+			delete(m.quoteServices, id)
+		}
+	}
+
+	for _, form := range adjustments {
+		id := m.id()
+		m.quoteServices[id] = &models.QuoteService{
+			QuoteServiceID: id,
+			ServiceID:      deref(form.ServiceID),
+			QuoteID:        quoteId,
+			Units:          deref(form.Units),
+			PricePerUnit:   deref(form.PricePerUnit),
+			PricingRule:    deref(form.PricingRule),
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var details types.ExternalQuoteDetails
+
+	var quote *models.Quote
+	for _, q := range m.quotes {
+		if q.ExternalID == externalQuoteId {
+			quote = q
+		}
+	}
+	if quote == nil {
+		return details, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+
+	amount := m.quoteTotal(quote.QuoteID)
+	details = types.ExternalQuoteDetails{
+		QuoteID:            quote.QuoteID,
+		ExternalID:         quote.ExternalID,
+		Amount:             amount,
+		Deposit:            amount * m.invoicePercentage(constants.DepositInvoiceTypeID),
+		RemainingAmount:    amount * m.invoicePercentage(constants.RemainingInvoiceTypeID),
+		Guests:             quote.Guests,
+		Hours:              quote.Hours,
+		EventDate:          formatMemoryTimestamp(quote.EventDate),
+		EventDateTimestamp: quote.EventDate,
+	}
+
+	if lead, ok := m.leads[quote.LeadID]; ok {
+		details.FullName = lead.FullName
+		details.PhoneNumber = lead.PhoneNumber
+		details.Email = lead.Email
+	}
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID != quote.QuoteID {
+			continue
+		}
+		switch {
+		case invoice.InvoiceTypeID == constants.DepositInvoiceTypeID && invoice.InvoiceStatusID == constants.PaidInvoiceStatusID:
+			details.IsDepositPaid = true
+		case invoice.InvoiceStatusID != constants.OpenInvoiceStatusID:
+		case invoice.InvoiceTypeID == constants.DepositInvoiceTypeID:
+			details.DepositInvoiceURL = invoice.URL
+		case invoice.InvoiceTypeID == constants.RemainingInvoiceTypeID:
+			details.RemainingInvoiceURL = invoice.URL
+		case invoice.InvoiceTypeID == constants.FullInvoiceTypeID:
+			details.FullInvoiceURL = invoice.URL
+		}
+	}
+
+	return details, nil
+}
+
+func (m *MemoryStore) GetQuoteServices(quoteId int) ([]types.QuoteServiceList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var services []types.QuoteServiceList
+	for _, id := range sortedKeys(m.quoteServices) {
+		qs := m.quoteServices[id]
+		if qs.QuoteID != quoteId {
+			continue
+		}
+
+		var name string
+		if service, ok := m.services[qs.ServiceID]; ok {
+			name = service.Service
+		}
+
+		services = append(services, types.QuoteServiceList{
+			QuoteServiceID: qs.QuoteServiceID,
+			ServiceID:      qs.ServiceID,
+			QuoteID:        qs.QuoteID,
+			Service:        name,
+			Units:          qs.Units,
+			PricePerUnit:   qs.PricePerUnit,
+			Total:          qs.Units * qs.PricePerUnit,
+			PricingRule:    qs.PricingRule,
+			PackageTier:    qs.PackageTier,
+			IsOptional:     qs.IsOptional,
+		})
+	}
+	return services, nil
+}
+
+func (m *MemoryStore) CreateQuoteService(form types.QuoteServiceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.quoteServices[id] = &models.QuoteService{
+		QuoteServiceID: id,
+		ServiceID:      deref(form.ServiceID),
+		QuoteID:        deref(form.QuoteID),
+		Units:          deref(form.Units),
+		PricePerUnit:   deref(form.PricePerUnit),
+		PricingRule:    deref(form.PricingRule),
+		PackageTier:    deref(form.PackageTier),
+		IsOptional:     deref(form.IsOptional),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateQuoteService(form types.QuoteServiceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	qs, ok := m.quoteServices[deref(form.QuoteServiceID)]
+	if !ok {
+		return fmt.Errorf("quote service %d not found", deref(form.QuoteServiceID))
+	}
+
+	if form.ServiceID != nil {
+		qs.ServiceID = *form.ServiceID
+	}
+	if form.Units != nil {
+		qs.Units = *form.Units
+	}
+	if form.PricePerUnit != nil {
+		qs.PricePerUnit = *form.PricePerUnit
+	}
+	if form.PricingRule != nil {
+		qs.PricingRule = *form.PricingRule
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteQuoteService(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.quoteServices, id)
+	return nil
+}
+
+func (m *MemoryStore) GetQuoteIDByQuoteServiceID(quoteServiceId int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	qs, ok := m.quoteServices[quoteServiceId]
+	if !ok {
+		return 0, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return qs.QuoteID, nil
+}
+
+func (m *MemoryStore) GetServices() ([]models.Service, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var services []models.Service
+	for _, id := range sortedKeys(m.services) {
+		services = append(services, *m.services[id])
+	}
+	return services, nil
+}
+
+func (m *MemoryStore) GetServicesList(pageNum int) ([]models.Service, int, error) {
+	services, _ := m.GetServices()
+	return paginate(services, pageNum), len(services), nil
+}
+
+func (m *MemoryStore) GetServiceListByType(serviceTypeId int) ([]models.Service, error) {
+	services, _ := m.GetServices()
+
+	var filtered []models.Service
+	for _, service := range services {
+		if service.ServiceTypeID == serviceTypeId {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *MemoryStore) GetQuickQuoteServiceListByTypeID(serviceTypeId int) ([]types.QuickQuoteServiceList, error) {
+	services, _ := m.GetServiceListByType(serviceTypeId)
+
+	var list []types.QuickQuoteServiceList
+	for _, service := range services {
+		list = append(list, types.QuickQuoteServiceList{
+			ServiceID:      service.ServiceID,
+			Service:        service.Service,
+			SuggestedPrice: service.SuggestedPrice,
+			UnitTypeID:     service.UnitTypeID,
+			ServiceTypeID:  service.ServiceTypeID,
+			GuestRatio:     service.GuestRatio,
+		})
+	}
+	return list, nil
+}
+
+func (m *MemoryStore) CreateService(form types.ServiceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.services[id] = &models.Service{
+		ServiceID:      id,
+		ServiceTypeID:  deref(form.ServiceTypeID),
+		Service:        deref(form.Service),
+		SuggestedPrice: deref(form.SuggestedPrice),
+		GuestRatio:     deref(form.GuestRatio),
+		UnitTypeID:     deref(form.UnitTypeID),
+		CostBasis:      deref(form.CostBasis),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateService(form types.ServiceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	service, ok := m.services[deref(form.ServiceID)]
+	if !ok {
+		return fmt.Errorf("service %d not found", deref(form.ServiceID))
+	}
+
+	if form.ServiceTypeID != nil {
+		service.ServiceTypeID = *form.ServiceTypeID
+	}
+	if form.Service != nil {
+		service.Service = *form.Service
+	}
+	if form.SuggestedPrice != nil {
+		service.SuggestedPrice = *form.SuggestedPrice
+	}
+	if form.GuestRatio != nil {
+		service.GuestRatio = *form.GuestRatio
+	}
+	if form.UnitTypeID != nil {
+		service.UnitTypeID = *form.UnitTypeID
+	}
+	if form.CostBasis != nil {
+		service.CostBasis = *form.CostBasis
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteService(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.services, id)
+	return nil
+}
+
+func (m *MemoryStore) GetServiceTypes() ([]models.ServiceType, error) {
+	return append([]models.ServiceType(nil), m.serviceTypes...), nil
+}
+
+func (m *MemoryStore) GetUnitTypes() ([]models.UnitType, error) {
+	return append([]models.UnitType(nil), m.unitTypes...), nil
+}
+
+func (m *MemoryStore) GetDiscountCodes() ([]types.DiscountCodeList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []types.DiscountCodeList
+	for _, id := range sortedKeys(m.discountCodes) {
+		code := m.discountCodes[id]
+
+		var serviceTypes []string
+		for _, serviceTypeId := range m.discountCodeServiceTypes[id] {
+			for _, serviceType := range m.serviceTypes {
+				if serviceType.ServiceTypeID == serviceTypeId {
+					serviceTypes = append(serviceTypes, serviceType.Type)
+				}
+			}
+		}
+		sort.Strings(serviceTypes)
+
+		row := types.DiscountCodeList{
+			DiscountCodeID: code.DiscountCodeID,
+			Code:           code.Code,
+			DiscountType:   code.DiscountType,
+			Amount:         code.Amount,
+			MaxRedemptions: code.MaxRedemptions,
+			Redemptions:    m.discountRedemptions(id, 0),
+			IsActive:       code.IsActive,
+			ServiceTypes:   strings.Join(serviceTypes, ", "),
+		}
+		if code.ExpiresAt > 0 {
+			row.ExpiresAt = utils.FormatTimestampWithOptions(code.ExpiresAt, &types.TimestampFormatOptions{Format: "Jan 2, 2006", TimeZone: constants.TimeZone})
+		}
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].IsActive && !rows[j].IsActive
+	})
+
+	return rows, nil
+}
+
+func (m *MemoryStore) GetDiscountCodeByCode(code string) (models.DiscountCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, discountCode := range m.discountCodes {
+		if strings.EqualFold(discountCode.Code, code) {
+			return *discountCode, nil
+		}
+	}
+	return models.DiscountCode{}, fmt.Errorf("no discount code found: %w", sql.ErrNoRows)
+}
+
+func (m *MemoryStore) GetDiscountCodeServiceTypeIDs(discountCodeId int) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]int(nil), m.discountCodeServiceTypes[discountCodeId]...), nil
+}
+
+func (m *MemoryStore) CountDiscountCodeRedemptions(discountCodeId, excludeQuoteId int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.discountRedemptions(discountCodeId, excludeQuoteId), nil
+}
+
+func (m *MemoryStore) discountRedemptions(discountCodeId, excludeQuoteId int) int {
+	var count int
+	for quoteId, discount := range m.quoteDiscounts {
+		if discount.DiscountCodeID == discountCodeId && quoteId != excludeQuoteId {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *MemoryStore) CreateDiscountCode(form types.DiscountCodeForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, discountCode := range m.discountCodes {
+		if strings.EqualFold(discountCode.Code, deref(form.Code)) {
+			return fmt.Errorf("error inserting discount code: code %s already exists", deref(form.Code))
+		}
+	}
+
+	id := m.id()
+	m.discountCodes[id] = &models.DiscountCode{
+		DiscountCodeID: id,
+		Code:           deref(form.Code),
+		DiscountType:   deref(form.DiscountType),
+		Amount:         deref(form.Amount),
+		ExpiresAt:      deref(form.ExpiresAt),
+		MaxRedemptions: deref(form.MaxRedemptions),
+		IsActive:       true,
+		DateCreated:    time.Now().Unix(),
+	}
+	m.discountCodeServiceTypes[id] = append([]int(nil), form.ServiceTypeIDs...)
+	return nil
+}
+
+func (m *MemoryStore) DeactivateDiscountCode(discountCodeId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if discountCode, ok := m.discountCodes[discountCodeId]; ok {
+		discountCode.IsActive = false
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetQuoteDiscount(quoteId int) (types.QuoteDiscountDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	discount, ok := m.quoteDiscounts[quoteId]
+	if !ok {
+		return types.QuoteDiscountDetails{}, nil
+	}
+
+	details := types.QuoteDiscountDetails{
+		QuoteDiscountID: discount.QuoteDiscountID,
+		QuoteID:         discount.QuoteID,
+		DiscountCodeID:  discount.DiscountCodeID,
+		Amount:          discount.Amount,
+	}
+	if code, ok := m.discountCodes[discount.DiscountCodeID]; ok {
+		details.Code = code.Code
+		details.DiscountType = code.DiscountType
+		details.Value = code.Amount
+	}
+	return details, nil
+}
+
+func (m *MemoryStore) SetQuoteDiscount(quoteId, discountCodeId int, amount float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setQuoteDiscount(quoteId, discountCodeId, amount)
+	return nil
+}
+
+func (m *MemoryStore) RedeemQuoteDiscount(quoteId, discountCodeId int, amount float64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code, ok := m.discountCodes[discountCodeId]
+	if !ok {
+		return false, fmt.Errorf("error locking discount code: %w", sql.ErrNoRows)
+	}
+
+	if code.MaxRedemptions > 0 && m.discountRedemptions(discountCodeId, quoteId) >= code.MaxRedemptions {
+		return false, nil
+	}
+
+	m.setQuoteDiscount(quoteId, discountCodeId, amount)
+	return true, nil
+}
+
+func (m *MemoryStore) setQuoteDiscount(quoteId, discountCodeId int, amount float64) {
+	if discount, ok := m.quoteDiscounts[quoteId]; ok {
+		if discount.DiscountCodeID != discountCodeId {
+			discount.DateRedeemed = time.Now().Unix()
+		}
+		discount.DiscountCodeID = discountCodeId
+		discount.Amount = amount
+		return
+	}
+
+	m.quoteDiscounts[quoteId] = &models.QuoteDiscount{
+		QuoteDiscountID: m.id(),
+		QuoteID:         quoteId,
+		DiscountCodeID:  discountCodeId,
+		Amount:          amount,
+		DateRedeemed:    time.Now().Unix(),
+	}
+}
+
+func (m *MemoryStore) DeleteQuoteDiscount(quoteId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.quoteDiscounts, quoteId)
+	return nil
+}
+
+func (m *MemoryStore) GetQuoteAcceptance(quoteId int) (types.QuoteAcceptanceDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acceptance, ok := m.quoteAcceptances[quoteId]
+	if !ok {
+		return types.QuoteAcceptanceDetails{}, nil
+	}
+
+	return types.QuoteAcceptanceDetails{
+		QuoteAcceptanceID: acceptance.QuoteAcceptanceID,
+		SignatureName:     acceptance.SignatureName,
+		PackageTier:       acceptance.PackageTier,
+		SelectedAddOns:    acceptance.SelectedAddOns,
+		IPAddress:         acceptance.IPAddress,
+		UserAgent:         acceptance.UserAgent,
+		DateAccepted:      formatMemoryTimestamp(acceptance.DateAccepted),
+		IsInvoiced:        acceptance.DateInvoiced > 0,
+	}, nil
+}
+
+func (m *MemoryStore) AcceptQuote(acceptance models.QuoteAcceptance, keepQuoteServiceIds []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.quoteAcceptances[acceptance.QuoteID]; ok {
+		return nil
+	}
+
+	for _, quoteServiceId := range keepQuoteServiceIds {
+		if qs, ok := m.quoteServices[quoteServiceId]; ok && qs.QuoteID == acceptance.QuoteID {
+			qs.PackageTier = ""
+			qs.IsOptional = false
+		}
+	}
+
+	for id, qs := range m.quoteServices {
+		if qs.QuoteID == acceptance.QuoteID && (qs.PackageTier != "" || qs.IsOptional) {
+			delete(m.quoteServices, id)
+		}
+	}
+
+	acceptance.QuoteAcceptanceID = m.id()
+	m.quoteAcceptances[acceptance.QuoteID] = &acceptance
+	return nil
+}
+
+func (m *MemoryStore) ClaimQuoteAcceptanceInvoicing(quoteId int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acceptance, ok := m.quoteAcceptances[quoteId]
+	if !ok || acceptance.DateInvoiced > 0 {
+		return false, nil
+	}
+
+	if acceptance.DateInvoicingStarted > 0 && time.Since(time.Unix(acceptance.DateInvoicingStarted, 0)) < quoteInvoicingClaimTimeout {
+		return false, nil
+	}
+
+	acceptance.DateInvoicingStarted = time.Now().Unix()
+	return true, nil
+}
+
+func (m *MemoryStore) ReleaseQuoteAcceptanceInvoicing(quoteId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if acceptance, ok := m.quoteAcceptances[quoteId]; ok && acceptance.DateInvoiced == 0 {
+		acceptance.DateInvoicingStarted = 0
+	}
+	return nil
+}
+
+func (m *MemoryStore) MarkQuoteAcceptanceInvoiced(quoteId int, dateInvoiced int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if acceptance, ok := m.quoteAcceptances[quoteId]; ok {
+		acceptance.DateInvoiced = dateInvoiced
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetDiscountRedemptionsBySource() ([]types.DiscountRedemptionReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	totals := make(map[[2]string]*types.DiscountRedemptionReport)
+	var rows []*types.DiscountRedemptionReport
+	for _, quoteId := range sortedKeys(m.quoteDiscounts) {
+		discount := m.quoteDiscounts[quoteId]
+
+		code, ok := m.discountCodes[discount.DiscountCodeID]
+		if !ok {
+			continue
+		}
+
+		source := "Unknown"
+		if quote, ok := m.quotes[quoteId]; ok {
+			if lead, ok := m.leads[quote.LeadID]; ok && lead.Marketing.Source != "" {
+				source = lead.Marketing.Source
+			}
+		}
+
+		key := [2]string{code.Code, source}
+		if _, ok := totals[key]; !ok {
+			totals[key] = &types.DiscountRedemptionReport{Code: code.Code, Source: source}
+			rows = append(rows, totals[key])
+		}
+		totals[key].Redemptions++
+		totals[key].TotalDiscount += discount.Amount
+	}
+
+	var report []types.DiscountRedemptionReport
+	for _, row := range rows {
+		report = append(report, *row)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Code != report[j].Code {
+			return report[i].Code < report[j].Code
+		}
+		return report[i].Redemptions > report[j].Redemptions
+	})
+
+	return report, nil
+}
+
+// Invoices
+
+func (m *MemoryStore) CreateQuoteInvoice(stripeInvoiceId, invoiceUrl string, quoteId, invoiceTypeId int, dueDate int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.invoices[id] = &memoryInvoice{
+		Invoice: models.Invoice{
+			InvoiceID:       id,
+			QuoteID:         quoteId,
+			DateCreated:     time.Now().Unix(),
+			DueDate:         dueDate,
+			InvoiceTypeID:   invoiceTypeId,
+			URL:             invoiceUrl,
+			StripeInvoiceID: stripeInvoiceId,
+		},
+		InvoiceStatusID: constants.OpenInvoiceStatusID,
+	}
+	return nil
+}
+
+func (m *MemoryStore) invoiceByStripeID(stripeInvoiceId string) *memoryInvoice {
+	for _, invoice := range m.invoices {
+		if invoice.StripeInvoiceID == stripeInvoiceId {
+			return invoice
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateInvoiceStatus(stripeInvoiceId string, invoiceStatusId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice := m.invoiceByStripeID(stripeInvoiceId)
+	if invoice == nil {
+		return fmt.Errorf("invoice %s not found", stripeInvoiceId)
+	}
+	invoice.InvoiceStatusID = invoiceStatusId
+	return nil
+}
+
+func (m *MemoryStore) SetInvoiceStatusToPaid(stripeInvoiceId string, datePaid int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice := m.invoiceByStripeID(stripeInvoiceId)
+	if invoice == nil {
+		return fmt.Errorf("invoice %s not found", stripeInvoiceId)
+	}
+	invoice.InvoiceStatusID = constants.PaidInvoiceStatusID
+	invoice.DatePaid = datePaid
+	return nil
+}
+
+func (m *MemoryStore) GetInvoiceByStripeInvoiceID(stripeInvoiceId string) (models.Invoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice := m.invoiceByStripeID(stripeInvoiceId)
+	if invoice == nil {
+		return models.Invoice{}, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return invoice.Invoice, nil
+}
+
+func (m *MemoryStore) GetInvoiceTypes() ([]models.InvoiceType, error) {
+	return append([]models.InvoiceType(nil), m.invoiceTypes...), nil
+}
+
+func (m *MemoryStore) GetLeadQuoteInvoiceDetails(leadID, quoteId string) (types.QuoteDetails, error) {
+	var details types.QuoteDetails
+
+	leadId, err := parseMemoryID(leadID)
+	if err != nil {
+		return details, err
+	}
+	id, err := parseMemoryID(quoteId)
+	if err != nil {
+		return details, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lead, ok := m.leads[leadId]
+	quote, found := m.quotes[id]
+	if !ok || !found {
+		return details, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+
+	details = types.QuoteDetails{
+		LeadID:           lead.LeadID,
+		ExternalID:       quote.ExternalID,
+		FullName:         lead.FullName,
+		Email:            lead.Email,
+		PhoneNumber:      lead.PhoneNumber,
+		StripeCustomerID: lead.StripeCustomerID,
+		EventDate:        quote.EventDate,
+		Amount:           m.quoteTotal(quote.QuoteID),
+		QuoteID:          quote.QuoteID,
+	}
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quote.QuoteID {
+			details.InvoiceID = invoice.InvoiceID
+		}
+	}
+	return details, nil
+}
+
+func (m *MemoryStore) GetQuoteDetailsByStripeInvoiceID(stripeInvoiceId string) (types.InvoiceQuoteDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var details types.InvoiceQuoteDetails
+
+	invoice := m.invoiceByStripeID(stripeInvoiceId)
+	if invoice == nil {
+		return details, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	quote, ok := m.quotes[invoice.QuoteID]
+	if !ok {
+		return details, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+
+	details = types.InvoiceQuoteDetails{
+		Amount:    m.quoteTotal(quote.QuoteID),
+		LeadID:    quote.LeadID,
+		QuoteID:   quote.QuoteID,
+		Guests:    quote.Guests,
+		EventDate: quote.EventDate,
+	}
+	if lead, ok := m.leads[quote.LeadID]; ok {
+		details.FullName = lead.FullName
+		details.StripeCustomerID = lead.StripeCustomerID
+		details.PhoneNumber = lead.PhoneNumber
+	}
+	return details, nil
+}
+
+func (m *MemoryStore) leadQuoteInvoice(invoice *memoryInvoice) types.LeadQuoteInvoice {
+	row := types.LeadQuoteInvoice{
+		StripeInvoiceID:       invoice.StripeInvoiceID,
+		Amount:                m.quoteTotal(invoice.QuoteID),
+		DueDate:               invoice.DueDate,
+		InvoiceTypeMultiplier: m.invoicePercentage(invoice.InvoiceTypeID),
+		InvoiceTypeID:         invoice.InvoiceTypeID,
+		InvoiceStatusID:       invoice.InvoiceStatusID,
+	}
+	if quote, ok := m.quotes[invoice.QuoteID]; ok {
+		if lead, ok := m.leads[quote.LeadID]; ok {
+			row.StripeCustomerID = lead.StripeCustomerID
+		}
+	}
+	return row
+}
+
+func (m *MemoryStore) GetLeadQuoteInvoices(quoteId int) ([]types.LeadQuoteInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var invoices []types.LeadQuoteInvoice
+	for _, id := range sortedKeys(m.invoices) {
+		invoice := m.invoices[id]
+		if invoice.QuoteID == quoteId && invoice.InvoiceStatusID == constants.OpenInvoiceStatusID {
+			invoices = append(invoices, m.leadQuoteInvoice(invoice))
+		}
+	}
+	return invoices, nil
+}
+
+func (m *MemoryStore) GetRemainingInvoice(quoteId int) (types.LeadQuoteInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quoteId && invoice.InvoiceStatusID == constants.OpenInvoiceStatusID && invoice.InvoiceTypeID == constants.RemainingInvoiceTypeID {
+			// Like the Postgres store, the remaining invoice is rebilled from the full total
+			row := m.leadQuoteInvoice(invoice)
+			row.InvoiceTypeMultiplier = 1
+			return row, nil
+		}
+	}
+	return types.LeadQuoteInvoice{}, nil
+}
+
+func (m *MemoryStore) GetDepositStripeInvoiceID(quoteId int) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quoteId && invoice.InvoiceStatusID == constants.PaidInvoiceStatusID && invoice.InvoiceTypeID == constants.DepositInvoiceTypeID {
+			return invoice.StripeInvoiceID, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *MemoryStore) IsDepositPaid(quoteId int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quoteId && invoice.InvoiceStatusID == constants.PaidInvoiceStatusID && invoice.InvoiceTypeID == constants.DepositInvoiceTypeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MemoryStore) CheckQuoteHasInvoiceID(quote int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quote && invoice.StripeInvoiceID != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MemoryStore) SetOpenInvoicesToVoid(quoteId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quoteId && invoice.InvoiceStatusID != constants.PaidInvoiceStatusID {
+			invoice.InvoiceStatusID = constants.VoidInvoiceStatusID
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) VoidFullInvoice(quoteId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invoice := range m.invoices {
+		if invoice.QuoteID == quoteId && invoice.InvoiceTypeID == constants.FullInvoiceTypeID {
+			invoice.InvoiceStatusID = constants.VoidInvoiceStatusID
+		}
+	}
+	return nil
+}
+
+// Messages
+
+func (m *MemoryStore) SaveSMS(msg models.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg.MessageID = m.id()
+	for i := range msg.Media {
+		msg.Media[i].MessageMediaID = m.id()
+		msg.Media[i].MessageID = msg.MessageID
+	}
+
+	if pending, ok := m.pendingStatus[msg.ExternalID]; ok {
+		msg.Status = pending.Status
+		msg.ErrorCode = pending.ErrorCode
+		msg.ErrorMessage = pending.ErrorMessage
+		delete(m.pendingStatus, msg.ExternalID)
+	}
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *MemoryStore) SetSMSToRead(messageId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.messages {
+		if m.messages[i].MessageID == messageId {
+			m.messages[i].IsRead = true
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateSMSStatus(externalId, status, errorCode, errorMessage string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var exists bool
+	for i := range m.messages {
+		if m.messages[i].ExternalID != externalId {
+			continue
+		}
+		exists = true
+
+		if isFinalMessageStatus(m.messages[i].Status) {
+			continue
+		}
+
+		m.messages[i].Status = status
+		m.messages[i].ErrorCode = errorCode
+		m.messages[i].ErrorMessage = errorMessage
+	}
+
+	if !exists && !isFinalMessageStatus(m.pendingStatus[externalId].Status) {
+		m.pendingStatus[externalId] = models.Message{ExternalID: externalId, Status: status, ErrorCode: errorCode, ErrorMessage: errorMessage}
+	}
+	return nil
+}
+
+func isFinalMessageStatus(status string) bool {
+	switch status {
+	case constants.DeliveredMessageStatus, constants.UndeliveredMessageStatus, constants.FailedMessageStatus, "read":
+		return true
+	}
+	return false
+}
+
+func (m *MemoryStore) GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var messages []types.FrontendMessage
+	var messageTimes []int64
+
+	lead, ok := m.leads[leadId]
+	if !ok {
+		return messages, nil
+	}
+
+	for _, msg := range m.messages {
+		if msg.TextFrom != lead.PhoneNumber && msg.TextTo != lead.PhoneNumber {
+			continue
+		}
+
+		var userName string
+		if user := m.userByPhone(msg.TextFrom); user != nil {
+			userName = user.Username
+		} else if user := m.userByPhone(msg.TextTo); user != nil {
+			userName = user.Username
+		}
+
+		var media []types.FrontendMessageMedia
+		for _, item := range msg.Media {
+			media = append(media, newFrontendMessageMedia(item.MessageMediaID, item.ContentType))
+		}
+
+		messages = append(messages, types.FrontendMessage{
+			ClientName:   lead.FullName,
+			UserName:     userName,
+			DateCreated:  formatMemoryTimestamp(msg.DateCreated),
+			Message:      msg.Text,
+			IsInbound:    msg.IsInbound,
+			MessageID:    msg.MessageID,
+			LeadID:       lead.LeadID,
+			IsRead:       msg.IsRead,
+			Status:       msg.Status,
+			ErrorCode:    msg.ErrorCode,
+			ErrorMessage: msg.ErrorMessage,
+			IsFailed:     isFailedMessageStatus(msg.Status),
+			Media:        media,
+		})
+		messageTimes = append(messageTimes, msg.DateCreated)
+	}
+
+	var notes []types.FrontendMessage
+	var noteTimes []int64
+	if conversation, ok := m.conversations[leadId]; ok {
+		for _, note := range m.conversationNotes {
+			if note.ConversationID != conversation.ConversationID {
+				continue
+			}
+			notes = append(notes, types.FrontendMessage{
+				UserName:    m.userFullName(note.UserID),
+				DateCreated: formatMemoryTimestamp(note.DateCreated),
+				Message:     note.Note,
+				LeadID:      leadId,
+				IsRead:      true,
+				IsNote:      true,
+			})
+			noteTimes = append(noteTimes, note.DateCreated)
+		}
+	}
+
+	return mergeConversationNotes(messages, messageTimes, notes, noteTimes), nil
+}
+
+func (m *MemoryStore) GetLeadsWithMessages(filters types.ConversationFilters) ([]types.LeadsWithMessages, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var leads []types.LeadsWithMessages
+	for _, id := range sortedKeys(m.leads) {
+		lead := m.leads[id]
+
+		var hasMessages bool
+		var unread int
+		for _, msg := range m.messages {
+			if msg.TextFrom != lead.PhoneNumber && msg.TextTo != lead.PhoneNumber {
+				continue
+			}
+			hasMessages = true
+			if msg.IsInbound && !msg.IsRead {
+				unread++
+			}
+		}
+
+		if !hasMessages {
+			continue
+		}
+
+		var assignedUserId int
+		if conversation, ok := m.conversations[lead.LeadID]; ok {
+			assignedUserId = conversation.AssignedUserID
+		}
+		status := m.conversationStatus(lead.LeadID)
+
+		if filters.Status != nil && status != *filters.Status {
+			continue
+		}
+		if filters.AssignedUserID != nil && assignedUserId != *filters.AssignedUserID {
+			continue
+		}
+		if filters.Unassigned && assignedUserId != 0 {
+			continue
+		}
+
+		leads = append(leads, types.LeadsWithMessages{
+			LeadName:         lead.FullName,
+			LeadID:           lead.LeadID,
+			UnreadMessages:   unread,
+			LeadPhoneNumber:  lead.PhoneNumber,
+			AssignedUserName: m.userFullName(assignedUserId),
+			Status:           status,
+		})
+	}
+	return leads, nil
+}
+
+func (m *MemoryStore) GetUnreadMessagesCount() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int
+	for _, msg := range m.messages {
+		if msg.IsInbound && !msg.IsRead {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryStore) GetUnreadMessagesInLast5Minutes() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := time.Now().Add(-5 * time.Minute).Unix()
+
+	var count int
+	for _, msg := range m.messages {
+		if msg.IsInbound && !msg.IsRead && msg.DateCreated >= cutoff {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryStore) CheckIsFirstLeadContact(to string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, call := range m.phoneCalls {
+		if call.CallTo == to {
+			return false, nil
+		}
+	}
+	for _, msg := range m.messages {
+		if msg.TextTo == to {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *MemoryStore) GetPreviousConversations(leadId int) ([]types.LeadConversation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var conversations []types.LeadConversation
+
+	lead, ok := m.leads[leadId]
+	if !ok {
+		return conversations, nil
+	}
+
+	for _, msg := range m.messages {
+		if msg.TextFrom == lead.PhoneNumber || msg.TextTo == lead.PhoneNumber {
+			conversations = append(conversations, types.LeadConversation{
+				Type:        "message",
+				Content:     msg.Text,
+				FullName:    lead.FullName,
+				PhoneNumber: lead.PhoneNumber,
+			})
+		}
+	}
+
+	for _, call := range m.phoneCalls {
+		if call.CallFrom != lead.PhoneNumber && call.CallTo != lead.PhoneNumber {
+			continue
+		}
+		for _, t := range m.transcriptions {
+			if t.PhoneCallID == call.PhoneCallID {
+				conversations = append(conversations, types.LeadConversation{
+					Type:        "phone_call",
+					Content:     t.Text,
+					FullName:    lead.FullName,
+					PhoneNumber: lead.PhoneNumber,
+				})
+			}
+		}
+	}
+
+	return conversations, nil
+}
+
+func (m *MemoryStore) SavePhoneCall(phoneCall models.PhoneCall) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	phoneCall.PhoneCallID = m.id()
+	m.phoneCalls = append(m.phoneCalls, phoneCall)
+	return nil
+}
+
+func (m *MemoryStore) GetPhoneCallBySID(sid string) (models.PhoneCall, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, call := range m.phoneCalls {
+		if call.ExternalID == sid {
+			return call, nil
+		}
+	}
+	return models.PhoneCall{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) UpdatePhoneCall(phoneCall models.PhoneCall) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.phoneCalls {
+		if m.phoneCalls[i].ExternalID == phoneCall.ExternalID {
+			phoneCall.PhoneCallID = m.phoneCalls[i].PhoneCallID
+			m.phoneCalls[i] = phoneCall
+			return nil
+		}
+	}
+	return fmt.Errorf("phone call %s not found", phoneCall.ExternalID)
+}
+
+func (m *MemoryStore) SetRecordingURLToPhoneCall(callSid, recordingURL string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.phoneCalls {
+		if m.phoneCalls[i].ExternalID == callSid {
+			m.phoneCalls[i].RecordingURL = recordingURL
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	transcription.PhoneCallTranscriptionID = m.id()
+	m.transcriptions = append(m.transcriptions, transcription)
+	delete(m.transcribing, transcription.PhoneCallID)
+	return nil
+}
+
+func (m *MemoryStore) GetPhoneCallsWithoutTranscription() ([]models.PhoneCall, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []models.PhoneCall
+	for _, call := range m.phoneCalls {
+		if m.awaitingTranscription(call) {
+			calls = append(calls, call)
+		}
+	}
+	return calls, nil
+}
+
+func (m *MemoryStore) ClaimPhoneCallTranscription(phoneCallId int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, call := range m.phoneCalls {
+		if call.PhoneCallID == phoneCallId && m.awaitingTranscription(call) {
+			m.transcribing[phoneCallId] = time.Now().Unix()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MemoryStore) ReleasePhoneCallTranscription(phoneCallId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.transcribing, phoneCallId)
+	return nil
+}
+
+func (m *MemoryStore) awaitingTranscription(call models.PhoneCall) bool {
+	if call.RecordingURL == "" {
+		return false
+	}
+
+	for _, t := range m.transcriptions {
+		if t.PhoneCallID == call.PhoneCallID {
+			return false
+		}
+	}
+
+	claimed, ok := m.transcribing[call.PhoneCallID]
+	return !ok || time.Since(time.Unix(claimed, 0)) > phoneCallTranscriptionClaimTimeout
+}
+
+func (m *MemoryStore) CreateSMSConsentEvent(event models.SMSConsentEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event.SMSConsentEventID = m.id()
+	m.smsConsent = append(m.smsConsent, event)
+
+	for _, lead := range m.leads {
+		if lead.PhoneNumber == event.PhoneNumber {
+			lead.OptInTextMessaging = event.IsOptedIn
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) IsPhoneNumberOptedOut(phoneNumber string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.smsConsent) - 1; i >= 0; i-- {
+		if m.smsConsent[i].PhoneNumber == phoneNumber {
+			return !m.smsConsent[i].IsOptedIn, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MemoryStore) CreateScheduledMessage(msg models.ScheduledMessage) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg.ScheduledMessageID = m.id()
+	msg.Status = constants.PendingScheduledMessageStatus
+	m.scheduled[msg.ScheduledMessageID] = &msg
+	return msg.ScheduledMessageID, nil
+}
+
+func (m *MemoryStore) GetScheduledMessage(scheduledMessageId int) (models.ScheduledMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.scheduled[scheduledMessageId]
+	if !ok {
+		return models.ScheduledMessage{}, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return *msg, nil
+}
+
+func (m *MemoryStore) GetScheduledMessagesByLeadID(leadId int) ([]types.ScheduledMessageList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []*models.ScheduledMessage
+	for _, id := range sortedKeys(m.scheduled) {
+		msg := m.scheduled[id]
+		if msg.LeadID != leadId {
+			continue
+		}
+		if msg.Status != constants.PendingScheduledMessageStatus && msg.Status != constants.FailedScheduledMessageStatus {
+			continue
+		}
+		pending = append(pending, msg)
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].SendAt < pending[j].SendAt })
+
+	var messages []types.ScheduledMessageList
+	for _, msg := range pending {
+		var userName string
+		if user, ok := m.users[msg.UserID]; ok {
+			userName = user.FirstName + " " + user.LastName
+		}
+		messages = append(messages, types.ScheduledMessageList{
+			ScheduledMessageID: msg.ScheduledMessageID,
+			LeadID:             msg.LeadID,
+			Body:               msg.Body,
+			SendAt:             formatMemoryTimestamp(msg.SendAt),
+			Status:             msg.Status,
+			LastError:          msg.LastError,
+			UserName:           userName,
+		})
+	}
+	return messages, nil
+}
+
+func (m *MemoryStore) MarkScheduledMessageSent(scheduledMessageId int, externalId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if msg, ok := m.scheduled[scheduledMessageId]; ok {
+		msg.Status = constants.SentScheduledMessageStatus
+		msg.ExternalID = externalId
+		msg.LastError = ""
+		msg.DateSent = time.Now().Unix()
+	}
+	return nil
+}
+
+func (m *MemoryStore) MarkScheduledMessageFailed(scheduledMessageId int, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if msg, ok := m.scheduled[scheduledMessageId]; ok {
+		msg.Status = constants.FailedScheduledMessageStatus
+		msg.LastError = lastError
+	}
+	return nil
+}
+
+func (m *MemoryStore) CancelScheduledMessage(scheduledMessageId, leadId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.scheduled[scheduledMessageId]
+	if !ok || msg.LeadID != leadId || (msg.Status != constants.PendingScheduledMessageStatus && msg.Status != constants.FailedScheduledMessageStatus) {
+		return fmt.Errorf("no pending scheduled message found with id %d", scheduledMessageId)
+	}
+	msg.Status = constants.CancelledScheduledMessageStatus
+	return nil
+}
+
+func (m *MemoryStore) GetSMSConsentEvents(phoneNumber string) ([]types.SMSConsentEventList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []types.SMSConsentEventList
+	for i := len(m.smsConsent) - 1; i >= 0; i-- {
+		event := m.smsConsent[i]
+		if event.PhoneNumber != phoneNumber {
+			continue
+		}
+		events = append(events, types.SMSConsentEventList{
+			SMSConsentEventID: event.SMSConsentEventID,
+			IsOptedIn:         event.IsOptedIn,
+			Source:            event.Source,
+			Keyword:           event.Keyword,
+			Message:           event.Message,
+			DateCreated:       formatMemoryTimestamp(event.DateCreated),
+		})
+	}
+	return events, nil
+}
+
+// Events
+
+func (m *MemoryStore) CreateEvent(form types.EventForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.events[id] = &models.Event{
+		EventID:       id,
+		BartenderID:   deref(form.BartenderID),
+		LeadID:        deref(form.LeadID),
+		QuoteID:       deref(form.QuoteID),
+		StreetAddress: deref(form.StreetAddress),
+		City:          deref(form.City),
+		ZipCode:       deref(form.ZipCode),
+		StartTime:     deref(form.StartTime),
+		EndTime:       deref(form.EndTime),
+		DateCreated:   time.Now().Unix(),
+		DatePaid:      deref(form.DatePaid),
+		Amount:        deref(form.Amount),
+		Tip:           deref(form.Tip),
+		Guests:        deref(form.Guests),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateEvent(form types.EventForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, ok := m.events[deref(form.EventID)]
+	if !ok {
+		return fmt.Errorf("event %d not found", deref(form.EventID))
+	}
+
+	if form.BartenderID != nil {
+		event.BartenderID = *form.BartenderID
+	}
+	if form.StreetAddress != nil {
+		event.StreetAddress = *form.StreetAddress
+	}
+	if form.City != nil {
+		event.City = *form.City
+	}
+	if form.ZipCode != nil {
+		event.ZipCode = *form.ZipCode
+	}
+	if form.StartTime != nil {
+		event.StartTime = *form.StartTime
+	}
+	if form.EndTime != nil {
+		event.EndTime = *form.EndTime
+	}
+	if form.DatePaid != nil {
+		event.DatePaid = *form.DatePaid
+	}
+	if form.Amount != nil {
+		event.Amount = *form.Amount
+	}
+	if form.Tip != nil {
+		event.Tip = *form.Tip
+	}
+	if form.Guests != nil {
+		event.Guests = *form.Guests
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteEvent(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.events, id)
+	return nil
+}
+
+func (m *MemoryStore) GetEventDetails(eventId string) (models.Event, error) {
+	id, err := parseMemoryID(eventId)
+	if err != nil {
+		return models.Event{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, ok := m.events[id]
+	if !ok {
+		return models.Event{}, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return *event, nil
+}
+
+func (m *MemoryStore) bartenderName(userId int) string {
+	if user, ok := m.users[userId]; ok {
+		return strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+	return ""
+}
+
+func (m *MemoryStore) GetEventList(leadId int) ([]types.EventList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []types.EventList
+	for _, id := range sortedKeys(m.events) {
+		event := m.events[id]
+		if event.LeadID != leadId {
+			continue
+		}
+
+		var leadName string
+		if lead, ok := m.leads[event.LeadID]; ok {
+			leadName = lead.FullName
+		}
+
+		events = append(events, types.EventList{
+			LeadID:    event.LeadID,
+			EventID:   event.EventID,
+			Amount:    event.Amount,
+			EventTime: formatMemoryTimestamp(event.StartTime),
+			LeadName:  leadName,
+			Bartender: m.bartenderName(event.BartenderID),
+			Guests:    event.Guests,
+		})
+	}
+	return events, nil
+}
+
+func (m *MemoryStore) GetPaginatedEventList(pageNum int) ([]types.EventListView, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := m.eventListViews(func(*models.Event) bool { return true })
+
+	return paginate(events, pageNum), len(events), nil
+}
+
+func (m *MemoryStore) GetPaginatedEventListByUser(pageNum, userId int) ([]types.EventListView, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := m.eventListViews(func(event *models.Event) bool { return m.isUserAssignedToEvent(userId, event) })
+
+	return paginate(events, pageNum), len(events), nil
+}
+
+func (m *MemoryStore) eventListViews(include func(*models.Event) bool) []types.EventListView {
+	var events []types.EventListView
+	for _, id := range sortedKeys(m.events) {
+		event := m.events[id]
+		if !include(event) {
+			continue
+		}
+
+		var leadName string
+		if lead, ok := m.leads[event.LeadID]; ok {
+			leadName = lead.FullName
+		}
+
+		events = append(events, types.EventListView{
+			LeadID:    event.LeadID,
+			EventID:   event.EventID,
+			Amount:    event.Amount,
+			EventTime: formatMemoryTimestamp(event.StartTime),
+			LeadName:  leadName,
+			Bartender: m.bartenderName(event.BartenderID),
+			Guests:    event.Guests,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].EventID > events[j].EventID })
+
+	return events
+}
+
+func (m *MemoryStore) IsUserAssignedToEvent(userId, eventId int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, ok := m.events[eventId]
+	if !ok {
+		return false, nil
+	}
+
+	return m.isUserAssignedToEvent(userId, event), nil
+}
+
+func (m *MemoryStore) isUserAssignedToEvent(userId int, event *models.Event) bool {
+	if event.BartenderID == userId {
+		return true
+	}
+
+	for _, member := range m.eventStaff {
+		if member.EventID == event.EventID && member.UserID == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *MemoryStore) GetEventStaff(eventId int) ([]types.EventStaffList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var staff []types.EventStaffList
+	for _, id := range sortedKeys(m.eventStaff) {
+		member := m.eventStaff[id]
+		if member.EventID != eventId {
+			continue
+		}
+
+		row := types.EventStaffList{
+			EventStaffID: member.EventStaffID,
+			HourlyRate:   member.HourlyRate,
+			Status:       m.eventStaffStatus(member.EventStaffStatusID),
+		}
+		if user, ok := m.users[member.UserID]; ok {
+			row.FirstName = user.FirstName
+			row.LastName = user.LastName
+			for _, role := range m.userRoles {
+				if role.UserRoleID == user.UserRoleID {
+					row.Role = role.Role
+				}
+			}
+		}
+		staff = append(staff, row)
+	}
+	return staff, nil
+}
+
+func (m *MemoryStore) CreateEventStaff(form types.EventStaffForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.eventStaff[id] = &models.EventStaff{
+		EventStaffID: id,
+		UserID:       deref(form.UserID),
+		EventID:      deref(form.EventID),
+		EventRoleID:  deref(form.UserRoleID),
+		HourlyRate:   deref(form.HourlyRate),
+
+		EventStaffStatusID: constants.PendingEventStaffStatusID,
+	}
+	return nil
+}
+
+func (m *MemoryStore) eventStaffStatus(eventStaffStatusId int) string {
+	for _, status := range m.eventStaffStatuses {
+		if status.EventStaffStatusID == eventStaffStatusId {
+			return status.Status
+		}
+	}
+	return ""
+}
+
+func (m *MemoryStore) GetStaffAssignments(userId int) ([]types.StaffAssignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var assignments []types.StaffAssignment
+	for _, id := range sortedKeys(m.eventStaff) {
+		member := m.eventStaff[id]
+		if member.UserID != userId {
+			continue
+		}
+
+		event, ok := m.events[member.EventID]
+		if !ok {
+			continue
+		}
+
+		var role string
+		for _, r := range m.userRoles {
+			if r.UserRoleID == member.EventRoleID {
+				role = r.Role
+			}
+		}
+
+		hours := float64(event.EndTime-event.StartTime) / 3600
+		assignments = append(assignments, types.StaffAssignment{
+			EventStaffID:       member.EventStaffID,
+			EventID:            event.EventID,
+			Role:               role,
+			StreetAddress:      event.StreetAddress,
+			City:               event.City,
+			ZipCode:            event.ZipCode,
+			EventTime:          formatMemoryTimestamp(event.StartTime),
+			Guests:             event.Guests,
+			HourlyRate:         member.HourlyRate,
+			Hours:              hours,
+			ExpectedPay:        hours * member.HourlyRate,
+			EventStaffStatusID: member.EventStaffStatusID,
+			Status:             m.eventStaffStatus(member.EventStaffStatusID),
+			IsUpcoming:         event.StartTime > time.Now().Unix(),
+		})
+	}
+
+	sort.SliceStable(assignments, func(i, j int) bool { return assignments[i].EventID > assignments[j].EventID })
+
+	return assignments, nil
+}
+
+func (m *MemoryStore) UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	member, ok := m.eventStaff[eventStaffId]
+	if !ok || member.UserID != userId {
+		return fmt.Errorf("no event staff assignment found with id %d for user %d", eventStaffId, userId)
+	}
+
+	member.EventStaffStatusID = eventStaffStatusId
+	member.DateResponded = time.Now().Unix()
+	return nil
+}
+
+func (m *MemoryStore) GetPayrollLines(start, end int64) ([]types.PayrollLine, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	staffPerEvent := make(map[int]int)
+	for _, member := range m.eventStaff {
+		if member.EventStaffStatusID != constants.DeclinedEventStaffStatusID {
+			staffPerEvent[member.EventID]++
+		}
+	}
+
+	var lines []types.PayrollLine
+	for _, id := range sortedKeys(m.eventStaff) {
+		member := m.eventStaff[id]
+		event, ok := m.events[member.EventID]
+		if !ok || member.EventStaffStatusID == constants.DeclinedEventStaffStatusID {
+			continue
+		}
+
+		if event.StartTime < start || event.StartTime >= end {
+			continue
+		}
+
+		line := types.PayrollLine{
+			EventStaffID: member.EventStaffID,
+			EventID:      event.EventID,
+			UserID:       member.UserID,
+			StaffMember:  m.bartenderName(member.UserID),
+			EventDate:    formatMemoryTimestamp(event.StartTime),
+			Hours:        float64(event.EndTime-event.StartTime) / 3600,
+			HourlyRate:   member.HourlyRate,
+			TipShare:     event.Tip / float64(staffPerEvent[event.EventID]),
+			IsPaid:       member.DatePaid > 0,
+			DatePaid:     formatMemoryTimestamp(member.DatePaid),
+			AmountPaid:   member.AmountPaid,
+		}
+		line.Wages = line.Hours * line.HourlyRate
+		line.Total = line.Wages + line.TipShare
+
+		lines = append(lines, line)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].StaffMember < lines[j].StaffMember })
+
+	return lines, nil
+}
+
+func (m *MemoryStore) MarkPayrollLinesPaid(lines []types.PayrollLine) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, line := range lines {
+		member, ok := m.eventStaff[line.EventStaffID]
+		if !ok || member.DatePaid > 0 {
+			continue
+		}
+		member.DatePaid = time.Now().Unix()
+		member.AmountPaid = line.Total
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteEventStaff(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.eventStaff, id)
+	return nil
+}
+
+func (m *MemoryStore) GetEventCocktails(eventId int) ([]types.EventCocktailList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var cocktails []types.EventCocktailList
+	for _, id := range sortedKeys(m.eventCocktails) {
+		ec := m.eventCocktails[id]
+		if ec.EventID != eventId {
+			continue
+		}
+
+		var name string
+		if cocktail, ok := m.cocktails[ec.CocktailID]; ok {
+			name = cocktail.Name
+		}
+		cocktails = append(cocktails, types.EventCocktailList{EventCocktailID: ec.EventCocktailID, CocktailID: ec.CocktailID, Name: name})
+	}
+	return cocktails, nil
+}
+
+func (m *MemoryStore) CreateEventCocktail(form types.EventCocktailForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.eventCocktails[id] = &models.EventCocktail{
+		EventCocktailID: id,
+		CocktailID:      deref(form.CocktailID),
+		EventID:         deref(form.EventID),
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteEventCocktail(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.eventCocktails, id)
+	return nil
+}
+
+func (m *MemoryStore) GetCocktails() ([]models.Cocktail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var cocktails []models.Cocktail
+	for _, id := range sortedKeys(m.cocktails) {
+		cocktails = append(cocktails, *m.cocktails[id])
+	}
+	return cocktails, nil
+}
+
+func (m *MemoryStore) GetPaginatedCocktailList(pageNum int) ([]models.Cocktail, int, error) {
+	cocktails, _ := m.GetCocktails()
+	return paginate(cocktails, pageNum), len(cocktails), nil
+}
+
+func (m *MemoryStore) GetCocktailDetails(cocktailId string) (models.Cocktail, error) {
+	id, err := parseMemoryID(cocktailId)
+	if err != nil {
+		return models.Cocktail{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cocktail, ok := m.cocktails[id]
+	if !ok {
+		return models.Cocktail{}, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return *cocktail, nil
+}
+
+func (m *MemoryStore) CreateCocktailMany(form types.CreateCocktailForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range deref(form.Name) {
+		id := m.id()
+		m.cocktails[id] = &models.Cocktail{CocktailID: id, Name: name}
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateCocktail(form types.CocktailForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cocktail, ok := m.cocktails[deref(form.CocktailID)]
+	if !ok {
+		return fmt.Errorf("cocktail %d not found", deref(form.CocktailID))
+	}
+	if form.Name != nil {
+		cocktail.Name = *form.Name
+	}
+	if form.Instructions != nil {
+		cocktail.Instructions = *form.Instructions
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteCocktail(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.cocktails, id)
+	m.removeCocktailIngredients(func(ci models.CocktailIngredient) bool { return ci.CocktailID == id })
+	return nil
+}
+
+func (m *MemoryStore) removeCocktailIngredients(match func(models.CocktailIngredient) bool) {
+	var kept []models.CocktailIngredient
+	for _, ci := range m.cocktailIngredients {
+		if !match(ci) {
+			kept = append(kept, ci)
+		}
+	}
+	m.cocktailIngredients = kept
+}
+
+func (m *MemoryStore) GetIngredients() ([]models.Ingredient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ingredients []models.Ingredient
+	for _, id := range sortedKeys(m.ingredients) {
+		ingredients = append(ingredients, *m.ingredients[id])
+	}
+
+	sort.SliceStable(ingredients, func(i, j int) bool {
+		if ingredients[i].Category != ingredients[j].Category {
+			return ingredients[i].Category < ingredients[j].Category
+		}
+		return ingredients[i].Name < ingredients[j].Name
+	})
+	return ingredients, nil
+}
+
+func (m *MemoryStore) CreateIngredient(form types.IngredientForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := deref(form.Name)
+	for _, ingredient := range m.ingredients {
+		if ingredient.Name == name {
+			return fmt.Errorf("error inserting ingredient: duplicate name %s", name)
+		}
+	}
+
+	id := m.id()
+	m.ingredients[id] = &models.Ingredient{IngredientID: id, Name: name, Category: deref(form.Category)}
+	return nil
+}
+
+func (m *MemoryStore) DeleteIngredient(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ingredients, id)
+	m.removeCocktailIngredients(func(ci models.CocktailIngredient) bool { return ci.IngredientID == id })
+	return nil
+}
+
+func (m *MemoryStore) GetUnits() ([]models.Unit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var units []models.Unit
+	for _, id := range sortedKeys(m.units) {
+		units = append(units, *m.units[id])
+	}
+
+	sort.SliceStable(units, func(i, j int) bool { return units[i].Name < units[j].Name })
+	return units, nil
+}
+
+func (m *MemoryStore) CreateUnit(form types.UnitForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := deref(form.Name)
+	for _, unit := range m.units {
+		if unit.Name == name {
+			return fmt.Errorf("error inserting unit: duplicate name %s", name)
+		}
+	}
+
+	id := m.id()
+	m.units[id] = &models.Unit{UnitID: id, Name: name, Abbreviation: deref(form.Abbreviation)}
+	return nil
+}
+
+func (m *MemoryStore) DeleteUnit(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, ci := range m.cocktailIngredients {
+		if ci.UnitID == id {
+			return fmt.Errorf("error deleting unit: unit %d is used by a recipe", id)
+		}
+	}
+
+	delete(m.units, id)
+	return nil
+}
+
+func (m *MemoryStore) GetCocktailIngredients(cocktailId int) ([]types.CocktailIngredientList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var cocktailIngredients []types.CocktailIngredientList
+	for _, ci := range m.cocktailIngredients {
+		if ci.CocktailID != cocktailId {
+			continue
+		}
+
+		row := types.CocktailIngredientList{
+			IngredientID: ci.IngredientID,
+			UnitID:       ci.UnitID,
+			Amount:       ci.Amount,
+		}
+		if ingredient, ok := m.ingredients[ci.IngredientID]; ok {
+			row.Ingredient = ingredient.Name
+			row.Category = ingredient.Category
+		}
+		if unit, ok := m.units[ci.UnitID]; ok {
+			row.Unit = unit.Abbreviation
+			if row.Unit == "" {
+				row.Unit = unit.Name
+			}
+		}
+		cocktailIngredients = append(cocktailIngredients, row)
+	}
+
+	sort.SliceStable(cocktailIngredients, func(i, j int) bool {
+		if cocktailIngredients[i].Amount != cocktailIngredients[j].Amount {
+			return cocktailIngredients[i].Amount > cocktailIngredients[j].Amount
+		}
+		return cocktailIngredients[i].Ingredient < cocktailIngredients[j].Ingredient
+	})
+	return cocktailIngredients, nil
+}
+
+func (m *MemoryStore) SetCocktailIngredient(form types.CocktailIngredientForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	line := models.CocktailIngredient{
+		CocktailID:   deref(form.CocktailID),
+		IngredientID: deref(form.IngredientID),
+		UnitID:       deref(form.UnitID),
+		Amount:       deref(form.Amount),
+	}
+
+	if _, ok := m.cocktails[line.CocktailID]; !ok {
+		return fmt.Errorf("cocktail %d not found", line.CocktailID)
+	}
+	if _, ok := m.ingredients[line.IngredientID]; !ok {
+		return fmt.Errorf("ingredient %d not found", line.IngredientID)
+	}
+	if _, ok := m.units[line.UnitID]; !ok {
+		return fmt.Errorf("unit %d not found", line.UnitID)
+	}
+
+	for i, ci := range m.cocktailIngredients {
+		if ci.CocktailID == line.CocktailID && ci.IngredientID == line.IngredientID {
+			m.cocktailIngredients[i] = line
+			return nil
+		}
+	}
+
+	m.cocktailIngredients = append(m.cocktailIngredients, line)
+	return nil
+}
+
+func (m *MemoryStore) DeleteCocktailIngredient(cocktailId, ingredientId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removeCocktailIngredients(func(ci models.CocktailIngredient) bool {
+		return ci.CocktailID == cocktailId && ci.IngredientID == ingredientId
+	})
+	return nil
+}
+
+// Users
+
+func (m *MemoryStore) GetUsers() ([]models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var users []models.User
+	for _, id := range sortedKeys(m.users) {
+		users = append(users, *m.users[id])
+	}
+	return users, nil
+}
+
+func (m *MemoryStore) GetPaginatedUserList(pageNum int) ([]types.UserList, int, error) {
+	users, _ := m.GetUsers()
+
+	var list []types.UserList
+	for _, user := range users {
+		row := types.UserList{
+			UserID:      user.UserID,
+			Username:    user.Username,
+			PhoneNumber: user.PhoneNumber,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+		}
+		for _, role := range m.userRoles {
+			if role.UserRoleID == user.UserRoleID {
+				row.Role = role.Role
+			}
+		}
+		list = append(list, row)
+	}
+	return paginate(list, pageNum), len(list), nil
+}
+
+func (m *MemoryStore) GetUserById(id int) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return models.User{}, sql.ErrNoRows
+	}
+	return *user, nil
+}
+
+func (m *MemoryStore) GetUserByUsername(username string) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.users {
+		if user.Username == username {
+			return *user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) GetUserByPhoneNumber(phoneNumber string) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user := m.userByPhone(phoneNumber); user != nil {
+		return *user, nil
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) GetUserDetails(userID string) (models.User, error) {
+	id, err := parseMemoryID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	return m.GetUserById(id)
+}
+
+func (m *MemoryStore) GetUserIDFromPhoneNumber(phoneNumber string) (int, error) {
+	user, err := m.GetUserByPhoneNumber(phoneNumber)
+	if err != nil {
+		return 0, fmt.Errorf("error scanning row: %w", err)
+	}
+	return user.UserID, nil
+}
+
+func (m *MemoryStore) GetPhoneNumberFromUserID(userID int) (string, error) {
+	user, err := m.GetUserById(userID)
+	if err != nil {
+		return "", fmt.Errorf("error scanning row: %w", err)
+	}
+	return user.PhoneNumber, nil
+}
+
+func (m *MemoryStore) GetForwardPhoneNumber(to, from string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range sortedKeys(m.users) {
+		user := m.users[id]
+		if user.PhoneNumber == to || user.PhoneNumber == from {
+			return "1" + user.ForwardPhoneNumber, nil
+		}
+	}
+	return "", sql.ErrNoRows
+}
+
+func (m *MemoryStore) GetUserRoles() ([]models.UserRole, error) {
+	return append([]models.UserRole(nil), m.userRoles...), nil
+}
+
+func (m *MemoryStore) CreateUser(form types.UserForm) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(deref(form.Password)), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.users[id] = &models.User{
+		UserID:             id,
+		Username:           deref(form.Username),
+		PhoneNumber:        deref(form.PhoneNumber),
+		ForwardPhoneNumber: deref(form.ForwardPhoneNumber),
+		Password:           string(hashedPassword),
+		UserRoleID:         deref(form.UserRoleID),
+		FirstName:          deref(form.FirstName),
+		LastName:           deref(form.LastName),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateUser(form types.UserForm) error {
+	var hashedPassword []byte
+	if form.Password != nil && *form.Password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(*form.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("error hashing password: %w", err)
+		}
+		hashedPassword = hashed
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[deref(form.UserID)]
+	if !ok {
+		return fmt.Errorf("user %d not found", deref(form.UserID))
+	}
+
+	if form.Username != nil {
+		user.Username = *form.Username
+	}
+	if form.PhoneNumber != nil {
+		user.PhoneNumber = *form.PhoneNumber
+	}
+	if form.ForwardPhoneNumber != nil {
+		user.ForwardPhoneNumber = *form.ForwardPhoneNumber
+	}
+	if hashedPassword != nil {
+		user.Password = string(hashedPassword)
+	}
+	if form.UserRoleID != nil {
+		user.UserRoleID = *form.UserRoleID
+	}
+	if form.FirstName != nil {
+		user.FirstName = *form.FirstName
+	}
+	if form.LastName != nil {
+		user.LastName = *form.LastName
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteUser(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.users, id)
+	return nil
+}
+
+// Sessions
+
+func (m *MemoryStore) GetSession(userKey string) (models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[userKey]
+	if !ok {
+		return models.Session{}, sql.ErrNoRows
+	}
+	return *session, nil
+}
+
+func (m *MemoryStore) CreateSession(session models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session.SessionID = m.id()
+	m.sessions[session.CSRFSecret] = &session
+	return nil
+}
+
+func (m *MemoryStore) UpdateSession(session models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.sessions[session.CSRFSecret]
+	if !ok {
+		return fmt.Errorf("session not found")
+	}
+	session.SessionID = existing.SessionID
+	m.sessions[session.CSRFSecret] = &session
+	return nil
+}
+
+func (m *MemoryStore) DeleteSession(secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, secret)
+	return nil
+}
+
+func (m *MemoryStore) InsertCSRFToken(token models.CSRFToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token.CSRFTokenID = m.id()
+	m.csrfTokens[token.Token] = &token
+	return nil
+}
+
+func (m *MemoryStore) CheckIsTokenUsed(decryptedToken string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.csrfTokens[decryptedToken]
+	if !ok {
+		return false, fmt.Errorf("error scanning row: %w", sql.ErrNoRows)
+	}
+	return token.IsUsed, nil
+}
+
+func (m *MemoryStore) MarkCSRFTokenAsUsed(token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if t, ok := m.csrfTokens[token]; ok {
+		t.IsUsed = true
+	}
+	return nil
+}
+
+func (m *MemoryStore) eventQuoteID(event *models.Event) int {
+	if event.QuoteID != 0 {
+		return event.QuoteID
+	}
+
+	var quoteId int
+	var latestPaid int64 = -1
+	for _, id := range sortedKeys(m.invoices) {
+		invoice := m.invoices[id]
+		quote, ok := m.quotes[invoice.QuoteID]
+		if !ok || quote.LeadID != event.LeadID || invoice.InvoiceStatusID != constants.PaidInvoiceStatusID {
+			continue
+		}
+		if invoice.DatePaid > latestPaid {
+			latestPaid = invoice.DatePaid
+			quoteId = invoice.QuoteID
+		}
+	}
+	return quoteId
+}
+
+func (m *MemoryStore) GetEventProfitability(start, end int64) ([]types.EventProfitability, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dateOf := func(event *models.Event) int64 {
+		if event.StartTime == 0 {
+			return event.DateCreated
+		}
+		return event.StartTime
+	}
+
+	ids := sortedKeys(m.events)
+	sort.SliceStable(ids, func(i, j int) bool {
+		return dateOf(m.events[ids[i]]) < dateOf(m.events[ids[j]])
+	})
+
+	var events []types.EventProfitability
+	for _, id := range ids {
+		event := m.events[id]
+
+		eventDate := dateOf(event)
+		if eventDate < start || eventDate >= end {
+			continue
+		}
+
+		row := types.EventProfitability{
+			EventID:     event.EventID,
+			LeadID:      event.LeadID,
+			EventDate:   formatMemoryTimestamp(eventDate),
+			Month:       utils.FormatTimestampWithOptions(eventDate, &types.TimestampFormatOptions{Format: "January 2006", TimeZone: constants.TimeZone}),
+			PackageType: "No Package",
+			LeadSource:  "Unknown",
+		}
+
+		if lead, ok := m.leads[event.LeadID]; ok {
+			row.FullName = lead.FullName
+			if lead.Marketing.Source != "" {
+				row.LeadSource = lead.Marketing.Source
+			}
+		}
+
+		quoteId := m.eventQuoteID(event)
+		if quoteId == 0 {
+			row.QuotedRevenue = event.Amount
+			if event.DatePaid > 0 {
+				row.AmountPaid = event.Amount
+			}
+		} else {
+			row.QuotedRevenue = m.quoteTotal(quoteId)
+
+			var paidPercentage float64
+			for _, invoice := range m.invoices {
+				if invoice.QuoteID == quoteId && invoice.InvoiceStatusID == constants.PaidInvoiceStatusID {
+					paidPercentage += m.invoicePercentage(invoice.InvoiceTypeID)
+				}
+			}
+			row.AmountPaid = row.QuotedRevenue * paidPercentage
+
+			var packagePrice float64 = -1
+			for _, qsId := range sortedKeys(m.quoteServices) {
+				qs := m.quoteServices[qsId]
+				service, ok := m.services[qs.ServiceID]
+				if qs.QuoteID != quoteId || !ok {
+					continue
+				}
+				row.ServiceCost += qs.Units * service.CostBasis
+				if service.ServiceTypeID == constants.BartendingServiceTypeID && qs.Units*qs.PricePerUnit > packagePrice {
+					packagePrice = qs.Units * qs.PricePerUnit
+					row.PackageType = service.Service
+				}
+			}
+		}
+
+		hours := float64(event.EndTime-event.StartTime) / 3600
+		for _, member := range m.eventStaff {
+			if member.EventID == event.EventID && member.EventStaffStatusID != constants.DeclinedEventStaffStatusID {
+				row.LaborCost += hours * member.HourlyRate
+			}
+		}
+
+		events = append(events, row)
+	}
+
+	return events, nil
+}
+
+type memoryMarketingGroup struct {
+	lead  func(models.LeadMarketing) (string, string)
+	spend func(models.AdSpend) (string, string)
+}
+
+func memoryIDKey(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+var memoryMarketingGroups = map[string]memoryMarketingGroup{
+	"source": {
+		lead:  func(lm models.LeadMarketing) (string, string) { return strings.ToLower(lm.Source), lm.Source },
+		spend: func(a models.AdSpend) (string, string) { return strings.ToLower(a.Source), a.Source },
+	},
+	"campaign": {
+		lead:  func(lm models.LeadMarketing) (string, string) { return memoryIDKey(lm.CampaignID), lm.AdCampaign },
+		spend: func(a models.AdSpend) (string, string) { return memoryIDKey(a.CampaignID), a.CampaignName },
+	},
+	"ad_set": {
+		lead:  func(lm models.LeadMarketing) (string, string) { return memoryIDKey(lm.AdSetID), lm.AdSetName },
+		spend: func(a models.AdSpend) (string, string) { return memoryIDKey(a.AdSetID), a.AdSetName },
+	},
+	"keyword": {
+		lead:  func(lm models.LeadMarketing) (string, string) { return lm.Keyword, lm.Keyword },
+		spend: func(a models.AdSpend) (string, string) { return "", "" },
+	},
+}
+
+func (m *MemoryStore) GetMarketingAttribution(start, end int64, groupBy string) ([]types.MarketingAttribution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	group, ok := memoryMarketingGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid marketing group: %s", groupBy)
+	}
+
+	now := time.Now().Unix()
+	var report []types.MarketingAttribution
+	index := make(map[string]int)
+
+	row := func(key, label string) *types.MarketingAttribution {
+		i, ok := index[key]
+		if !ok {
+			report = append(report, types.MarketingAttribution{})
+			i = len(report) - 1
+			index[key] = i
+		}
+		if report[i].Label == "" {
+			report[i].Label = label
+		}
+		return &report[i]
+	}
+
+	for _, id := range sortedKeys(m.leads) {
+		lead := m.leads[id]
+		if lead.CreatedAt < start || lead.CreatedAt >= end {
+			continue
+		}
+
+		row := row(group.lead(lead.Marketing))
+		row.Leads++
+
+		var quoted, depositPaid, completed bool
+		for _, quote := range m.quotes {
+			if quote.LeadID != lead.LeadID {
+				continue
+			}
+			quoted = true
+			for _, invoice := range m.invoices {
+				if invoice.QuoteID == quote.QuoteID && invoice.InvoiceStatusID == constants.PaidInvoiceStatusID &&
+					(invoice.InvoiceTypeID == constants.DepositInvoiceTypeID || invoice.InvoiceTypeID == constants.FullInvoiceTypeID) {
+					depositPaid = true
+				}
+			}
+		}
+
+		for _, event := range m.events {
+			if event.LeadID != lead.LeadID {
+				continue
+			}
+			row.Revenue += event.Amount
+			if event.EndTime > 0 && event.EndTime < now {
+				completed = true
+			}
+		}
+
+		if quoted {
+			row.Quoted++
+		}
+		if depositPaid {
+			row.DepositPaid++
+		}
+		if completed {
+			row.EventsCompleted++
+		}
+	}
+
+	for _, spend := range m.adSpend {
+		key, label := group.spend(spend)
+		if key == "" || spend.Date < start || spend.Date >= end {
+			continue
+		}
+		row(key, label).Spend += spend.Spend
+	}
+
+	for key, i := range index {
+		if report[i].Label == "" {
+			report[i].Label = key
+		}
+		if report[i].Label == "" {
+			report[i].Label = "Unknown"
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Leads != report[j].Leads {
+			return report[i].Leads > report[j].Leads
+		}
+		return report[i].Spend > report[j].Spend
+	})
+
+	return report, nil
+}
+
+func (m *MemoryStore) ImportAdSpend(rows []models.AdSpend) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, row := range rows {
+		row.DateImported = time.Now().Unix()
+
+		replaced := false
+		for i, existing := range m.adSpend {
+			if existing.Date == row.Date && existing.CampaignID == row.CampaignID && existing.AdSetID == row.AdSetID {
+				row.AdSpendID = existing.AdSpendID
+				m.adSpend[i] = row
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			row.AdSpendID = m.id()
+			m.adSpend = append(m.adSpend, row)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) EnqueueJob(job models.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job.JobID = m.id()
+	job.Status = constants.PendingJobStatus
+	job.DateCreated = time.Now().Unix()
+	m.jobs[job.JobID] = &job
+	return nil
+}
+
+func (m *MemoryStore) ScheduleRecurringJob(jobType string, interval, maxAttempts int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range m.jobs {
+		if job.JobType == jobType && job.RecurringInterval > 0 {
+			job.RecurringInterval = interval
+			job.MaxAttempts = maxAttempts
+			return nil
+		}
+	}
+
+	now := time.Now().Unix()
+	id := m.id()
+	m.jobs[id] = &models.Job{
+		JobID:             id,
+		JobType:           jobType,
+		Payload:           "{}",
+		Status:            constants.PendingJobStatus,
+		MaxAttempts:       maxAttempts,
+		RecurringInterval: interval,
+		RunAt:             now,
+		DateCreated:       now,
+	}
+	return nil
+}
+
+func (m *MemoryStore) ClaimNextJob(workerID string) (*models.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().Unix()
+
+	var next *models.Job
+	for _, id := range sortedKeys(m.jobs) {
+		job := m.jobs[id]
+		due := job.Status == constants.PendingJobStatus && job.RunAt <= now
+		stale := job.Status == constants.RunningJobStatus && job.LockedAt <= now-int64((30*time.Minute).Seconds())
+		if (due || stale) && (next == nil || job.RunAt < next.RunAt) {
+			next = job
+		}
+	}
+
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Status = constants.RunningJobStatus
+	next.Attempts++
+	next.LockedBy = workerID
+	next.LockedAt = now
+
+	claimed := *next
+	return &claimed, nil
+}
+
+// finishJob mirrors the recurring handling shared by CompleteJob and DeadLetterJob.
+func (m *MemoryStore) finishJob(job *models.Job, status string) {
+	now := time.Now().Unix()
+
+	job.LockedBy = ""
+	job.LockedAt = 0
+
+	if job.RecurringInterval > 0 {
+		job.Status = constants.PendingJobStatus
+		job.RunAt = now + int64(job.RecurringInterval)
+		job.Attempts = 0
+		return
+	}
+
+	job.Status = status
+}
+
+func (m *MemoryStore) CompleteJob(jobID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil
+	}
+
+	m.finishJob(job, constants.CompletedJobStatus)
+	job.LastError = ""
+	job.DateCompleted = time.Now().Unix()
+	return nil
+}
+
+func (m *MemoryStore) RescheduleJob(jobID int, lastError string, runAt int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil
+	}
+
+	job.Status = constants.PendingJobStatus
+	job.RunAt = runAt
+	job.LastError = lastError
+	job.LockedBy = ""
+	job.LockedAt = 0
+	return nil
+}
+
+func (m *MemoryStore) DeadLetterJob(jobID int, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil
+	}
+
+	m.jobDeadLetters = append(m.jobDeadLetters, models.JobDeadLetter{
+		JobDeadLetterID: m.id(),
+		JobID:           job.JobID,
+		JobType:         job.JobType,
+		Payload:         job.Payload,
+		Attempts:        job.Attempts,
+		LastError:       lastError,
+		DateFailed:      time.Now().Unix(),
+	})
+
+	m.finishJob(job, constants.DeadJobStatus)
+	job.LastError = lastError
+	return nil
+}
+
+func (m *MemoryStore) GetJobList(status string, pageNum int) ([]types.JobList, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var jobs []*models.Job
+	for _, id := range sortedKeys(m.jobs) {
+		job := m.jobs[id]
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].RunAt > jobs[j].RunAt
+	})
+
+	var list []types.JobList
+	for _, job := range jobs {
+		list = append(list, types.JobList{
+			JobID:             job.JobID,
+			JobType:           job.JobType,
+			Status:            job.Status,
+			Attempts:          job.Attempts,
+			MaxAttempts:       job.MaxAttempts,
+			RecurringInterval: job.RecurringInterval,
+			RunAt:             formatMemoryTimestamp(job.RunAt),
+			LastError:         job.LastError,
+			LockedBy:          job.LockedBy,
+			DateCompleted:     formatMemoryTimestamp(job.DateCompleted),
+		})
+	}
+	return paginate(list, pageNum), len(list), nil
+}
+
+func (m *MemoryStore) GetJobDeadLetters() ([]types.JobDeadLetterList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deadLetters []types.JobDeadLetterList
+	for i := len(m.jobDeadLetters) - 1; i >= 0 && len(deadLetters) < 50; i-- {
+		deadLetter := m.jobDeadLetters[i]
+		if deadLetter.DateRequeued > 0 {
+			continue
+		}
+
+		deadLetters = append(deadLetters, types.JobDeadLetterList{
+			JobDeadLetterID: deadLetter.JobDeadLetterID,
+			JobID:           deadLetter.JobID,
+			JobType:         deadLetter.JobType,
+			Payload:         deadLetter.Payload,
+			Attempts:        deadLetter.Attempts,
+			LastError:       deadLetter.LastError,
+			DateFailed:      formatMemoryTimestamp(deadLetter.DateFailed),
+		})
+	}
+	return deadLetters, nil
+}
+
+func (m *MemoryStore) RequeueJobDeadLetter(jobDeadLetterID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, deadLetter := range m.jobDeadLetters {
+		if deadLetter.JobDeadLetterID != jobDeadLetterID || deadLetter.DateRequeued > 0 {
+			continue
+		}
+
+		now := time.Now().Unix()
+		id := m.id()
+		m.jobs[id] = &models.Job{
+			JobID:       id,
+			JobType:     deadLetter.JobType,
+			Payload:     deadLetter.Payload,
+			Status:      constants.PendingJobStatus,
+			MaxAttempts: constants.DefaultJobMaxAttempts,
+			RunAt:       now,
+			DateCreated: now,
+		}
+		m.jobDeadLetters[i].DateRequeued = now
+		return nil
+	}
+	return fmt.Errorf("dead letter not found or already requeued")
+}
+
+func (m *MemoryStore) RunJobNow(jobID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok || (job.Status != constants.PendingJobStatus && job.Status != constants.DeadJobStatus) {
+		return fmt.Errorf("only pending or dead jobs can be run")
+	}
+
+	job.Status = constants.PendingJobStatus
+	job.RunAt = time.Now().Unix()
+	job.Attempts = 0
+	return nil
+}
+
+func (m *MemoryStore) GetSMSSequences() ([]types.SMSSequenceDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sequences []types.SMSSequenceDetails
+	for _, id := range sortedKeys(m.smsSequences) {
+		sequence := m.smsSequences[id]
+		details := types.SMSSequenceDetails{
+			SMSSequenceID:       sequence.SMSSequenceID,
+			Name:                sequence.Name,
+			IsActive:            sequence.IsActive,
+			ArchiveOnCompletion: sequence.ArchiveOnCompletion,
+		}
+
+		for _, enrollment := range m.smsSequenceEnrollments {
+			if enrollment.SMSSequenceID == id && enrollment.Status == constants.ActiveSMSSequenceEnrollmentStatus {
+				details.ActiveEnrollments++
+			}
+		}
+
+		for _, step := range m.sequenceSteps(id) {
+			details.Steps = append(details.Steps, types.SMSSequenceStepList{
+				SMSSequenceStepID: step.SMSSequenceStepID,
+				StepNumber:        step.StepNumber,
+				DelayDays:         step.DelayDays,
+				Body:              step.Body,
+				IsAIGenerated:     step.IsAIGenerated,
+			})
+		}
+
+		sequences = append(sequences, details)
+	}
+	return sequences, nil
+}
+
+// sequenceSteps returns a sequence's steps ordered by step number.
+func (m *MemoryStore) sequenceSteps(sequenceId int) []*models.SMSSequenceStep {
+	var steps []*models.SMSSequenceStep
+	for _, id := range sortedKeys(m.smsSequenceSteps) {
+		if m.smsSequenceSteps[id].SMSSequenceID == sequenceId {
+			steps = append(steps, m.smsSequenceSteps[id])
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].StepNumber < steps[j].StepNumber
+	})
+	return steps
+}
+
+func (m *MemoryStore) CreateSMSSequence(form types.SMSSequenceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.id()
+	m.smsSequences[id] = &models.SMSSequence{
+		SMSSequenceID:       id,
+		Name:                deref(form.Name),
+		IsActive:            deref(form.IsActive),
+		ArchiveOnCompletion: deref(form.ArchiveOnCompletion),
+		DateCreated:         time.Now().Unix(),
+	}
+	return nil
+}
+
+func (m *MemoryStore) UpdateSMSSequence(form types.SMSSequenceForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sequence, ok := m.smsSequences[deref(form.SMSSequenceID)]
+	if !ok {
+		return nil
+	}
+
+	if form.Name != nil {
+		sequence.Name = *form.Name
+	}
+	if form.IsActive != nil {
+		sequence.IsActive = *form.IsActive
+	}
+	if form.ArchiveOnCompletion != nil {
+		sequence.ArchiveOnCompletion = *form.ArchiveOnCompletion
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteSMSSequence(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.smsSequences, id)
+	for stepId, step := range m.smsSequenceSteps {
+		if step.SMSSequenceID == id {
+			delete(m.smsSequenceSteps, stepId)
+		}
+	}
+	for enrollmentId, enrollment := range m.smsSequenceEnrollments {
+		if enrollment.SMSSequenceID == id {
+			delete(m.smsSequenceEnrollments, enrollmentId)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreateSMSSequenceStep(form types.SMSSequenceStepForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sequenceId := deref(form.SMSSequenceID)
+
+	stepNumber := 1
+	if steps := m.sequenceSteps(sequenceId); len(steps) > 0 {
+		stepNumber = steps[len(steps)-1].StepNumber + 1
+	}
+
+	id := m.id()
+	m.smsSequenceSteps[id] = &models.SMSSequenceStep{
+		SMSSequenceStepID: id,
+		SMSSequenceID:     sequenceId,
+		StepNumber:        stepNumber,
+		DelayDays:         deref(form.DelayDays),
+		Body:              deref(form.Body),
+		IsAIGenerated:     deref(form.IsAIGenerated),
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteSMSSequenceStep(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.smsSequenceSteps, id)
+	return nil
+}
+
+func (m *MemoryStore) GetDueSMSSequenceSteps() ([]types.DueSMSSequenceStep, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().Unix()
+
+	var due []types.DueSMSSequenceStep
+	for _, id := range sortedKeys(m.smsSequenceEnrollments) {
+		enrollment := m.smsSequenceEnrollments[id]
+		if enrollment.Status != constants.ActiveSMSSequenceEnrollmentStatus {
+			continue
+		}
+
+		sequence, ok := m.smsSequences[enrollment.SMSSequenceID]
+		lead, hasLead := m.leads[enrollment.LeadID]
+		if !ok || !hasLead || !sequence.IsActive {
+			continue
+		}
+
+		steps := m.sequenceSteps(enrollment.SMSSequenceID)
+		for i, step := range steps {
+			if step.StepNumber <= enrollment.LastStepNumber {
+				continue
+			}
+
+			// Each step waits from the previous send, the first one from enrollment
+			lastSent := enrollment.DateEnrolled
+			if enrollment.DateLastSent > 0 {
+				lastSent = enrollment.DateLastSent
+			}
+
+			if lastSent+int64(step.DelayDays)*86400 > now {
+				break
+			}
+
+			isBooked := false
+			for _, event := range m.events {
+				if event.LeadID == lead.LeadID {
+					isBooked = true
+				}
+			}
+
+			due = append(due, types.DueSMSSequenceStep{
+				SMSSequenceEnrollmentID: enrollment.SMSSequenceEnrollmentID,
+				LeadID:                  lead.LeadID,
+				FullName:                lead.FullName,
+				PhoneNumber:             lead.PhoneNumber,
+				Message:                 lead.Message,
+				StepNumber:              step.StepNumber,
+				Body:                    step.Body,
+				IsAIGenerated:           step.IsAIGenerated,
+				IsLastStep:              i == len(steps)-1,
+				ArchiveOnCompletion:     sequence.ArchiveOnCompletion,
+				IsBooked:                isBooked,
+			})
+			break
+		}
+	}
+	return due, nil
+}
+
+func (m *MemoryStore) MarkSMSSequenceStepSent(enrollmentID, stepNumber int, isLastStep bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	enrollment, ok := m.smsSequenceEnrollments[enrollmentID]
+	if !ok {
+		return nil
+	}
+
+	enrollment.LastStepNumber = stepNumber
+	enrollment.DateLastSent = time.Now().Unix()
+	if isLastStep {
+		enrollment.Status = constants.CompletedSMSSequenceEnrollmentStatus
+	}
+	return nil
+}
+
+func (m *MemoryStore) StopSMSSequenceEnrollments(leadID int, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, enrollment := range m.smsSequenceEnrollments {
+		if enrollment.LeadID != leadID || enrollment.Status != constants.ActiveSMSSequenceEnrollmentStatus {
+			continue
+		}
+		enrollment.Status = constants.StoppedSMSSequenceEnrollmentStatus
+		enrollment.StopReason = reason
+		enrollment.DateStopped = time.Now().Unix()
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetConversation(leadId int) (types.FrontendConversation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conversation := types.FrontendConversation{
+		LeadID: leadId,
+		Status: m.conversationStatus(leadId),
+	}
+
+	if row, ok := m.conversations[leadId]; ok {
+		conversation.AssignedUserID = row.AssignedUserID
+		conversation.AssignedUserName = m.userFullName(row.AssignedUserID)
+		if row.SnoozedUntil > 0 {
+			conversation.SnoozedUntil = formatMemoryTimestamp(row.SnoozedUntil)
+		}
+	}
+	return conversation, nil
+}
+
+func (m *MemoryStore) UpdateConversation(leadId int, assignedUserId *int, status string, snoozedUntil *int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conversation := m.conversation(leadId)
+	if deref(assignedUserId) != conversation.AssignedUserID {
+		conversation.AssignedUserID = deref(assignedUserId)
+		conversation.DateAssigned = 0
+		if conversation.AssignedUserID > 0 {
+			conversation.DateAssigned = time.Now().Unix()
+		}
+	}
+	conversation.Status = status
+	conversation.SnoozedUntil = deref(snoozedUntil)
+	return nil
+}
+
+func (m *MemoryStore) ReopenConversation(leadId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conversation := m.conversation(leadId)
+	conversation.Status = constants.OpenConversationStatus
+	conversation.SnoozedUntil = 0
+	return nil
+}
+
+func (m *MemoryStore) CreateConversationNote(leadId int, note models.ConversationNote) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	note.ConversationNoteID = m.id()
+	note.ConversationID = m.conversation(leadId).ConversationID
+	m.conversationNotes = append(m.conversationNotes, note)
+	return nil
+}
+
+func (m *MemoryStore) GetCallFlow() (types.CallFlowDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	flow := types.CallFlowDetails{
+		CallFlowID:        m.callFlow.CallFlowID,
+		Greeting:          m.callFlow.Greeting,
+		HasLanguageMenu:   m.callFlow.HasLanguageMenu,
+		RingStrategy:      m.callFlow.RingStrategy,
+		RingTimeout:       m.callFlow.RingTimeout,
+		VoicemailGreeting: m.callFlow.VoicemailGreeting,
+		AfterHoursMessage: m.callFlow.AfterHoursMessage,
+		BusinessHours:     newCallFlowBusinessHours(),
+	}
+
+	for _, hours := range m.callFlowHours {
+		flow.BusinessHours[hours.DayOfWeek].OpenTime = hours.OpenTime
+		flow.BusinessHours[hours.DayOfWeek].CloseTime = hours.CloseTime
+	}
+
+	for _, id := range sortedKeys(m.callFlowRecipients) {
+		recipient := m.callFlowRecipients[id]
+		var phoneNumber string
+		if user, ok := m.users[recipient.UserID]; ok {
+			phoneNumber = user.ForwardPhoneNumber
+		}
+		flow.Recipients = append(flow.Recipients, types.CallFlowRecipientList{
+			CallFlowRecipientID: recipient.CallFlowRecipientID,
+			UserID:              recipient.UserID,
+			UserName:            m.userFullName(recipient.UserID),
+			PhoneNumber:         phoneNumber,
+			Language:            recipient.Language,
+			RingOrder:           recipient.RingOrder,
+		})
+	}
+	sort.SliceStable(flow.Recipients, func(i, j int) bool {
+		return flow.Recipients[i].RingOrder < flow.Recipients[j].RingOrder
+	})
+
+	return flow, nil
+}
+
+func (m *MemoryStore) UpdateCallFlow(form types.CallFlowForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callFlow.Greeting = deref(form.Greeting)
+	m.callFlow.HasLanguageMenu = deref(form.HasLanguageMenu)
+	m.callFlow.RingStrategy = deref(form.RingStrategy)
+	m.callFlow.RingTimeout = deref(form.RingTimeout)
+	m.callFlow.VoicemailGreeting = deref(form.VoicemailGreeting)
+	m.callFlow.AfterHoursMessage = deref(form.AfterHoursMessage)
+	m.callFlow.DateUpdated = time.Now().Unix()
+
+	m.callFlowHours = nil
+	for day, hours := range form.BusinessHours {
+		if hours.OpenTime == nil || hours.CloseTime == nil {
+			continue
+		}
+		m.callFlowHours = append(m.callFlowHours, models.CallFlowBusinessHours{
+			CallFlowBusinessHoursID: m.id(),
+			CallFlowID:              m.callFlow.CallFlowID,
+			DayOfWeek:               day,
+			OpenTime:                *hours.OpenTime,
+			CloseTime:               *hours.CloseTime,
+		})
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreateCallFlowRecipient(form types.CallFlowRecipientForm) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, recipient := range m.callFlowRecipients {
+		if recipient.UserID == deref(form.UserID) {
+			recipient.Language = deref(form.Language)
+			recipient.RingOrder = deref(form.RingOrder)
+			return nil
+		}
+	}
+
+	id := m.id()
+	m.callFlowRecipients[id] = &models.CallFlowRecipient{
+		CallFlowRecipientID: id,
+		CallFlowID:          m.callFlow.CallFlowID,
+		UserID:              deref(form.UserID),
+		Language:            deref(form.Language),
+		RingOrder:           deref(form.RingOrder),
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteCallFlowRecipient(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.callFlowRecipients, id)
+	return nil
+}
+
+func (m *MemoryStore) GetMessageMedia(messageMediaId int) (models.MessageMedia, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msg := range m.messages {
+		for _, item := range msg.Media {
+			if item.MessageMediaID == messageMediaId {
+				return item, nil
+			}
+		}
+	}
+	return models.MessageMedia{}, fmt.Errorf("error scanning message media: %w", sql.ErrNoRows)
+}
@@ -0,0 +1,30 @@
+DROP TABLE IF EXISTS invoice;
+DROP TABLE IF EXISTS invoice_status;
+DROP TABLE IF EXISTS invoice_type;
+DROP TABLE IF EXISTS quote_service;
+DROP TABLE IF EXISTS quote;
+DROP TABLE IF EXISTS service;
+DROP TABLE IF EXISTS unit_type;
+DROP TABLE IF EXISTS service_type;
+DROP TABLE IF EXISTS cocktail_ingredient;
+DROP TABLE IF EXISTS unit;
+DROP TABLE IF EXISTS ingredient;
+DROP TABLE IF EXISTS event_cocktail;
+DROP TABLE IF EXISTS cocktail;
+DROP TABLE IF EXISTS event_staff;
+DROP TABLE IF EXISTS event;
+DROP TABLE IF EXISTS event_role;
+DROP TABLE IF EXISTS phone_call_transcription;
+DROP TABLE IF EXISTS phone_call;
+DROP TABLE IF EXISTS message;
+DROP TABLE IF EXISTS lead_note;
+DROP TABLE IF EXISTS lead_next_action;
+DROP TABLE IF EXISTS lead_marketing;
+DROP TABLE IF EXISTS lead;
+DROP TABLE IF EXISTS next_action;
+DROP TABLE IF EXISTS lead_interest;
+DROP TABLE IF EXISTS lead_status;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS user_role;
+DROP TABLE IF EXISTS csrf_token;
@@ -0,0 +1,345 @@
+CREATE TABLE IF NOT EXISTS csrf_token (
+	csrf_token_id SERIAL PRIMARY KEY,
+	expiry_time TIMESTAMP NOT NULL,
+	token TEXT NOT NULL UNIQUE,
+	is_used BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS user_role (
+	user_role_id SERIAL PRIMARY KEY,
+	role VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS "user" (
+	user_id SERIAL PRIMARY KEY,
+	username VARCHAR(255) NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	user_role_id INTEGER NOT NULL REFERENCES user_role(user_role_id),
+	phone_number VARCHAR(20),
+	forward_phone_number VARCHAR(20),
+	first_name VARCHAR(255),
+	last_name VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+	session_id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES "user"(user_id) ON DELETE CASCADE,
+	csrf_secret TEXT NOT NULL UNIQUE,
+	external_id VARCHAR(255) NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_expires TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS lead_status (
+	lead_status_id SERIAL PRIMARY KEY,
+	status VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS lead_interest (
+	lead_interest_id SERIAL PRIMARY KEY,
+	interest VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS next_action (
+	next_action_id SERIAL PRIMARY KEY,
+	action VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS lead (
+	lead_id SERIAL PRIMARY KEY,
+	full_name VARCHAR(255) NOT NULL,
+	phone_number VARCHAR(20) NOT NULL UNIQUE,
+	created_at TIMESTAMP NOT NULL,
+	message TEXT,
+	opt_in_text_messaging BOOLEAN NOT NULL DEFAULT FALSE,
+	email VARCHAR(255),
+	lead_status_id INTEGER REFERENCES lead_status(lead_status_id),
+	next_action_id INTEGER REFERENCES next_action(next_action_id),
+	lead_interest_id INTEGER REFERENCES lead_interest(lead_interest_id),
+	stripe_customer_id VARCHAR(255),
+	search_vector TSVECTOR GENERATED ALWAYS AS (
+		to_tsvector('english', COALESCE(full_name, '') || ' ' || COALESCE(phone_number, '') || ' ' || COALESCE(email, '') || ' ' || COALESCE(message, ''))
+	) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_search_vector ON lead USING GIN (search_vector);
+
+CREATE TABLE IF NOT EXISTS lead_marketing (
+	lead_marketing_id SERIAL PRIMARY KEY,
+	lead_id INTEGER NOT NULL UNIQUE REFERENCES lead(lead_id) ON DELETE CASCADE,
+	source VARCHAR(255),
+	medium VARCHAR(255),
+	channel VARCHAR(255),
+	landing_page TEXT,
+	keyword VARCHAR(255),
+	referrer TEXT,
+	click_id TEXT,
+	campaign_id BIGINT,
+	ad_campaign VARCHAR(255),
+	ad_group_id BIGINT,
+	ad_group_name VARCHAR(255),
+	ad_set_id BIGINT,
+	ad_set_name VARCHAR(255),
+	ad_id BIGINT,
+	ad_headline BIGINT,
+	language VARCHAR(255),
+	user_agent TEXT,
+	button_clicked VARCHAR(255),
+	ip VARCHAR(255),
+	external_id VARCHAR(255),
+	google_client_id VARCHAR(255),
+	csrf_secret TEXT,
+	facebook_click_id TEXT,
+	facebook_client_id VARCHAR(255),
+	longitude VARCHAR(255),
+	latitude VARCHAR(255),
+	instant_form_lead_id BIGINT,
+	instant_form_id BIGINT,
+	instant_form_name VARCHAR(255),
+	referral_lead_id INTEGER REFERENCES lead(lead_id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS lead_next_action (
+	lead_next_action_id SERIAL PRIMARY KEY,
+	next_action_id INTEGER NOT NULL REFERENCES next_action(next_action_id),
+	lead_id INTEGER NOT NULL REFERENCES lead(lead_id) ON DELETE CASCADE,
+	action_date TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS lead_note (
+	lead_note_id SERIAL PRIMARY KEY,
+	note TEXT NOT NULL,
+	lead_id INTEGER NOT NULL REFERENCES lead(lead_id) ON DELETE CASCADE,
+	date_added TIMESTAMP NOT NULL,
+	added_by_user_id INTEGER REFERENCES "user"(user_id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS message (
+	message_id SERIAL PRIMARY KEY,
+	external_id VARCHAR(255) NOT NULL,
+	text TEXT NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	text_from VARCHAR(20) NOT NULL,
+	text_to VARCHAR(20) NOT NULL,
+	is_inbound BOOLEAN NOT NULL,
+	is_read BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_text_from ON message (text_from);
+CREATE INDEX IF NOT EXISTS idx_message_text_to ON message (text_to);
+
+CREATE TABLE IF NOT EXISTS phone_call (
+	phone_call_id SERIAL PRIMARY KEY,
+	external_id VARCHAR(255) NOT NULL UNIQUE,
+	call_duration INTEGER NOT NULL DEFAULT 0,
+	date_created TIMESTAMP NOT NULL,
+	call_from VARCHAR(20) NOT NULL,
+	call_to VARCHAR(20) NOT NULL,
+	is_inbound BOOLEAN NOT NULL,
+	recording_url TEXT,
+	status VARCHAR(50)
+);
+
+CREATE TABLE IF NOT EXISTS phone_call_transcription (
+	phone_call_transcription_id SERIAL PRIMARY KEY,
+	phone_call_id INTEGER NOT NULL REFERENCES phone_call(phone_call_id) ON DELETE CASCADE,
+	text TEXT,
+	audio_url TEXT,
+	text_url TEXT
+);
+
+CREATE TABLE IF NOT EXISTS event_role (
+	event_role_id SERIAL PRIMARY KEY,
+	role VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS event (
+	event_id SERIAL PRIMARY KEY,
+	bartender_id INTEGER REFERENCES "user"(user_id) ON DELETE SET NULL,
+	lead_id INTEGER NOT NULL REFERENCES lead(lead_id) ON DELETE CASCADE,
+	street_address VARCHAR(255),
+	city VARCHAR(255),
+	zip_code VARCHAR(20),
+	start_time TIMESTAMP,
+	end_time TIMESTAMP,
+	date_created TIMESTAMP NOT NULL,
+	date_paid TIMESTAMP,
+	amount MONEY,
+	tip MONEY,
+	guests INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS event_staff (
+	event_staff_id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES event(event_id) ON DELETE CASCADE,
+	user_id INTEGER NOT NULL REFERENCES "user"(user_id) ON DELETE CASCADE,
+	user_role_id INTEGER REFERENCES user_role(user_role_id),
+	event_role_id INTEGER REFERENCES event_role(event_role_id),
+	hourly_rate MONEY
+);
+
+CREATE TABLE IF NOT EXISTS cocktail (
+	cocktail_id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS event_cocktail (
+	event_cocktail_id SERIAL PRIMARY KEY,
+	event_id INTEGER NOT NULL REFERENCES event(event_id) ON DELETE CASCADE,
+	cocktail_id INTEGER NOT NULL REFERENCES cocktail(cocktail_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS ingredient (
+	ingredient_id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL UNIQUE,
+	category VARCHAR(100)
+);
+
+CREATE TABLE IF NOT EXISTS unit (
+	unit_id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL UNIQUE,
+	abbreviation VARCHAR(20)
+);
+
+CREATE TABLE IF NOT EXISTS cocktail_ingredient (
+	cocktail_id INTEGER NOT NULL REFERENCES cocktail(cocktail_id) ON DELETE CASCADE,
+	ingredient_id INTEGER NOT NULL REFERENCES ingredient(ingredient_id) ON DELETE CASCADE,
+	unit_id INTEGER NOT NULL REFERENCES unit(unit_id),
+	amount NUMERIC(10, 2) NOT NULL,
+	PRIMARY KEY (cocktail_id, ingredient_id)
+);
+
+CREATE TABLE IF NOT EXISTS service_type (
+	service_type_id SERIAL PRIMARY KEY,
+	service_type VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS unit_type (
+	unit_type_id SERIAL PRIMARY KEY,
+	type VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS service (
+	service_id SERIAL PRIMARY KEY,
+	service VARCHAR(255) NOT NULL,
+	suggested_price MONEY NOT NULL,
+	service_type_id INTEGER NOT NULL REFERENCES service_type(service_type_id),
+	guest_ratio INTEGER,
+	unit_type_id INTEGER REFERENCES unit_type(unit_type_id)
+);
+
+CREATE TABLE IF NOT EXISTS quote (
+	quote_id SERIAL PRIMARY KEY,
+	lead_id INTEGER NOT NULL REFERENCES lead(lead_id) ON DELETE CASCADE,
+	guests INTEGER NOT NULL,
+	hours NUMERIC(5, 2) NOT NULL,
+	event_date TIMESTAMP,
+	external_id VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS quote_service (
+	quote_service_id SERIAL PRIMARY KEY,
+	service_id INTEGER NOT NULL REFERENCES service(service_id),
+	quote_id INTEGER NOT NULL REFERENCES quote(quote_id) ON DELETE CASCADE,
+	units NUMERIC(10, 2) NOT NULL,
+	price_per_unit MONEY NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_type (
+	invoice_type_id SERIAL PRIMARY KEY,
+	type VARCHAR(100) NOT NULL UNIQUE,
+	amount_percentage NUMERIC(5, 4) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_status (
+	invoice_status_id SERIAL PRIMARY KEY,
+	status VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS invoice (
+	invoice_id SERIAL PRIMARY KEY,
+	stripe_invoice_id VARCHAR(255) NOT NULL UNIQUE,
+	quote_id INTEGER NOT NULL REFERENCES quote(quote_id) ON DELETE CASCADE,
+	invoice_type_id INTEGER NOT NULL REFERENCES invoice_type(invoice_type_id),
+	url TEXT,
+	due_date TIMESTAMP,
+	date_created TIMESTAMP NOT NULL,
+	date_paid TIMESTAMP,
+	invoice_status_id INTEGER NOT NULL REFERENCES invoice_status(invoice_status_id)
+);
+
+-- Lookup rows referenced by ID in constants/constants.go
+INSERT INTO user_role (user_role_id, role) VALUES
+	(1, 'Admin'),
+	(2, 'Bartender'),
+	(3, 'Barback')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO lead_status (lead_status_id, status) VALUES
+	(1, 'New'),
+	(2, 'Contacted'),
+	(3, 'Quoted'),
+	(4, 'Negotiating'),
+	(5, 'Booked'),
+	(6, 'Lost'),
+	(7, 'Archived')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO lead_interest (lead_interest_id, interest) VALUES
+	(1, 'High'),
+	(2, 'Medium'),
+	(3, 'Low'),
+	(4, 'No Interest')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO next_action (next_action_id, action) VALUES
+	(1, 'Initial Contact'),
+	(2, 'Send Quote'),
+	(3, 'First Follow Up'),
+	(4, 'Second Follow Up'),
+	(5, 'Call Back')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO invoice_type (invoice_type_id, type, amount_percentage) VALUES
+	(1, 'Deposit', 0.25),
+	(2, 'Remaining', 0.75),
+	(3, 'Full', 1.00)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO invoice_status (invoice_status_id, status) VALUES
+	(1, 'Open'),
+	(2, 'Void'),
+	(3, 'Paid')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO service_type (service_type_id, service_type) VALUES
+	(1, 'Alcohol'),
+	(2, 'Bar Rental'),
+	(3, 'Cooler Rental'),
+	(4, 'Bartending Add-On'),
+	(5, 'Bartending'),
+	(6, 'Glassware'),
+	(7, 'Mixers'),
+	(8, 'Extra')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO unit_type (unit_type_id, type) VALUES
+	(1, 'Per Person'),
+	(2, 'Per Hour'),
+	(3, 'Flat')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO event_role (event_role_id, role) VALUES
+	(1, 'Bartender'),
+	(2, 'Barback')
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('user_role', 'user_role_id'), (SELECT MAX(user_role_id) FROM user_role));
+SELECT setval(pg_get_serial_sequence('lead_status', 'lead_status_id'), (SELECT MAX(lead_status_id) FROM lead_status));
+SELECT setval(pg_get_serial_sequence('lead_interest', 'lead_interest_id'), (SELECT MAX(lead_interest_id) FROM lead_interest));
+SELECT setval(pg_get_serial_sequence('next_action', 'next_action_id'), (SELECT MAX(next_action_id) FROM next_action));
+SELECT setval(pg_get_serial_sequence('invoice_type', 'invoice_type_id'), (SELECT MAX(invoice_type_id) FROM invoice_type));
+SELECT setval(pg_get_serial_sequence('invoice_status', 'invoice_status_id'), (SELECT MAX(invoice_status_id) FROM invoice_status));
+SELECT setval(pg_get_serial_sequence('service_type', 'service_type_id'), (SELECT MAX(service_type_id) FROM service_type));
+SELECT setval(pg_get_serial_sequence('unit_type', 'unit_type_id'), (SELECT MAX(unit_type_id) FROM unit_type));
+SELECT setval(pg_get_serial_sequence('event_role', 'event_role_id'), (SELECT MAX(event_role_id) FROM event_role));
@@ -0,0 +1,3 @@
+ALTER TABLE event_staff DROP COLUMN IF EXISTS date_responded;
+ALTER TABLE event_staff DROP COLUMN IF EXISTS event_staff_status_id;
+DROP TABLE IF EXISTS event_staff_status;
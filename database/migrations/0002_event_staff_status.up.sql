@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_staff_status (
+	event_staff_status_id SERIAL PRIMARY KEY,
+	status VARCHAR(100) NOT NULL UNIQUE
+);
+
+INSERT INTO event_staff_status (event_staff_status_id, status) VALUES
+	(1, 'Pending'),
+	(2, 'Accepted'),
+	(3, 'Declined')
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('event_staff_status', 'event_staff_status_id'), (SELECT MAX(event_staff_status_id) FROM event_staff_status));
+
+ALTER TABLE event_staff ADD COLUMN IF NOT EXISTS event_staff_status_id INTEGER NOT NULL DEFAULT 1 REFERENCES event_staff_status(event_staff_status_id);
+ALTER TABLE event_staff ADD COLUMN IF NOT EXISTS date_responded TIMESTAMP;
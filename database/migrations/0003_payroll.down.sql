@@ -0,0 +1,2 @@
+ALTER TABLE event_staff DROP COLUMN IF EXISTS amount_paid;
+ALTER TABLE event_staff DROP COLUMN IF EXISTS date_paid;
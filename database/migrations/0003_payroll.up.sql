@@ -0,0 +1,2 @@
+ALTER TABLE event_staff ADD COLUMN IF NOT EXISTS date_paid TIMESTAMP;
+ALTER TABLE event_staff ADD COLUMN IF NOT EXISTS amount_paid MONEY;
@@ -0,0 +1,2 @@
+ALTER TABLE event DROP COLUMN IF EXISTS quote_id;
+ALTER TABLE service DROP COLUMN IF EXISTS cost_basis;
@@ -0,0 +1,2 @@
+ALTER TABLE service ADD COLUMN IF NOT EXISTS cost_basis MONEY NOT NULL DEFAULT 0;
+ALTER TABLE event ADD COLUMN IF NOT EXISTS quote_id INTEGER REFERENCES quote(quote_id) ON DELETE SET NULL;
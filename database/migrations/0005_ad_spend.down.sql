@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_lead_marketing_ad_set_id;
+DROP INDEX IF EXISTS idx_lead_marketing_campaign_id;
+DROP TABLE IF EXISTS ad_spend;
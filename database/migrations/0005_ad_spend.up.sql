@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS ad_spend (
+	ad_spend_id SERIAL PRIMARY KEY,
+	date DATE NOT NULL,
+	source VARCHAR(255),
+	campaign_id BIGINT NOT NULL,
+	campaign_name VARCHAR(255),
+	ad_set_id BIGINT NOT NULL DEFAULT 0,
+	ad_set_name VARCHAR(255),
+	spend MONEY NOT NULL,
+	date_imported TIMESTAMP NOT NULL,
+	UNIQUE (date, campaign_id, ad_set_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_marketing_campaign_id ON lead_marketing (campaign_id);
+CREATE INDEX IF NOT EXISTS idx_lead_marketing_ad_set_id ON lead_marketing (ad_set_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS job_dead_letter;
+DROP INDEX IF EXISTS idx_job_recurring_job_type;
+DROP INDEX IF EXISTS idx_job_status_run_at;
+DROP TABLE IF EXISTS job;
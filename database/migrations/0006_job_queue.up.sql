@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS job (
+	job_id SERIAL PRIMARY KEY,
+	job_type VARCHAR(100) NOT NULL,
+	payload JSONB NOT NULL DEFAULT '{}',
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL DEFAULT 5,
+	recurring_interval INTEGER,
+	run_at TIMESTAMP NOT NULL,
+	last_error TEXT,
+	locked_by VARCHAR(255),
+	locked_at TIMESTAMP,
+	date_created TIMESTAMP NOT NULL,
+	date_completed TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_status_run_at ON job (status, run_at);
+
+-- Only one row per recurring job type so every instance can safely schedule them on boot
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_recurring_job_type ON job (job_type) WHERE recurring_interval IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS job_dead_letter (
+	job_dead_letter_id SERIAL PRIMARY KEY,
+	job_id INTEGER REFERENCES job(job_id) ON DELETE SET NULL,
+	job_type VARCHAR(100) NOT NULL,
+	payload JSONB NOT NULL DEFAULT '{}',
+	attempts INTEGER NOT NULL,
+	last_error TEXT,
+	date_failed TIMESTAMP NOT NULL,
+	date_requeued TIMESTAMP
+);
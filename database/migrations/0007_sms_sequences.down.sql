@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sms_sequence_enrollment_status;
+DROP INDEX IF EXISTS idx_sms_sequence_enrollment_lead_id;
+DROP TABLE IF EXISTS sms_sequence_enrollment;
+DROP TABLE IF EXISTS sms_sequence_step;
+DROP TABLE IF EXISTS sms_sequence;
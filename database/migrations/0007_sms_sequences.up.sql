@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS sms_sequence (
+	sms_sequence_id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	archive_on_completion BOOLEAN NOT NULL DEFAULT FALSE,
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS sms_sequence_step (
+	sms_sequence_step_id SERIAL PRIMARY KEY,
+	sms_sequence_id INTEGER NOT NULL REFERENCES sms_sequence(sms_sequence_id) ON DELETE CASCADE,
+	step_number INTEGER NOT NULL,
+	delay_days INTEGER NOT NULL DEFAULT 0,
+	body TEXT NOT NULL,
+	is_ai_generated BOOLEAN NOT NULL DEFAULT FALSE,
+	UNIQUE (sms_sequence_id, step_number)
+);
+
+CREATE TABLE IF NOT EXISTS sms_sequence_enrollment (
+	sms_sequence_enrollment_id SERIAL PRIMARY KEY,
+	sms_sequence_id INTEGER NOT NULL REFERENCES sms_sequence(sms_sequence_id) ON DELETE CASCADE,
+	lead_id INTEGER NOT NULL REFERENCES lead(lead_id) ON DELETE CASCADE,
+	last_step_number INTEGER NOT NULL DEFAULT 0,
+	status VARCHAR(20) NOT NULL DEFAULT 'active',
+	stop_reason VARCHAR(50),
+	date_enrolled TIMESTAMP NOT NULL,
+	date_last_sent TIMESTAMP,
+	date_stopped TIMESTAMP,
+	UNIQUE (sms_sequence_id, lead_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sms_sequence_enrollment_lead_id ON sms_sequence_enrollment (lead_id);
+CREATE INDEX IF NOT EXISTS idx_sms_sequence_enrollment_status ON sms_sequence_enrollment (status);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sms_consent_event (
+	sms_consent_event_id SERIAL PRIMARY KEY,
+	phone_number VARCHAR(20) NOT NULL,
+	lead_id INTEGER REFERENCES lead(lead_id) ON DELETE SET NULL,
+	is_opted_in BOOLEAN NOT NULL,
+	source VARCHAR(50) NOT NULL,
+	keyword VARCHAR(50),
+	message TEXT,
+	external_id VARCHAR(255),
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sms_consent_event_phone_number ON sms_consent_event (phone_number, date_created DESC);
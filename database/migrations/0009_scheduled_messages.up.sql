@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS scheduled_message (
+	scheduled_message_id SERIAL PRIMARY KEY,
+	lead_id INTEGER REFERENCES lead(lead_id) ON DELETE CASCADE,
+	user_id INTEGER REFERENCES "user"(user_id) ON DELETE SET NULL,
+	text_from VARCHAR(20) NOT NULL,
+	text_to VARCHAR(20) NOT NULL,
+	body TEXT NOT NULL,
+	send_at TIMESTAMP NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	external_id VARCHAR(255),
+	last_error TEXT,
+	date_created TIMESTAMP NOT NULL,
+	date_sent TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_message_lead_id ON scheduled_message (lead_id, status);
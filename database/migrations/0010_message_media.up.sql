@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS message_media (
+	message_media_id SERIAL PRIMARY KEY,
+	message_id INTEGER NOT NULL REFERENCES message(message_id) ON DELETE CASCADE,
+	file_path VARCHAR(255) NOT NULL,
+	content_type VARCHAR(100) NOT NULL,
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_media_message_id ON message_media (message_id);
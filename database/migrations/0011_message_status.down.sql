@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_message_external_id;
+
+ALTER TABLE message DROP COLUMN IF EXISTS date_updated;
+ALTER TABLE message DROP COLUMN IF EXISTS error_message;
+ALTER TABLE message DROP COLUMN IF EXISTS error_code;
+ALTER TABLE message DROP COLUMN IF EXISTS status;
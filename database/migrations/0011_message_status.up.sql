@@ -0,0 +1,6 @@
+ALTER TABLE message ADD COLUMN IF NOT EXISTS status VARCHAR(20);
+ALTER TABLE message ADD COLUMN IF NOT EXISTS error_code VARCHAR(10);
+ALTER TABLE message ADD COLUMN IF NOT EXISTS error_message TEXT;
+ALTER TABLE message ADD COLUMN IF NOT EXISTS date_updated TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_message_external_id ON message (external_id);
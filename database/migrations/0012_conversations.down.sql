@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS conversation_note;
+DROP TABLE IF EXISTS conversation;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS conversation (
+	conversation_id SERIAL PRIMARY KEY,
+	lead_id INTEGER NOT NULL UNIQUE REFERENCES lead(lead_id) ON DELETE CASCADE,
+	assigned_user_id INTEGER REFERENCES "user"(user_id) ON DELETE SET NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'open',
+	snoozed_until TIMESTAMP,
+	date_assigned TIMESTAMP,
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_assigned_user_id ON conversation (assigned_user_id, status);
+
+CREATE TABLE IF NOT EXISTS conversation_note (
+	conversation_note_id SERIAL PRIMARY KEY,
+	conversation_id INTEGER NOT NULL REFERENCES conversation(conversation_id) ON DELETE CASCADE,
+	user_id INTEGER REFERENCES "user"(user_id) ON DELETE SET NULL,
+	note TEXT NOT NULL,
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_note_conversation_id ON conversation_note (conversation_id);
+
+INSERT INTO conversation (lead_id, status, date_created)
+SELECT lead_id, 'open', (NOW() AT TIME ZONE 'America/New_York')
+FROM lead
+ON CONFLICT (lead_id) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS call_flow_recipient;
+DROP TABLE IF EXISTS call_flow_business_hours;
+DROP TABLE IF EXISTS call_flow;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS call_flow (
+	call_flow_id SERIAL PRIMARY KEY,
+	greeting TEXT,
+	has_language_menu BOOLEAN NOT NULL DEFAULT FALSE,
+	ring_strategy VARCHAR(20) NOT NULL DEFAULT 'simultaneous',
+	ring_timeout INTEGER NOT NULL DEFAULT 20,
+	voicemail_greeting TEXT NOT NULL,
+	after_hours_message TEXT NOT NULL,
+	date_updated TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS call_flow_business_hours (
+	call_flow_business_hours_id SERIAL PRIMARY KEY,
+	call_flow_id INTEGER NOT NULL REFERENCES call_flow(call_flow_id) ON DELETE CASCADE,
+	day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+	open_time TIME NOT NULL,
+	close_time TIME NOT NULL,
+	UNIQUE (call_flow_id, day_of_week)
+);
+
+CREATE TABLE IF NOT EXISTS call_flow_recipient (
+	call_flow_recipient_id SERIAL PRIMARY KEY,
+	call_flow_id INTEGER NOT NULL REFERENCES call_flow(call_flow_id) ON DELETE CASCADE,
+	user_id INTEGER NOT NULL REFERENCES "user"(user_id) ON DELETE CASCADE,
+	language VARCHAR(2),
+	ring_order INTEGER NOT NULL DEFAULT 1,
+	UNIQUE (call_flow_id, user_id)
+);
+
+INSERT INTO call_flow (ring_strategy, ring_timeout, voicemail_greeting, after_hours_message, date_updated)
+SELECT 'simultaneous', 20,
+	'Sorry we missed your call. Please leave your name, event date and a short message after the tone and we will call you back.',
+	'Thanks for calling YD Cocktails. Our office is closed right now.',
+	(NOW() AT TIME ZONE 'America/New_York')
+WHERE NOT EXISTS (SELECT 1 FROM call_flow);
@@ -0,0 +1 @@
+ALTER TABLE lead_marketing DROP COLUMN IF EXISTS tracking_phone_number;
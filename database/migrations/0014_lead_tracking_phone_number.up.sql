@@ -0,0 +1 @@
+ALTER TABLE lead_marketing ADD COLUMN IF NOT EXISTS tracking_phone_number VARCHAR(20);
@@ -0,0 +1 @@
+ALTER TABLE cocktail DROP COLUMN IF EXISTS instructions;
@@ -0,0 +1,10 @@
+ALTER TABLE cocktail ADD COLUMN IF NOT EXISTS instructions TEXT;
+
+INSERT INTO unit (name, abbreviation) VALUES
+	('Ounce', 'oz'),
+	('Dash', 'dash'),
+	('Barspoon', 'bsp'),
+	('Splash', 'splash'),
+	('Piece', 'pc'),
+	('Leaf', 'leaf')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DELETE FROM quote_service WHERE service_id IN (SELECT service_id FROM service WHERE service_type_id = 9);
+DELETE FROM service WHERE service_type_id = 9;
+DELETE FROM service_type WHERE service_type_id = 9;
+
+ALTER TABLE quote_service DROP COLUMN IF EXISTS pricing_rule;
+ALTER TABLE quote DROP COLUMN IF EXISTS travel_miles;
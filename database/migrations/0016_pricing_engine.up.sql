@@ -0,0 +1,27 @@
+ALTER TABLE quote ADD COLUMN IF NOT EXISTS travel_miles NUMERIC(6, 2);
+ALTER TABLE quote_service ADD COLUMN IF NOT EXISTS pricing_rule TEXT;
+
+INSERT INTO unit_type (unit_type_id, type) VALUES
+	(4, 'Ratio'),
+	(5, 'Ad Hoc'),
+	(6, 'Fixed')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO service_type (service_type_id, service_type) VALUES
+	(9, 'Pricing Adjustment')
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('unit_type', 'unit_type_id'), (SELECT MAX(unit_type_id) FROM unit_type));
+SELECT setval(pg_get_serial_sequence('service_type', 'service_type_id'), (SELECT MAX(service_type_id) FROM service_type));
+
+INSERT INTO service (service, suggested_price, service_type_id, unit_type_id)
+SELECT adjustment.service, 0, 9, 6
+FROM (VALUES
+	('Weekend Surcharge'),
+	('Holiday Surcharge'),
+	('Travel Fee'),
+	('Minimum Order Adjustment')
+) AS adjustment(service)
+WHERE NOT EXISTS (
+	SELECT 1 FROM service AS s WHERE s.service = adjustment.service AND s.service_type_id = 9
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quote_discount;
+DROP TABLE IF EXISTS discount_code_service_type;
+DROP TABLE IF EXISTS discount_code;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS discount_code (
+	discount_code_id SERIAL PRIMARY KEY,
+	code VARCHAR(50) NOT NULL UNIQUE,
+	discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percentage', 'fixed')),
+	amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+	expires_at TIMESTAMP,
+	max_redemptions INTEGER,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	date_created TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS discount_code_service_type (
+	discount_code_id INTEGER NOT NULL REFERENCES discount_code(discount_code_id) ON DELETE CASCADE,
+	service_type_id INTEGER NOT NULL REFERENCES service_type(service_type_id) ON DELETE CASCADE,
+	PRIMARY KEY (discount_code_id, service_type_id)
+);
+
+CREATE TABLE IF NOT EXISTS quote_discount (
+	quote_discount_id SERIAL PRIMARY KEY,
+	quote_id INTEGER NOT NULL UNIQUE REFERENCES quote(quote_id) ON DELETE CASCADE,
+	discount_code_id INTEGER NOT NULL REFERENCES discount_code(discount_code_id),
+	amount NUMERIC(10, 2) NOT NULL,
+	date_redeemed TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_discount_discount_code_id ON quote_discount (discount_code_id);
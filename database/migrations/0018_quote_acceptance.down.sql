@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS quote_acceptance;
+
+ALTER TABLE quote_service DROP COLUMN IF EXISTS is_optional;
+ALTER TABLE quote_service DROP COLUMN IF EXISTS package_tier;
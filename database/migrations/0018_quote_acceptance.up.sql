@@ -0,0 +1,14 @@
+ALTER TABLE quote_service ADD COLUMN IF NOT EXISTS package_tier VARCHAR(50);
+ALTER TABLE quote_service ADD COLUMN IF NOT EXISTS is_optional BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS quote_acceptance (
+	quote_acceptance_id SERIAL PRIMARY KEY,
+	quote_id INTEGER NOT NULL UNIQUE REFERENCES quote(quote_id) ON DELETE CASCADE,
+	signature_name VARCHAR(255) NOT NULL,
+	package_tier VARCHAR(50),
+	selected_add_ons TEXT,
+	ip_address VARCHAR(255) NOT NULL,
+	user_agent TEXT,
+	date_accepted TIMESTAMP NOT NULL,
+	date_invoiced TIMESTAMP
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pending_message_status (
+	external_id VARCHAR(255) PRIMARY KEY,
+	status VARCHAR(20) NOT NULL,
+	error_code VARCHAR(10),
+	error_message TEXT,
+	date_updated TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE phone_call DROP COLUMN IF EXISTS date_transcription_claimed;
+ALTER TABLE phone_call DROP COLUMN IF EXISTS transcription_status;
@@ -0,0 +1,6 @@
+ALTER TABLE phone_call ADD COLUMN IF NOT EXISTS transcription_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE phone_call ADD COLUMN IF NOT EXISTS date_transcription_claimed TIMESTAMP;
+
+UPDATE phone_call AS p
+SET transcription_status = 'completed'
+WHERE EXISTS (SELECT 1 FROM phone_call_transcription AS t WHERE t.phone_call_id = p.phone_call_id);
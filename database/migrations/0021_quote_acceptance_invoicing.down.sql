@@ -0,0 +1 @@
+ALTER TABLE quote_acceptance DROP COLUMN IF EXISTS date_invoicing_started;
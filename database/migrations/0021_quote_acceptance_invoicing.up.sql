@@ -0,0 +1 @@
+ALTER TABLE quote_acceptance ADD COLUMN IF NOT EXISTS date_invoicing_started TIMESTAMP;
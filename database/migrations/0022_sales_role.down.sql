@@ -0,0 +1 @@
+DELETE FROM user_role WHERE user_role_id = 4 AND NOT EXISTS (SELECT 1 FROM "user" WHERE user_role_id = 4);
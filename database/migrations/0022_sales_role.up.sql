@@ -0,0 +1,5 @@
+INSERT INTO user_role (user_role_id, role) VALUES
+	(4, 'Sales')
+ON CONFLICT DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('user_role', 'user_role_id'), (SELECT MAX(user_role_id) FROM user_role));
@@ -0,0 +1,770 @@
+package database
+
+import (
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+type PostgresLeadStore struct{}
+
+func (PostgresLeadStore) CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error) {
+	return CreateLeadAndMarketing(quoteForm)
+}
+
+func (PostgresLeadStore) GetOrCreateLeadFromPhoneNumber(quoteForm types.QuoteForm) (int, bool, error) {
+	return GetOrCreateLeadFromPhoneNumber(quoteForm)
+}
+
+func (PostgresLeadStore) GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error) {
+	return GetLeadList(params)
+}
+
+func (PostgresLeadStore) GetReferrals() ([]types.Referral, error) {
+	return GetReferrals()
+}
+
+func (PostgresLeadStore) GetLeadDetails(leadID string) (types.LeadDetails, error) {
+	return GetLeadDetails(leadID)
+}
+
+func (PostgresLeadStore) GetConversionReporting(leadID int) (types.ConversionReporting, error) {
+	return GetConversionReporting(leadID)
+}
+
+func (PostgresLeadStore) GetLeadIDFromPhoneNumber(phoneNumber string) (int, error) {
+	return GetLeadIDFromPhoneNumber(phoneNumber)
+}
+
+func (PostgresLeadStore) GetLeadByStripeCustomerID(stripeCustomerID string) (int, error) {
+	return GetLeadByStripeCustomerID(stripeCustomerID)
+}
+
+func (PostgresLeadStore) IsPhoneNumberInDB(phoneNumber string) (bool, error) {
+	return IsPhoneNumberInDB(phoneNumber)
+}
+
+func (PostgresLeadStore) UpdateLead(form types.UpdateLeadForm) error {
+	return UpdateLead(form)
+}
+
+func (PostgresLeadStore) UpdateLeadMarketing(form types.UpdateLeadMarketingForm) error {
+	return UpdateLeadMarketing(form)
+}
+
+func (PostgresLeadStore) UpdateLeadStatus(id, leadStatusId int) error {
+	return UpdateLeadStatus(id, leadStatusId)
+}
+
+func (PostgresLeadStore) DeleteLead(id int) error {
+	return DeleteLead(id)
+}
+
+func (PostgresLeadStore) AssignStripeCustomerIDToLead(stripeCustomerId string, leadId int) error {
+	return AssignStripeCustomerIDToLead(stripeCustomerId, leadId)
+}
+
+func (PostgresLeadStore) GetLeadStatusList() ([]models.LeadStatus, error) {
+	return GetLeadStatusList()
+}
+
+func (PostgresLeadStore) GetLeadInterestList() ([]models.LeadInterest, error) {
+	return GetLeadInterestList()
+}
+
+func (PostgresLeadStore) GetNextActionList() ([]models.NextAction, error) {
+	return GetNextActionList()
+}
+
+func (PostgresLeadStore) CreateLeadNote(note models.LeadNote) error {
+	return CreateLeadNote(note)
+}
+
+func (PostgresLeadStore) GetLeadNotesByLeadID(leadId int) ([]types.FrontendNote, error) {
+	return GetLeadNotesByLeadID(leadId)
+}
+
+func (PostgresLeadStore) CreateLeadNextAction(leadNextAction types.LeadNextActionForm) error {
+	return CreateLeadNextAction(leadNextAction)
+}
+
+func (PostgresLeadStore) GetLeadNextActionsByLeadID(leadId int) ([]types.LeadNextActionList, error) {
+	return GetLeadNextActionsByLeadID(leadId)
+}
+
+func (PostgresLeadStore) DeleteLeadNextAction(id int) error {
+	return DeleteLeadNextAction(id)
+}
+
+func (PostgresLeadStore) ArchivedLeadsWithLastContactOverTwoWeeks() error {
+	return ArchivedLeadsWithLastContactOverTwoWeeks()
+}
+
+type PostgresQuoteStore struct{}
+
+func (PostgresQuoteStore) CreateLeadQuote(form types.LeadQuoteForm) error {
+	return CreateLeadQuote(form)
+}
+
+func (PostgresQuoteStore) GetLeadQuotes(leadId int) ([]types.LeadQuoteList, error) {
+	return GetLeadQuotes(leadId)
+}
+
+func (PostgresQuoteStore) GetLeadQuoteDetails(quoteId string) (models.Quote, error) {
+	return GetLeadQuoteDetails(quoteId)
+}
+
+func (PostgresQuoteStore) UpdateLeadQuote(form types.LeadQuoteForm) error {
+	return UpdateLeadQuote(form)
+}
+
+func (PostgresQuoteStore) DeleteLeadQuote(id int) error {
+	return DeleteLeadQuote(id)
+}
+
+func (PostgresQuoteStore) CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.QuoteServiceForm) (int, string, error) {
+	return CreateQuickQuote(quickQuote, quoteServices)
+}
+
+func (PostgresQuoteStore) ReplaceQuoteAdjustments(quoteId int, adjustments []types.QuoteServiceForm) error {
+	return ReplaceQuoteAdjustments(quoteId, adjustments)
+}
+
+func (PostgresQuoteStore) GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails, error) {
+	return GetExternalQuoteDetails(externalQuoteId)
+}
+
+func (PostgresQuoteStore) GetQuoteServices(quoteId int) ([]types.QuoteServiceList, error) {
+	return GetQuoteServices(quoteId)
+}
+
+func (PostgresQuoteStore) CreateQuoteService(form types.QuoteServiceForm) error {
+	return CreateQuoteService(form)
+}
+
+func (PostgresQuoteStore) UpdateQuoteService(form types.QuoteServiceForm) error {
+	return UpdateQuoteService(form)
+}
+
+func (PostgresQuoteStore) DeleteQuoteService(id int) error {
+	return DeleteQuoteService(id)
+}
+
+func (PostgresQuoteStore) GetQuoteIDByQuoteServiceID(quoteServiceId int) (int, error) {
+	return GetQuoteIDByQuoteServiceID(quoteServiceId)
+}
+
+func (PostgresQuoteStore) GetServices() ([]models.Service, error) {
+	return GetServices()
+}
+
+func (PostgresQuoteStore) GetServicesList(pageNum int) ([]models.Service, int, error) {
+	return GetServicesList(pageNum)
+}
+
+func (PostgresQuoteStore) GetServiceListByType(serviceTypeId int) ([]models.Service, error) {
+	return GetServiceListByType(serviceTypeId)
+}
+
+func (PostgresQuoteStore) GetQuickQuoteServiceListByTypeID(serviceTypeId int) ([]types.QuickQuoteServiceList, error) {
+	return GetQuickQuoteServiceListByTypeID(serviceTypeId)
+}
+
+func (PostgresQuoteStore) CreateService(form types.ServiceForm) error {
+	return CreateService(form)
+}
+
+func (PostgresQuoteStore) UpdateService(form types.ServiceForm) error {
+	return UpdateService(form)
+}
+
+func (PostgresQuoteStore) DeleteService(id int) error {
+	return DeleteService(id)
+}
+
+func (PostgresQuoteStore) GetServiceTypes() ([]models.ServiceType, error) {
+	return GetServiceTypes()
+}
+
+func (PostgresQuoteStore) GetUnitTypes() ([]models.UnitType, error) {
+	return GetUnitTypes()
+}
+
+func (PostgresQuoteStore) GetDiscountCodes() ([]types.DiscountCodeList, error) {
+	return GetDiscountCodes()
+}
+
+func (PostgresQuoteStore) GetDiscountCodeByCode(code string) (models.DiscountCode, error) {
+	return GetDiscountCodeByCode(code)
+}
+
+func (PostgresQuoteStore) GetDiscountCodeServiceTypeIDs(discountCodeId int) ([]int, error) {
+	return GetDiscountCodeServiceTypeIDs(discountCodeId)
+}
+
+func (PostgresQuoteStore) CountDiscountCodeRedemptions(discountCodeId, excludeQuoteId int) (int, error) {
+	return CountDiscountCodeRedemptions(discountCodeId, excludeQuoteId)
+}
+
+func (PostgresQuoteStore) CreateDiscountCode(form types.DiscountCodeForm) error {
+	return CreateDiscountCode(form)
+}
+
+func (PostgresQuoteStore) DeactivateDiscountCode(discountCodeId int) error {
+	return DeactivateDiscountCode(discountCodeId)
+}
+
+func (PostgresQuoteStore) GetQuoteDiscount(quoteId int) (types.QuoteDiscountDetails, error) {
+	return GetQuoteDiscount(quoteId)
+}
+
+func (PostgresQuoteStore) SetQuoteDiscount(quoteId, discountCodeId int, amount float64) error {
+	return SetQuoteDiscount(quoteId, discountCodeId, amount)
+}
+
+func (PostgresQuoteStore) RedeemQuoteDiscount(quoteId, discountCodeId int, amount float64) (bool, error) {
+	return RedeemQuoteDiscount(quoteId, discountCodeId, amount)
+}
+
+func (PostgresQuoteStore) DeleteQuoteDiscount(quoteId int) error {
+	return DeleteQuoteDiscount(quoteId)
+}
+
+func (PostgresQuoteStore) GetDiscountRedemptionsBySource() ([]types.DiscountRedemptionReport, error) {
+	return GetDiscountRedemptionsBySource()
+}
+
+func (PostgresQuoteStore) GetQuoteAcceptance(quoteId int) (types.QuoteAcceptanceDetails, error) {
+	return GetQuoteAcceptance(quoteId)
+}
+
+func (PostgresQuoteStore) AcceptQuote(acceptance models.QuoteAcceptance, keepQuoteServiceIds []int) error {
+	return AcceptQuote(acceptance, keepQuoteServiceIds)
+}
+
+func (PostgresQuoteStore) ClaimQuoteAcceptanceInvoicing(quoteId int) (bool, error) {
+	return ClaimQuoteAcceptanceInvoicing(quoteId)
+}
+
+func (PostgresQuoteStore) ReleaseQuoteAcceptanceInvoicing(quoteId int) error {
+	return ReleaseQuoteAcceptanceInvoicing(quoteId)
+}
+
+func (PostgresQuoteStore) MarkQuoteAcceptanceInvoiced(quoteId int, dateInvoiced int64) error {
+	return MarkQuoteAcceptanceInvoiced(quoteId, dateInvoiced)
+}
+
+type PostgresInvoiceStore struct{}
+
+func (PostgresInvoiceStore) CreateQuoteInvoice(stripeInvoiceId, invoiceUrl string, quoteId, invoiceTypeId int, dueDate int64) error {
+	return CreateQuoteInvoice(stripeInvoiceId, invoiceUrl, quoteId, invoiceTypeId, dueDate)
+}
+
+func (PostgresInvoiceStore) UpdateInvoiceStatus(stripeInvoiceId string, invoiceStatusId int) error {
+	return UpdateInvoiceStatus(stripeInvoiceId, invoiceStatusId)
+}
+
+func (PostgresInvoiceStore) SetInvoiceStatusToPaid(stripeInvoiceId string, datePaid int64) error {
+	return SetInvoiceStatusToPaid(stripeInvoiceId, datePaid)
+}
+
+func (PostgresInvoiceStore) GetInvoiceByStripeInvoiceID(stripeInvoiceId string) (models.Invoice, error) {
+	return GetInvoiceByStripeInvoiceID(stripeInvoiceId)
+}
+
+func (PostgresInvoiceStore) GetInvoiceTypes() ([]models.InvoiceType, error) {
+	return GetInvoiceTypes()
+}
+
+func (PostgresInvoiceStore) GetLeadQuoteInvoiceDetails(leadID, quoteId string) (types.QuoteDetails, error) {
+	return GetLeadQuoteInvoiceDetails(leadID, quoteId)
+}
+
+func (PostgresInvoiceStore) GetQuoteDetailsByStripeInvoiceID(stripeInvoiceId string) (types.InvoiceQuoteDetails, error) {
+	return GetQuoteDetailsByStripeInvoiceID(stripeInvoiceId)
+}
+
+func (PostgresInvoiceStore) GetLeadQuoteInvoices(quoteId int) ([]types.LeadQuoteInvoice, error) {
+	return GetLeadQuoteInvoices(quoteId)
+}
+
+func (PostgresInvoiceStore) GetRemainingInvoice(quoteId int) (types.LeadQuoteInvoice, error) {
+	return GetRemainingInvoice(quoteId)
+}
+
+func (PostgresInvoiceStore) GetDepositStripeInvoiceID(quoteId int) (string, error) {
+	return GetDepositStripeInvoiceID(quoteId)
+}
+
+func (PostgresInvoiceStore) IsDepositPaid(quoteId int) (bool, error) {
+	return IsDepositPaid(quoteId)
+}
+
+func (PostgresInvoiceStore) CheckQuoteHasInvoiceID(quote int) (bool, error) {
+	return CheckQuoteHasInvoiceID(quote)
+}
+
+func (PostgresInvoiceStore) SetOpenInvoicesToVoid(quoteId int) error {
+	return SetOpenInvoicesToVoid(quoteId)
+}
+
+func (PostgresInvoiceStore) VoidFullInvoice(quoteId int) error {
+	return VoidFullInvoice(quoteId)
+}
+
+type PostgresMessageStore struct{}
+
+func (PostgresMessageStore) SaveSMS(msg models.Message) error {
+	return SaveSMS(msg)
+}
+
+func (PostgresMessageStore) SetSMSToRead(messageId int) error {
+	return SetSMSToRead(messageId)
+}
+
+func (PostgresMessageStore) UpdateSMSStatus(externalId, status, errorCode, errorMessage string) error {
+	return UpdateSMSStatus(externalId, status, errorCode, errorMessage)
+}
+
+func (PostgresMessageStore) GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error) {
+	return GetMessagesByLeadID(leadId)
+}
+
+func (PostgresMessageStore) GetMessageMedia(messageMediaId int) (models.MessageMedia, error) {
+	return GetMessageMedia(messageMediaId)
+}
+
+func (PostgresMessageStore) GetLeadsWithMessages(filters types.ConversationFilters) ([]types.LeadsWithMessages, error) {
+	return GetLeadsWithMessages(filters)
+}
+
+func (PostgresMessageStore) GetUnreadMessagesCount() (int, error) {
+	return GetUnreadMessagesCount()
+}
+
+func (PostgresMessageStore) GetUnreadMessagesInLast5Minutes() (int, error) {
+	return GetUnreadMessagesInLast5Minutes()
+}
+
+func (PostgresMessageStore) CheckIsFirstLeadContact(to string) (bool, error) {
+	return CheckIsFirstLeadContact(to)
+}
+
+func (PostgresMessageStore) GetPreviousConversations(leadId int) ([]types.LeadConversation, error) {
+	return GetPreviousConversations(leadId)
+}
+
+func (PostgresMessageStore) SavePhoneCall(phoneCall models.PhoneCall) error {
+	return SavePhoneCall(phoneCall)
+}
+
+func (PostgresMessageStore) GetPhoneCallBySID(sid string) (models.PhoneCall, error) {
+	return GetPhoneCallBySID(sid)
+}
+
+func (PostgresMessageStore) UpdatePhoneCall(phoneCall models.PhoneCall) error {
+	return UpdatePhoneCall(phoneCall)
+}
+
+func (PostgresMessageStore) SetRecordingURLToPhoneCall(callSid, recordingURL string) error {
+	return SetRecordingURLToPhoneCall(callSid, recordingURL)
+}
+
+func (PostgresMessageStore) CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) error {
+	return CreatePhoneCallTranscription(transcription)
+}
+
+func (PostgresMessageStore) GetPhoneCallsWithoutTranscription() ([]models.PhoneCall, error) {
+	return GetPhoneCallsWithoutTranscription()
+}
+
+func (PostgresMessageStore) ClaimPhoneCallTranscription(phoneCallId int) (bool, error) {
+	return ClaimPhoneCallTranscription(phoneCallId)
+}
+
+func (PostgresMessageStore) ReleasePhoneCallTranscription(phoneCallId int) error {
+	return ReleasePhoneCallTranscription(phoneCallId)
+}
+
+func (PostgresMessageStore) CreateSMSConsentEvent(event models.SMSConsentEvent) error {
+	return CreateSMSConsentEvent(event)
+}
+
+func (PostgresMessageStore) IsPhoneNumberOptedOut(phoneNumber string) (bool, error) {
+	return IsPhoneNumberOptedOut(phoneNumber)
+}
+
+func (PostgresMessageStore) GetSMSConsentEvents(phoneNumber string) ([]types.SMSConsentEventList, error) {
+	return GetSMSConsentEvents(phoneNumber)
+}
+
+func (PostgresMessageStore) CreateScheduledMessage(msg models.ScheduledMessage) (int, error) {
+	return CreateScheduledMessage(msg)
+}
+
+func (PostgresMessageStore) GetScheduledMessage(scheduledMessageId int) (models.ScheduledMessage, error) {
+	return GetScheduledMessage(scheduledMessageId)
+}
+
+func (PostgresMessageStore) GetScheduledMessagesByLeadID(leadId int) ([]types.ScheduledMessageList, error) {
+	return GetScheduledMessagesByLeadID(leadId)
+}
+
+func (PostgresMessageStore) MarkScheduledMessageSent(scheduledMessageId int, externalId string) error {
+	return MarkScheduledMessageSent(scheduledMessageId, externalId)
+}
+
+func (PostgresMessageStore) MarkScheduledMessageFailed(scheduledMessageId int, lastError string) error {
+	return MarkScheduledMessageFailed(scheduledMessageId, lastError)
+}
+
+func (PostgresMessageStore) CancelScheduledMessage(scheduledMessageId, leadId int) error {
+	return CancelScheduledMessage(scheduledMessageId, leadId)
+}
+
+type PostgresEventStore struct{}
+
+func (PostgresEventStore) CreateEvent(form types.EventForm) error {
+	return CreateEvent(form)
+}
+
+func (PostgresEventStore) UpdateEvent(form types.EventForm) error {
+	return UpdateEvent(form)
+}
+
+func (PostgresEventStore) DeleteEvent(id int) error {
+	return DeleteEvent(id)
+}
+
+func (PostgresEventStore) GetEventDetails(eventId string) (models.Event, error) {
+	return GetEventDetails(eventId)
+}
+
+func (PostgresEventStore) GetEventList(leadId int) ([]types.EventList, error) {
+	return GetEventList(leadId)
+}
+
+func (PostgresEventStore) GetPaginatedEventList(pageNum int) ([]types.EventListView, int, error) {
+	return GetPaginatedEventList(pageNum)
+}
+
+func (PostgresEventStore) GetPaginatedEventListByUser(pageNum, userId int) ([]types.EventListView, int, error) {
+	return GetPaginatedEventListByUser(pageNum, userId)
+}
+
+func (PostgresEventStore) IsUserAssignedToEvent(userId, eventId int) (bool, error) {
+	return IsUserAssignedToEvent(userId, eventId)
+}
+
+func (PostgresEventStore) GetEventStaff(eventId int) ([]types.EventStaffList, error) {
+	return GetEventStaff(eventId)
+}
+
+func (PostgresEventStore) CreateEventStaff(form types.EventStaffForm) error {
+	return CreateEventStaff(form)
+}
+
+func (PostgresEventStore) DeleteEventStaff(id int) error {
+	return DeleteEventStaff(id)
+}
+
+func (PostgresEventStore) GetStaffAssignments(userId int) ([]types.StaffAssignment, error) {
+	return GetStaffAssignments(userId)
+}
+
+func (PostgresEventStore) UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId int) error {
+	return UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId)
+}
+
+func (PostgresEventStore) GetPayrollLines(start, end int64) ([]types.PayrollLine, error) {
+	return GetPayrollLines(start, end)
+}
+
+func (PostgresEventStore) MarkPayrollLinesPaid(lines []types.PayrollLine) error {
+	return MarkPayrollLinesPaid(lines)
+}
+
+func (PostgresEventStore) GetEventCocktails(eventId int) ([]types.EventCocktailList, error) {
+	return GetEventCocktails(eventId)
+}
+
+func (PostgresEventStore) CreateEventCocktail(form types.EventCocktailForm) error {
+	return CreateEventCocktail(form)
+}
+
+func (PostgresEventStore) DeleteEventCocktail(id int) error {
+	return DeleteEventCocktail(id)
+}
+
+func (PostgresEventStore) GetCocktails() ([]models.Cocktail, error) {
+	return GetCocktails()
+}
+
+func (PostgresEventStore) GetPaginatedCocktailList(pageNum int) ([]models.Cocktail, int, error) {
+	return GetPaginatedCocktailList(pageNum)
+}
+
+func (PostgresEventStore) GetCocktailDetails(cocktailId string) (models.Cocktail, error) {
+	return GetCocktailDetails(cocktailId)
+}
+
+func (PostgresEventStore) CreateCocktailMany(form types.CreateCocktailForm) error {
+	return CreateCocktailMany(form)
+}
+
+func (PostgresEventStore) UpdateCocktail(form types.CocktailForm) error {
+	return UpdateCocktail(form)
+}
+
+func (PostgresEventStore) DeleteCocktail(id int) error {
+	return DeleteCocktail(id)
+}
+
+func (PostgresEventStore) GetIngredients() ([]models.Ingredient, error) {
+	return GetIngredients()
+}
+
+func (PostgresEventStore) CreateIngredient(form types.IngredientForm) error {
+	return CreateIngredient(form)
+}
+
+func (PostgresEventStore) DeleteIngredient(id int) error {
+	return DeleteIngredient(id)
+}
+
+func (PostgresEventStore) GetUnits() ([]models.Unit, error) {
+	return GetUnits()
+}
+
+func (PostgresEventStore) CreateUnit(form types.UnitForm) error {
+	return CreateUnit(form)
+}
+
+func (PostgresEventStore) DeleteUnit(id int) error {
+	return DeleteUnit(id)
+}
+
+func (PostgresEventStore) GetCocktailIngredients(cocktailId int) ([]types.CocktailIngredientList, error) {
+	return GetCocktailIngredients(cocktailId)
+}
+
+func (PostgresEventStore) SetCocktailIngredient(form types.CocktailIngredientForm) error {
+	return SetCocktailIngredient(form)
+}
+
+func (PostgresEventStore) DeleteCocktailIngredient(cocktailId, ingredientId int) error {
+	return DeleteCocktailIngredient(cocktailId, ingredientId)
+}
+
+type PostgresUserStore struct{}
+
+func (PostgresUserStore) GetUsers() ([]models.User, error) {
+	return GetUsers()
+}
+
+func (PostgresUserStore) GetPaginatedUserList(pageNum int) ([]types.UserList, int, error) {
+	return GetPaginatedUserList(pageNum)
+}
+
+func (PostgresUserStore) GetUserById(id int) (models.User, error) {
+	return GetUserById(id)
+}
+
+func (PostgresUserStore) GetUserByUsername(username string) (models.User, error) {
+	return GetUserByUsername(username)
+}
+
+func (PostgresUserStore) GetUserByPhoneNumber(phoneNumber string) (models.User, error) {
+	return GetUserByPhoneNumber(phoneNumber)
+}
+
+func (PostgresUserStore) GetUserDetails(userID string) (models.User, error) {
+	return GetUserDetails(userID)
+}
+
+func (PostgresUserStore) GetUserIDFromPhoneNumber(phoneNumber string) (int, error) {
+	return GetUserIDFromPhoneNumber(phoneNumber)
+}
+
+func (PostgresUserStore) GetPhoneNumberFromUserID(userID int) (string, error) {
+	return GetPhoneNumberFromUserID(userID)
+}
+
+func (PostgresUserStore) GetForwardPhoneNumber(to, from string) (string, error) {
+	return GetForwardPhoneNumber(to, from)
+}
+
+func (PostgresUserStore) GetUserRoles() ([]models.UserRole, error) {
+	return GetUserRoles()
+}
+
+func (PostgresUserStore) CreateUser(form types.UserForm) error {
+	return CreateUser(form)
+}
+
+func (PostgresUserStore) UpdateUser(form types.UserForm) error {
+	return UpdateUser(form)
+}
+
+func (PostgresUserStore) DeleteUser(id int) error {
+	return DeleteUser(id)
+}
+
+type PostgresSessionStore struct{}
+
+func (PostgresSessionStore) GetSession(userKey string) (models.Session, error) {
+	return GetSession(userKey)
+}
+
+func (PostgresSessionStore) CreateSession(session models.Session) error {
+	return CreateSession(session)
+}
+
+func (PostgresSessionStore) UpdateSession(session models.Session) error {
+	return UpdateSession(session)
+}
+
+func (PostgresSessionStore) DeleteSession(secret string) error {
+	return DeleteSession(secret)
+}
+
+func (PostgresSessionStore) InsertCSRFToken(token models.CSRFToken) error {
+	return InsertCSRFToken(token)
+}
+
+func (PostgresSessionStore) CheckIsTokenUsed(decryptedToken string) (bool, error) {
+	return CheckIsTokenUsed(decryptedToken)
+}
+
+func (PostgresSessionStore) MarkCSRFTokenAsUsed(token string) error {
+	return MarkCSRFTokenAsUsed(token)
+}
+
+type PostgresReportStore struct{}
+
+func (PostgresReportStore) GetEventProfitability(start, end int64) ([]types.EventProfitability, error) {
+	return GetEventProfitability(start, end)
+}
+
+func (PostgresReportStore) GetMarketingAttribution(start, end int64, groupBy string) ([]types.MarketingAttribution, error) {
+	return GetMarketingAttribution(start, end, groupBy)
+}
+
+func (PostgresReportStore) ImportAdSpend(rows []models.AdSpend) error {
+	return ImportAdSpend(rows)
+}
+
+type PostgresJobStore struct{}
+
+func (PostgresJobStore) EnqueueJob(job models.Job) error {
+	return EnqueueJob(job)
+}
+
+func (PostgresJobStore) ScheduleRecurringJob(jobType string, interval, maxAttempts int) error {
+	return ScheduleRecurringJob(jobType, interval, maxAttempts)
+}
+
+func (PostgresJobStore) ClaimNextJob(workerID string) (*models.Job, error) {
+	return ClaimNextJob(workerID)
+}
+
+func (PostgresJobStore) CompleteJob(jobID int) error {
+	return CompleteJob(jobID)
+}
+
+func (PostgresJobStore) RescheduleJob(jobID int, lastError string, runAt int64) error {
+	return RescheduleJob(jobID, lastError, runAt)
+}
+
+func (PostgresJobStore) DeadLetterJob(jobID int, lastError string) error {
+	return DeadLetterJob(jobID, lastError)
+}
+
+func (PostgresJobStore) GetJobList(status string, pageNum int) ([]types.JobList, int, error) {
+	return GetJobList(status, pageNum)
+}
+
+func (PostgresJobStore) GetJobDeadLetters() ([]types.JobDeadLetterList, error) {
+	return GetJobDeadLetters()
+}
+
+func (PostgresJobStore) RequeueJobDeadLetter(jobDeadLetterID int) error {
+	return RequeueJobDeadLetter(jobDeadLetterID)
+}
+
+func (PostgresJobStore) RunJobNow(jobID int) error {
+	return RunJobNow(jobID)
+}
+
+type PostgresSequenceStore struct{}
+
+func (PostgresSequenceStore) GetSMSSequences() ([]types.SMSSequenceDetails, error) {
+	return GetSMSSequences()
+}
+
+func (PostgresSequenceStore) CreateSMSSequence(form types.SMSSequenceForm) error {
+	return CreateSMSSequence(form)
+}
+
+func (PostgresSequenceStore) UpdateSMSSequence(form types.SMSSequenceForm) error {
+	return UpdateSMSSequence(form)
+}
+
+func (PostgresSequenceStore) DeleteSMSSequence(id int) error {
+	return DeleteSMSSequence(id)
+}
+
+func (PostgresSequenceStore) CreateSMSSequenceStep(form types.SMSSequenceStepForm) error {
+	return CreateSMSSequenceStep(form)
+}
+
+func (PostgresSequenceStore) DeleteSMSSequenceStep(id int) error {
+	return DeleteSMSSequenceStep(id)
+}
+
+func (PostgresSequenceStore) GetDueSMSSequenceSteps() ([]types.DueSMSSequenceStep, error) {
+	return GetDueSMSSequenceSteps()
+}
+
+func (PostgresSequenceStore) MarkSMSSequenceStepSent(enrollmentID, stepNumber int, isLastStep bool) error {
+	return MarkSMSSequenceStepSent(enrollmentID, stepNumber, isLastStep)
+}
+
+func (PostgresSequenceStore) StopSMSSequenceEnrollments(leadID int, reason string) error {
+	return StopSMSSequenceEnrollments(leadID, reason)
+}
+
+type PostgresConversationStore struct{}
+
+func (PostgresConversationStore) GetConversation(leadId int) (types.FrontendConversation, error) {
+	return GetConversation(leadId)
+}
+
+func (PostgresConversationStore) UpdateConversation(leadId int, assignedUserId *int, status string, snoozedUntil *int64) error {
+	return UpdateConversation(leadId, assignedUserId, status, snoozedUntil)
+}
+
+func (PostgresConversationStore) ReopenConversation(leadId int) error {
+	return ReopenConversation(leadId)
+}
+
+func (PostgresConversationStore) CreateConversationNote(leadId int, note models.ConversationNote) error {
+	return CreateConversationNote(leadId, note)
+}
+
+type PostgresCallFlowStore struct{}
+
+func (PostgresCallFlowStore) GetCallFlow() (types.CallFlowDetails, error) {
+	return GetCallFlow()
+}
+
+func (PostgresCallFlowStore) UpdateCallFlow(form types.CallFlowForm) error {
+	return UpdateCallFlow(form)
+}
+
+func (PostgresCallFlowStore) CreateCallFlowRecipient(form types.CallFlowRecipientForm) error {
+	return CreateCallFlowRecipient(form)
+}
+
+func (PostgresCallFlowStore) DeleteCallFlowRecipient(id int) error {
+	return DeleteCallFlowRecipient(id)
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+type LeadStore interface {
+	CreateLeadAndMarketing(quoteForm types.QuoteForm) (int, error)
+	GetOrCreateLeadFromPhoneNumber(quoteForm types.QuoteForm) (int, bool, error)
+	GetLeadList(params types.GetLeadsParams) ([]types.LeadList, int, error)
+	GetReferrals() ([]types.Referral, error)
+	GetLeadDetails(leadID string) (types.LeadDetails, error)
+	GetConversionReporting(leadID int) (types.ConversionReporting, error)
+	GetLeadIDFromPhoneNumber(phoneNumber string) (int, error)
+	GetLeadByStripeCustomerID(stripeCustomerID string) (int, error)
+	IsPhoneNumberInDB(phoneNumber string) (bool, error)
+	UpdateLead(form types.UpdateLeadForm) error
+	UpdateLeadMarketing(form types.UpdateLeadMarketingForm) error
+	UpdateLeadStatus(id, leadStatusId int) error
+	DeleteLead(id int) error
+	AssignStripeCustomerIDToLead(stripeCustomerId string, leadId int) error
+	GetLeadStatusList() ([]models.LeadStatus, error)
+	GetLeadInterestList() ([]models.LeadInterest, error)
+	GetNextActionList() ([]models.NextAction, error)
+	CreateLeadNote(note models.LeadNote) error
+	GetLeadNotesByLeadID(leadId int) ([]types.FrontendNote, error)
+	CreateLeadNextAction(leadNextAction types.LeadNextActionForm) error
+	GetLeadNextActionsByLeadID(leadId int) ([]types.LeadNextActionList, error)
+	DeleteLeadNextAction(id int) error
+	ArchivedLeadsWithLastContactOverTwoWeeks() error
+}
+
+type QuoteStore interface {
+	CreateLeadQuote(form types.LeadQuoteForm) error
+	GetLeadQuotes(leadId int) ([]types.LeadQuoteList, error)
+	GetLeadQuoteDetails(quoteId string) (models.Quote, error)
+	UpdateLeadQuote(form types.LeadQuoteForm) error
+	DeleteLeadQuote(id int) error
+	CreateQuickQuote(quickQuote types.QuickQuoteForm, quoteServices []types.QuoteServiceForm) (int, string, error)
+	ReplaceQuoteAdjustments(quoteId int, adjustments []types.QuoteServiceForm) error
+	GetExternalQuoteDetails(externalQuoteId string) (types.ExternalQuoteDetails, error)
+	GetQuoteServices(quoteId int) ([]types.QuoteServiceList, error)
+	CreateQuoteService(form types.QuoteServiceForm) error
+	UpdateQuoteService(form types.QuoteServiceForm) error
+	DeleteQuoteService(id int) error
+	GetQuoteIDByQuoteServiceID(quoteServiceId int) (int, error)
+	GetServices() ([]models.Service, error)
+	GetServicesList(pageNum int) ([]models.Service, int, error)
+	GetServiceListByType(serviceTypeId int) ([]models.Service, error)
+	GetQuickQuoteServiceListByTypeID(serviceTypeId int) ([]types.QuickQuoteServiceList, error)
+	CreateService(form types.ServiceForm) error
+	UpdateService(form types.ServiceForm) error
+	DeleteService(id int) error
+	GetServiceTypes() ([]models.ServiceType, error)
+	GetUnitTypes() ([]models.UnitType, error)
+	GetDiscountCodes() ([]types.DiscountCodeList, error)
+	GetDiscountCodeByCode(code string) (models.DiscountCode, error)
+	GetDiscountCodeServiceTypeIDs(discountCodeId int) ([]int, error)
+	CountDiscountCodeRedemptions(discountCodeId, excludeQuoteId int) (int, error)
+	CreateDiscountCode(form types.DiscountCodeForm) error
+	DeactivateDiscountCode(discountCodeId int) error
+	GetQuoteDiscount(quoteId int) (types.QuoteDiscountDetails, error)
+	SetQuoteDiscount(quoteId, discountCodeId int, amount float64) error
+	RedeemQuoteDiscount(quoteId, discountCodeId int, amount float64) (bool, error)
+	DeleteQuoteDiscount(quoteId int) error
+	GetDiscountRedemptionsBySource() ([]types.DiscountRedemptionReport, error)
+	GetQuoteAcceptance(quoteId int) (types.QuoteAcceptanceDetails, error)
+	AcceptQuote(acceptance models.QuoteAcceptance, keepQuoteServiceIds []int) error
+	ClaimQuoteAcceptanceInvoicing(quoteId int) (bool, error)
+	ReleaseQuoteAcceptanceInvoicing(quoteId int) error
+	MarkQuoteAcceptanceInvoiced(quoteId int, dateInvoiced int64) error
+}
+
+type InvoiceStore interface {
+	CreateQuoteInvoice(stripeInvoiceId, invoiceUrl string, quoteId, invoiceTypeId int, dueDate int64) error
+	UpdateInvoiceStatus(stripeInvoiceId string, invoiceStatusId int) error
+	SetInvoiceStatusToPaid(stripeInvoiceId string, datePaid int64) error
+	GetInvoiceByStripeInvoiceID(stripeInvoiceId string) (models.Invoice, error)
+	GetInvoiceTypes() ([]models.InvoiceType, error)
+	GetLeadQuoteInvoiceDetails(leadID, quoteId string) (types.QuoteDetails, error)
+	GetQuoteDetailsByStripeInvoiceID(stripeInvoiceId string) (types.InvoiceQuoteDetails, error)
+	GetLeadQuoteInvoices(quoteId int) ([]types.LeadQuoteInvoice, error)
+	GetRemainingInvoice(quoteId int) (types.LeadQuoteInvoice, error)
+	GetDepositStripeInvoiceID(quoteId int) (string, error)
+	IsDepositPaid(quoteId int) (bool, error)
+	CheckQuoteHasInvoiceID(quote int) (bool, error)
+	SetOpenInvoicesToVoid(quoteId int) error
+	VoidFullInvoice(quoteId int) error
+}
+
+type MessageStore interface {
+	SaveSMS(msg models.Message) error
+	SetSMSToRead(messageId int) error
+	UpdateSMSStatus(externalId, status, errorCode, errorMessage string) error
+	GetMessagesByLeadID(leadId int) ([]types.FrontendMessage, error)
+	GetMessageMedia(messageMediaId int) (models.MessageMedia, error)
+	GetLeadsWithMessages(filters types.ConversationFilters) ([]types.LeadsWithMessages, error)
+	GetUnreadMessagesCount() (int, error)
+	GetUnreadMessagesInLast5Minutes() (int, error)
+	CheckIsFirstLeadContact(to string) (bool, error)
+	GetPreviousConversations(leadId int) ([]types.LeadConversation, error)
+	SavePhoneCall(phoneCall models.PhoneCall) error
+	GetPhoneCallBySID(sid string) (models.PhoneCall, error)
+	UpdatePhoneCall(phoneCall models.PhoneCall) error
+	SetRecordingURLToPhoneCall(callSid, recordingURL string) error
+	CreatePhoneCallTranscription(transcription models.PhoneCallTranscription) error
+	GetPhoneCallsWithoutTranscription() ([]models.PhoneCall, error)
+	ClaimPhoneCallTranscription(phoneCallId int) (bool, error)
+	ReleasePhoneCallTranscription(phoneCallId int) error
+	CreateSMSConsentEvent(event models.SMSConsentEvent) error
+	IsPhoneNumberOptedOut(phoneNumber string) (bool, error)
+	GetSMSConsentEvents(phoneNumber string) ([]types.SMSConsentEventList, error)
+	CreateScheduledMessage(msg models.ScheduledMessage) (int, error)
+	GetScheduledMessage(scheduledMessageId int) (models.ScheduledMessage, error)
+	GetScheduledMessagesByLeadID(leadId int) ([]types.ScheduledMessageList, error)
+	MarkScheduledMessageSent(scheduledMessageId int, externalId string) error
+	MarkScheduledMessageFailed(scheduledMessageId int, lastError string) error
+	CancelScheduledMessage(scheduledMessageId, leadId int) error
+}
+
+type EventStore interface {
+	CreateEvent(form types.EventForm) error
+	UpdateEvent(form types.EventForm) error
+	DeleteEvent(id int) error
+	GetEventDetails(eventId string) (models.Event, error)
+	GetEventList(leadId int) ([]types.EventList, error)
+	GetPaginatedEventList(pageNum int) ([]types.EventListView, int, error)
+	GetPaginatedEventListByUser(pageNum, userId int) ([]types.EventListView, int, error)
+	IsUserAssignedToEvent(userId, eventId int) (bool, error)
+	GetEventStaff(eventId int) ([]types.EventStaffList, error)
+	CreateEventStaff(form types.EventStaffForm) error
+	DeleteEventStaff(id int) error
+	GetStaffAssignments(userId int) ([]types.StaffAssignment, error)
+	UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId int) error
+	GetPayrollLines(start, end int64) ([]types.PayrollLine, error)
+	MarkPayrollLinesPaid(lines []types.PayrollLine) error
+	GetEventCocktails(eventId int) ([]types.EventCocktailList, error)
+	CreateEventCocktail(form types.EventCocktailForm) error
+	DeleteEventCocktail(id int) error
+	GetCocktails() ([]models.Cocktail, error)
+	GetPaginatedCocktailList(pageNum int) ([]models.Cocktail, int, error)
+	GetCocktailDetails(cocktailId string) (models.Cocktail, error)
+	CreateCocktailMany(form types.CreateCocktailForm) error
+	UpdateCocktail(form types.CocktailForm) error
+	DeleteCocktail(id int) error
+	GetIngredients() ([]models.Ingredient, error)
+	CreateIngredient(form types.IngredientForm) error
+	DeleteIngredient(id int) error
+	GetUnits() ([]models.Unit, error)
+	CreateUnit(form types.UnitForm) error
+	DeleteUnit(id int) error
+	GetCocktailIngredients(cocktailId int) ([]types.CocktailIngredientList, error)
+	SetCocktailIngredient(form types.CocktailIngredientForm) error
+	DeleteCocktailIngredient(cocktailId, ingredientId int) error
+}
+
+type UserStore interface {
+	GetUsers() ([]models.User, error)
+	GetPaginatedUserList(pageNum int) ([]types.UserList, int, error)
+	GetUserById(id int) (models.User, error)
+	GetUserByUsername(username string) (models.User, error)
+	GetUserByPhoneNumber(phoneNumber string) (models.User, error)
+	GetUserDetails(userID string) (models.User, error)
+	GetUserIDFromPhoneNumber(phoneNumber string) (int, error)
+	GetPhoneNumberFromUserID(userID int) (string, error)
+	GetForwardPhoneNumber(to, from string) (string, error)
+	GetUserRoles() ([]models.UserRole, error)
+	CreateUser(form types.UserForm) error
+	UpdateUser(form types.UserForm) error
+	DeleteUser(id int) error
+}
+
+type SessionStore interface {
+	GetSession(userKey string) (models.Session, error)
+	CreateSession(session models.Session) error
+	UpdateSession(session models.Session) error
+	DeleteSession(secret string) error
+	InsertCSRFToken(token models.CSRFToken) error
+	CheckIsTokenUsed(decryptedToken string) (bool, error)
+	MarkCSRFTokenAsUsed(token string) error
+}
+
+type ReportStore interface {
+	GetEventProfitability(start, end int64) ([]types.EventProfitability, error)
+	GetMarketingAttribution(start, end int64, groupBy string) ([]types.MarketingAttribution, error)
+	ImportAdSpend(rows []models.AdSpend) error
+}
+
+type SequenceStore interface {
+	GetSMSSequences() ([]types.SMSSequenceDetails, error)
+	CreateSMSSequence(form types.SMSSequenceForm) error
+	UpdateSMSSequence(form types.SMSSequenceForm) error
+	DeleteSMSSequence(id int) error
+	CreateSMSSequenceStep(form types.SMSSequenceStepForm) error
+	DeleteSMSSequenceStep(id int) error
+	GetDueSMSSequenceSteps() ([]types.DueSMSSequenceStep, error)
+	MarkSMSSequenceStepSent(enrollmentID, stepNumber int, isLastStep bool) error
+	StopSMSSequenceEnrollments(leadID int, reason string) error
+}
+
+type JobStore interface {
+	EnqueueJob(job models.Job) error
+	ScheduleRecurringJob(jobType string, interval, maxAttempts int) error
+	ClaimNextJob(workerID string) (*models.Job, error)
+	CompleteJob(jobID int) error
+	RescheduleJob(jobID int, lastError string, runAt int64) error
+	DeadLetterJob(jobID int, lastError string) error
+	GetJobList(status string, pageNum int) ([]types.JobList, int, error)
+	GetJobDeadLetters() ([]types.JobDeadLetterList, error)
+	RequeueJobDeadLetter(jobDeadLetterID int) error
+	RunJobNow(jobID int) error
+}
+
+type ConversationStore interface {
+	GetConversation(leadId int) (types.FrontendConversation, error)
+	UpdateConversation(leadId int, assignedUserId *int, status string, snoozedUntil *int64) error
+	ReopenConversation(leadId int) error
+	CreateConversationNote(leadId int, note models.ConversationNote) error
+}
+
+type CallFlowStore interface {
+	GetCallFlow() (types.CallFlowDetails, error)
+	UpdateCallFlow(form types.CallFlowForm) error
+	CreateCallFlowRecipient(form types.CallFlowRecipientForm) error
+	DeleteCallFlowRecipient(id int) error
+}
+
+// Stores groups every repository the handlers and services depend on.
+type Stores struct {
+	Leads     LeadStore
+	Quotes    QuoteStore
+	Invoices  InvoiceStore
+	Messages  MessageStore
+	Events    EventStore
+	Users     UserStore
+	Sessions  SessionStore
+	Reports   ReportStore
+	Jobs      JobStore
+	Sequences SequenceStore
+
+	Conversations ConversationStore
+	CallFlows     CallFlowStore
+}
+
+func NewPostgresStores() Stores {
+	return Stores{
+		Leads:     PostgresLeadStore{},
+		Quotes:    PostgresQuoteStore{},
+		Invoices:  PostgresInvoiceStore{},
+		Messages:  PostgresMessageStore{},
+		Events:    PostgresEventStore{},
+		Users:     PostgresUserStore{},
+		Sessions:  PostgresSessionStore{},
+		Reports:   PostgresReportStore{},
+		Jobs:      PostgresJobStore{},
+		Sequences: PostgresSequenceStore{},
+
+		Conversations: PostgresConversationStore{},
+		CallFlows:     PostgresCallFlowStore{},
+	}
+}
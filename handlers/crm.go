@@ -1,21 +1,24 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/conversions"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/davidalvarez305/yd_cocktails/services"
 	"github.com/davidalvarez305/yd_cocktails/sessions"
 	"github.com/davidalvarez305/yd_cocktails/types"
+	"github.com/davidalvarez305/yd_cocktails/utils"
 )
 
 var crmBaseFilePath = constants.CRM_TEMPLATES_DIR + "base.html"
@@ -35,12 +38,122 @@ func createCrmContext() map[string]any {
 	}
 }
 
-func CRMHandler(w http.ResponseWriter, r *http.Request) {
+// crmRouteCapabilities maps every CRM route to the capability a user's role needs to hit it, with numeric
+// path segments written as {id}. Event staff can only read events, so anything that changes one requires ManageEvents.
+var crmRouteCapabilities = map[string]string{
+	"GET /crm/lead":                            constants.ViewLeadsCapability,
+	"GET /crm/lead/{id}":                       constants.ViewLeadsCapability,
+	"GET /crm/lead/{id}/quote/{id}":            constants.ViewLeadsCapability,
+	"GET /crm/lead/{id}/event/{id}":            constants.ViewOwnEventsCapability,
+	"GET /crm/message":                         constants.ViewLeadsCapability,
+	"GET /crm/message/leads":                   constants.ViewLeadsCapability,
+	"GET /crm/message/{id}":                    constants.ViewLeadsCapability,
+	"GET /crm/message/{id}/conversation":       constants.ViewLeadsCapability,
+	"GET /crm/message/media/{id}":              constants.ViewLeadsCapability,
+	"GET /crm/automated-follow-up":             constants.ViewLeadsCapability,
+	"GET /crm/stream":                          constants.ViewLeadsCapability,
+	"GET /crm/sms-sequence":                    constants.ViewLeadsCapability,
+	"GET /crm/user":                            constants.ManageUsersCapability,
+	"GET /crm/user/{id}":                       constants.ManageUsersCapability,
+	"GET /crm/call-flow":                       constants.ManageUsersCapability,
+	"GET /crm/cocktail":                        constants.ViewOwnEventsCapability,
+	"GET /crm/cocktail/{id}":                   constants.ViewOwnEventsCapability,
+	"GET /crm/ingredient":                      constants.ManageEventsCapability,
+	"GET /crm/service":                         constants.EditQuotesCapability,
+	"GET /crm/discount-code":                   constants.EditQuotesCapability,
+	"GET /crm/event":                           constants.ViewOwnEventsCapability,
+	"GET /crm/event/{id}/shopping-list":        constants.ViewOwnEventsCapability,
+	"GET /crm/event/{id}/shopping-list/print":  constants.ViewOwnEventsCapability,
+	"GET /crm/event/{id}/shopping-list/export": constants.ViewOwnEventsCapability,
+	"GET /crm/payroll":                         constants.ManagePayrollCapability,
+	"GET /crm/payroll/export":                  constants.ManagePayrollCapability,
+	"GET /crm/reports":                         constants.ViewReportsCapability,
+	"GET /crm/reports/marketing":               constants.ViewReportsCapability,
+	"GET /crm/reports/marketing/export":        constants.ViewReportsCapability,
+	"GET /crm/jobs":                            constants.ManageJobsCapability,
+
+	"PUT /crm/lead/{id}":                 constants.ViewLeadsCapability,
+	"PUT /crm/lead/{id}/archive":         constants.ViewLeadsCapability,
+	"PUT /crm/lead/{id}/marketing":       constants.ViewLeadsCapability,
+	"PUT /crm/lead/{id}/quote/{id}":      constants.EditQuotesCapability,
+	"PUT /crm/lead/{id}/event/{id}":      constants.ManageEventsCapability,
+	"PUT /crm/message/{id}/read":         constants.ViewLeadsCapability,
+	"PUT /crm/message/{id}/conversation": constants.ViewLeadsCapability,
+	"PUT /crm/sms-sequence/{id}":         constants.ViewLeadsCapability,
+	"PUT /crm/quote-service/{id}":        constants.EditQuotesCapability,
+	"PUT /crm/service/{id}":              constants.EditQuotesCapability,
+	"PUT /crm/user/{id}":                 constants.ManageUsersCapability,
+	"PUT /crm/call-flow":                 constants.ManageUsersCapability,
+	"PUT /crm/cocktail/{id}":             constants.ManageEventsCapability,
+
+	"POST /crm/lead/{id}/note":                        constants.ViewLeadsCapability,
+	"POST /crm/lead/{id}/next-action":                 constants.ViewLeadsCapability,
+	"POST /crm/lead/{id}/scheduled-message":           constants.ViewLeadsCapability,
+	"POST /crm/lead/{id}/quick-quote":                 constants.EditQuotesCapability,
+	"POST /crm/lead/{id}/quote":                       constants.EditQuotesCapability,
+	"POST /crm/lead/{id}/quote/{id}/discount":         constants.EditQuotesCapability,
+	"POST /crm/lead/{id}/quote/{id}/invoice":          constants.SendInvoicesCapability,
+	"POST /crm/lead/{id}/quote/{id}/invoice-reminder": constants.SendInvoicesCapability,
+	"POST /crm/lead/{id}/event":                       constants.ManageEventsCapability,
+	"POST /crm/message/{id}/conversation/note":        constants.ViewLeadsCapability,
+	"POST /crm/sms-sequence":                          constants.ViewLeadsCapability,
+	"POST /crm/sms-sequence/{id}/step":                constants.ViewLeadsCapability,
+	"POST /crm/quote-service":                         constants.EditQuotesCapability,
+	"POST /crm/service":                               constants.EditQuotesCapability,
+	"POST /crm/discount-code":                         constants.EditQuotesCapability,
+	"POST /crm/user":                                  constants.ManageUsersCapability,
+	"POST /crm/call-flow/recipient":                   constants.ManageUsersCapability,
+	"POST /crm/cocktail":                              constants.ManageEventsCapability,
+	"POST /crm/cocktail/{id}/ingredient":              constants.ManageEventsCapability,
+	"POST /crm/ingredient":                            constants.ManageEventsCapability,
+	"POST /crm/unit":                                  constants.ManageEventsCapability,
+	"POST /crm/event/{id}/staff":                      constants.ManageEventsCapability,
+	"POST /crm/event/{id}/cocktail":                   constants.ManageEventsCapability,
+	"POST /crm/payroll/paid":                          constants.ManagePayrollCapability,
+	"POST /crm/reports/ad-spend":                      constants.ViewReportsCapability,
+	"POST /crm/reports/ad-spend/sheet":                constants.ViewReportsCapability,
+	"POST /crm/jobs/run":                              constants.ManageJobsCapability,
+	"POST /crm/jobs/requeue":                          constants.ManageJobsCapability,
+
+	"DELETE /crm/lead/{id}/next-action/{id}":       constants.ViewLeadsCapability,
+	"DELETE /crm/lead/{id}/scheduled-message/{id}": constants.ViewLeadsCapability,
+	"DELETE /crm/lead/{id}/quote/{id}":             constants.EditQuotesCapability,
+	"DELETE /crm/lead/{id}/quote/{id}/discount":    constants.EditQuotesCapability,
+	"DELETE /crm/lead/{id}/event/{id}":             constants.ManageEventsCapability,
+	"DELETE /crm/sms-sequence/{id}":                constants.ViewLeadsCapability,
+	"DELETE /crm/sms-sequence/{id}/step/{id}":      constants.ViewLeadsCapability,
+	"DELETE /crm/quote-service/{id}":               constants.EditQuotesCapability,
+	"DELETE /crm/service/{id}":                     constants.EditQuotesCapability,
+	"DELETE /crm/discount-code/{id}":               constants.EditQuotesCapability,
+	"DELETE /crm/user/{id}":                        constants.ManageUsersCapability,
+	"DELETE /crm/call-flow/recipient/{id}":         constants.ManageUsersCapability,
+	"DELETE /crm/cocktail/{id}":                    constants.ManageEventsCapability,
+	"DELETE /crm/cocktail/{id}/ingredient/{id}":    constants.ManageEventsCapability,
+	"DELETE /crm/ingredient/{id}":                  constants.ManageEventsCapability,
+	"DELETE /crm/unit/{id}":                        constants.ManageEventsCapability,
+	"DELETE /crm/event/{id}/staff/{id}":            constants.ManageEventsCapability,
+	"DELETE /crm/event/{id}/cocktail/{id}":         constants.ManageEventsCapability,
+}
+
+// crmRouteCapability returns the capability needed for a CRM route, or "" for a route that isn't mapped,
+// which no role has.
+func crmRouteCapability(method, path string) string {
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, part := range parts {
+		if part != "" && helpers.IsNumeric(part) {
+			parts[i] = "{id}"
+		}
+	}
+
+	return crmRouteCapabilities[method+" "+strings.Join(parts, "/")]
+}
+
+func (s *Server) CRMHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := createCrmContext()
 	ctx["PagePath"] = constants.RootDomain + r.URL.Path
 	path := r.URL.Path
 
-	unreadMessages, err := database.GetUnreadMessagesCount()
+	unreadMessages, err := s.Messages.GetUnreadMessagesCount()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting unread messages from DB.", http.StatusInternalServerError)
@@ -55,7 +168,7 @@ func CRMHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	phoneNumber, err := database.GetPhoneNumberFromUserID(values.UserID)
+	phoneNumber, err := s.Users.GetPhoneNumberFromUserID(values.UserID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting phone number from user ID.", http.StatusInternalServerError)
@@ -63,126 +176,208 @@ func CRMHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx["CRMUserPhoneNumber"] = phoneNumber
 
+	userRoleId, ok := r.Context().Value("user_role_id").(int)
+	if !ok {
+		http.Error(w, "Error retrieving user role.", http.StatusInternalServerError)
+		return
+	}
+	ctx["Can"] = helpers.GetCapabilities(userRoleId)
+
+	if !helpers.HasCapability(userRoleId, crmRouteCapability(r.Method, path)) {
+		if r.Method == http.MethodGet {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+			return
+		}
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "You do not have permission to do that.",
+			},
+		}
+		w.WriteHeader(http.StatusForbidden)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		parts := strings.Split(path, "/")
 
 		if strings.HasPrefix(path, "/crm/user/") {
 			if len(path) > len("/crm/user/") && helpers.IsNumeric(path[len("/crm/user/"):]) {
-				GetUserDetail(w, r, ctx)
+				s.GetUserDetail(w, r, ctx)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/cocktail/") {
 			if len(path) > len("/crm/cocktail/") && helpers.IsNumeric(path[len("/crm/cocktail/"):]) {
-				GetCocktailDetail(w, r, ctx)
+				s.GetCocktailDetail(w, r, ctx)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/lead/") {
 			if len(path) > len("/crm/lead/") && helpers.IsNumeric(path[len("/crm/lead/"):]) {
-				GetLeadDetail(w, r, ctx)
+				s.GetLeadDetail(w, r, ctx)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "event" && helpers.IsNumeric(parts[3]) {
-				GetEventDetail(w, r, ctx)
+				s.GetEventDetail(w, r, ctx)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "quote" && helpers.IsNumeric(parts[3]) {
-				GetLeadQuoteDetail(w, r, ctx)
+				s.GetLeadQuoteDetail(w, r, ctx)
 				return
 			}
 			return
 		}
 
+		if strings.HasPrefix(path, "/crm/event/") {
+			if len(parts) >= 6 && parts[4] == "shopping-list" && parts[5] == "print" && helpers.IsNumeric(parts[3]) {
+				s.GetEventShoppingListPrint(w, r, ctx)
+				return
+			}
+			if len(parts) >= 6 && parts[4] == "shopping-list" && parts[5] == "export" && helpers.IsNumeric(parts[3]) {
+				s.GetEventShoppingListExport(w, r)
+				return
+			}
+			if len(parts) >= 5 && parts[4] == "shopping-list" && helpers.IsNumeric(parts[3]) {
+				s.GetEventShoppingList(w, r)
+				return
+			}
+		}
+
 		if strings.HasPrefix(path, "/crm/message/leads") {
-			GetLeadsWithMessages(w, r, ctx)
+			s.GetLeadsWithMessages(w, r, ctx)
 			return
 		}
 
 		if strings.HasPrefix(path, "/crm/message") {
+			if len(parts) >= 5 && parts[3] == "media" && helpers.IsNumeric(parts[4]) {
+				s.GetMessageMedia(w, r)
+				return
+			}
 			if len(path) > len("/crm/message/") && helpers.IsNumeric(path[len("/crm/message/"):]) {
-				GetMessagesByLeadID(w, r, ctx)
+				s.GetMessagesByLeadID(w, r, ctx)
+				return
+			}
+			if len(parts) >= 5 && parts[4] == "conversation" && helpers.IsNumeric(parts[3]) {
+				s.GetConversation(w, r)
 				return
 			}
 		}
 
 		switch path {
 		case "/crm/lead":
-			GetLeads(w, r, ctx)
+			s.GetLeads(w, r, ctx)
 		case "/crm/user":
-			GetUsers(w, r, ctx)
+			s.GetUsers(w, r, ctx)
 		case "/crm/cocktail":
-			GetCocktails(w, r, ctx)
+			s.GetCocktails(w, r, ctx)
+		case "/crm/ingredient":
+			s.GetIngredients(w, r, ctx)
 		case "/crm/service":
-			GetServices(w, r, ctx)
+			s.GetServices(w, r, ctx)
+		case "/crm/discount-code":
+			s.GetDiscountCodes(w, r, ctx)
 		case "/crm/message":
-			GetMessages(w, r, ctx)
+			s.GetMessages(w, r, ctx)
 		case "/crm/event":
-			GetEvents(w, r, ctx)
+			s.GetEvents(w, r, ctx)
 		case "/crm/automated-follow-up":
-			GetAutomatedFollowUpMessage(w, r)
+			s.GetAutomatedFollowUpMessage(w, r)
+		case "/crm/payroll":
+			s.GetPayroll(w, r, ctx)
+		case "/crm/payroll/export":
+			s.GetPayrollExport(w, r)
+		case "/crm/reports":
+			s.GetReports(w, r, ctx)
+		case "/crm/reports/marketing":
+			s.GetMarketingReport(w, r, ctx)
+		case "/crm/reports/marketing/export":
+			s.GetMarketingReportExport(w, r)
+		case "/crm/jobs":
+			s.GetJobs(w, r, ctx)
+		case "/crm/sms-sequence":
+			s.GetSMSSequences(w, r, ctx)
+		case "/crm/stream":
+			s.GetCRMStream(w, r)
+		case "/crm/call-flow":
+			s.GetCallFlow(w, r, ctx)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
 	case http.MethodPut:
 		parts := strings.Split(path, "/")
 		if strings.HasPrefix(path, "/crm/quote-service/") {
-			PutQuoteService(w, r)
+			s.PutQuoteService(w, r)
 			return
 		}
 
 		if strings.HasPrefix(path, "/crm/service/") {
-			PutService(w, r)
+			s.PutService(w, r)
 			return
 		}
 
 		if strings.HasPrefix(path, "/crm/message/") {
 			if len(parts) >= 5 && parts[4] == "read" && helpers.IsNumeric(parts[3]) {
-				SetSMSToRead(w, r)
+				s.SetSMSToRead(w, r)
+				return
+			}
+			if len(parts) >= 5 && parts[4] == "conversation" && helpers.IsNumeric(parts[3]) {
+				s.PutConversation(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/user/") {
 			if len(path) > len("/crm/user/") && helpers.IsNumeric(path[len("/crm/user/"):]) {
-				PutUser(w, r)
+				s.PutUser(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/cocktail/") {
 			if len(path) > len("/crm/cocktail/") && helpers.IsNumeric(path[len("/crm/cocktail/"):]) {
-				PutCocktail(w, r)
+				s.PutCocktail(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/sms-sequence/") {
+			if len(path) > len("/crm/sms-sequence/") && helpers.IsNumeric(path[len("/crm/sms-sequence/"):]) {
+				s.PutSMSSequence(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/lead/") {
 			if len(path) > len("/crm/lead/") && strings.Contains(path, "archive") {
-				ArchiveLead(w, r)
+				s.ArchiveLead(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "marketing" && helpers.IsNumeric(parts[3]) {
-				PutLeadMarketing(w, r)
+				s.PutLeadMarketing(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "event" && helpers.IsNumeric(parts[3]) {
-				PutEvent(w, r)
+				s.PutEvent(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "quote" && helpers.IsNumeric(parts[3]) {
-				PutLeadQuote(w, r)
+				s.PutLeadQuote(w, r)
 				return
 			}
 			if len(path) > len("/crm/lead/") && helpers.IsNumeric(path[len("/crm/lead/"):]) {
-				PutLead(w, r)
+				s.PutLead(w, r)
 				return
 			}
 		}
 		switch path {
+		case "/crm/call-flow":
+			s.PutCallFlow(w, r)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
@@ -190,104 +385,210 @@ func CRMHandler(w http.ResponseWriter, r *http.Request) {
 		parts := strings.Split(path, "/")
 
 		if strings.HasPrefix(path, "/crm/quote-service/") {
-			DeleteQuoteService(w, r)
+			s.DeleteQuoteService(w, r)
 			return
 		}
 
 		if strings.HasPrefix(path, "/crm/user/") {
 			if len(path) > len("/crm/user/") && helpers.IsNumeric(path[len("/crm/user/"):]) {
-				DeleteUser(w, r)
+				s.DeleteUser(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/cocktail/") {
+			if len(parts) >= 6 && parts[4] == "ingredient" && helpers.IsNumeric(parts[3]) && helpers.IsNumeric(parts[5]) {
+				s.DeleteCocktailIngredient(w, r)
+				return
+			}
 			if len(path) > len("/crm/cocktail/") && helpers.IsNumeric(path[len("/crm/cocktail/"):]) {
-				DeleteCocktail(w, r)
+				s.DeleteCocktail(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/ingredient/") {
+			if len(path) > len("/crm/ingredient/") && helpers.IsNumeric(path[len("/crm/ingredient/"):]) {
+				s.DeleteIngredient(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/unit/") {
+			if len(path) > len("/crm/unit/") && helpers.IsNumeric(path[len("/crm/unit/"):]) {
+				s.DeleteUnit(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/discount-code/") {
+			if len(path) > len("/crm/discount-code/") && helpers.IsNumeric(path[len("/crm/discount-code/"):]) {
+				s.DeleteDiscountCode(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/sms-sequence/") {
+			if len(parts) >= 6 && parts[4] == "step" && helpers.IsNumeric(parts[5]) {
+				s.DeleteSMSSequenceStep(w, r)
+				return
+			}
+			if len(path) > len("/crm/sms-sequence/") && helpers.IsNumeric(path[len("/crm/sms-sequence/"):]) {
+				s.DeleteSMSSequence(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/lead/") {
 			if len(parts) >= 5 && parts[4] == "event" && helpers.IsNumeric(parts[3]) {
-				DeleteEvent(w, r)
+				s.DeleteEvent(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "next-action" && helpers.IsNumeric(parts[3]) {
-				DeleteLeadNextAction(w, r)
+				s.DeleteLeadNextAction(w, r)
+				return
+			}
+			if len(parts) >= 6 && parts[4] == "scheduled-message" && helpers.IsNumeric(parts[5]) {
+				s.DeleteScheduledMessage(w, r)
+				return
+			}
+			if len(parts) >= 7 && parts[4] == "quote" && parts[6] == "discount" && helpers.IsNumeric(parts[5]) {
+				s.DeleteQuoteDiscount(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "quote" && helpers.IsNumeric(parts[3]) {
-				DeleteLeadQuote(w, r)
+				s.DeleteLeadQuote(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/event/") {
 			if len(parts) >= 5 && parts[4] == "staff" && helpers.IsNumeric(parts[3]) {
-				DeleteEventStaff(w, r)
+				s.DeleteEventStaff(w, r)
+				return
+			}
+			if len(parts) >= 6 && parts[4] == "cocktail" && helpers.IsNumeric(parts[3]) && helpers.IsNumeric(parts[5]) {
+				s.DeleteEventCocktail(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/service/") {
-			DeleteService(w, r)
+			s.DeleteService(w, r)
+			return
+		}
+
+		if strings.HasPrefix(path, "/crm/call-flow/recipient/") {
+			s.DeleteCallFlowRecipient(w, r)
 			return
 		}
 	case http.MethodPost:
 		parts := strings.Split(path, "/")
 
 		if strings.HasPrefix(path, "/crm/quote-service") {
-			PostQuoteService(w, r)
+			s.PostQuoteService(w, r)
 			return
 		}
 
 		if strings.HasPrefix(path, "/crm/event/") {
 			if len(parts) >= 5 && parts[4] == "staff" && helpers.IsNumeric(parts[3]) {
-				PostEventStaff(w, r)
+				s.PostEventStaff(w, r)
+				return
+			}
+			if len(parts) >= 5 && parts[4] == "cocktail" && helpers.IsNumeric(parts[3]) {
+				s.PostEventCocktail(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/cocktail/") {
+			if len(parts) >= 5 && parts[4] == "ingredient" && helpers.IsNumeric(parts[3]) {
+				s.PostCocktailIngredient(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/sms-sequence/") {
+			if len(parts) >= 5 && parts[4] == "step" && helpers.IsNumeric(parts[3]) {
+				s.PostSMSSequenceStep(w, r)
+				return
+			}
+		}
+
+		if strings.HasPrefix(path, "/crm/message/") {
+			if len(parts) >= 6 && parts[4] == "conversation" && parts[5] == "note" && helpers.IsNumeric(parts[3]) {
+				s.PostConversationNote(w, r)
 				return
 			}
 		}
 
 		if strings.HasPrefix(path, "/crm/lead/") {
 			if strings.Contains(path, "quick-quote") {
-				PostQuickQuote(w, r)
+				s.PostQuickQuote(w, r)
 				return
 			}
 			if strings.Contains(path, "invoice-reminder") {
-				PostSendInvoiceReminder(w, r)
+				s.PostSendInvoiceReminder(w, r)
 				return
 			}
 			if strings.Contains(path, "invoice") {
-				PostSendInvoice(w, r)
+				s.PostSendInvoice(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "event" && helpers.IsNumeric(parts[3]) {
-				PostEvent(w, r)
+				s.PostEvent(w, r)
+				return
+			}
+			if len(parts) >= 7 && parts[4] == "quote" && parts[6] == "discount" && helpers.IsNumeric(parts[5]) {
+				s.PostQuoteDiscount(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "quote" && helpers.IsNumeric(parts[3]) {
-				PostLeadQuote(w, r)
+				s.PostLeadQuote(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "note" && helpers.IsNumeric(parts[3]) {
-				PostLeadNote(w, r)
+				s.PostLeadNote(w, r)
+				return
+			}
+			if len(parts) >= 5 && parts[4] == "scheduled-message" && helpers.IsNumeric(parts[3]) {
+				s.PostScheduledMessage(w, r)
 				return
 			}
 			if len(parts) >= 5 && parts[4] == "next-action" && helpers.IsNumeric(parts[3]) {
-				PostLeadNextAction(w, r)
+				s.PostLeadNextAction(w, r)
 				return
 			}
 		}
 		switch path {
 		case "/crm/service":
-			PostService(w, r)
+			s.PostService(w, r)
+		case "/crm/discount-code":
+			s.PostDiscountCode(w, r)
 		case "/crm/user":
-			PostUser(w, r)
+			s.PostUser(w, r)
 		case "/crm/cocktail":
-			PostCocktail(w, r)
+			s.PostCocktail(w, r)
+		case "/crm/ingredient":
+			s.PostIngredient(w, r)
+		case "/crm/unit":
+			s.PostUnit(w, r)
+		case "/crm/payroll/paid":
+			s.PostPayrollPaid(w, r)
+		case "/crm/reports/ad-spend":
+			s.PostAdSpendUpload(w, r)
+		case "/crm/reports/ad-spend/sheet":
+			s.PostAdSpendSheetImport(w, r)
+		case "/crm/jobs/run":
+			s.PostRunJob(w, r)
+		case "/crm/jobs/requeue":
+			s.PostRequeueJob(w, r)
+		case "/crm/sms-sequence":
+			s.PostSMSSequence(w, r)
+		case "/crm/call-flow/recipient":
+			s.PostCallFlowRecipient(w, r)
 		case "/crm/quote-service":
-			PostSendInvoice(w, r)
+			s.PostSendInvoice(w, r)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
@@ -296,7 +597,7 @@ func CRMHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func GetLeads(w http.ResponseWriter, r *http.Request, ctx map[string]interface{}) {
+func (s *Server) GetLeads(w http.ResponseWriter, r *http.Request, ctx map[string]interface{}) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "leads.html"
 	leadsTable := constants.PARTIAL_TEMPLATES_DIR + "leads_table.html"
 	files := []string{crmBaseFilePath, crmFooterFilePath, leadsTable, baseFile}
@@ -322,28 +623,28 @@ func GetLeads(w http.ResponseWriter, r *http.Request, ctx map[string]interface{}
 	params.LeadStatusID = helpers.SafeStringToIntPointer(r.URL.Query().Get("lead_status_id"))
 	params.NextActionID = helpers.SafeStringToIntPointer(r.URL.Query().Get("next_action_id"))
 
-	leads, totalRows, err := database.GetLeadList(params)
+	leads, totalRows, err := s.Leads.GetLeadList(params)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting leads from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	interests, err := database.GetLeadInterestList()
+	interests, err := s.Leads.GetLeadInterestList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting vending locations.", http.StatusInternalServerError)
 		return
 	}
 
-	statuses, err := database.GetLeadStatusList()
+	statuses, err := s.Leads.GetLeadStatusList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting vending locations.", http.StatusInternalServerError)
 		return
 	}
 
-	nextActions, err := database.GetNextActionList()
+	nextActions, err := s.Leads.GetNextActionList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting vending locations.", http.StatusInternalServerError)
@@ -371,7 +672,7 @@ func GetLeads(w http.ResponseWriter, r *http.Request, ctx map[string]interface{}
 	helpers.ServeContent(w, files, data)
 }
 
-func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	fileName := "lead_detail.html"
 	eventForm := constants.PARTIAL_TEMPLATES_DIR + "event_form.html"
 	eventTable := constants.PARTIAL_TEMPLATES_DIR + "events_table.html"
@@ -384,7 +685,10 @@ func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	createLeadNextActionForm := constants.PARTIAL_TEMPLATES_DIR + "create_lead_next_action_form.html"
 	leadNextActionsTable := constants.PARTIAL_TEMPLATES_DIR + "lead_next_actions_table.html"
 	createQuickQuoteForm := constants.PARTIAL_TEMPLATES_DIR + "create_quick_quote_form.html"
-	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, eventForm, eventTable, leadQuoteForm, leadQuoteTable, createLeadMessageForm, leadMessagesTemplate, createLeadNoteForm, leadNotesTemplate, createLeadNextActionForm, leadNextActionsTable, createQuickQuoteForm}
+	smsConsentEventsTable := constants.PARTIAL_TEMPLATES_DIR + "sms_consent_events_table.html"
+	scheduledMessagesTemplate := constants.PARTIAL_TEMPLATES_DIR + "scheduled_messages.html"
+	conversationPanel := constants.PARTIAL_TEMPLATES_DIR + "conversation_panel.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, eventForm, eventTable, leadQuoteForm, leadQuoteTable, createLeadMessageForm, leadMessagesTemplate, createLeadNoteForm, leadNotesTemplate, createLeadNextActionForm, leadNextActionsTable, createQuickQuoteForm, smsConsentEventsTable, scheduledMessagesTemplate, conversationPanel}
 	nonce, ok := r.Context().Value("nonce").(string)
 	if !ok {
 		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
@@ -399,49 +703,49 @@ func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 
 	leadId := strings.TrimPrefix(r.URL.Path, "/crm/lead/")
 
-	leadDetails, err := database.GetLeadDetails(leadId)
+	leadDetails, err := s.Leads.GetLeadDetails(leadId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead details from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	events, err := database.GetEventList(leadDetails.LeadID)
+	events, err := s.Events.GetEventList(leadDetails.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting events.", http.StatusInternalServerError)
 		return
 	}
 
-	leadQuotes, err := database.GetLeadQuotes(leadDetails.LeadID)
+	leadQuotes, err := s.Quotes.GetLeadQuotes(leadDetails.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead quotes.", http.StatusInternalServerError)
 		return
 	}
 
-	bartenders, err := database.GetUsers()
+	bartenders, err := s.Users.GetUsers()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bartenders.", http.StatusInternalServerError)
 		return
 	}
 
-	leadInterestList, err := database.GetLeadInterestList()
+	leadInterestList, err := s.Leads.GetLeadInterestList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead interest list.", http.StatusInternalServerError)
 		return
 	}
 
-	leadStatusList, err := database.GetLeadStatusList()
+	leadStatusList, err := s.Leads.GetLeadStatusList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead status list.", http.StatusInternalServerError)
 		return
 	}
 
-	nextActionList, err := database.GetNextActionList()
+	nextActionList, err := s.Leads.GetNextActionList()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting next action list.", http.StatusInternalServerError)
@@ -451,63 +755,91 @@ func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	var params types.GetLeadsParams
 	params.PageNum = helpers.SafeStringToPointer(r.URL.Query().Get("page_num"))
 
-	referrals, err := database.GetReferrals()
+	referrals, err := s.Leads.GetReferrals()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting referrals.", http.StatusInternalServerError)
 		return
 	}
 
-	leadMessages, err := database.GetMessagesByLeadID(leadDetails.LeadID)
+	leadMessages, err := s.Messages.GetMessagesByLeadID(leadDetails.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead messages.", http.StatusInternalServerError)
 		return
 	}
 
-	leadNotes, err := database.GetLeadNotesByLeadID(leadDetails.LeadID)
+	scheduledMessages, err := s.Messages.GetScheduledMessagesByLeadID(leadDetails.LeadID)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting scheduled messages.", http.StatusInternalServerError)
+		return
+	}
+
+	conversation, err := s.Conversations.GetConversation(leadDetails.LeadID)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting conversation.", http.StatusInternalServerError)
+		return
+	}
+
+	conversationOwners, err := s.getConversationOwners()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting users.", http.StatusInternalServerError)
+		return
+	}
+
+	smsConsentEvents, err := s.Messages.GetSMSConsentEvents(leadDetails.PhoneNumber)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting text message consent history.", http.StatusInternalServerError)
+		return
+	}
+
+	leadNotes, err := s.Leads.GetLeadNotesByLeadID(leadDetails.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead notes.", http.StatusInternalServerError)
 		return
 	}
 
-	leadNextActions, err := database.GetLeadNextActionsByLeadID(leadDetails.LeadID)
+	leadNextActions, err := s.Leads.GetLeadNextActionsByLeadID(leadDetails.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead next actions.", http.StatusInternalServerError)
 		return
 	}
 
-	alcoholQuoteServices, err := database.GetServiceListByType(constants.AlcoholServiceTypeID)
+	alcoholQuoteServices, err := s.Quotes.GetServiceListByType(constants.AlcoholServiceTypeID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting alcohol quote services.", http.StatusInternalServerError)
 		return
 	}
 
-	barRentalQuoteServices, err := database.GetServiceListByType(constants.BarRentalServiceTypeID)
+	barRentalQuoteServices, err := s.Quotes.GetServiceListByType(constants.BarRentalServiceTypeID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bar rental quote services.", http.StatusInternalServerError)
 		return
 	}
 
-	coolerRentalQuoteServices, err := database.GetServiceListByType(constants.CoolerRentalServiceTypeID)
+	coolerRentalQuoteServices, err := s.Quotes.GetServiceListByType(constants.CoolerRentalServiceTypeID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bar rental quote services.", http.StatusInternalServerError)
 		return
 	}
 
-	bartendingAddOnServices, err := database.GetQuickQuoteServiceListByTypeID(constants.BartendingAddOnServiceTypeID)
+	bartendingAddOnServices, err := s.Quotes.GetQuickQuoteServiceListByTypeID(constants.BartendingAddOnServiceTypeID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bartending add on services.", http.StatusInternalServerError)
 		return
 	}
 
-	bartendingHourlyServices, err := database.GetServiceListByType(constants.BartendingServiceTypeID)
+	bartendingHourlyServices, err := s.Quotes.GetServiceListByType(constants.BartendingServiceTypeID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bartending service.", http.StatusInternalServerError)
@@ -528,6 +860,10 @@ func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	data["NextActionList"] = nextActionList
 	data["LeadNotes"] = leadNotes
 	data["LeadMessages"] = leadMessages
+	data["SMSConsentEvents"] = smsConsentEvents
+	data["ScheduledMessages"] = scheduledMessages
+	data["Conversation"] = conversation
+	data["ConversationOwners"] = conversationOwners
 	data["LeadNextActions"] = leadNextActions
 	data["BarRentalQuoteServices"] = barRentalQuoteServices
 	data["CoolerRentalQuoteServices"] = coolerRentalQuoteServices
@@ -540,7 +876,7 @@ func GetLeadDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PutLead(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutLead(w http.ResponseWriter, r *http.Request) {
 	token, err := helpers.GenerateTokenInHeader(w, r)
 	if err != nil {
 		fmt.Printf("Error generating token: %+v\n", err)
@@ -590,7 +926,7 @@ func PutLead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateLead(form)
+	err = s.Leads.UpdateLead(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -617,7 +953,7 @@ func PutLead(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PutLeadMarketing(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutLeadMarketing(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 
 	if err != nil {
@@ -651,7 +987,7 @@ func PutLeadMarketing(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateLeadMarketing(form)
+	err = s.Leads.UpdateLeadMarketing(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -678,7 +1014,7 @@ func PutLeadMarketing(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func ArchiveLead(w http.ResponseWriter, r *http.Request) {
+func (s *Server) ArchiveLead(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -700,7 +1036,7 @@ func ArchiveLead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateLeadStatus(leadId, constants.ArchivedLeadStatusID)
+	err = s.Leads.UpdateLeadStatus(leadId, constants.ArchivedLeadStatusID)
 	if err != nil {
 		fmt.Printf("Error archiving lead: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -718,7 +1054,7 @@ func ArchiveLead(w http.ResponseWriter, r *http.Request) {
 	var params types.GetLeadsParams
 	params.PageNum = helpers.SafeStringToPointer(r.URL.Query().Get("page_num"))
 
-	leads, totalRows, err := database.GetLeadList(params)
+	leads, totalRows, err := s.Leads.GetLeadList(params)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -752,7 +1088,7 @@ func ArchiveLead(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostEvent(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostEvent(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -786,7 +1122,7 @@ func PostEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateEvent(form)
+	err = s.Events.CreateEvent(form)
 	if err != nil {
 		fmt.Printf("Error creating event: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -817,7 +1153,7 @@ func PostEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if constants.Production {
-		lead, err := database.GetConversionReporting(int(helpers.SafeInt(form.LeadID)))
+		lead, err := s.Leads.GetConversionReporting(int(helpers.SafeInt(form.LeadID)))
 		if err != nil {
 			fmt.Printf("Error getting conversion: %+v\n", err)
 			tmplCtx := types.DynamicPartialTemplate{
@@ -909,7 +1245,7 @@ func PostEvent(w http.ResponseWriter, r *http.Request) {
 		go conversions.SendGoogleConversion(googlePayload)
 	}
 
-	events, err := database.GetEventList(helpers.SafeInt(form.LeadID))
+	events, err := s.Events.GetEventList(helpers.SafeInt(form.LeadID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -935,13 +1271,14 @@ func PostEvent(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetEventDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetEventDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	fileName := "event_detail.html"
 	createEventStaffForm := constants.PARTIAL_TEMPLATES_DIR + "create_event_staff_form.html"
 	eventStaffTable := constants.PARTIAL_TEMPLATES_DIR + "event_staff_table.html"
 	createEventCocktailsForm := constants.PARTIAL_TEMPLATES_DIR + "create_event_cocktails_form.html"
 	eventCocktailsTable := constants.PARTIAL_TEMPLATES_DIR + "event_cocktails_table.html"
-	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, createEventStaffForm, eventStaffTable, createEventCocktailsForm, eventCocktailsTable}
+	eventShoppingList := constants.PARTIAL_TEMPLATES_DIR + "event_shopping_list.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, createEventStaffForm, eventStaffTable, createEventCocktailsForm, eventCocktailsTable, eventShoppingList}
 	nonce, ok := r.Context().Value("nonce").(string)
 	if !ok {
 		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
@@ -961,42 +1298,54 @@ func GetEventDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any)
 		return
 	}
 
-	eventDetails, err := database.GetEventDetails(fmt.Sprint(eventId))
+	canView, err := s.canViewEvent(r, eventId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error checking event assignment.", http.StatusInternalServerError)
+		return
+	}
+
+	if !canView {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	eventDetails, err := s.Events.GetEventDetails(fmt.Sprint(eventId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting event details from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	eventStaff, err := database.GetEventStaff(eventId)
+	eventStaff, err := s.Events.GetEventStaff(eventId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting event staff.", http.StatusInternalServerError)
 		return
 	}
 
-	users, err := database.GetUsers()
+	users, err := s.Users.GetUsers()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting users.", http.StatusInternalServerError)
 		return
 	}
 
-	userRoles, err := database.GetUserRoles()
+	userRoles, err := s.Users.GetUserRoles()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting user roles.", http.StatusInternalServerError)
 		return
 	}
 
-	eventCocktails, err := database.GetEventCocktails(eventId)
+	eventCocktails, err := s.Events.GetEventCocktails(eventId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting event cocktails.", http.StatusInternalServerError)
 		return
 	}
 
-	cocktails, err := database.GetCocktails()
+	cocktails, err := s.Events.GetCocktails()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting cocktails.", http.StatusInternalServerError)
@@ -1013,13 +1362,23 @@ func GetEventDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any)
 	data["UserRoles"] = userRoles
 	data["EventCocktails"] = eventCocktails
 	data["Cocktails"] = cocktails
+	data["DrinksPerGuestPerHour"] = constants.DefaultDrinksPerGuestPerHour
+
+	shoppingList, shoppingListError, err := s.buildEventShoppingList(eventDetails, constants.DefaultDrinksPerGuestPerHour)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error building event shopping list.", http.StatusInternalServerError)
+		return
+	}
+	data["ShoppingList"] = shoppingList
+	data["ShoppingListError"] = shoppingListError
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
 	helpers.ServeContent(w, files, data)
 }
 
-func PutEvent(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutEvent(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -1052,7 +1411,7 @@ func PutEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateEvent(form)
+	err = s.Events.UpdateEvent(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1068,7 +1427,7 @@ func PutEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if constants.Production {
-		lead, err := database.GetConversionReporting(int(helpers.SafeInt(form.LeadID)))
+		lead, err := s.Leads.GetConversionReporting(int(helpers.SafeInt(form.LeadID)))
 		if err != nil {
 			fmt.Printf("Error getting conversion: %+v\n", err)
 			tmplCtx := types.DynamicPartialTemplate{
@@ -1172,7 +1531,7 @@ func PutEvent(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteEvent(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -1200,7 +1559,7 @@ func DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteEvent(eventId)
+	err = s.Events.DeleteEvent(eventId)
 	if err != nil {
 		fmt.Printf("Error deleting event: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1215,7 +1574,7 @@ func DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	events, err := database.GetEventList(leadId)
+	events, err := s.Events.GetEventList(leadId)
 	if err != nil {
 		fmt.Printf("Error querying events after deletion: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1241,7 +1600,7 @@ func DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostLeadQuote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostLeadQuote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -1275,7 +1634,7 @@ func PostLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateLeadQuote(form)
+	err = s.Quotes.CreateLeadQuote(form)
 	if err != nil {
 		fmt.Printf("Error creating lead quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1305,7 +1664,7 @@ func PostLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadQuotes, err := database.GetLeadQuotes(helpers.SafeInt(form.LeadID))
+	leadQuotes, err := s.Quotes.GetLeadQuotes(helpers.SafeInt(form.LeadID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1331,7 +1690,7 @@ func PostLeadQuote(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PutLeadQuote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutLeadQuote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -1364,7 +1723,7 @@ func PutLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateLeadQuote(form)
+	err = s.Quotes.UpdateLeadQuote(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1394,6 +1753,21 @@ func PutLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = services.SyncQuoteAdjustments(helpers.SafeInt(form.QuoteID))
+	if err != nil {
+		fmt.Printf("Error syncing quote adjustments: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error recalculating quote adjustments.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
 	err = services.UpdateInvoicesWorkflow(helpers.SafeInt(form.QuoteID), formEventDate)
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1420,7 +1794,7 @@ func PutLeadQuote(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostSendInvoice(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostSendInvoice(w http.ResponseWriter, r *http.Request) {
 	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/lead/")
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -1451,7 +1825,7 @@ func PostSendInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	quote, err := database.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
+	quote, err := s.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1466,8 +1840,31 @@ func PostSendInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	quoteServices, err := s.Quotes.GetQuoteServices(quoteId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting quote services.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	// Quotes with packages or add-ons are invoiced once the customer accepts them
+	hasOptions := false
+	for _, qs := range quoteServices {
+		if helpers.IsQuoteOption(qs) {
+			hasOptions = true
+		}
+	}
+
 	// Do not create invoice if quote has invoice already
-	if quote.InvoiceID == 0 {
+	if quote.InvoiceID == 0 && !hasOptions {
 		err = services.CreateInvoiceWorkflow(quote)
 
 		if err != nil {
@@ -1494,11 +1891,15 @@ func PostSendInvoice(w http.ResponseWriter, r *http.Request) {
 	_, err = services.SendTextMessage(quote.PhoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
+		message := "Error sending invoice via text."
+		if errors.Is(err, services.ErrTextMessagingOptOut) {
+			message = "This lead has opted out of text messages."
+		}
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
 			Data: map[string]any{
-				"Message": "Error sending invoice via text.",
+				"Message": message,
 			},
 		}
 		w.WriteHeader(http.StatusBadRequest)
@@ -1506,19 +1907,24 @@ func PostSendInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	alertMessage := "Invoice has been sent."
+	if hasOptions {
+		alertMessage = "Quote has been sent. Invoices will be created once the customer accepts it."
+	}
+
 	tmplCtx := types.DynamicPartialTemplate{
 		TemplateName: "modal",
 		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "modal.html",
 		Data: map[string]any{
 			"AlertHeader":  "Success!",
-			"AlertMessage": "Invoice has been sent.",
+			"AlertMessage": alertMessage,
 		},
 	}
 
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetServices(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetServices(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "services.html"
 	createServiceForm := constants.PARTIAL_TEMPLATES_DIR + "create_service_form.html"
 	table := constants.PARTIAL_TEMPLATES_DIR + "services_table.html"
@@ -1537,21 +1943,21 @@ func GetServices(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	}
 
 	pageNum := helpers.ParsePageNum(r.URL.Query().Get("page_num"))
-	services, totalRows, err := database.GetServicesList(pageNum)
+	services, totalRows, err := s.Quotes.GetServicesList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting services from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	serviceTypes, err := database.GetServiceTypes()
+	serviceTypes, err := s.Quotes.GetServiceTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting service types from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	unitTypes, err := database.GetUnitTypes()
+	unitTypes, err := s.Quotes.GetUnitTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting unit types from DB.", http.StatusInternalServerError)
@@ -1573,7 +1979,7 @@ func GetServices(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PostService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -1606,7 +2012,7 @@ func PostService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateService(form)
+	err = s.Quotes.CreateService(form)
 	if err != nil {
 		fmt.Printf("Error creating service: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1622,7 +2028,7 @@ func PostService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := helpers.ParsePageNum(r.URL.Query().Get("page_num"))
-	services, totalRows, err := database.GetServicesList(pageNum)
+	services, totalRows, err := s.Quotes.GetServicesList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1637,14 +2043,14 @@ func PostService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	serviceTypes, err := database.GetServiceTypes()
+	serviceTypes, err := s.Quotes.GetServiceTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting service types from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	unitTypes, err := database.GetUnitTypes()
+	unitTypes, err := s.Quotes.GetUnitTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting unit types from DB.", http.StatusInternalServerError)
@@ -1666,7 +2072,7 @@ func PostService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PutService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -1699,7 +2105,7 @@ func PutService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateService(form)
+	err = s.Quotes.UpdateService(form)
 	if err != nil {
 		fmt.Printf("Error updating service: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1715,7 +2121,7 @@ func PutService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := helpers.ParsePageNum(r.URL.Query().Get("page_num"))
-	services, totalRows, err := database.GetServicesList(pageNum)
+	services, totalRows, err := s.Quotes.GetServicesList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1730,14 +2136,14 @@ func PutService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	serviceTypes, err := database.GetServiceTypes()
+	serviceTypes, err := s.Quotes.GetServiceTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting service types from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	unitTypes, err := database.GetUnitTypes()
+	unitTypes, err := s.Quotes.GetUnitTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting unit types from DB.", http.StatusInternalServerError)
@@ -1759,7 +2165,7 @@ func PutService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -1781,7 +2187,7 @@ func DeleteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteService(serviceId)
+	err = s.Quotes.DeleteService(serviceId)
 	if err != nil {
 		fmt.Printf("Error deleting service: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1797,7 +2203,7 @@ func DeleteService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := helpers.ParsePageNum(r.URL.Query().Get("page_num"))
-	services, totalRows, err := database.GetServicesList(pageNum)
+	services, totalRows, err := s.Quotes.GetServicesList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1812,14 +2218,14 @@ func DeleteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	serviceTypes, err := database.GetServiceTypes()
+	serviceTypes, err := s.Quotes.GetServiceTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting service types from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	unitTypes, err := database.GetUnitTypes()
+	unitTypes, err := s.Quotes.GetUnitTypes()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting unit types from DB.", http.StatusInternalServerError)
@@ -1841,11 +2247,12 @@ func DeleteService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetLeadQuoteDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetLeadQuoteDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	fileName := "lead_quote_detail.html"
 	quoteServicesTable := constants.PARTIAL_TEMPLATES_DIR + "quote_services_table.html"
 	createQuoteServiceForm := constants.PARTIAL_TEMPLATES_DIR + "create_quote_service_form.html"
-	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, quoteServicesTable, createQuoteServiceForm}
+	quoteDiscount := constants.PARTIAL_TEMPLATES_DIR + "quote_discount.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, quoteServicesTable, createQuoteServiceForm, quoteDiscount}
 	nonce, ok := r.Context().Value("nonce").(string)
 	if !ok {
 		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
@@ -1865,27 +2272,41 @@ func GetLeadQuoteDetail(w http.ResponseWriter, r *http.Request, ctx map[string]a
 		return
 	}
 
-	quoteDetails, err := database.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+	quoteDetails, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting quote details from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	services, err := database.GetServices()
+	services, err := s.Quotes.GetServices()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting bar types.", http.StatusInternalServerError)
 		return
 	}
 
-	quoteServices, err := database.GetQuoteServices(quoteId)
+	quoteServices, err := s.Quotes.GetQuoteServices(quoteId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting quote services.", http.StatusInternalServerError)
 		return
 	}
 
+	discount, err := s.Quotes.GetQuoteDiscount(quoteId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting quote discount.", http.StatusInternalServerError)
+		return
+	}
+
+	acceptance, err := s.Quotes.GetQuoteAcceptance(quoteId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting quote acceptance.", http.StatusInternalServerError)
+		return
+	}
+
 	data := ctx
 	data["PageTitle"] = "Quote Detail — " + constants.CompanyName
 	data["Nonce"] = nonce
@@ -1893,13 +2314,15 @@ func GetLeadQuoteDetail(w http.ResponseWriter, r *http.Request, ctx map[string]a
 	data["Quote"] = quoteDetails
 	data["QuoteServices"] = quoteServices
 	data["Services"] = services
+	data["Discount"] = discount
+	data["Acceptance"] = acceptance
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
 	helpers.ServeContent(w, files, data)
 }
 
-func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -1922,7 +2345,7 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Cannot get quote id after quote service has been deleted...
-	quoteId, err := database.GetQuoteIDByQuoteServiceID(quoteServiceId)
+	quoteId, err := s.Quotes.GetQuoteIDByQuoteServiceID(quoteServiceId)
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -1936,7 +2359,7 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteQuoteService(quoteServiceId)
+	err = s.Quotes.DeleteQuoteService(quoteServiceId)
 	if err != nil {
 		fmt.Printf("Error deleting quote service: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -1951,7 +2374,22 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasInvoice, err := database.CheckQuoteHasInvoiceID(quoteId)
+	err = services.SyncQuoteAdjustments(quoteId)
+	if err != nil {
+		fmt.Printf("Error syncing quote adjustments: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error recalculating quote adjustments.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	hasInvoice, err := s.Invoices.CheckQuoteHasInvoiceID(quoteId)
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -1966,7 +2404,7 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if hasInvoice {
-		quote, err := database.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+		quote, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
 		if err != nil {
 			tmplCtx := types.DynamicPartialTemplate{
 				TemplateName: "error",
@@ -1995,7 +2433,7 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	quoteServices, err := database.GetQuoteServices(quoteId)
+	quoteServices, err := s.Quotes.GetQuoteServices(quoteId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2021,7 +2459,7 @@ func DeleteQuoteService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostQuoteService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostQuoteService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2055,7 +2493,84 @@ func PostQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateQuoteService(form)
+	quote, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(helpers.SafeInt(form.QuoteID)))
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting quote details.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	packageTier := strings.TrimSpace(helpers.SafeString(form.PackageTier))
+	form.PackageTier = &packageTier
+
+	// Options are only offered before the customer accepts, since accepting is what creates the invoices
+	if packageTier != "" || helpers.SafeBoolDefaultFalse(form.IsOptional) {
+		hasInvoice, err := s.Invoices.CheckQuoteHasInvoiceID(helpers.SafeInt(form.QuoteID))
+		if err != nil {
+			fmt.Printf("Error checking quote invoices: %+v\n", err)
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": "Error checking quote invoices.",
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+
+		if hasInvoice {
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": "Packages and add-ons can't be added to a quote that has already been invoiced.",
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+	}
+
+	pricedQuote, err := services.PriceQuote(types.PricingRequest{
+		Guests:      quote.Guests,
+		Hours:       quote.Hours,
+		EventDate:   quote.EventDate,
+		TravelMiles: quote.TravelMiles,
+		Services: []types.PricingRequestService{
+			{ServiceID: helpers.SafeInt(form.ServiceID), Units: helpers.SafeFloat64(form.Units)},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error pricing quote service: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": fmt.Sprintf("Error pricing service: %s.", err),
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	line := pricedQuote.Services[0]
+	form.Units = &line.Units
+	form.PricePerUnit = &line.PricePerUnit
+	form.PricingRule = &line.PricingRule
+
+	err = s.Quotes.CreateQuoteService(form)
 	if err != nil {
 		fmt.Printf("Error creating lead quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2071,7 +2586,22 @@ func PostQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasInvoice, err := database.CheckQuoteHasInvoiceID(helpers.SafeInt(form.QuoteID))
+	err = services.SyncQuoteAdjustments(helpers.SafeInt(form.QuoteID))
+	if err != nil {
+		fmt.Printf("Error syncing quote adjustments: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error recalculating quote adjustments.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	hasInvoice, err := s.Invoices.CheckQuoteHasInvoiceID(helpers.SafeInt(form.QuoteID))
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -2086,7 +2616,7 @@ func PostQuoteService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if hasInvoice {
-		quote, err := database.GetLeadQuoteDetails(fmt.Sprint(helpers.SafeInt(form.QuoteID)))
+		quote, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(helpers.SafeInt(form.QuoteID)))
 		if err != nil {
 			tmplCtx := types.DynamicPartialTemplate{
 				TemplateName: "error",
@@ -2115,7 +2645,7 @@ func PostQuoteService(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	quoteServices, err := database.GetQuoteServices(helpers.SafeInt(form.QuoteID))
+	quoteServices, err := s.Quotes.GetQuoteServices(helpers.SafeInt(form.QuoteID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2141,7 +2671,7 @@ func PostQuoteService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PutQuoteService(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutQuoteService(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2174,7 +2704,10 @@ func PutQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateQuoteService(form)
+	pricingRule := "Price set by hand"
+	form.PricingRule = &pricingRule
+
+	err = s.Quotes.UpdateQuoteService(form)
 	if err != nil {
 		fmt.Printf("Error updating quote service: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2190,7 +2723,22 @@ func PutQuoteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasInvoice, err := database.CheckQuoteHasInvoiceID(helpers.SafeInt(form.QuoteID))
+	err = services.SyncQuoteAdjustments(helpers.SafeInt(form.QuoteID))
+	if err != nil {
+		fmt.Printf("Error syncing quote adjustments: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error recalculating quote adjustments.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	hasInvoice, err := s.Invoices.CheckQuoteHasInvoiceID(helpers.SafeInt(form.QuoteID))
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -2205,7 +2753,7 @@ func PutQuoteService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if hasInvoice {
-		quote, err := database.GetLeadQuoteDetails(fmt.Sprint(helpers.SafeInt(form.QuoteID)))
+		quote, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(helpers.SafeInt(form.QuoteID)))
 		if err != nil {
 			tmplCtx := types.DynamicPartialTemplate{
 				TemplateName: "error",
@@ -2234,7 +2782,7 @@ func PutQuoteService(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	quoteServices, err := database.GetQuoteServices(helpers.SafeInt(form.QuoteID))
+	quoteServices, err := s.Quotes.GetQuoteServices(helpers.SafeInt(form.QuoteID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2260,7 +2808,7 @@ func PutQuoteService(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostLeadNote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostLeadNote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2323,7 +2871,7 @@ func PostLeadNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateLeadNote(leadNote)
+	err = s.Leads.CreateLeadNote(leadNote)
 	if err != nil {
 		fmt.Printf("Error creating lead quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2339,7 +2887,7 @@ func PostLeadNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadNotes, err := database.GetLeadNotesByLeadID(leadID)
+	leadNotes, err := s.Leads.GetLeadNotesByLeadID(leadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2365,10 +2913,11 @@ func PostLeadNote(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "messages.html"
 	leadsWithMessagesTemplate := constants.PARTIAL_TEMPLATES_DIR + "leads_with_messages_list.html"
-	files := []string{crmBaseFilePath, crmFooterFilePath, leadsWithMessagesTemplate, baseFile}
+	conversationPanel := constants.PARTIAL_TEMPLATES_DIR + "conversation_panel.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, leadsWithMessagesTemplate, conversationPanel, baseFile}
 
 	nonce, ok := r.Context().Value("nonce").(string)
 	if !ok {
@@ -2382,7 +2931,14 @@ func GetMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 		return
 	}
 
-	messages, err := database.GetLeadsWithMessages()
+	filters, err := getConversationFilters(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting user ID from session.", http.StatusInternalServerError)
+		return
+	}
+
+	messages, err := s.Messages.GetLeadsWithMessages(filters)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting messages from DB.", http.StatusInternalServerError)
@@ -2394,20 +2950,24 @@ func GetMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	data["Nonce"] = nonce
 	data["CSRFToken"] = csrfToken
 	data["LeadsWithMessages"] = messages
+	data["ConversationFilters"] = map[string]string{
+		"Assigned": r.URL.Query().Get("assigned"),
+		"Status":   r.URL.Query().Get("status"),
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
 	helpers.ServeContent(w, files, data)
 }
 
-func GetMessagesByLeadID(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetMessagesByLeadID(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/message/")
 	if err != nil {
 		http.Error(w, "Bad lead id.", http.StatusBadRequest)
 		return
 	}
 
-	leadMessages, err := database.GetMessagesByLeadID(leadId)
+	leadMessages, err := s.Messages.GetMessagesByLeadID(leadId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting lead messages from DB.", http.StatusInternalServerError)
@@ -2427,7 +2987,7 @@ func GetMessagesByLeadID(w http.ResponseWriter, r *http.Request, ctx map[string]
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func SetSMSToRead(w http.ResponseWriter, r *http.Request) {
+func (s *Server) SetSMSToRead(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2460,7 +3020,7 @@ func SetSMSToRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.SetSMSToRead(helpers.SafeInt(form.MessageID))
+	err = s.Messages.SetSMSToRead(helpers.SafeInt(form.MessageID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2475,7 +3035,12 @@ func SetSMSToRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadMessages, err := database.GetMessagesByLeadID(helpers.SafeInt(form.LeadID))
+	services.PublishCRMEvent(types.CRMEvent{
+		Type:   constants.MessageReadCRMEvent,
+		LeadID: helpers.SafeInt(form.LeadID),
+	})
+
+	leadMessages, err := s.Messages.GetMessagesByLeadID(helpers.SafeInt(form.LeadID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2503,8 +3068,15 @@ func SetSMSToRead(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetLeadsWithMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
-	leadsWithMessages, err := database.GetLeadsWithMessages()
+func (s *Server) GetLeadsWithMessages(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	filters, err := getConversationFilters(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting user ID from session.", http.StatusInternalServerError)
+		return
+	}
+
+	leadsWithMessages, err := s.Messages.GetLeadsWithMessages(filters)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting leads with messages from DB.", http.StatusInternalServerError)
@@ -2524,7 +3096,7 @@ func GetLeadsWithMessages(w http.ResponseWriter, r *http.Request, ctx map[string
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetAutomatedFollowUpMessage(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetAutomatedFollowUpMessage(w http.ResponseWriter, r *http.Request) {
 	option := r.URL.Query().Get("option")
 	leadId := r.URL.Query().Get("leadId")
 
@@ -2542,7 +3114,7 @@ func GetAutomatedFollowUpMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	lead, err := database.GetLeadDetails(leadId)
+	lead, err := s.Leads.GetLeadDetails(leadId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2557,7 +3129,7 @@ func GetAutomatedFollowUpMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	previousConversations, err := database.GetPreviousConversations(lead.LeadID)
+	previousConversations, err := s.Messages.GetPreviousConversations(lead.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2656,7 +3228,7 @@ func GetAutomatedFollowUpMessage(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(response))
 }
 
-func PostLeadNextAction(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostLeadNextAction(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2689,7 +3261,7 @@ func PostLeadNextAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateLeadNextAction(form)
+	err = s.Leads.CreateLeadNextAction(form)
 	if err != nil {
 		fmt.Printf("Error creating lead next action: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2705,7 +3277,7 @@ func PostLeadNextAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadNextActions, err := database.GetLeadNextActionsByLeadID(helpers.SafeInt(form.LeadID))
+	leadNextActions, err := s.Leads.GetLeadNextActionsByLeadID(helpers.SafeInt(form.LeadID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2731,7 +3303,7 @@ func PostLeadNextAction(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteLeadNextAction(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteLeadNextAction(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -2777,7 +3349,7 @@ func DeleteLeadNextAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteLeadNextAction(leadNextActionId)
+	err = s.Leads.DeleteLeadNextAction(leadNextActionId)
 	if err != nil {
 		fmt.Printf("Error deleting lead's next action: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2792,7 +3364,7 @@ func DeleteLeadNextAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadNextActions, err := database.GetLeadNextActionsByLeadID(leadId)
+	leadNextActions, err := s.Leads.GetLeadNextActionsByLeadID(leadId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2818,7 +3390,7 @@ func DeleteLeadNextAction(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2871,7 +3443,53 @@ func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	quoteId, quoteExternalId, err := database.CreateQuickQuote(form, quoteServiceForm)
+	pricingRequest := types.PricingRequest{
+		Guests:      helpers.SafeInt(form.Guests),
+		Hours:       helpers.SafeFloat64(form.Hours),
+		EventDate:   helpers.SafeInt64(form.EventDate),
+		TravelMiles: helpers.SafeFloat64(form.TravelMiles),
+	}
+
+	for _, quoteService := range quoteServiceForm {
+		pricingRequest.Services = append(pricingRequest.Services, types.PricingRequestService{
+			ServiceID: helpers.SafeInt(quoteService.ServiceID),
+			Units:     helpers.SafeFloat64(quoteService.Units),
+		})
+	}
+
+	pricedQuote, err := services.PriceQuote(pricingRequest)
+	if err != nil {
+		fmt.Printf("Error pricing quick quote: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": fmt.Sprintf("Error pricing quote: %s.", err),
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	discountCode := strings.TrimSpace(helpers.SafeString(form.DiscountCode))
+	if discountCode != "" {
+		_, err = services.GetRedeemableDiscountCode(discountCode)
+		if err != nil {
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": fmt.Sprintf("Error applying discount code: %s.", err),
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+	}
+
+	quoteId, quoteExternalId, err := s.Quotes.CreateQuickQuote(form, services.QuoteServiceForms(append(pricedQuote.Services, pricedQuote.Adjustments...)))
 	if err != nil {
 		fmt.Printf("Error creating quick quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2887,6 +3505,23 @@ func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if discountCode != "" {
+		err = services.ApplyDiscountCode(quoteId, discountCode)
+		if err != nil {
+			fmt.Printf("Error applying discount code: %+v\n", err)
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": fmt.Sprintf("Error applying discount code: %s.", err),
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+	}
+
 	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/lead/")
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -2902,7 +3537,7 @@ func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	quote, err := database.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
+	quote, err := s.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -2943,11 +3578,15 @@ func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 	_, err = services.SendTextMessage(quote.PhoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
+		message := "Error sending invoice via text."
+		if errors.Is(err, services.ErrTextMessagingOptOut) {
+			message = "This lead has opted out of text messages."
+		}
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
 			Data: map[string]any{
-				"Message": "Error sending invoice via text.",
+				"Message": message,
 			},
 		}
 		w.WriteHeader(http.StatusBadRequest)
@@ -2959,7 +3598,7 @@ func PostQuickQuote(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(fmt.Sprint(quoteId)))
 }
 
-func DeleteLeadQuote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteLeadQuote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -3005,7 +3644,7 @@ func DeleteLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteLeadQuote(leadQuoteId)
+	err = s.Quotes.DeleteLeadQuote(leadQuoteId)
 	if err != nil {
 		fmt.Printf("Error deleting lead's quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3020,7 +3659,7 @@ func DeleteLeadQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadQuotes, err := database.GetLeadQuotes(leadId)
+	leadQuotes, err := s.Quotes.GetLeadQuotes(leadId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3046,7 +3685,194 @@ func DeleteLeadQuote(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetEvents(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) serveQuoteDiscount(w http.ResponseWriter, r *http.Request, quoteId int) {
+	hasInvoice, err := s.Invoices.CheckQuoteHasInvoiceID(quoteId)
+	if err != nil {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error checking if quote has invoices.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if hasInvoice {
+		quote, err := s.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+		if err != nil {
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": "Error during invoices workflow.",
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+
+		err = services.UpdateInvoicesWorkflow(quote.QuoteID, quote.EventDate)
+		if err != nil {
+			fmt.Printf("Error updating invoices: %+v\n", err)
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": "Error during invoices workflow.",
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+	}
+
+	discount, err := s.Quotes.GetQuoteDiscount(quoteId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting quote discount.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	userRoleId, _ := r.Context().Value("user_role_id").(int)
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "quote_discount.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "quote_discount.html",
+		Data: map[string]any{
+			"Discount": discount,
+			"Can":      helpers.GetCapabilities(userRoleId),
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostQuoteDiscount(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.QuoteDiscountForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	quoteId, err := helpers.GetSecondIDFromPath(r, "/crm/lead/")
+	if err != nil {
+		fmt.Printf("Error getting quote id from path: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get quote id from path.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if strings.TrimSpace(helpers.SafeString(form.Code)) == "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Discount code is required.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = services.ApplyDiscountCode(quoteId, helpers.SafeString(form.Code))
+	if err != nil {
+		fmt.Printf("Error applying discount code: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": fmt.Sprintf("Error applying discount code: %s.", err),
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveQuoteDiscount(w, r, quoteId)
+}
+
+func (s *Server) DeleteQuoteDiscount(w http.ResponseWriter, r *http.Request) {
+	quoteId, err := helpers.GetSecondIDFromPath(r, "/crm/lead/")
+	if err != nil {
+		fmt.Printf("Error getting quote id from path: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get quote id from path.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Quotes.DeleteQuoteDiscount(quoteId)
+	if err != nil {
+		fmt.Printf("Error deleting quote discount: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to remove discount.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveQuoteDiscount(w, r, quoteId)
+}
+
+func (s *Server) GetEvents(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "events.html"
 	createEventForm := constants.PARTIAL_TEMPLATES_DIR + "event_form.html"
 	table := constants.PARTIAL_TEMPLATES_DIR + "event_list_view.html"
@@ -3074,7 +3900,17 @@ func GetEvents(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 		}
 	}
 
-	events, totalRows, err := database.GetPaginatedEventList(pageNum)
+	var events []types.EventListView
+	var totalRows int
+	var err error
+
+	userRoleId, _ := r.Context().Value("user_role_id").(int)
+	if helpers.HasCapability(userRoleId, constants.ManageEventsCapability) {
+		events, totalRows, err = s.Events.GetPaginatedEventList(pageNum)
+	} else {
+		userId, _ := r.Context().Value("user_id").(int)
+		events, totalRows, err = s.Events.GetPaginatedEventListByUser(pageNum, userId)
+	}
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting services from DB.", http.StatusInternalServerError)
@@ -3094,7 +3930,7 @@ func GetEvents(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PostSendInvoiceReminder(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostSendInvoiceReminder(w http.ResponseWriter, r *http.Request) {
 	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/lead/")
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3125,7 +3961,7 @@ func PostSendInvoiceReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	quote, err := database.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
+	quote, err := s.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3146,14 +3982,18 @@ func PostSendInvoiceReminder(w http.ResponseWriter, r *http.Request) {
 		Here's the link to the invoice: %s
 	`, externalQuoteView)
 
-	_, err = services.SendTextMessage(quote.PhoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
+	isQueued, err := services.SendAutomatedTextMessage(leadId, quote.PhoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
+		message := "Error sending invoice via text."
+		if errors.Is(err, services.ErrTextMessagingOptOut) {
+			message = "This lead has opted out of text messages."
+		}
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
 			Data: map[string]any{
-				"Message": "Error sending invoice via text.",
+				"Message": message,
 			},
 		}
 		w.WriteHeader(http.StatusBadRequest)
@@ -3161,19 +4001,24 @@ func PostSendInvoiceReminder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	alertMessage := "Reminder has been sent."
+	if isQueued {
+		alertMessage = "It's quiet hours, so the reminder will be sent in the morning."
+	}
+
 	tmplCtx := types.DynamicPartialTemplate{
 		TemplateName: "modal",
 		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "modal.html",
 		Data: map[string]any{
 			"AlertHeader":  "Success!",
-			"AlertMessage": "Reminder has been sent.",
+			"AlertMessage": alertMessage,
 		},
 	}
 
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostEventStaff(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostEventStaff(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3207,7 +4052,7 @@ func PostEventStaff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateEventStaff(form)
+	err = s.Events.CreateEventStaff(form)
 	if err != nil {
 		fmt.Printf("Error creating event: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3223,7 +4068,7 @@ func PostEventStaff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventStaff, err := database.GetEventStaff(helpers.SafeInt(form.EventID))
+	eventStaff, err := s.Events.GetEventStaff(helpers.SafeInt(form.EventID))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3249,7 +4094,7 @@ func PostEventStaff(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteEventStaff(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteEventStaff(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -3295,7 +4140,7 @@ func DeleteEventStaff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteEventStaff(eventStaffId)
+	err = s.Events.DeleteEventStaff(eventStaffId)
 	if err != nil {
 		fmt.Printf("Error deleting lead's quote: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3310,7 +4155,7 @@ func DeleteEventStaff(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventStaff, err := database.GetEventStaff(eventId)
+	eventStaff, err := s.Events.GetEventStaff(eventId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3336,7 +4181,7 @@ func DeleteEventStaff(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func PostEventCocktail(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostEventCocktail(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3370,9 +4215,25 @@ func PostEventCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateEventCocktail(form)
+	eventId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/event/")
 	if err != nil {
-		fmt.Printf("Error creating event: %+v\n", err)
+		fmt.Printf("Error getting event id from path: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get event id from path.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+	form.EventID = &eventId
+
+	err = s.Events.CreateEventCocktail(form)
+	if err != nil {
+		fmt.Printf("Error creating event cocktail: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
@@ -3386,7 +4247,7 @@ func PostEventCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventCocktails, err := database.GetEventCocktails(helpers.SafeInt(form.EventID))
+	eventCocktails, err := s.Events.GetEventCocktails(eventId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3406,13 +4267,14 @@ func PostEventCocktail(w http.ResponseWriter, r *http.Request) {
 		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "event_cocktails_table.html",
 		Data: map[string]any{
 			"EventCocktails": eventCocktails,
+			"Event":          models.Event{EventID: eventId},
 		},
 	}
 
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteEventCocktail(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteEventCocktail(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -3458,7 +4320,7 @@ func DeleteEventCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteEventStaff(eventCocktailId)
+	err = s.Events.DeleteEventCocktail(eventCocktailId)
 	if err != nil {
 		fmt.Printf("Error deleting event cocktail: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3473,7 +4335,7 @@ func DeleteEventCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventCocktails, err := database.GetEventCocktails(eventId)
+	eventCocktails, err := s.Events.GetEventCocktails(eventId)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3493,13 +4355,14 @@ func DeleteEventCocktail(w http.ResponseWriter, r *http.Request) {
 		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "event_cocktails_table.html",
 		Data: map[string]any{
 			"EventCocktails": eventCocktails,
+			"Event":          models.Event{EventID: eventId},
 		},
 	}
 
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetUsers(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetUsers(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "users.html"
 	createUserForm := constants.PARTIAL_TEMPLATES_DIR + "create_user_form.html"
 	table := constants.PARTIAL_TEMPLATES_DIR + "users_table.html"
@@ -3527,14 +4390,14 @@ func GetUsers(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 		}
 	}
 
-	users, totalRows, err := database.GetPaginatedUserList(pageNum)
+	users, totalRows, err := s.Users.GetPaginatedUserList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting users from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	userRoles, err := database.GetUserRoles()
+	userRoles, err := s.Users.GetUserRoles()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting user roles from DB.", http.StatusInternalServerError)
@@ -3555,7 +4418,7 @@ func GetUsers(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PostUser(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostUser(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3589,7 +4452,7 @@ func PostUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateUser(form)
+	err = s.Users.CreateUser(form)
 	if err != nil {
 		fmt.Printf("Error creating user: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3606,7 +4469,7 @@ func PostUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := 1
-	users, totalRows, err := database.GetPaginatedUserList(pageNum)
+	users, totalRows, err := s.Users.GetPaginatedUserList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting users from DB.", http.StatusInternalServerError)
@@ -3626,7 +4489,7 @@ func PostUser(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetUserDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetUserDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	fileName := "user_detail.html"
 	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName}
 	nonce, ok := r.Context().Value("nonce").(string)
@@ -3648,14 +4511,14 @@ func GetUserDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 		return
 	}
 
-	userDetails, err := database.GetUserDetails(fmt.Sprint(userId))
+	userDetails, err := s.Users.GetUserDetails(fmt.Sprint(userId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting user details from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	userRoles, err := database.GetUserRoles()
+	userRoles, err := s.Users.GetUserRoles()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting user roles.", http.StatusInternalServerError)
@@ -3674,7 +4537,7 @@ func GetUserDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PutUser(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PutUser(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3707,7 +4570,7 @@ func PutUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateUser(form)
+	err = s.Users.UpdateUser(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3734,7 +4597,7 @@ func PutUser(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteUser(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -3756,7 +4619,7 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteUser(userId)
+	err = s.Users.DeleteUser(userId)
 	if err != nil {
 		fmt.Printf("Error deleting event: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3772,7 +4635,7 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := 1
-	users, totalRows, err := database.GetPaginatedUserList(pageNum)
+	users, totalRows, err := s.Users.GetPaginatedUserList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting users from DB.", http.StatusInternalServerError)
@@ -3792,7 +4655,7 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetCocktails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetCocktails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	baseFile := constants.CRM_TEMPLATES_DIR + "cocktails.html"
 	createCocktailForm := constants.PARTIAL_TEMPLATES_DIR + "create_cocktail_form.html"
 	table := constants.PARTIAL_TEMPLATES_DIR + "cocktails_table.html"
@@ -3820,7 +4683,7 @@ func GetCocktails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 		}
 	}
 
-	cocktails, totalRows, err := database.GetPaginatedCocktailList(pageNum)
+	cocktails, totalRows, err := s.Events.GetPaginatedCocktailList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting cocktails from DB.", http.StatusInternalServerError)
@@ -3840,7 +4703,7 @@ func GetCocktails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	helpers.ServeContent(w, files, data)
 }
 
-func PostCocktail(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostCocktail(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -3874,7 +4737,7 @@ func PostCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.CreateCocktailMany(form)
+	err = s.Events.CreateCocktailMany(form)
 	if err != nil {
 		fmt.Printf("Error creating cocktails: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -3891,7 +4754,7 @@ func PostCocktail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := 1
-	cocktails, totalRows, err := database.GetPaginatedCocktailList(pageNum)
+	cocktails, totalRows, err := s.Events.GetPaginatedCocktailList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting users from DB.", http.StatusInternalServerError)
@@ -3911,9 +4774,10 @@ func PostCocktail(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetCocktailDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetCocktailDetail(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	fileName := "cocktail_detail.html"
-	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName}
+	recipeCard := constants.PARTIAL_TEMPLATES_DIR + "cocktail_recipe_card.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName, recipeCard}
 	nonce, ok := r.Context().Value("nonce").(string)
 	if !ok {
 		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
@@ -3926,36 +4790,63 @@ func GetCocktailDetail(w http.ResponseWriter, r *http.Request, ctx map[string]an
 		return
 	}
 
-	cocktailId, err := helpers.GetSecondIDFromPath(r, "/crm/cocktail/")
+	cocktailId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/cocktail/")
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting cocktail id from path.", http.StatusInternalServerError)
 		return
 	}
 
-	cocktailDetails, err := database.GetCocktailDetails(fmt.Sprint(cocktailId))
+	cocktailDetails, err := s.Events.GetCocktailDetails(fmt.Sprint(cocktailId))
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting cocktail details from DB.", http.StatusInternalServerError)
 		return
 	}
 
-	data := ctx
-	data["PageTitle"] = "User Detail — " + constants.CompanyName
-	data["Nonce"] = nonce
-	data["CSRFToken"] = csrfToken
-	data["Cocktail"] = cocktailDetails
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-
-	helpers.ServeContent(w, files, data)
-}
+	cocktailIngredients, err := s.Events.GetCocktailIngredients(cocktailId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting cocktail ingredients from DB.", http.StatusInternalServerError)
+		return
+	}
 
-func PutCocktail(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseForm()
+	ingredients, err := s.Events.GetIngredients()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
-		tmplCtx := types.DynamicPartialTemplate{
+		http.Error(w, "Error getting ingredients from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	units, err := s.Events.GetUnits()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting units from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	can, _ := ctx["Can"].(map[string]bool)
+
+	data := ctx
+	data["PageTitle"] = "Cocktail Detail — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["Cocktail"] = cocktailDetails
+	data["RecipeSections"] = helpers.GroupCocktailRecipe(cocktailIngredients)
+	data["Ingredients"] = ingredients
+	data["Units"] = units
+	data["CanEdit"] = can[constants.ManageEventsCapability]
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) PutCocktail(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
 			Data: map[string]any{
@@ -3984,7 +4875,7 @@ func PutCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.UpdateCocktail(form)
+	err = s.Events.UpdateCocktail(form)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -4011,7 +4902,7 @@ func PutCocktail(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func DeleteCocktail(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteCocktail(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("Error parsing form: %+v\n", err)
@@ -4033,7 +4924,7 @@ func DeleteCocktail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.DeleteCocktail(cocktailId)
+	err = s.Events.DeleteCocktail(cocktailId)
 	if err != nil {
 		fmt.Printf("Error deleting cocktail: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -4049,7 +4940,7 @@ func DeleteCocktail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageNum := 1
-	cocktails, totalRows, err := database.GetPaginatedCocktailList(pageNum)
+	cocktails, totalRows, err := s.Events.GetPaginatedCocktailList(pageNum)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		http.Error(w, "Error getting cocktails from DB.", http.StatusInternalServerError)
@@ -4068,3 +4959,2462 @@ func DeleteCocktail(w http.ResponseWriter, r *http.Request) {
 
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
+
+func (s *Server) GetPayroll(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "payroll.html"
+	table := constants.PARTIAL_TEMPLATES_DIR + "payroll_table.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, table}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	start, end, err := helpers.GetPayPeriod(r.URL.Query().Get("start_date"), r.URL.Query().Get("end_date"), time.Now())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Invalid pay period.", http.StatusBadRequest)
+		return
+	}
+
+	lines, err := s.Events.GetPayrollLines(start.Unix(), end.Unix())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting payroll from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Payroll — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["StartDate"] = helpers.FormatPayPeriodDate(start)
+	data["EndDate"] = helpers.FormatPayPeriodDate(end.AddDate(0, 0, -1))
+	data["PayrollLines"] = lines
+	data["PayrollSummary"] = helpers.SummarizePayroll(lines)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) GetPayrollExport(w http.ResponseWriter, r *http.Request) {
+	start, end, err := helpers.GetPayPeriod(r.URL.Query().Get("start_date"), r.URL.Query().Get("end_date"), time.Now())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Invalid pay period.", http.StatusBadRequest)
+		return
+	}
+
+	lines, err := s.Events.GetPayrollLines(start.Unix(), end.Unix())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting payroll from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	fileName := fmt.Sprintf("payroll_%s_%s.xlsx", helpers.FormatPayPeriodDate(start), helpers.FormatPayPeriodDate(end.AddDate(0, 0, -1)))
+
+	filePath, err := helpers.GenerateExcelFile(lines, "Payroll", constants.LOCAL_FILES_DIR+fileName)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error generating payroll export.", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(filePath)
+
+	w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	http.ServeFile(w, r, filePath)
+}
+
+func (s *Server) PostPayrollPaid(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	start, end, err := helpers.GetPayPeriod(r.FormValue("start_date"), r.FormValue("end_date"), time.Now())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid pay period.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	lines, err := s.Events.GetPayrollLines(start.Unix(), end.Unix())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting payroll from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	// Without specific lines every unpaid line in the period is settled.
+	selected := make(map[int]bool)
+	for _, id := range r.Form["event_staff_id"] {
+		if eventStaffId, err := strconv.Atoi(id); err == nil {
+			selected[eventStaffId] = true
+		}
+	}
+
+	var toPay []types.PayrollLine
+	for _, line := range lines {
+		if line.IsPaid {
+			continue
+		}
+		if len(selected) > 0 && !selected[line.EventStaffID] {
+			continue
+		}
+		toPay = append(toPay, line)
+	}
+
+	err = s.Events.MarkPayrollLinesPaid(toPay)
+	if err != nil {
+		fmt.Printf("Error marking payroll lines as paid: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to mark payroll as paid.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	lines, err = s.Events.GetPayrollLines(start.Unix(), end.Unix())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting payroll from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "payroll_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "payroll_table.html",
+		Data: map[string]any{
+			"PayrollLines":   lines,
+			"PayrollSummary": helpers.SummarizePayroll(lines),
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) GetReports(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	fileName := "reports.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	start, end, err := helpers.GetReportPeriod(r.URL.Query().Get("start_date"), r.URL.Query().Get("end_date"), time.Now())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Invalid report period.", http.StatusBadRequest)
+		return
+	}
+
+	events, err := s.Reports.GetEventProfitability(start.Unix(), end.Unix())
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting event profitability from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.CalculateProfitability(events)
+
+	byPackage := helpers.SummarizeProfitability(events, func(e types.EventProfitability) string { return e.PackageType })
+	helpers.SortProfitabilityByMargin(byPackage)
+
+	bySource := helpers.SummarizeProfitability(events, func(e types.EventProfitability) string { return e.LeadSource })
+	helpers.SortProfitabilityByMargin(bySource)
+
+	data := ctx
+	data["PageTitle"] = "Reports — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["StartDate"] = helpers.FormatPayPeriodDate(start)
+	data["EndDate"] = helpers.FormatPayPeriodDate(end.AddDate(0, 0, -1))
+	data["Events"] = events
+	data["Totals"] = helpers.SummarizeProfitability(events, func(e types.EventProfitability) string { return "Total" })
+	data["MonthlySummary"] = helpers.SummarizeProfitability(events, func(e types.EventProfitability) string { return e.Month })
+	data["PackageSummary"] = byPackage
+	data["SourceSummary"] = bySource
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) getMarketingAttribution(r *http.Request) ([]types.MarketingAttribution, time.Time, time.Time, string, error) {
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "source"
+	}
+
+	start, end, err := helpers.GetReportPeriod(r.URL.Query().Get("start_date"), r.URL.Query().Get("end_date"), time.Now())
+	if err != nil {
+		return nil, start, end, groupBy, err
+	}
+
+	report, err := s.Reports.GetMarketingAttribution(start.Unix(), end.Unix(), groupBy)
+	if err != nil {
+		return nil, start, end, groupBy, err
+	}
+
+	helpers.CalculateMarketingAttribution(report)
+
+	return report, start, end, groupBy, nil
+}
+
+func (s *Server) GetMarketingReport(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	fileName := "marketing_report.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.CRM_TEMPLATES_DIR + fileName}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	report, start, end, groupBy, err := s.getMarketingAttribution(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting marketing report.", http.StatusBadRequest)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Marketing Report — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["StartDate"] = helpers.FormatPayPeriodDate(start)
+	data["EndDate"] = helpers.FormatPayPeriodDate(end.AddDate(0, 0, -1))
+	data["GroupBy"] = groupBy
+	data["MarketingReport"] = report
+	data["MarketingTotal"] = helpers.TotalMarketingAttribution(report)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) GetMarketingReportExport(w http.ResponseWriter, r *http.Request) {
+	report, start, end, groupBy, err := s.getMarketingAttribution(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting marketing report.", http.StatusBadRequest)
+		return
+	}
+
+	report = append(report, helpers.TotalMarketingAttribution(report))
+	baseName := fmt.Sprintf("marketing_%s_%s_%s", groupBy, helpers.FormatPayPeriodDate(start), helpers.FormatPayPeriodDate(end.AddDate(0, 0, -1)))
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Disposition", "attachment; filename="+baseName+".csv")
+		w.Header().Set("Content-Type", "text/csv")
+
+		if err := helpers.WriteCSV(w, report); err != nil {
+			fmt.Printf("%+v\n", err)
+		}
+		return
+	}
+
+	fileName := baseName + ".xlsx"
+
+	filePath, err := helpers.GenerateExcelFile(report, "Marketing", constants.LOCAL_FILES_DIR+fileName)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error generating marketing export.", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(filePath)
+
+	w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	http.ServeFile(w, r, filePath)
+}
+
+func (s *Server) PostAdSpendUpload(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("ad_spend_file")
+	if err != nil {
+		fmt.Printf("Error reading ad spend file: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Please choose a CSV file to upload.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		fmt.Printf("Error parsing ad spend CSV: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Could not read CSV file.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.importAdSpend(w, rows, r.FormValue("source"))
+}
+
+func (s *Server) PostAdSpendSheetImport(w http.ResponseWriter, r *http.Request) {
+	rows, err := services.GetAdSpendFromSheets()
+	if err != nil {
+		fmt.Printf("Error getting ad spend from sheets: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Could not get ad spend from Google Sheets.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.importAdSpend(w, rows, r.FormValue("source"))
+}
+
+func (s *Server) importAdSpend(w http.ResponseWriter, rows [][]string, defaultSource string) {
+	spend, err := helpers.ParseAdSpendRows(rows, defaultSource)
+	if err != nil {
+		fmt.Printf("Error parsing ad spend rows: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid ad spend data: " + err.Error(),
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Reports.ImportAdSpend(spend)
+	if err != nil {
+		fmt.Printf("Error importing ad spend: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to save ad spend.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "modal",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "modal.html",
+		Data: map[string]any{
+			"AlertHeader":  "Success!",
+			"AlertMessage": fmt.Sprintf("Imported %d ad spend rows.", len(spend)),
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func getJobListParams(r *http.Request) (string, int) {
+	status := r.URL.Query().Get("status")
+
+	pageNum := 1
+	if num, err := strconv.Atoi(r.URL.Query().Get("page_num")); err == nil && num > 1 {
+		pageNum = num
+	}
+
+	return status, pageNum
+}
+
+func (s *Server) GetJobs(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "jobs.html"
+	table := constants.PARTIAL_TEMPLATES_DIR + "jobs_table.html"
+	deadLettersTable := constants.PARTIAL_TEMPLATES_DIR + "job_dead_letters_table.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, table, deadLettersTable}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	status, pageNum := getJobListParams(r)
+
+	jobs, totalRows, err := s.Jobs.GetJobList(status, pageNum)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting jobs from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	deadLetters, err := s.Jobs.GetJobDeadLetters()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting dead letter jobs from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Jobs — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["Jobs"] = jobs
+	data["DeadLetters"] = deadLetters
+	data["Status"] = status
+	data["JobStatuses"] = []string{constants.PendingJobStatus, constants.RunningJobStatus, constants.CompletedJobStatus, constants.DeadJobStatus}
+	data["MaxPages"] = helpers.CalculateMaxPages(totalRows, constants.LeadsPerPage)
+	data["CurrentPage"] = pageNum
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) PostRunJob(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	jobId, err := strconv.Atoi(r.FormValue("job_id"))
+	if err != nil {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid job.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Jobs.RunJobNow(jobId)
+	if err != nil {
+		fmt.Printf("Error running job: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to run job.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	status, pageNum := getJobListParams(r)
+
+	jobs, totalRows, err := s.Jobs.GetJobList(status, pageNum)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting jobs from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "jobs_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "jobs_table.html",
+		Data: map[string]any{
+			"Jobs":        jobs,
+			"CurrentPage": pageNum,
+			"MaxPages":    helpers.CalculateMaxPages(totalRows, constants.LeadsPerPage),
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostRequeueJob(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	jobDeadLetterId, err := strconv.Atoi(r.FormValue("job_dead_letter_id"))
+	if err != nil {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid dead letter job.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Jobs.RequeueJobDeadLetter(jobDeadLetterId)
+	if err != nil {
+		fmt.Printf("Error requeueing job: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to requeue job.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	deadLetters, err := s.Jobs.GetJobDeadLetters()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting dead letter jobs from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "job_dead_letters_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "job_dead_letters_table.html",
+		Data: map[string]any{
+			"DeadLetters": deadLetters,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) GetSMSSequences(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "sms_sequences.html"
+	list := constants.PARTIAL_TEMPLATES_DIR + "sms_sequences_list.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, list}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	sequences, err := s.Sequences.GetSMSSequences()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting SMS sequences from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "SMS Sequences — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["Sequences"] = sequences
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+// serveSMSSequencesList re-renders every sequence after a change so the page stays in sync.
+func (s *Server) serveSMSSequencesList(w http.ResponseWriter) {
+	sequences, err := s.Sequences.GetSMSSequences()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting SMS sequences from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "sms_sequences_list.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "sms_sequences_list.html",
+		Data: map[string]any{
+			"Sequences": sequences,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostSMSSequence(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.SMSSequenceForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if strings.TrimSpace(helpers.SafeString(form.Name)) == "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Sequence name is required.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Sequences.CreateSMSSequence(form)
+	if err != nil {
+		fmt.Printf("Error creating SMS sequence: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to create SMS sequence.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveSMSSequencesList(w)
+}
+
+func (s *Server) PutSMSSequence(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	sequenceId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/sms-sequence/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.SMSSequenceForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+	form.SMSSequenceID = &sequenceId
+
+	err = s.Sequences.UpdateSMSSequence(form)
+	if err != nil {
+		fmt.Printf("Error updating SMS sequence: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to update SMS sequence.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveSMSSequencesList(w)
+}
+
+func (s *Server) DeleteSMSSequence(w http.ResponseWriter, r *http.Request) {
+	sequenceId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/sms-sequence/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Sequences.DeleteSMSSequence(sequenceId)
+	if err != nil {
+		fmt.Printf("Error deleting SMS sequence: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to delete SMS sequence.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveSMSSequencesList(w)
+}
+
+func (s *Server) PostSMSSequenceStep(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	sequenceId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/sms-sequence/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.SMSSequenceStepForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+	form.SMSSequenceID = &sequenceId
+
+	if strings.TrimSpace(helpers.SafeString(form.Body)) == "" || helpers.SafeInt(form.DelayDays) < 0 {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Each step needs a message and a delay of zero or more days.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Sequences.CreateSMSSequenceStep(form)
+	if err != nil {
+		fmt.Printf("Error creating SMS sequence step: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to add step.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveSMSSequencesList(w)
+}
+
+func (s *Server) DeleteSMSSequenceStep(w http.ResponseWriter, r *http.Request) {
+	stepId, err := helpers.GetSecondIDFromPath(r, "/crm/sms-sequence/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Sequences.DeleteSMSSequenceStep(stepId)
+	if err != nil {
+		fmt.Printf("Error deleting SMS sequence step: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to delete step.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveSMSSequencesList(w)
+}
+
+func (s *Server) serveScheduledMessages(w http.ResponseWriter, leadId int) {
+	scheduledMessages, err := s.Messages.GetScheduledMessagesByLeadID(leadId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get scheduled messages.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "scheduled_messages.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "scheduled_messages.html",
+		Data: map[string]any{
+			"ScheduledMessages": scheduledMessages,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostScheduledMessage(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/lead/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.ScheduledMessageForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if r.MultipartForm != nil && len(r.MultipartForm.File["media"]) > 0 {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Texts with attachments can only be sent right away.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	sendAt, err := helpers.ParseScheduledMessageTime(helpers.SafeString(form.SendAt))
+	if err != nil || !sendAt.After(time.Now()) || strings.TrimSpace(helpers.SafeString(form.Body)) == "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Scheduled texts need a message and a send time in the future.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	values, err := sessions.Get(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting user ID from session.", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = services.ScheduleTextMessage(models.ScheduledMessage{
+		LeadID:   leadId,
+		UserID:   values.UserID,
+		TextFrom: helpers.SafeString(form.From),
+		TextTo:   helpers.SafeString(form.To),
+		Body:     helpers.SafeString(form.Body),
+		SendAt:   sendAt.Unix(),
+	})
+	if err != nil {
+		fmt.Printf("Error scheduling text message: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to schedule text message.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveScheduledMessages(w, leadId)
+}
+
+func (s *Server) DeleteScheduledMessage(w http.ResponseWriter, r *http.Request) {
+	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/lead/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scheduledMessageId, err := helpers.GetSecondIDFromPath(r, "/crm/lead/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Messages.CancelScheduledMessage(scheduledMessageId, leadId)
+	if err != nil {
+		fmt.Printf("Error cancelling scheduled message: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to cancel scheduled message.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveScheduledMessages(w, leadId)
+}
+
+func (s *Server) GetCRMStream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	// The server write timeout would otherwise close the stream after a few seconds
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Streaming not supported.", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := services.SubscribeCRMEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	writeEvent := func(event types.CRMEvent) error {
+		unreadMessages, err := s.Messages.GetUnreadMessagesCount()
+		if err != nil {
+			return err
+		}
+		event.UnreadMessages = unreadMessages
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}
+
+	// Sync the unread count in case something happened while the tab was reconnecting
+	if err := writeEvent(types.CRMEvent{Type: constants.MessageReadCRMEvent}); err != nil {
+		fmt.Printf("%+v\n", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(25 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case event := <-events:
+			if err := writeEvent(event); err != nil {
+				fmt.Printf("%+v\n", err)
+				return
+			}
+		}
+	}
+}
+
+func getConversationFilters(r *http.Request) (types.ConversationFilters, error) {
+	var filters types.ConversationFilters
+
+	switch r.URL.Query().Get("assigned") {
+	case "me":
+		values, err := sessions.Get(r)
+		if err != nil {
+			return filters, err
+		}
+		filters.AssignedUserID = &values.UserID
+	case "unassigned":
+		filters.Unassigned = true
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = constants.OpenConversationStatus
+	}
+	if status != "all" {
+		filters.Status = &status
+	}
+
+	return filters, nil
+}
+
+func (s *Server) getConversationOwners() ([]models.User, error) {
+	var owners []models.User
+
+	users, err := s.Users.GetUsers()
+	if err != nil {
+		return owners, err
+	}
+
+	for _, user := range users {
+		if helpers.HasCapability(user.UserRoleID, constants.ViewLeadsCapability) {
+			owners = append(owners, user)
+		}
+	}
+
+	return owners, nil
+}
+
+func (s *Server) serveConversationPanel(w http.ResponseWriter, leadId int) {
+	conversation, err := s.Conversations.GetConversation(leadId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get conversation.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	owners, err := s.getConversationOwners()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get users.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "conversation_panel.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "conversation_panel.html",
+		Data: map[string]any{
+			"Conversation":       conversation,
+			"ConversationOwners": owners,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) GetConversation(w http.ResponseWriter, r *http.Request) {
+	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/message/")
+	if err != nil {
+		http.Error(w, "Bad lead id.", http.StatusBadRequest)
+		return
+	}
+
+	s.serveConversationPanel(w, leadId)
+}
+
+// GetMessageMedia sends logged in users on to a short-lived link for a text message attachment.
+func (s *Server) GetMessageMedia(w http.ResponseWriter, r *http.Request) {
+	messageMediaId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/message/media/")
+	if err != nil {
+		http.Error(w, "Bad media id.", http.StatusBadRequest)
+		return
+	}
+
+	media, err := s.Messages.GetMessageMedia(messageMediaId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	mediaURL, err := services.GetMessageMediaURL(media.FilePath)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting message media.", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, mediaURL, http.StatusFound)
+}
+
+func (s *Server) PutConversation(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/message/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.ConversationForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	status := helpers.SafeString(form.Status)
+	var snoozedUntil *int64
+
+	switch status {
+	case constants.OpenConversationStatus, constants.ClosedConversationStatus:
+	case constants.SnoozedConversationStatus:
+		until, err := helpers.ParseScheduledMessageTime(helpers.SafeString(form.SnoozedUntil))
+		if err != nil || !until.After(time.Now()) {
+			tmplCtx := types.DynamicPartialTemplate{
+				TemplateName: "error",
+				TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+				Data: map[string]any{
+					"Message": "Snoozed conversations need a time in the future.",
+				},
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+			return
+		}
+		unix := until.Unix()
+		snoozedUntil = &unix
+	default:
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid conversation status.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var assignedUserId *int
+	if helpers.SafeInt(form.AssignedUserID) > 0 {
+		assignedUserId = form.AssignedUserID
+	}
+
+	err = s.Conversations.UpdateConversation(leadId, assignedUserId, status, snoozedUntil)
+	if err != nil {
+		fmt.Printf("Error updating conversation: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to update conversation.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveConversationPanel(w, leadId)
+}
+
+func (s *Server) PostConversationNote(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	leadId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/message/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.ConversationNoteForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil || strings.TrimSpace(helpers.SafeString(form.Note)) == "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Notes cannot be empty.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	values, err := sessions.Get(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting user ID from session.", http.StatusInternalServerError)
+		return
+	}
+
+	err = s.Conversations.CreateConversationNote(leadId, models.ConversationNote{
+		UserID:      values.UserID,
+		Note:        strings.TrimSpace(helpers.SafeString(form.Note)),
+		DateCreated: time.Now().Unix(),
+	})
+	if err != nil {
+		fmt.Printf("Error creating conversation note: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to save note.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveConversationPanel(w, leadId)
+}
+
+func (s *Server) GetCallFlow(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "call_flow.html"
+	callFlowForm := constants.PARTIAL_TEMPLATES_DIR + "call_flow_form.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, callFlowForm}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	callFlow, err := s.CallFlows.GetCallFlow()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting call flow from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	users, err := s.Users.GetUsers()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting users from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Call Flow — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["CallFlow"] = callFlow
+	data["Users"] = users
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) serveCallFlowForm(w http.ResponseWriter) {
+	callFlow, err := s.CallFlows.GetCallFlow()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting call flow from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	users, err := s.Users.GetUsers()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting users from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "call_flow_form.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "call_flow_form.html",
+		Data: map[string]any{
+			"CallFlow": callFlow,
+			"Users":    users,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PutCallFlow(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.CallFlowForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	ringStrategy := helpers.SafeString(form.RingStrategy)
+	ringTimeout := helpers.SafeInt(form.RingTimeout)
+
+	message := ""
+	switch {
+	case ringStrategy != constants.SimultaneousRingStrategy && ringStrategy != constants.SequentialRingStrategy:
+		message = "Pick how phones should ring."
+	case ringTimeout < 5 || ringTimeout > 60:
+		message = "Phones should ring for between 5 and 60 seconds."
+	case strings.TrimSpace(helpers.SafeString(form.VoicemailGreeting)) == "" || strings.TrimSpace(helpers.SafeString(form.AfterHoursMessage)) == "":
+		message = "The voicemail greeting and after hours message are required."
+	}
+
+	if len(form.BusinessHours) > 7 {
+		message = "Business hours can only be set for the days of the week."
+	}
+
+	for _, hours := range form.BusinessHours {
+		if !helpers.IsValidBusinessHours(helpers.SafeString(hours.OpenTime), helpers.SafeString(hours.CloseTime)) {
+			message = "Each open day needs an opening time before its closing time."
+		}
+	}
+
+	if message != "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": message,
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.CallFlows.UpdateCallFlow(form)
+	if err != nil {
+		fmt.Printf("Error updating call flow: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to update call flow.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveCallFlowForm(w)
+}
+
+func (s *Server) PostCallFlowRecipient(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("Error parsing form: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.CallFlowRecipientForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	language := helpers.SafeString(form.Language)
+	if form.UserID == nil || helpers.SafeInt(form.RingOrder) < 1 || (language != "" && language != constants.EnglishCallLanguage && language != constants.SpanishCallLanguage) {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Pick a user, a language and a ring order of 1 or more.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.CallFlows.CreateCallFlowRecipient(form)
+	if err != nil {
+		fmt.Printf("Error creating call flow recipient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to add phone to call flow.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveCallFlowForm(w)
+}
+
+func (s *Server) DeleteCallFlowRecipient(w http.ResponseWriter, r *http.Request) {
+	recipientId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/call-flow/recipient/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.CallFlows.DeleteCallFlowRecipient(recipientId)
+	if err != nil {
+		fmt.Printf("Error deleting call flow recipient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to remove phone from call flow.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveCallFlowForm(w)
+}
+
+func (s *Server) serveCocktailRecipeCard(w http.ResponseWriter, cocktailId int) {
+	cocktail, err := s.Events.GetCocktailDetails(fmt.Sprint(cocktailId))
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting cocktail from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	cocktailIngredients, err := s.Events.GetCocktailIngredients(cocktailId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting cocktail ingredients from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	// Only users who can manage events get this far, so the card is always editable
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "cocktail_recipe_card.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "cocktail_recipe_card.html",
+		Data: map[string]any{
+			"Cocktail":       cocktail,
+			"RecipeSections": helpers.GroupCocktailRecipe(cocktailIngredients),
+			"CanEdit":        true,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostCocktailIngredient(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	cocktailId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/cocktail/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form types.CocktailIngredientForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if form.IngredientID == nil || form.UnitID == nil || helpers.SafeFloat64(form.Amount) <= 0 {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Ingredient, unit and an amount greater than zero are required.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	form.CocktailID = &cocktailId
+
+	err = s.Events.SetCocktailIngredient(form)
+	if err != nil {
+		fmt.Printf("Error saving cocktail ingredient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Server error while saving cocktail ingredient.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveCocktailRecipeCard(w, cocktailId)
+}
+
+func (s *Server) DeleteCocktailIngredient(w http.ResponseWriter, r *http.Request) {
+	cocktailId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/cocktail/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ingredientId, err := helpers.GetSecondIDFromPath(r, "/crm/cocktail/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Events.DeleteCocktailIngredient(cocktailId, ingredientId)
+	if err != nil {
+		fmt.Printf("Error deleting cocktail ingredient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to delete cocktail ingredient.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveCocktailRecipeCard(w, cocktailId)
+}
+
+func (s *Server) GetIngredients(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "ingredients.html"
+	ingredientsTable := constants.PARTIAL_TEMPLATES_DIR + "ingredients_table.html"
+	unitsTable := constants.PARTIAL_TEMPLATES_DIR + "units_table.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, ingredientsTable, unitsTable}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	ingredients, err := s.Events.GetIngredients()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting ingredients from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	units, err := s.Events.GetUnits()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting units from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Ingredients — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["Ingredients"] = ingredients
+	data["Units"] = units
+	data["IngredientCategories"] = constants.IngredientCategories
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) serveIngredientsTable(w http.ResponseWriter) {
+	ingredients, err := s.Events.GetIngredients()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting ingredients from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "ingredients_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "ingredients_table.html",
+		Data: map[string]any{
+			"Ingredients": ingredients,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostIngredient(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.IngredientForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if strings.TrimSpace(helpers.SafeString(form.Name)) == "" || !helpers.IsValidIngredientCategory(helpers.SafeString(form.Category)) {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Ingredient name and a valid category are required.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Events.CreateIngredient(form)
+	if err != nil {
+		fmt.Printf("Error creating ingredient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Server error while creating ingredient.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveIngredientsTable(w)
+}
+
+func (s *Server) DeleteIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredientId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/ingredient/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Events.DeleteIngredient(ingredientId)
+	if err != nil {
+		fmt.Printf("Error deleting ingredient: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to delete ingredient.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveIngredientsTable(w)
+}
+
+func (s *Server) serveUnitsTable(w http.ResponseWriter) {
+	units, err := s.Events.GetUnits()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting units from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "units_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "units_table.html",
+		Data: map[string]any{
+			"Units": units,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostUnit(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.UnitForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	if strings.TrimSpace(helpers.SafeString(form.Name)) == "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Unit name is required.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Events.CreateUnit(form)
+	if err != nil {
+		fmt.Printf("Error creating unit: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Server error while creating unit.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveUnitsTable(w)
+}
+
+func (s *Server) DeleteUnit(w http.ResponseWriter, r *http.Request) {
+	unitId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/unit/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Events.DeleteUnit(unitId)
+	if err != nil {
+		fmt.Printf("Error deleting unit: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to delete unit. Remove it from any recipes first.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveUnitsTable(w)
+}
+
+// canViewEvent lets staff without ManageEvents see only the events they are assigned to.
+func (s *Server) canViewEvent(r *http.Request, eventId int) (bool, error) {
+	userRoleId, _ := r.Context().Value("user_role_id").(int)
+	if helpers.HasCapability(userRoleId, constants.ManageEventsCapability) {
+		return true, nil
+	}
+
+	userId, _ := r.Context().Value("user_id").(int)
+	return s.Events.IsUserAssignedToEvent(userId, eventId)
+}
+
+// buildEventShoppingList returns the list, or a message explaining why the event can't have one yet.
+func (s *Server) buildEventShoppingList(event models.Event, drinksPerGuestPerHour float64) (types.EventShoppingList, string, error) {
+	var list types.EventShoppingList
+
+	eventCocktails, err := s.Events.GetEventCocktails(event.EventID)
+	if err != nil {
+		return list, "", fmt.Errorf("error getting event cocktails: %w", err)
+	}
+
+	var cocktails []types.ShoppingListCocktail
+	for _, eventCocktail := range eventCocktails {
+		ingredients, err := s.Events.GetCocktailIngredients(eventCocktail.CocktailID)
+		if err != nil {
+			return list, "", fmt.Errorf("error getting cocktail ingredients: %w", err)
+		}
+
+		cocktails = append(cocktails, types.ShoppingListCocktail{
+			Name:        eventCocktail.Name,
+			Ingredients: ingredients,
+		})
+	}
+
+	list, err = helpers.BuildEventShoppingList(event.Guests, event.StartTime, event.EndTime, drinksPerGuestPerHour, cocktails)
+	if err != nil {
+		return list, fmt.Sprintf("Shopping list unavailable: %s.", err.Error()), nil
+	}
+
+	return list, "", nil
+}
+
+// getEventShoppingList loads the event in the path and builds its list at the rate given in the query string.
+func (s *Server) getEventShoppingList(r *http.Request) (models.Event, types.EventShoppingList, string, int, error) {
+	var list types.EventShoppingList
+
+	eventId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/event/")
+	if err != nil {
+		return models.Event{}, list, "", http.StatusBadRequest, fmt.Errorf("error getting event id from path: %w", err)
+	}
+
+	canView, err := s.canViewEvent(r, eventId)
+	if err != nil {
+		return models.Event{}, list, "", http.StatusInternalServerError, err
+	}
+
+	if !canView {
+		return models.Event{}, list, "", http.StatusForbidden, fmt.Errorf("user is not assigned to event %d", eventId)
+	}
+
+	drinksPerGuestPerHour := constants.DefaultDrinksPerGuestPerHour
+	if rate := r.URL.Query().Get("rate"); rate != "" {
+		drinksPerGuestPerHour, err = strconv.ParseFloat(rate, 64)
+		if err != nil || drinksPerGuestPerHour <= 0 {
+			return models.Event{}, list, "", http.StatusBadRequest, fmt.Errorf("invalid drinks per guest per hour: %s", rate)
+		}
+	}
+
+	event, err := s.Events.GetEventDetails(fmt.Sprint(eventId))
+	if err != nil {
+		return models.Event{}, list, "", http.StatusInternalServerError, fmt.Errorf("error getting event details: %w", err)
+	}
+
+	list, message, err := s.buildEventShoppingList(event, drinksPerGuestPerHour)
+	if err != nil {
+		return event, list, "", http.StatusInternalServerError, err
+	}
+	list.DrinksPerGuestPerHour = drinksPerGuestPerHour
+
+	return event, list, message, http.StatusOK, nil
+}
+
+func (s *Server) GetEventShoppingList(w http.ResponseWriter, r *http.Request) {
+	event, list, message, status, err := s.getEventShoppingList(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error building event shopping list.",
+			},
+		}
+		w.WriteHeader(status)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "event_shopping_list.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "event_shopping_list.html",
+		Data: map[string]any{
+			"Event":             event,
+			"ShoppingList":      list,
+			"ShoppingListError": message,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) GetEventShoppingListPrint(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	files := []string{constants.CRM_TEMPLATES_DIR + "event_shopping_list_print.html", constants.PARTIAL_TEMPLATES_DIR + "event_shopping_list.html"}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	event, list, message, status, err := s.getEventShoppingList(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error building event shopping list.", status)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = fmt.Sprintf("Shopping List — Event %d", event.EventID)
+	data["Nonce"] = nonce
+	data["Event"] = event
+	data["EventDate"] = utils.FormatTimestampWithOptions(event.StartTime, &types.TimestampFormatOptions{Format: "Mon Jan 2, 2006 3:04 PM", TimeZone: constants.TimeZone})
+	data["ShoppingList"] = list
+	data["ShoppingListError"] = message
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) GetEventShoppingListExport(w http.ResponseWriter, r *http.Request) {
+	event, list, message, status, err := s.getEventShoppingList(r)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error building event shopping list.", status)
+		return
+	}
+
+	if message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=event_%d_shopping_list.csv", event.EventID))
+	w.Header().Set("Content-Type", "text/csv")
+
+	if err := helpers.WriteCSV(w, list.Items); err != nil {
+		fmt.Printf("%+v\n", err)
+	}
+}
+
+func (s *Server) GetDiscountCodes(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	baseFile := constants.CRM_TEMPLATES_DIR + "discount_codes.html"
+	discountCodesTable := constants.PARTIAL_TEMPLATES_DIR + "discount_codes_table.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, baseFile, discountCodesTable}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	discountCodes, err := s.Quotes.GetDiscountCodes()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting discount codes from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	redemptions, err := s.Quotes.GetDiscountRedemptionsBySource()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting discount redemptions from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	serviceTypes, err := s.Quotes.GetServiceTypes()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting service types from DB.", http.StatusInternalServerError)
+		return
+	}
+
+	data := ctx
+	data["PageTitle"] = "Discount Codes — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["DiscountCodes"] = discountCodes
+	data["Redemptions"] = redemptions
+	data["ServiceTypes"] = serviceTypes
+	data["DiscountTypes"] = constants.DiscountTypes
+	data["PricingAdjustmentServiceTypeID"] = constants.PricingAdjustmentServiceTypeID
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) serveDiscountCodesTable(w http.ResponseWriter) {
+	discountCodes, err := s.Quotes.GetDiscountCodes()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error getting discount codes from DB.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "discount_codes_table.html",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "discount_codes_table.html",
+		Data: map[string]any{
+			"DiscountCodes": discountCodes,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
+
+func (s *Server) PostDiscountCode(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.DiscountCodeForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	code := helpers.NormalizeDiscountCode(helpers.SafeString(form.Code))
+	form.Code = &code
+
+	amount := helpers.SafeFloat64(form.Amount)
+	discountType := helpers.SafeString(form.DiscountType)
+
+	message := ""
+	switch {
+	case code == "" || !helpers.IsValidDiscountType(discountType) || amount <= 0:
+		message = "Code, a valid discount type and an amount greater than zero are required."
+	case discountType == constants.PercentageDiscountType && amount > 100:
+		message = "A percentage discount cannot be more than 100%."
+	case helpers.SafeInt(form.MaxRedemptions) < 0:
+		message = "Usage limit cannot be negative."
+	}
+
+	if message != "" {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": message,
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Quotes.CreateDiscountCode(form)
+	if err != nil {
+		fmt.Printf("Error creating discount code: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Server error while creating discount code.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveDiscountCodesTable(w)
+}
+
+func (s *Server) DeleteDiscountCode(w http.ResponseWriter, r *http.Request) {
+	discountCodeId, err := helpers.GetFirstIDAfterPrefix(r, "/crm/discount-code/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.Quotes.DeactivateDiscountCode(discountCodeId)
+	if err != nil {
+		fmt.Printf("Error deactivating discount code: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to deactivate discount code.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	s.serveDiscountCodesTable(w)
+}
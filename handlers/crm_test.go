@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+func postQuickQuote(srv *Server, leadId int, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/crm/lead/%d/quote/quick", leadId), strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rec := httptest.NewRecorder()
+	srv.PostQuickQuote(rec, req)
+	return rec
+}
+
+func TestPostQuickQuote(t *testing.T) {
+	srv, stores := newTestServer(t)
+
+	leadId := createTestLead(t, stores, "3055550101")
+	serviceId := createTestService(t, stores, "Open Bar", constants.FlatUnitTypeID, 800)
+
+	rec := postQuickQuote(srv, leadId, url.Values{
+		"lead_id":            {fmt.Sprint(leadId)},
+		"event_date_service": {fmt.Sprint(testEventDate(t))},
+		"hours_service":      {"4"},
+		"guests_service":     {"50"},
+		"quote_services":     {fmt.Sprintf(`[{"service_id": %d, "units": 1}]`, serviceId)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	quoteId, err := strconv.Atoi(rec.Body.String())
+	if err != nil {
+		t.Fatalf("expected the quote id in the body, got %q", rec.Body.String())
+	}
+
+	quoteServices, err := stores.Quotes.GetQuoteServices(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quoteServices) != 1 || quoteServices[0].Total != 800 {
+		t.Fatalf("expected a single $800 line, got %+v", quoteServices)
+	}
+
+	invoices, err := stores.Invoices.GetLeadQuoteInvoices(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	amounts := make(map[int]float64)
+	for _, invoice := range invoices {
+		amounts[invoice.InvoiceTypeID] = invoice.Amount * invoice.InvoiceTypeMultiplier
+	}
+	expected := map[int]float64{
+		constants.DepositInvoiceTypeID:   200,
+		constants.RemainingInvoiceTypeID: 600,
+		constants.FullInvoiceTypeID:      800,
+	}
+	for invoiceTypeId, amount := range expected {
+		if amounts[invoiceTypeId] != amount {
+			t.Errorf("invoice type %d: expected $%.2f, got $%.2f", invoiceTypeId, amount, amounts[invoiceTypeId])
+		}
+	}
+}
+
+func TestPostQuickQuoteRejectsInvalidRequests(t *testing.T) {
+	srv, stores := newTestServer(t)
+
+	leadId := createTestLead(t, stores, "3055550102")
+	serviceId := createTestService(t, stores, "Open Bar", constants.FlatUnitTypeID, 800)
+
+	tests := []struct {
+		name string
+		form url.Values
+	}{
+		{
+			name: "malformed services",
+			form: url.Values{"guests_service": {"50"}, "hours_service": {"4"}, "quote_services": {"not json"}},
+		},
+		{
+			name: "no guests",
+			form: url.Values{"hours_service": {"4"}, "quote_services": {fmt.Sprintf(`[{"service_id": %d}]`, serviceId)}},
+		},
+		{
+			name: "unknown service",
+			form: url.Values{"guests_service": {"50"}, "hours_service": {"4"}, "quote_services": {`[{"service_id": 9999}]`}},
+		},
+		{
+			name: "unknown discount code",
+			form: url.Values{"guests_service": {"50"}, "hours_service": {"4"}, "discount_code_service": {"NOPE"}, "quote_services": {fmt.Sprintf(`[{"service_id": %d}]`, serviceId)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.form.Set("lead_id", fmt.Sprint(leadId))
+
+			rec := postQuickQuote(srv, leadId, tt.form)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	quotes, err := stores.Quotes.GetLeadQuotes(leadId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotes) != 0 {
+		t.Fatalf("expected no quotes to be created, got %d", len(quotes))
+	}
+}
+
+func TestCRMRouteCapability(t *testing.T) {
+	tests := []struct {
+		method     string
+		path       string
+		capability string
+	}{
+		{http.MethodGet, "/crm/lead", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/lead/12", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/lead/12/quote/7", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/lead/12/event/3", constants.ViewOwnEventsCapability},
+		{http.MethodPut, "/crm/lead/12/event/3", constants.ManageEventsCapability},
+		{http.MethodPost, "/crm/lead/12/event", constants.ManageEventsCapability},
+		{http.MethodPost, "/crm/lead/12/note", constants.ViewLeadsCapability},
+		{http.MethodPut, "/crm/lead/12/archive", constants.ViewLeadsCapability},
+		{http.MethodPost, "/crm/lead/12/quick-quote", constants.EditQuotesCapability},
+		{http.MethodPost, "/crm/lead/12/quote", constants.EditQuotesCapability},
+		{http.MethodPut, "/crm/lead/12/quote/7", constants.EditQuotesCapability},
+		{http.MethodDelete, "/crm/lead/12/quote/7/discount", constants.EditQuotesCapability},
+		{http.MethodPost, "/crm/lead/12/quote/7/invoice", constants.SendInvoicesCapability},
+		{http.MethodPost, "/crm/lead/12/quote/7/invoice-reminder", constants.SendInvoicesCapability},
+		{http.MethodGet, "/crm/message/12/conversation", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/message/media/9", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/event", constants.ViewOwnEventsCapability},
+		{http.MethodGet, "/crm/event/3/shopping-list/print", constants.ViewOwnEventsCapability},
+		{http.MethodPost, "/crm/event/3/staff", constants.ManageEventsCapability},
+		{http.MethodDelete, "/crm/event/3/cocktail/4", constants.ManageEventsCapability},
+		{http.MethodGet, "/crm/cocktail/4", constants.ViewOwnEventsCapability},
+		{http.MethodPut, "/crm/cocktail/4", constants.ManageEventsCapability},
+		{http.MethodDelete, "/crm/unit/5", constants.ManageEventsCapability},
+		{http.MethodPut, "/crm/service/6", constants.EditQuotesCapability},
+		{http.MethodPost, "/crm/quote-service", constants.EditQuotesCapability},
+		{http.MethodDelete, "/crm/discount-code/8", constants.EditQuotesCapability},
+		{http.MethodGet, "/crm/user/9", constants.ManageUsersCapability},
+		{http.MethodDelete, "/crm/call-flow/recipient/2", constants.ManageUsersCapability},
+		{http.MethodPost, "/crm/payroll/paid", constants.ManagePayrollCapability},
+		{http.MethodGet, "/crm/reports/marketing/export", constants.ViewReportsCapability},
+		{http.MethodPost, "/crm/jobs/requeue", constants.ManageJobsCapability},
+		{http.MethodDelete, "/crm/sms-sequence/1/step/2", constants.ViewLeadsCapability},
+		{http.MethodGet, "/crm/lead/", constants.ViewLeadsCapability},
+
+		// Anything not mapped is denied
+		{http.MethodGet, "/crm/lead/12/secret", ""},
+		{http.MethodDelete, "/crm/lead/12", ""},
+		{http.MethodPost, "/crm/new-page", ""},
+		{http.MethodPatch, "/crm/lead/12", ""},
+		{http.MethodGet, "/crm/lead/abc", ""},
+	}
+
+	for _, tt := range tests {
+		if got := crmRouteCapability(tt.method, tt.path); got != tt.capability {
+			t.Errorf("%s %s: expected %q, got %q", tt.method, tt.path, tt.capability, got)
+		}
+	}
+}
@@ -1,15 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/services"
+	"github.com/davidalvarez305/yd_cocktails/types"
 )
 
 func createExternalViewContext() map[string]any {
@@ -27,7 +29,7 @@ func createExternalViewContext() map[string]any {
 
 var externalBaseFilePath = constants.EXTERNAL_TEMPLATES_DIR + "base.html"
 
-func ExternalHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) ExternalHandler(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 
 	ctx := createExternalViewContext()
@@ -36,19 +38,25 @@ func ExternalHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		if strings.HasPrefix(path, "/external/") {
-			GetExternalQuoteDetails(w, r, ctx)
+			s.GetExternalQuoteDetails(w, r, ctx)
 			return
 		}
 		switch path {
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
+	case http.MethodPost:
+		if strings.HasPrefix(path, "/external/") && strings.HasSuffix(path, "/accept") {
+			s.PostAcceptExternalQuote(w, r)
+			return
+		}
+		http.Error(w, "Not Found", http.StatusNotFound)
 	default:
 		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-func GetExternalQuoteDetails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+func (s *Server) GetExternalQuoteDetails(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
 	headerPath := "header_desktop.html"
 	if helpers.IsMobileRequest(r) {
 		headerPath = "header_mobile.html"
@@ -62,18 +70,69 @@ func GetExternalQuoteDetails(w http.ResponseWriter, r *http.Request, ctx map[str
 		return
 	}
 
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
 	externalQuoteId := strings.TrimPrefix(r.URL.Path, "/external/")
 
-	quote, err := database.GetExternalQuoteDetails(externalQuoteId)
+	quote, err := s.Quotes.GetExternalQuoteDetails(externalQuoteId)
 	if err != nil {
 		fmt.Printf("ERROR GETTING QUOTE DETAILS: %+v\n", err)
 		http.Error(w, "Error retrieving quote details.", http.StatusInternalServerError)
 		return
 	}
 
+	quoteServices, err := s.Quotes.GetQuoteServices(quote.QuoteID)
+	if err != nil {
+		fmt.Printf("ERROR GETTING QUOTE SERVICES: %+v\n", err)
+		http.Error(w, "Error retrieving quote services.", http.StatusInternalServerError)
+		return
+	}
+
+	acceptance, err := s.Quotes.GetQuoteAcceptance(quote.QuoteID)
+	if err != nil {
+		fmt.Printf("ERROR GETTING QUOTE ACCEPTANCE: %+v\n", err)
+		http.Error(w, "Error retrieving quote details.", http.StatusInternalServerError)
+		return
+	}
+
+	// Until the customer chooses, the totals only cover what every package includes
+	quoteOptions := helpers.GroupQuoteOptions(quoteServices)
+	if len(quoteOptions.Packages) > 0 || len(quoteOptions.AddOns) > 0 {
+		var included float64
+		for _, qs := range quoteOptions.Included {
+			included += qs.Total
+		}
+
+		if quote.Amount > 0 {
+			ratio := included / quote.Amount
+			quote.Deposit = math.Round(quote.Deposit*ratio*100) / 100
+			quote.RemainingAmount = math.Round(quote.RemainingAmount*ratio*100) / 100
+		}
+		quote.Amount = math.Round(included*100) / 100
+	}
+
+	discount, err := s.Quotes.GetQuoteDiscount(quote.QuoteID)
+	if err != nil {
+		fmt.Printf("ERROR GETTING QUOTE DISCOUNT: %+v\n", err)
+		http.Error(w, "Error retrieving quote details.", http.StatusInternalServerError)
+		return
+	}
+
+	// Invoices take the discount off proportionally, so the deposit and remaining amounts shrink with the total
+	if discount.Amount > 0 && quote.Amount > 0 {
+		ratio := (quote.Amount - discount.Amount) / quote.Amount
+		quote.Deposit = math.Round(quote.Deposit*ratio*100) / 100
+		quote.RemainingAmount = math.Round(quote.RemainingAmount*ratio*100) / 100
+		quote.Amount = math.Round((quote.Amount-discount.Amount)*100) / 100
+	}
+
 	// I have to do this for now because I don't know if it's a good idea or not to save the quote as a column on invoices
 	if quote.IsDepositPaid {
-		inv, err := database.GetRemainingInvoice(quote.QuoteID)
+		inv, err := s.Invoices.GetRemainingInvoice(quote.QuoteID)
 		if err != nil {
 			fmt.Printf("ERROR GETTING REMAINING INVOICE FROM DB: %+v\n", err)
 			http.Error(w, "Error retrieving quote details.", http.StatusInternalServerError)
@@ -90,13 +149,6 @@ func GetExternalQuoteDetails(w http.ResponseWriter, r *http.Request, ctx map[str
 		quote.RemainingAmount = float64(remainingInvoice.AmountDue / 100)
 	}
 
-	quoteServices, err := database.GetQuoteServices(quote.QuoteID)
-	if err != nil {
-		fmt.Printf("ERROR GETTING QUOTE SERVICES: %+v\n", err)
-		http.Error(w, "Error retrieving quote services.", http.StatusInternalServerError)
-		return
-	}
-
 	isWithin48Hours := false
 	t := time.Unix(quote.EventDateTimestamp, 0)
 	currentTime := time.Now()
@@ -111,9 +163,85 @@ func GetExternalQuoteDetails(w http.ResponseWriter, r *http.Request, ctx map[str
 	data["Nonce"] = nonce
 	data["Quote"] = quote
 	data["QuoteServices"] = quoteServices
+	data["QuoteOptions"] = quoteOptions
+	data["Acceptance"] = acceptance
+	data["CSRFToken"] = csrfToken
+	data["Discount"] = discount
 	data["IsWithin48Hours"] = isWithin48Hours
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
 	helpers.ServeContent(w, files, data)
 }
+
+func (s *Server) PostAcceptExternalQuote(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Invalid request.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	var form types.QuoteAcceptanceForm
+	err = decoder.Decode(&form, r.PostForm)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Error decoding form data.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	externalQuoteId := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/external/"), "/accept")
+
+	err = services.AcceptQuote(externalQuoteId, form, helpers.GetUserIPFromRequest(r), r.UserAgent())
+	if err != nil {
+		fmt.Printf("ERROR ACCEPTING QUOTE: %+v\n", err)
+
+		// Only problems with the customer's own submission are shown, everything else stays in the logs
+		message := "We couldn't accept your quote right now. Please try again or give us a call."
+		status := http.StatusInternalServerError
+
+		var acceptanceErr services.QuoteAcceptanceError
+		if errors.As(err, &acceptanceErr) || errors.Is(err, services.ErrQuoteAlreadyAccepted) || errors.Is(err, services.ErrQuoteAcceptanceInProgress) {
+			message = fmt.Sprintf("Error accepting quote: %s.", err)
+			status = http.StatusBadRequest
+		}
+
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": message,
+			},
+		}
+		w.WriteHeader(status)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "modal",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "modal.html",
+		Data: map[string]any{
+			"AlertHeader":  "Thank you!",
+			"AlertMessage": "Your quote has been accepted. You can now reserve your date.",
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func TestPostAcceptExternalQuoteOnlyShowsCustomerErrors(t *testing.T) {
+	srv, stores := newTestServer(t)
+
+	leadId := createTestLead(t, stores, "3055550801")
+	serviceId := createTestService(t, stores, "Open Bar", constants.FlatUnitTypeID, 800)
+
+	guests, hours, eventDate := 50, 4.0, testEventDate(t)
+	units, price := 1.0, 800.0
+	_, externalQuoteId, err := stores.Quotes.CreateQuickQuote(types.QuickQuoteForm{
+		LeadID:    &leadId,
+		Guests:    &guests,
+		Hours:     &hours,
+		EventDate: &eventDate,
+	}, []types.QuoteServiceForm{{ServiceID: &serviceId, Units: &units, PricePerUnit: &price}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		externalQuoteId string
+		form            url.Values
+		status          int
+		message         string
+	}{
+		{"missing signature", externalQuoteId, url.Values{"agree_to_terms": {"true"}}, http.StatusBadRequest, "please type your full name to sign"},
+		{"terms not accepted", externalQuoteId, url.Values{"signature_name": {"Jane Doe"}}, http.StatusBadRequest, "please agree to the terms and conditions"},
+		{"internal failure", "not-a-quote", url.Values{"signature_name": {"Jane Doe"}, "agree_to_terms": {"true"}}, http.StatusInternalServerError, "We couldn&#39;t accept your quote right now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/external/"+tt.externalQuoteId+"/accept", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			rec := httptest.NewRecorder()
+			srv.PostAcceptExternalQuote(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("got status %d, expected %d", rec.Code, tt.status)
+			}
+			if !strings.Contains(rec.Body.String(), tt.message) {
+				t.Errorf("expected %q in %s", tt.message, rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), "error getting quote") {
+				t.Errorf("internal error leaked to the customer: %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -8,16 +8,16 @@ import (
 	"github.com/davidalvarez305/yd_cocktails/types"
 )
 
-func PartialsHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PartialsHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		switch r.URL.Path {
 		case "/partials/pop-up-modal":
-			GetPopUpModal(w, r)
+			s.GetPopUpModal(w, r)
 		case "/partials/error-modal":
-			GetErrorModal(w, r)
+			s.GetErrorModal(w, r)
 		case "/partials/opt-out-confirmation-modal":
-			GetOptOutConfirmationModal(w, r)
+			s.GetOptOutConfirmationModal(w, r)
 		default:
 			http.Error(w, "No partials found.", http.StatusNotFound)
 		}
@@ -26,7 +26,7 @@ func PartialsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func GetPopUpModal(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetPopUpModal(w http.ResponseWriter, r *http.Request) {
 	fileName := "pop_up.html"
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -34,7 +34,7 @@ func GetPopUpModal(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, constants.PARTIAL_TEMPLATES_DIR+fileName)
 }
 
-func GetErrorModal(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetErrorModal(w http.ResponseWriter, r *http.Request) {
 	errMessage := r.URL.Query().Get("err")
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -50,7 +50,7 @@ func GetErrorModal(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetOptOutConfirmationModal(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetOptOutConfirmationModal(w http.ResponseWriter, r *http.Request) {
 	fileName := "opt_out_confirmation_modal.html"
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
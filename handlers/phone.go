@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,31 +10,39 @@ import (
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/davidalvarez305/yd_cocktails/services"
 	"github.com/davidalvarez305/yd_cocktails/types"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
 )
 
-func PhoneServiceHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PhoneServiceHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		switch r.URL.Path {
 		case "/call/inbound":
-			handleInboundCall(w, r)
+			s.handleInboundCall(w, r)
 		case "/call/outbound":
-			handleOutboundCall(w, r)
+			s.handleOutboundCall(w, r)
 		case "/call/inbound/end":
-			handleInboundCallEnd(w, r)
+			s.handleInboundCallEnd(w, r)
 		case "/call/inbound/recording-callback":
-			handleCallRecordingCallback(w, r)
+			s.handleCallRecordingCallback(w, r)
 		case "/sms/inbound":
-			handleInboundSMS(w, r)
+			s.handleInboundSMS(w, r)
 		case "/sms/outbound":
-			handleOutboundSMS(w, r)
+			s.handleOutboundSMS(w, r)
+		case constants.TwilioSMSStatusCallbackWebhook:
+			s.handleSMSStatusCallback(w, r)
 		case "/call/inbound/amd":
-			handleAmdStatusCallback(w, r)
+			s.handleAmdStatusCallback(w, r)
+		case constants.TwilioCallMenuWebhook:
+			s.handleInboundCallMenu(w, r)
+		case constants.TwilioVoicemailWebhook:
+			s.handleVoicemailComplete(w, r)
+		case constants.TwilioVoicemailRecordingWebhook:
+			s.handleVoicemailRecording(w, r)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
@@ -41,7 +51,7 @@ func PhoneServiceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleInboundCall(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleInboundCall(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 		return
@@ -55,44 +65,185 @@ func handleInboundCall(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if incomingPhoneCall.To != incomingPhoneCall.From {
-		forwardPhoneNumber, err := database.GetForwardPhoneNumber(helpers.RemoveCountryCode(incomingPhoneCall.To), helpers.RemoveCountryCode(incomingPhoneCall.From))
+		callTo := helpers.RemoveCountryCode(incomingPhoneCall.To)
+		callFrom := helpers.RemoveCountryCode(incomingPhoneCall.From)
+
+		flow, fallbackNumber, err := s.getCallFlow(callTo, callFrom)
 		if err != nil {
-			fmt.Printf("Failed to get matching phone number: %+v\n", err)
-			http.Error(w, "Failed to get matching phone number.", http.StatusInternalServerError)
+			fmt.Printf("Failed to get call flow: %+v\n", err)
+			http.Error(w, "Failed to get call flow.", http.StatusInternalServerError)
 			return
 		}
 
-		recordingCallbackURL := fmt.Sprintf("%s%s", constants.RootDomain, constants.TwilioRecordingCallbackWebhook)
-
-		twiML := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-		<Response>
-			<Dial record="true" recordingStatusCallback="%s" recordingStatusCallbackEvent="completed" action="%s">%s</Dial>
-		</Response>`, recordingCallbackURL, constants.RootDomain+constants.TwilioCallbackWebhook, forwardPhoneNumber)
+		twiML, err := services.InboundCallTwiML(flow, fallbackNumber, time.Now())
+		if err != nil {
+			fmt.Printf("Failed to build call flow: %+v\n", err)
+			http.Error(w, "Failed to build call flow.", http.StatusInternalServerError)
+			return
+		}
 
 		phoneCall := models.PhoneCall{
 			ExternalID:   incomingPhoneCall.CallSid,
 			CallDuration: 0,
 			DateCreated:  time.Now().Unix(),
-			CallFrom:     helpers.RemoveCountryCode(incomingPhoneCall.From),
-			CallTo:       helpers.RemoveCountryCode(incomingPhoneCall.To),
+			CallFrom:     callFrom,
+			CallTo:       callTo,
 			IsInbound:    true,
 			RecordingURL: "",
 			Status:       incomingPhoneCall.CallStatus,
 		}
 
-		if err := database.SavePhoneCall(phoneCall); err != nil {
+		if err := s.Messages.SavePhoneCall(phoneCall); err != nil {
 			fmt.Printf("Failed to save phone call: %+v\n", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
+		// A lead that doesn't exist yet shouldn't keep the caller from getting through
+		if _, err := services.GetOrCreateLeadFromPhoneNumber(callFrom, constants.PhoneLeadSource, callTo); err != nil && !errors.Is(err, services.ErrStaffPhoneNumber) {
+			fmt.Printf("Failed to get or create lead for caller: %+v\n", err)
+		}
+
+		s.publishCallStatus(phoneCall)
+
 		w.Header().Set("Content-Type", "application/xml")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(twiML))
 	}
 }
 
-func handleInboundCallEnd(w http.ResponseWriter, r *http.Request) {
+// getCallFlow only looks up the tracking number's forwarding phone when nobody has been added to the call flow.
+func (s *Server) getCallFlow(to, from string) (types.CallFlowDetails, string, error) {
+	flow, err := s.CallFlows.GetCallFlow()
+	if err != nil {
+		return flow, "", err
+	}
+
+	if len(flow.Recipients) > 0 {
+		return flow, "", nil
+	}
+
+	forwardPhoneNumber, err := s.Users.GetForwardPhoneNumber(to, from)
+	if err != nil {
+		return flow, "", fmt.Errorf("failed to get matching phone number: %w", err)
+	}
+
+	return flow, forwardPhoneNumber, nil
+}
+
+func (s *Server) handleInboundCallMenu(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	flow, fallbackNumber, err := s.getCallFlow(helpers.RemoveCountryCode(r.FormValue("To")), helpers.RemoveCountryCode(r.FormValue("From")))
+	if err != nil {
+		fmt.Printf("Failed to get call flow: %+v\n", err)
+		http.Error(w, "Failed to get call flow.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(services.CallFlowMenuTwiML(flow, r.FormValue("Digits"), fallbackNumber)))
+}
+
+func (s *Server) handleVoicemailComplete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(services.VoicemailCompleteTwiML()))
+}
+
+// handleVoicemailRecording runs once Twilio has the voicemail audio ready. The caller gets a call back task and the
+// recording is queued for transcription.
+func (s *Server) handleVoicemailRecording(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	callSID := r.FormValue("CallSid")
+	recordingSID := r.FormValue("RecordingSid")
+
+	if callSID == "" || recordingSID == "" {
+		http.Error(w, "Missing CallSid or RecordingSid", http.StatusBadRequest)
+		return
+	}
+
+	recordingURL := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Recordings/%s.mp3", constants.TwilioAccountSID, recordingSID)
+
+	if err := s.Messages.SetRecordingURLToPhoneCall(callSID, recordingURL); err != nil {
+		fmt.Printf("FAILED TO UPDATE PHONE CALL WITH VOICEMAIL URL: %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	phoneCall, err := s.Messages.GetPhoneCallBySID(callSID)
+	if err != nil {
+		fmt.Printf("FAILED TO GET VOICEMAIL PHONE CALL: %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	phoneCall.Status = constants.VoicemailCallStatus
+
+	if err := s.Messages.UpdatePhoneCall(phoneCall); err != nil {
+		fmt.Printf("FAILED TO UPDATE VOICEMAIL PHONE CALL: %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	leadId, err := services.GetOrCreateLeadFromPhoneNumber(phoneCall.CallFrom, constants.PhoneLeadSource, phoneCall.CallTo)
+	if err != nil && !errors.Is(err, services.ErrStaffPhoneNumber) {
+		fmt.Printf("FAILED TO GET LEAD FOR VOICEMAIL: %+v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Staff leaving a voicemail on a tracking number don't need a call back
+	if err == nil {
+		callBackActionID := constants.CallBackActionID
+		now := time.Now().Unix()
+
+		err = s.Leads.CreateLeadNextAction(types.LeadNextActionForm{
+			NextActionID:   &callBackActionID,
+			LeadID:         &leadId,
+			NextActionDate: &now,
+		})
+		if err != nil {
+			fmt.Printf("FAILED TO CREATE CALL BACK TASK: %+v\n", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Puts the lead back in its owner's open conversations
+		if err := s.Conversations.ReopenConversation(leadId); err != nil {
+			fmt.Printf("FAILED TO REOPEN CONVERSATION: %+v\n", err)
+		}
+	}
+
+	if err := services.QueueVoicemailTranscription(callSID); err != nil {
+		fmt.Printf("FAILED TO QUEUE VOICEMAIL TRANSCRIPTION: %+v\n", err)
+	}
+
+	s.publishCallStatus(phoneCall)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) publishCallStatus(phoneCall models.PhoneCall) {
+	leadId, _ := s.Leads.GetLeadIDFromPhoneNumber(phoneCall.CallFrom)
+
+	services.PublishCRMEvent(types.CRMEvent{
+		Type:    constants.CallStatusCRMEvent,
+		LeadID:  leadId,
+		Message: fmt.Sprintf("Call from %s: %s", phoneCall.CallFrom, phoneCall.Status),
+	})
+}
+
+func (s *Server) handleInboundCallEnd(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 		return
@@ -109,7 +260,7 @@ func handleInboundCallEnd(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	phoneCall, err := database.GetPhoneCallBySID(dialStatus.CallSid)
+	phoneCall, err := s.Messages.GetPhoneCallBySID(dialStatus.CallSid)
 	if err != nil {
 		fmt.Printf("FAILED TO GET PREVIOUS PHONE CALL: %+v\n", dialStatus)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -119,17 +270,39 @@ func handleInboundCallEnd(w http.ResponseWriter, r *http.Request) {
 	phoneCall.CallDuration = dialStatus.DialCallDuration
 	phoneCall.Status = dialStatus.DialCallStatus
 
-	if err := database.UpdatePhoneCall(phoneCall); err != nil {
+	if err := s.Messages.UpdatePhoneCall(phoneCall); err != nil {
 		fmt.Printf("FAILED TO UPDATE PREVIOUS PHONE CALL: %+v\n", dialStatus)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	s.publishCallStatus(phoneCall)
+
+	if phoneCall.IsInbound && !isAnsweredDialStatus(dialStatus.DialCallStatus) {
+		flow, fallbackNumber, err := s.getCallFlow(phoneCall.CallTo, phoneCall.CallFrom)
+		if err != nil {
+			fmt.Printf("Failed to get call flow: %+v\n", err)
+			http.Error(w, "Failed to get call flow.", http.StatusInternalServerError)
+			return
+		}
+
+		twiML := services.CallFlowNoAnswerTwiML(flow, r.URL.Query().Get("language"), r.URL.Query().Get("next"), fallbackNumber)
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(twiML))
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/xml")
 	w.WriteHeader(http.StatusOK)
 }
 
-func handleOutboundCall(w http.ResponseWriter, r *http.Request) {
+func isAnsweredDialStatus(status string) bool {
+	return status == "completed" || status == "answered"
+}
+
+func (s *Server) handleOutboundCall(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 		return
@@ -174,7 +347,7 @@ func handleOutboundCall(w http.ResponseWriter, r *http.Request) {
 		Status:       helpers.SafeString(outboundCall.Status),
 	}
 
-	if err := database.SavePhoneCall(phoneCall); err != nil {
+	if err := s.Messages.SavePhoneCall(phoneCall); err != nil {
 		fmt.Printf("Failed to save phone call: %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -183,7 +356,7 @@ func handleOutboundCall(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func handleInboundSMS(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleInboundSMS(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 		return
@@ -210,18 +383,92 @@ func handleInboundSMS(w http.ResponseWriter, r *http.Request) {
 		IsRead:      false,
 	}
 
-	if err := database.SaveSMS(message); err != nil {
+	numMedia, _ := strconv.Atoi(twilioMessage.NumMedia)
+	for i := 0; i < numMedia; i++ {
+		mediaURL := r.FormValue(fmt.Sprintf("MediaUrl%d", i))
+		contentType := r.FormValue(fmt.Sprintf("MediaContentType%d", i))
+
+		media, err := services.StoreInboundMessageMedia(mediaURL, contentType)
+		if err != nil {
+			log.Printf("Error storing message media: %s", err)
+			continue
+		}
+
+		message.Media = append(message.Media, media)
+	}
+
+	if err := s.Messages.SaveSMS(message); err != nil {
 		log.Printf("Error saving SMS to database: %s", err)
 		http.Error(w, "Failed to save message.", http.StatusInternalServerError)
 		return
 	}
 
+	if err := services.ProcessSMSConsentKeyword(message); err != nil {
+		log.Printf("Error processing SMS consent keyword: %s", err)
+	}
+
+	var leadId int
+	var err error
+
+	// Opting out never makes someone a lead, it only applies to one that already exists
+	_, action := helpers.ParseSMSConsentKeyword(message.Text)
+	isOptOut := action == constants.OptOutSMSConsentAction
+
+	if isOptOut {
+		leadId, err = s.Leads.GetLeadIDFromPhoneNumber(message.TextFrom)
+	} else {
+		leadId, err = services.GetOrCreateLeadFromPhoneNumber(message.TextFrom, constants.SMSLeadSource, message.TextTo)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, services.ErrStaffPhoneNumber) {
+		log.Printf("Error getting or creating lead for text: %s", err)
+	}
+
+	// Any reply other than an opt out hands the conversation back to a person
+	if err == nil && !isOptOut {
+		if err := s.Sequences.StopSMSSequenceEnrollments(leadId, constants.RepliedSMSSequenceStopReason); err != nil {
+			log.Printf("Error stopping SMS sequences: %s", err)
+		}
+		if err := s.Conversations.ReopenConversation(leadId); err != nil {
+			log.Printf("Error reopening conversation: %s", err)
+		}
+	}
+
+	services.PublishCRMEvent(types.CRMEvent{
+		Type:    constants.InboundMessageCRMEvent,
+		LeadID:  leadId,
+		Message: fmt.Sprintf("New text from %s", message.TextFrom),
+	})
+
 	w.WriteHeader(http.StatusOK)
 }
 
-func handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseForm()
-	if err != nil {
+func (s *Server) handleSMSStatusCallback(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	messageSid := r.FormValue("MessageSid")
+	messageStatus := r.FormValue("MessageStatus")
+	errorCode := r.FormValue("ErrorCode")
+
+	var errorMessage string
+	if errorCode != "" {
+		errorMessage = helpers.GetTwilioMessageErrorDescription(errorCode)
+	}
+
+	if err := s.Messages.UpdateSMSStatus(messageSid, messageStatus, errorCode, errorMessage); err != nil {
+		log.Printf("Error updating SMS status: %s", err)
+		http.Error(w, "Failed to update message status.", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseMultipartForm(services.MaxMessageMediaSize)
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -252,14 +499,59 @@ func handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	messageResponse, err := services.SendTextMessage(form.To, form.From, form.Body)
+	var media []models.MessageMedia
+	if r.MultipartForm != nil {
+		for _, fileHeader := range r.MultipartForm.File["media"] {
+			item, err := services.UploadOutboundMessageMedia(fileHeader)
+			if err != nil {
+				fmt.Printf("%+v\n", err)
+				message := "Failed to upload attachment."
+				if errors.Is(err, services.ErrInvalidMessageMedia) {
+					message = "Attachments must be images under 5MB."
+				}
+				tmplCtx := types.DynamicPartialTemplate{
+					TemplateName: "error",
+					TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+					Data: map[string]any{
+						"Message": message,
+					},
+				}
+				w.WriteHeader(http.StatusBadRequest)
+				helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+				return
+			}
+			media = append(media, item)
+		}
+	}
+
+	var messageResponse openapi.ApiV2010Message
+	if len(media) > 0 {
+		var mediaURLs []string
+		for _, item := range media {
+			var mediaURL string
+			mediaURL, err = services.GetMessageMediaURL(item.FilePath)
+			if err != nil {
+				break
+			}
+			mediaURLs = append(mediaURLs, mediaURL)
+		}
+		if err == nil {
+			messageResponse, err = services.SendMediaMessage(form.To, form.From, form.Body, mediaURLs)
+		}
+	} else {
+		messageResponse, err = services.SendTextMessage(form.To, form.From, form.Body)
+	}
 	if err != nil {
 		fmt.Printf("%+v\n", err)
+		message := "Failed to send text message."
+		if errors.Is(err, services.ErrTextMessagingOptOut) {
+			message = "This number has opted out of text messages."
+		}
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
 			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
 			Data: map[string]any{
-				"Message": "Failed to send text message.",
+				"Message": message,
 			},
 		}
 		w.WriteHeader(http.StatusBadRequest)
@@ -279,9 +571,10 @@ func handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
 		DateCreated: time.Now().Unix(),
 		Status:      messageStatus,
 		IsRead:      true,
+		Media:       media,
 	}
 
-	err = database.SaveSMS(message)
+	err = s.Messages.SaveSMS(message)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -296,7 +589,7 @@ func handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leadMessages, err := database.GetMessagesByLeadID(form.LeadID)
+	leadMessages, err := s.Messages.GetMessagesByLeadID(form.LeadID)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -324,7 +617,7 @@ func handleOutboundSMS(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func handleCallRecordingCallback(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleCallRecordingCallback(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 		return
@@ -340,7 +633,7 @@ func handleCallRecordingCallback(w http.ResponseWriter, r *http.Request) {
 
 	recordingURL := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Recordings/%s.mp3?RequestedChannels=2", constants.TwilioAccountSID, recordingSID)
 
-	if err := database.SetRecordingURLToPhoneCall(callSID, recordingURL); err != nil {
+	if err := s.Messages.SetRecordingURLToPhoneCall(callSID, recordingURL); err != nil {
 		fmt.Printf("FAILED TO UPDATE PHONE CALL WITH RECORDING URL: %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -350,7 +643,7 @@ func handleCallRecordingCallback(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 	fmt.Println("Received request for AMD status callback")
 
 	// Parse request form data
@@ -383,7 +676,7 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch the existing call record from the database
 	fmt.Println("Fetching phone call record from database...")
-	phoneCall, err := database.GetPhoneCallBySID(callSid)
+	phoneCall, err := s.Messages.GetPhoneCallBySID(callSid)
 	if err != nil {
 		fmt.Printf("ERROR: Failed to get phone call record (CallSid: %s) - %+v\n", callSid, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -393,7 +686,7 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Get user details based on the caller's phone number
 	fmt.Println("Fetching user details from database...")
-	user, err := database.GetUserByPhoneNumber(phoneCall.CallFrom)
+	user, err := s.Users.GetUserByPhoneNumber(phoneCall.CallFrom)
 	if err != nil {
 		fmt.Printf("ERROR: Failed to get user from phone number (%s) - %+v\n", phoneCall.CallFrom, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -404,7 +697,7 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 	// Mark call as "missed"
 	phoneCall.Status = "missed"
 	fmt.Println("Updating phone call status to 'missed'...")
-	if err := database.UpdatePhoneCall(phoneCall); err != nil {
+	if err := s.Messages.UpdatePhoneCall(phoneCall); err != nil {
 		fmt.Printf("ERROR: Failed to update phone call record - %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -413,7 +706,7 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Check if this is the first time this lead has been contacted
 	fmt.Println("Checking if this is the first lead contact...")
-	isFirstCall, err := database.CheckIsFirstLeadContact(phoneCall.CallTo)
+	isFirstCall, err := s.Messages.CheckIsFirstLeadContact(phoneCall.CallTo)
 	if err != nil {
 		fmt.Printf("ERROR: Failed to check if first lead contact - %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -431,16 +724,19 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 	// Send follow-up text for a missed call
 	fmt.Println("Sending missed call follow-up text...")
 	err = services.MissedCallFollowUpText(phoneCall, user)
-	if err != nil {
+	if errors.Is(err, services.ErrTextMessagingOptOut) {
+		fmt.Println("Lead has opted out of text messages. Skipping missed call follow-up text.")
+	} else if err != nil {
 		fmt.Printf("ERROR: Failed to send missed call follow-up text - %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
+	} else {
+		fmt.Println("Successfully sent missed call follow-up text.")
 	}
-	fmt.Println("Successfully sent missed call follow-up text.")
 
 	// Fetch the lead ID from the phone number
 	fmt.Println("Fetching lead ID from phone number...")
-	leadId, err := database.GetLeadIDFromPhoneNumber(phoneCall.CallTo)
+	leadId, err := s.Leads.GetLeadIDFromPhoneNumber(phoneCall.CallTo)
 	if err != nil {
 		fmt.Printf("ERROR: Failed to get lead ID from phone number - %+v\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -453,7 +749,7 @@ func handleAmdStatusCallback(w http.ResponseWriter, r *http.Request) {
 	firstFollowUpActionID := constants.FirstFollowUpActionID
 	twentyFourHours := time.Now().Add(24 * time.Hour).Unix()
 
-	err = database.CreateLeadNextAction(types.LeadNextActionForm{
+	err = s.Leads.CreateLeadNextAction(types.LeadNextActionForm{
 		NextActionID:   &firstFollowUpActionID,
 		LeadID:         &leadId,
 		NextActionDate: &twentyFourHours,
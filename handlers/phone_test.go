@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+func postSMSStatusCallback(t *testing.T, srv *Server, messageSid, status, errorCode string) {
+	t.Helper()
+
+	form := url.Values{"MessageSid": {messageSid}, "MessageStatus": {status}, "ErrorCode": {errorCode}}
+	req := httptest.NewRequest(http.MethodPost, constants.TwilioSMSStatusCallbackWebhook, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rec := httptest.NewRecorder()
+	srv.PhoneServiceHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status callback returned %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func getTestMessageStatus(t *testing.T, stores database.Stores, leadId int, messageSid string) string {
+	t.Helper()
+
+	messages, err := stores.Messages.GetMessagesByLeadID(leadId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message for %s, got %d", messageSid, len(messages))
+	}
+
+	return messages[0].Status
+}
+
+func TestSMSStatusCallbackBeforeMessageIsSaved(t *testing.T) {
+	srv, stores := newTestServer(t)
+
+	phoneNumber := "3055550601"
+	leadId := createTestLead(t, stores, phoneNumber)
+
+	postSMSStatusCallback(t, srv, "SMearly", "sent", "")
+	postSMSStatusCallback(t, srv, "SMearly", constants.UndeliveredMessageStatus, "30003")
+	postSMSStatusCallback(t, srv, "SMearly", "sent", "")
+
+	err := stores.Messages.SaveSMS(models.Message{
+		ExternalID:  "SMearly",
+		Text:        "Hi Jane, here is your quote.",
+		TextFrom:    constants.CompanyPhoneNumber,
+		TextTo:      phoneNumber,
+		Status:      "queued",
+		DateCreated: time.Now().Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := getTestMessageStatus(t, stores, leadId, "SMearly"); status != constants.UndeliveredMessageStatus {
+		t.Errorf("got status %q, expected %q", status, constants.UndeliveredMessageStatus)
+	}
+
+	postSMSStatusCallback(t, srv, "SMearly", "delivered", "")
+	if status := getTestMessageStatus(t, stores, leadId, "SMearly"); status != constants.UndeliveredMessageStatus {
+		t.Errorf("final status was replaced with %q", status)
+	}
+}
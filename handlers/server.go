@@ -0,0 +1,11 @@
+package handlers
+
+import "github.com/davidalvarez305/yd_cocktails/database"
+
+type Server struct {
+	database.Stores
+}
+
+func NewServer(stores database.Stores) *Server {
+	return &Server{Stores: stores}
+}
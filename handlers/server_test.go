@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/services"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// Templates are loaded relative to the repository root.
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func newTestServer(t *testing.T) (*Server, database.Stores) {
+	t.Helper()
+
+	stores := database.NewMemoryStores()
+	services.SetStores(stores)
+
+	providers, err := services.NewProviders(services.FakeProviders, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	services.SetProviders(providers)
+
+	return NewServer(stores), stores
+}
+
+func createTestLead(t *testing.T, stores database.Stores, phoneNumber string) int {
+	t.Helper()
+
+	fullName, source, optIn := "Jane Doe", "google", true
+	leadId, err := stores.Leads.CreateLeadAndMarketing(types.QuoteForm{
+		FullName:           &fullName,
+		PhoneNumber:        &phoneNumber,
+		OptInTextMessaging: &optIn,
+		Source:             &source,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leadId
+}
+
+func createTestService(t *testing.T, stores database.Stores, name string, unitTypeId int, price float64) int {
+	t.Helper()
+
+	serviceTypeId := constants.BartendingServiceTypeID
+	if err := stores.Quotes.CreateService(types.ServiceForm{
+		ServiceTypeID:  &serviceTypeId,
+		Service:        &name,
+		SuggestedPrice: &price,
+		UnitTypeID:     &unitTypeId,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := stores.Quotes.GetServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, service := range catalog {
+		if service.Service == name {
+			return service.ServiceID
+		}
+	}
+
+	t.Fatalf("service %s was not created", name)
+	return 0
+}
+
+// A Wednesday with no holiday, so quotes for it carry no surcharge.
+func testEventDate(t *testing.T) int64 {
+	t.Helper()
+
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return time.Date(2030, time.January, 16, 18, 0, 0, 0, loc).Unix()
+}
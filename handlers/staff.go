@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func (s *Server) StaffHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := createCrmContext()
+	ctx["PagePath"] = constants.RootDomain + r.URL.Path
+	path := r.URL.Path
+
+	userRoleId, ok := r.Context().Value("user_role_id").(int)
+	if !ok {
+		http.Error(w, "Error retrieving user role.", http.StatusInternalServerError)
+		return
+	}
+
+	if !helpers.HasCapability(userRoleId, constants.ViewOwnEventsCapability) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+	ctx["Can"] = helpers.GetCapabilities(userRoleId)
+
+	if helpers.HasCapability(userRoleId, constants.ViewLeadsCapability) {
+		unreadMessages, err := s.Messages.GetUnreadMessagesCount()
+		if err != nil {
+			fmt.Printf("%+v\n", err)
+			http.Error(w, "Error getting unread messages from DB.", http.StatusInternalServerError)
+			return
+		}
+		ctx["UnreadMessages"] = unreadMessages
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		switch path {
+		case "/staff/":
+			s.GetStaffEvents(w, r, ctx)
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	case http.MethodPost:
+		parts := strings.Split(path, "/")
+
+		if strings.HasPrefix(path, "/staff/assignment/") && len(parts) >= 5 && helpers.IsNumeric(parts[3]) {
+			switch parts[4] {
+			case "accept":
+				s.PostStaffAssignmentResponse(w, r, constants.AcceptedEventStaffStatusID)
+				return
+			case "decline":
+				s.PostStaffAssignmentResponse(w, r, constants.DeclinedEventStaffStatusID)
+				return
+			}
+		}
+
+		http.Error(w, "Not Found", http.StatusNotFound)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) GetStaffEvents(w http.ResponseWriter, r *http.Request, ctx map[string]any) {
+	fileName := "staff_events.html"
+	files := []string{crmBaseFilePath, crmFooterFilePath, constants.STAFF_TEMPLATES_DIR + fileName}
+
+	nonce, ok := r.Context().Value("nonce").(string)
+	if !ok {
+		http.Error(w, "Error retrieving nonce.", http.StatusInternalServerError)
+		return
+	}
+
+	csrfToken, ok := r.Context().Value("csrf_token").(string)
+	if !ok {
+		http.Error(w, "Error retrieving CSRF token.", http.StatusInternalServerError)
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		http.Error(w, "Error retrieving user ID.", http.StatusInternalServerError)
+		return
+	}
+
+	assignments, err := s.Events.GetStaffAssignments(userId)
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		http.Error(w, "Error getting staff assignments.", http.StatusInternalServerError)
+		return
+	}
+
+	var upcoming, past []types.StaffAssignment
+	for _, assignment := range assignments {
+		cocktails, err := s.Events.GetEventCocktails(assignment.EventID)
+		if err != nil {
+			fmt.Printf("%+v\n", err)
+			http.Error(w, "Error getting event cocktails.", http.StatusInternalServerError)
+			return
+		}
+		assignment.Cocktails = cocktails
+
+		if assignment.IsUpcoming {
+			upcoming = append(upcoming, assignment)
+		} else {
+			past = append(past, assignment)
+		}
+	}
+
+	data := ctx
+	data["PageTitle"] = "My Events — " + constants.CompanyName
+	data["Nonce"] = nonce
+	data["CSRFToken"] = csrfToken
+	data["UpcomingAssignments"] = upcoming
+	data["PastAssignments"] = past
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	helpers.ServeContent(w, files, data)
+}
+
+func (s *Server) PostStaffAssignmentResponse(w http.ResponseWriter, r *http.Request, eventStaffStatusId int) {
+	parts := strings.Split(r.URL.Path, "/")
+
+	eventStaffId, err := strconv.Atoi(parts[3])
+	if err != nil {
+		fmt.Printf("Error getting event staff id from path: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to get assignment id from path.",
+			},
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Could not retrieve user.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	err = s.Events.UpdateEventStaffStatus(eventStaffId, userId, eventStaffStatusId)
+	if err != nil {
+		fmt.Printf("Error updating event staff status: %+v\n", err)
+		tmplCtx := types.DynamicPartialTemplate{
+			TemplateName: "error",
+			TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "error_banner.html",
+			Data: map[string]any{
+				"Message": "Failed to update assignment.",
+			},
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+		return
+	}
+
+	message := "You have accepted this event."
+	if eventStaffStatusId == constants.DeclinedEventStaffStatusID {
+		message = "You have declined this event."
+	}
+
+	tmplCtx := types.DynamicPartialTemplate{
+		TemplateName: "modal",
+		TemplatePath: constants.PARTIAL_TEMPLATES_DIR + "modal.html",
+		Data: map[string]any{
+			"AlertHeader":  "Success!",
+			"AlertMessage": message,
+		},
+	}
+
+	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
+}
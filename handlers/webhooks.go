@@ -1,16 +1,17 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/conversions"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/services"
 	"github.com/davidalvarez305/yd_cocktails/types"
@@ -20,12 +21,12 @@ import (
 	"github.com/stripe/stripe-go/v81/webhook"
 )
 
-func WebhookHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) WebhookHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		switch r.URL.Path {
 		case "/webhooks/stripe/invoice":
-			handleStripeInvoicePayment(w, r)
+			s.handleStripeInvoicePayment(w, r)
 			return
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
@@ -35,7 +36,29 @@ func WebhookHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleStripeInvoicePayment(w http.ResponseWriter, r *http.Request) {
+// FakeInvoicePaymentHandler is only routed with PROVIDERS=fake. Fake invoices link here so paying one runs the real webhook flow.
+func (s *Server) FakeInvoicePaymentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stripeInvoiceId := strings.TrimPrefix(r.URL.Path, "/webhooks/stripe/fake/pay/")
+
+	payload, signature, err := services.SimulateInvoicePayment(stripeInvoiceId)
+	if err != nil {
+		log.Printf("Failed to simulate invoice payment: %v", err)
+		http.Error(w, "Failed to simulate invoice payment.", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(payload))
+	r.Header.Set("Stripe-Signature", signature)
+
+	s.handleStripeInvoicePayment(w, r)
+}
+
+func (s *Server) handleStripeInvoicePayment(w http.ResponseWriter, r *http.Request) {
 	const MaxBodyBytes = int64(65536)
 	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
 	body, err := io.ReadAll(r.Body)
@@ -66,7 +89,7 @@ func handleStripeInvoicePayment(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		inv, err := database.GetInvoiceByStripeInvoiceID(invoice.ID)
+		inv, err := s.Invoices.GetInvoiceByStripeInvoiceID(invoice.ID)
 		if err != nil {
 			log.Printf("Failed to get invoice by stripe invoice id: %v", err)
 			http.Error(w, "Failed to find invoice by stripe invoice id.", http.StatusInternalServerError)
@@ -76,38 +99,50 @@ func handleStripeInvoicePayment(w http.ResponseWriter, r *http.Request) {
 		// Update invoice status to paid
 		datePaid := time.Now().Unix()
 		dateEventCreated := time.Now().Unix()
-		err = database.SetInvoiceStatusToPaid(invoice.ID, datePaid)
+		err = s.Invoices.SetInvoiceStatusToPaid(invoice.ID, datePaid)
 		if err != nil {
 			log.Printf("Failed to update invoice status to paid: %v", err)
 			http.Error(w, "Error updating invoice status to paid", http.StatusInternalServerError)
 			return
 		}
 
-		quote, err := database.GetQuoteDetailsByStripeInvoiceID(inv.StripeInvoiceID)
+		quote, err := s.Invoices.GetQuoteDetailsByStripeInvoiceID(inv.StripeInvoiceID)
 		if err != nil {
 			log.Printf("Failed to get quote details by stripe invoice id: %v", err)
 			http.Error(w, "Error creating event", http.StatusInternalServerError)
 			return
 		}
 
+		services.PublishCRMEvent(types.CRMEvent{
+			Type:    constants.PaymentCRMEvent,
+			LeadID:  quote.LeadID,
+			Message: fmt.Sprintf("Invoice paid for quote #%d", quote.QuoteID),
+		})
+
 		// If invoice type = deposit || full, schedule event + report to google
 		if inv.InvoiceTypeID == constants.DepositInvoiceTypeID || inv.InvoiceTypeID == constants.FullInvoiceTypeID {
 			eventForm := types.EventForm{
 				LeadID:      &quote.LeadID,
+				QuoteID:     &quote.QuoteID,
 				DateCreated: &dateEventCreated,
 				DatePaid:    &datePaid,
 				Amount:      &quote.Amount,
 				Guests:      &quote.Guests,
 			}
-			err = database.CreateEvent(eventForm)
+			err = s.Events.CreateEvent(eventForm)
 			if err != nil {
 				log.Printf("Failed to create event after successful payment: %v", err)
 				http.Error(w, "Error creating event", http.StatusInternalServerError)
 				return
 			}
 
+			err = s.Sequences.StopSMSSequenceEnrollments(quote.LeadID, constants.BookedSMSSequenceStopReason)
+			if err != nil {
+				log.Printf("Failed to stop SMS sequences after booking: %v", err)
+			}
+
 			if constants.Production {
-				lead, err := database.GetConversionReporting(int(helpers.SafeInt(eventForm.LeadID)))
+				lead, err := s.Leads.GetConversionReporting(int(helpers.SafeInt(eventForm.LeadID)))
 				if err != nil {
 					log.Printf("Error reporting getting conversion details: %v", err)
 					http.Error(w, "Error reporting getting conversion details.", http.StatusInternalServerError)
@@ -211,14 +246,14 @@ func handleStripeInvoicePayment(w http.ResponseWriter, r *http.Request) {
 
 		// Void all invoices if full or remaining has been paid
 		if inv.InvoiceTypeID == constants.FullInvoiceTypeID || inv.InvoiceTypeID == constants.RemainingInvoiceTypeID {
-			err = database.SetOpenInvoicesToVoid(quote.QuoteID)
+			err = s.Invoices.SetOpenInvoicesToVoid(quote.QuoteID)
 			if err != nil {
 				fmt.Printf("ERROR SETTING INVOICES TO VOID: %+v\n", err)
 			}
 		}
 
 		if inv.InvoiceTypeID == constants.DepositInvoiceTypeID {
-			err = database.VoidFullInvoice(quote.QuoteID)
+			err = s.Invoices.VoidFullInvoice(quote.QuoteID)
 			if err != nil {
 				fmt.Printf("ERROR SETTING FULL INVOICE TO VOID: %+v\n", err)
 			}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/services"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func createTestInvoicedQuote(t *testing.T, stores database.Stores) (int, int) {
+	t.Helper()
+
+	leadId := createTestLead(t, stores, "3055550201")
+	serviceId := createTestService(t, stores, "Open Bar", constants.FlatUnitTypeID, 800)
+
+	guests, hours, eventDate := 50, 4.0, testEventDate(t)
+	units, price := 1.0, 800.0
+	quoteId, _, err := stores.Quotes.CreateQuickQuote(types.QuickQuoteForm{
+		LeadID:    &leadId,
+		Guests:    &guests,
+		Hours:     &hours,
+		EventDate: &eventDate,
+	}, []types.QuoteServiceForm{{ServiceID: &serviceId, Units: &units, PricePerUnit: &price}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := stores.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(leadId), fmt.Sprint(quoteId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := services.CreateInvoiceWorkflow(quote); err != nil {
+		t.Fatal(err)
+	}
+
+	return leadId, quoteId
+}
+
+func openDepositStripeInvoiceID(t *testing.T, stores database.Stores, quoteId int) string {
+	t.Helper()
+
+	invoices, err := stores.Invoices.GetLeadQuoteInvoices(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invoice := range invoices {
+		if invoice.InvoiceTypeID == constants.DepositInvoiceTypeID {
+			return invoice.StripeInvoiceID
+		}
+	}
+
+	t.Fatal("expected an open deposit invoice")
+	return ""
+}
+
+func postStripeWebhook(srv *Server, payload []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe/invoice", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signature)
+
+	rec := httptest.NewRecorder()
+	srv.WebhookHandler(rec, req)
+	return rec
+}
+
+func TestHandleStripeInvoicePaymentBooksDeposit(t *testing.T) {
+	srv, stores := newTestServer(t)
+	leadId, quoteId := createTestInvoicedQuote(t, stores)
+
+	depositStripeInvoiceId := openDepositStripeInvoiceID(t, stores, quoteId)
+
+	payload, signature, err := services.SimulateInvoicePayment(depositStripeInvoiceId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := postStripeWebhook(srv, payload, signature)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	isDepositPaid, err := stores.Invoices.IsDepositPaid(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isDepositPaid {
+		t.Error("expected the deposit to be marked paid")
+	}
+
+	events, err := stores.Events.GetEventList(leadId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Amount != 800 {
+		t.Fatalf("expected one $800 event to be booked, got %+v", events)
+	}
+}
+
+func TestHandleStripeInvoicePaymentRejectsBadSignature(t *testing.T) {
+	srv, stores := newTestServer(t)
+	leadId, quoteId := createTestInvoicedQuote(t, stores)
+
+	depositStripeInvoiceId := openDepositStripeInvoiceID(t, stores, quoteId)
+
+	payload, _, err := services.SimulateInvoicePayment(depositStripeInvoiceId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := postStripeWebhook(srv, payload, "t=1,v1=forged")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	isDepositPaid, err := stores.Invoices.IsDepositPaid(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isDepositPaid {
+		t.Error("a forged webhook must not mark the deposit paid")
+	}
+
+	events, err := stores.Events.GetEventList(leadId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("a forged webhook must not book an event, got %+v", events)
+	}
+}
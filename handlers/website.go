@@ -7,7 +7,6 @@ import (
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/conversions"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/services"
 	"github.com/davidalvarez305/yd_cocktails/sessions"
@@ -53,7 +52,7 @@ func createWebsiteContext() types.WebsiteContext {
 	}
 }
 
-func WebsiteHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) WebsiteHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := createWebsiteContext()
 	ctx.PagePath = constants.RootDomain + r.URL.Path
 	ctx.IsMobile = helpers.IsMobileRequest(r)
@@ -70,34 +69,34 @@ func WebsiteHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		switch r.URL.Path {
 		case "/contact":
-			GetContactForm(w, r, ctx)
+			s.GetContactForm(w, r, ctx)
 		case "/login":
-			GetLogin(w, r, ctx)
+			s.GetLogin(w, r, ctx)
 		case "/privacy-policy":
-			GetPrivacyPolicy(w, r, ctx)
+			s.GetPrivacyPolicy(w, r, ctx)
 		case "/terms-and-conditions":
-			GetTermsAndConditions(w, r, ctx)
+			s.GetTermsAndConditions(w, r, ctx)
 		case "/robots.txt":
-			GetRobots(w, r, ctx)
+			s.GetRobots(w, r, ctx)
 		case "/":
-			GetHome(w, r, ctx)
+			s.GetHome(w, r, ctx)
 		case "/planning":
-			GetPlanningLP(w, r, ctx)
+			s.GetPlanningLP(w, r, ctx)
 		case "/staffing":
-			GetStaffingLP(w, r, ctx)
+			s.GetStaffingLP(w, r, ctx)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
 	case http.MethodPost:
 		switch r.URL.Path {
 		case "/quote":
-			PostQuote(w, r)
+			s.PostQuote(w, r)
 		case "/contact":
-			PostContactForm(w, r)
+			s.PostContactForm(w, r)
 		case "/login":
-			PostLogin(w, r)
+			s.PostLogin(w, r)
 		case "/logout":
-			PostLogout(w, r)
+			s.PostLogout(w, r)
 		default:
 			http.Error(w, "Not Found", http.StatusNotFound)
 		}
@@ -106,7 +105,7 @@ func WebsiteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func GetHome(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetHome(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	heroImagePath := "hero_image_desktop.html"
 	headerPath := "header_desktop.html"
 	if ctx.IsMobile {
@@ -150,7 +149,7 @@ func GetHome(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	helpers.ServeContent(w, files, data)
 }
 
-func GetPlanningLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetPlanningLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	heroImagePath := "planning_hero_image_desktop.html"
 	headerPath := "header_desktop.html"
 	if ctx.IsMobile {
@@ -195,7 +194,7 @@ func GetPlanningLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteCont
 	helpers.ServeContent(w, files, data)
 }
 
-func GetStaffingLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetStaffingLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	heroImagePath := "staffing_hero_image_desktop.html"
 	headerPath := "header_desktop.html"
 	if ctx.IsMobile {
@@ -240,7 +239,7 @@ func GetStaffingLP(w http.ResponseWriter, r *http.Request, ctx types.WebsiteCont
 	helpers.ServeContent(w, files, data)
 }
 
-func GetRobots(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetRobots(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	robotsTxtContent := `
 	# robots.txt for https://ydcocktails.com/
 
@@ -257,7 +256,7 @@ func GetRobots(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext)
 	}
 }
 
-func GetPrivacyPolicy(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetPrivacyPolicy(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	fileName := "privacy.html"
 	quoteForm := constants.WEBSITE_TEMPLATES_DIR + "quote_form.html"
 
@@ -282,7 +281,7 @@ func GetPrivacyPolicy(w http.ResponseWriter, r *http.Request, ctx types.WebsiteC
 	helpers.ServeContent(w, files, data)
 }
 
-func GetTermsAndConditions(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetTermsAndConditions(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	fileName := "terms.html"
 	quoteForm := constants.WEBSITE_TEMPLATES_DIR + "quote_form.html"
 
@@ -307,7 +306,7 @@ func GetTermsAndConditions(w http.ResponseWriter, r *http.Request, ctx types.Web
 	helpers.ServeContent(w, files, data)
 }
 
-func PostQuote(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostQuote(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
 		fmt.Printf("%+v\n", err)
@@ -342,7 +341,7 @@ func PostQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	phoneNumberExists, err := database.IsPhoneNumberInDB(cleanedPhoneNumber)
+	phoneNumberExists, err := s.Leads.IsPhoneNumberInDB(cleanedPhoneNumber)
 	if err != nil {
 		tmplCtx := types.DynamicPartialTemplate{
 			TemplateName: "error",
@@ -442,7 +441,7 @@ func PostQuote(w http.ResponseWriter, r *http.Request) {
 		form.CSRFSecret = &session.CSRFSecret
 	}
 
-	leadID, err := database.CreateLeadAndMarketing(form)
+	leadID, err := s.Leads.CreateLeadAndMarketing(form)
 	if err != nil {
 		fmt.Printf("Error creating lead: %+v\n", err)
 		tmplCtx := types.DynamicPartialTemplate{
@@ -507,53 +506,8 @@ func PostQuote(w http.ResponseWriter, r *http.Request) {
 		go conversions.SendFacebookConversion(metaPayload)
 
 		go func() {
-			subject := "YD Cocktails: New Lead"
-			recipients := []string{constants.CompanyEmail}
-			templateFile := constants.PARTIAL_TEMPLATES_DIR + "new_lead_notification_email.html"
-
-			var notificationTemplateData = map[string]any{
-				"Name":           helpers.SafeString(form.FullName),
-				"PhoneNumber":    helpers.SafeString(form.PhoneNumber),
-				"DateCreated":    utils.FormatTimestampWithOptions(createdAt, &types.TimestampFormatOptions{TimeZone: constants.TimeZone}),
-				"ButtonClicked":  helpers.SafeString(form.ButtonClicked),
-				"Message":        helpers.SafeString(form.Message),
-				"LeadDetailsURL": fmt.Sprintf("%s/crm/lead/%d", constants.RootDomain, leadID),
-				"Location":       "",
-			}
-
-			if helpers.SafeString(form.Longitude) != "0.0" && len(helpers.SafeString(form.Longitude)) > 0 || helpers.SafeString(form.Latitude) != "0.0" && len(helpers.SafeString(form.Latitude)) > 0 {
-				notificationTemplateData["Location"] = fmt.Sprintf("https://www.google.com/maps?q=%s,%s", helpers.SafeString(form.Latitude), helpers.SafeString(form.Longitude))
-			}
-
-			template, err := helpers.BuildStringFromTemplate(templateFile, "email", notificationTemplateData)
-
-			if err != nil {
-				fmt.Printf("ERROR BUILDING LEAD NOTIFICATION TEMPLATE: %+v\n", err)
-				return
-			}
-
-			body := fmt.Sprintf("Content-Type: text/html; charset=UTF-8\r\n%s", template)
-			err = services.SendGmail(recipients, subject, constants.CompanyEmail, body)
-			if err != nil {
-				fmt.Printf("ERROR SENDING LEAD NOTIFICATION EMAIL: %+v\n", err)
-				return
-			}
-
-			for _, phoneNumber := range constants.NotificationSubscribers {
-
-				var textMessageTemplateNotification = fmt.Sprintf(
-					`NEW LEAD:
-
-				Phone: %s,
-				Full Name: %s,
-				Message: %s
-			`, helpers.SafeString(form.PhoneNumber), helpers.SafeString(form.FullName), helpers.SafeString(form.Message))
-
-				_, err := services.SendTextMessage(phoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
-
-				if err != nil {
-					fmt.Printf("ERROR SENDING FB LEAD AD NOTIFICATION MSG: %+v\n", err)
-				}
+			if err := services.NotifyNewLead(leadID, form); err != nil {
+				fmt.Printf("ERROR SENDING LEAD NOTIFICATION: %+v\n", err)
 			}
 		}()
 	}
@@ -570,7 +524,7 @@ func PostQuote(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetContactForm(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetContactForm(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	fileName := "contact_form.html"
 	quoteForm := constants.WEBSITE_TEMPLATES_DIR + "quote_form.html"
 
@@ -607,7 +561,7 @@ func GetContactForm(w http.ResponseWriter, r *http.Request, ctx types.WebsiteCon
 	}
 }
 
-func PostContactForm(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostContactForm(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 
 	if err != nil {
@@ -692,7 +646,7 @@ func PostContactForm(w http.ResponseWriter, r *http.Request) {
 	helpers.ServeDynamicPartialTemplate(w, tmplCtx)
 }
 
-func GetLogin(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
+func (s *Server) GetLogin(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext) {
 	fileName := "login.html"
 	quoteForm := constants.WEBSITE_TEMPLATES_DIR + "quote_form.html"
 
@@ -709,23 +663,28 @@ func GetLogin(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext)
 		return
 	}
 
-	session, err := database.GetSession(csrfSecret)
+	session, err := s.Sessions.GetSession(csrfSecret)
 	if err != nil {
 		http.Error(w, "Error trying to get session in login page.", http.StatusInternalServerError)
 		return
 	}
 
 	if session.UserID > 0 {
-		user, err := database.GetUserById(session.UserID)
+		user, err := s.Users.GetUserById(session.UserID)
 		if err != nil {
 			http.Error(w, "Error trying to get existing user from DB.", http.StatusInternalServerError)
 			return
 		}
 
-		if user.UserRoleID == constants.UserAdminRoleID {
+		if helpers.HasCapability(user.UserRoleID, constants.ViewLeadsCapability) {
 			http.Redirect(w, r, "/crm/dashboard", http.StatusSeeOther)
 			return
 		}
+
+		if helpers.CanAccessCRM(user.UserRoleID) {
+			http.Redirect(w, r, "/staff/", http.StatusSeeOther)
+			return
+		}
 	}
 
 	nonce, ok := r.Context().Value("nonce").(string)
@@ -754,7 +713,7 @@ func GetLogin(w http.ResponseWriter, r *http.Request, ctx types.WebsiteContext)
 	}
 }
 
-func PostLogin(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostLogin(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "Failed to parse form data.", http.StatusBadRequest)
 		return
@@ -769,7 +728,7 @@ func PostLogin(w http.ResponseWriter, r *http.Request) {
 		Data:         map[string]any{},
 	}
 
-	user, err := database.GetUserByUsername(username)
+	user, err := s.Users.GetUserByUsername(username)
 	if err != nil {
 		tmplCtx.Data["Message"] = "Invalid username."
 		helpers.ServeDynamicPartialTemplate(w, tmplCtx)
@@ -803,7 +762,7 @@ func PostLogin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func PostLogout(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PostLogout(w http.ResponseWriter, r *http.Request) {
 
 	sessions.SetCookie(w, time.Now().Add(-1*time.Hour), "")
 
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+var adSpendDateFormats = []string{"2006-01-02", "01/02/2006", "1/2/2006"}
+
+// ParseAdSpendRows reads spend rows from a CSV upload or sheet. The first row must be a header with at
+// least date, campaign_id and spend columns; source, campaign_name, ad_set_id and ad_set_name are optional.
+// defaultSource is used for rows without a source.
+func ParseAdSpendRows(rows [][]string, defaultSource string) ([]models.AdSpend, error) {
+	var spend []models.AdSpend
+
+	if len(rows) == 0 {
+		return spend, fmt.Errorf("no rows to import")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+
+	for _, required := range []string{"date", "campaign_id", "spend"} {
+		if _, ok := columns[required]; !ok {
+			return spend, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return spend, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	value := func(row []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	for n, row := range rows[1:] {
+		line := n + 2
+
+		if strings.Join(row, "") == "" {
+			continue
+		}
+
+		date, err := parseAdSpendDate(value(row, "date"), loc)
+		if err != nil {
+			return spend, fmt.Errorf("row %d: %w", line, err)
+		}
+
+		campaignId, err := strconv.ParseInt(value(row, "campaign_id"), 10, 64)
+		if err != nil {
+			return spend, fmt.Errorf("row %d: invalid campaign_id: %w", line, err)
+		}
+
+		var adSetId int64
+		if v := value(row, "ad_set_id"); v != "" {
+			adSetId, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return spend, fmt.Errorf("row %d: invalid ad_set_id: %w", line, err)
+			}
+		}
+
+		amount, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "").Replace(value(row, "spend")), 64)
+		if err != nil {
+			return spend, fmt.Errorf("row %d: invalid spend: %w", line, err)
+		}
+
+		source := value(row, "source")
+		if source == "" {
+			source = defaultSource
+		}
+
+		spend = append(spend, models.AdSpend{
+			Date:         date.Unix(),
+			Source:       source,
+			CampaignID:   campaignId,
+			CampaignName: value(row, "campaign_name"),
+			AdSetID:      adSetId,
+			AdSetName:    value(row, "ad_set_name"),
+			Spend:        amount,
+		})
+	}
+
+	return spend, nil
+}
+
+func parseAdSpendDate(value string, loc *time.Location) (time.Time, error) {
+	for _, format := range adSpendDateFormats {
+		if date, err := time.ParseInLocation(format, value, loc); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+const businessHoursInputFormat = "15:04"
+
+// IsWithinBusinessHours treats a schedule with no open days as always open so a fresh call flow keeps ringing.
+func IsWithinBusinessHours(hours []types.CallFlowBusinessHoursList, t time.Time) (bool, error) {
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	var hasSchedule bool
+	for _, day := range hours {
+		if day.OpenTime != "" {
+			hasSchedule = true
+		}
+	}
+	if !hasSchedule {
+		return true, nil
+	}
+
+	local := t.In(loc)
+	now := local.Format(businessHoursInputFormat)
+
+	for _, day := range hours {
+		if day.DayOfWeek == int(local.Weekday()) && day.OpenTime != "" {
+			return now >= day.OpenTime && now < day.CloseTime, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsValidBusinessHours checks a time input pair where both blank means closed for the day.
+func IsValidBusinessHours(openTime, closeTime string) bool {
+	if openTime == "" && closeTime == "" {
+		return true
+	}
+
+	open, err := time.Parse(businessHoursInputFormat, openTime)
+	if err != nil {
+		return false
+	}
+
+	close, err := time.Parse(businessHoursInputFormat, closeTime)
+	if err != nil {
+		return false
+	}
+
+	return open.Before(close)
+}
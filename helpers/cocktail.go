@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func IsValidIngredientCategory(category string) bool {
+	for _, c := range constants.IngredientCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupCocktailRecipe splits a recipe into liquor, mixer and garnish sections. Ingredients saved before categories
+// existed land in a trailing "Other" section.
+func GroupCocktailRecipe(ingredients []types.CocktailIngredientList) []types.CocktailRecipeSection {
+	var sections []types.CocktailRecipeSection
+
+	for _, category := range constants.IngredientCategories {
+		section := types.CocktailRecipeSection{Category: category}
+		for _, ingredient := range ingredients {
+			if ingredient.Category == category {
+				section.Ingredients = append(section.Ingredients, ingredient)
+			}
+		}
+		if len(section.Ingredients) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	other := types.CocktailRecipeSection{Category: "Other"}
+	for _, ingredient := range ingredients {
+		if !IsValidIngredientCategory(ingredient.Category) {
+			other.Ingredients = append(other.Ingredients, ingredient)
+		}
+	}
+	if len(other.Ingredients) > 0 {
+		sections = append(sections, other)
+	}
+
+	return sections
+}
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func NormalizeDiscountCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func IsValidDiscountType(discountType string) bool {
+	for _, t := range constants.DiscountTypes {
+		if t == discountType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDiscountCode checks whether a code can still be redeemed, given how many other quotes already use it.
+func ValidateDiscountCode(code models.DiscountCode, redemptions int, now time.Time) error {
+	if !code.IsActive {
+		return fmt.Errorf("discount code %s is no longer active", code.Code)
+	}
+
+	if code.ExpiresAt > 0 && !now.Before(time.Unix(code.ExpiresAt, 0)) {
+		return fmt.Errorf("discount code %s expired", code.Code)
+	}
+
+	if code.MaxRedemptions > 0 && redemptions >= code.MaxRedemptions {
+		return fmt.Errorf("discount code %s has reached its usage limit", code.Code)
+	}
+
+	return nil
+}
+
+// CalculateDiscount applies the code to the quote lines whose service type it is eligible for. A code with no
+// service types covers every service. Pricing adjustments and lines the customer hasn't chosen yet are never
+// discounted, and a fixed discount never exceeds what it applies to.
+func CalculateDiscount(code models.DiscountCode, eligibleServiceTypeIds []int, quoteServices []types.QuoteServiceList, catalog []models.Service) (float64, error) {
+	serviceTypes := make(map[int]int, len(catalog))
+	for _, service := range catalog {
+		serviceTypes[service.ServiceID] = service.ServiceTypeID
+	}
+
+	eligible := make(map[int]bool, len(eligibleServiceTypeIds))
+	for _, id := range eligibleServiceTypeIds {
+		eligible[id] = true
+	}
+
+	var subtotal float64
+	var matched bool
+	for _, qs := range quoteServices {
+		serviceTypeId := serviceTypes[qs.ServiceID]
+		if serviceTypeId == constants.PricingAdjustmentServiceTypeID || IsQuoteOption(qs) {
+			continue
+		}
+		if len(eligible) > 0 && !eligible[serviceTypeId] {
+			continue
+		}
+		matched = true
+		subtotal += qs.Total
+	}
+
+	if !matched {
+		return 0, fmt.Errorf("discount code %s does not apply to any service on this quote", code.Code)
+	}
+
+	switch code.DiscountType {
+	case constants.PercentageDiscountType:
+		return roundCents(subtotal * code.Amount / 100), nil
+	case constants.FixedDiscountType:
+		if code.Amount > subtotal {
+			return roundCents(subtotal), nil
+		}
+		return roundCents(code.Amount), nil
+	}
+
+	return 0, fmt.Errorf("discount code %s has an unknown discount type %s", code.Code, code.DiscountType)
+}
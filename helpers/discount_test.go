@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func TestCalculateDiscount(t *testing.T) {
+	quoteServices := []types.QuoteServiceList{
+		{ServiceID: 1, Service: "Open Bar", Total: 1000},
+		{ServiceID: 5, Service: "Bar Rental", Total: 300},
+		{ServiceID: 2, Service: "Bartender", Total: 600, PackageTier: "Premium"},
+		{ServiceID: 6, Service: "Ice Sculpture", Total: 60, IsOptional: true},
+		{ServiceID: 90, Service: constants.WeekendSurchargeService, Total: 130},
+	}
+
+	tests := []struct {
+		name         string
+		discountType string
+		amount       float64
+		serviceTypes []int
+		want         float64
+		wantErr      bool
+	}{
+		{"percentage of every service", constants.PercentageDiscountType, 10, nil, 130, false},
+		{"percentage rounds to cents", constants.PercentageDiscountType, 12.345, nil, 160.49, false},
+		{"percentage of eligible service types", constants.PercentageDiscountType, 10, []int{constants.AlcoholServiceTypeID}, 100, false},
+		{"fixed", constants.FixedDiscountType, 50, []int{constants.BarRentalServiceTypeID}, 50, false},
+		{"fixed never exceeds what it applies to", constants.FixedDiscountType, 500, []int{constants.BarRentalServiceTypeID}, 300, false},
+		{"package tiers are not discounted until chosen", constants.PercentageDiscountType, 10, []int{constants.BartendingServiceTypeID}, 0, true},
+		{"optional add-ons are not discounted until chosen", constants.FixedDiscountType, 20, []int{constants.ExtraServiceTypeID}, 0, true},
+		{"pricing adjustments are never discounted", constants.PercentageDiscountType, 10, []int{constants.PricingAdjustmentServiceTypeID}, 0, true},
+		{"unknown discount type", "bogo", 10, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := models.DiscountCode{Code: "SPRING", DiscountType: tt.discountType, Amount: tt.amount}
+
+			got, err := CalculateDiscount(code, tt.serviceTypes, quoteServices, testPricingCatalog)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got $%.2f", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got $%.2f, expected $%.2f", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+const payPeriodDateFormat = "2006-01-02"
+
+// GetPayPeriod parses inclusive YYYY-MM-DD dates and returns [start, end) in the business time zone.
+// Missing dates default to the current Monday through Sunday week.
+func GetPayPeriod(startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	now = now.In(loc)
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	start := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+
+	return parseDateRange(startDate, endDate, start, start.AddDate(0, 0, 7), loc)
+}
+
+func parseDateRange(startDate, endDate string, start, end time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	var err error
+
+	if startDate != "" {
+		start, err = time.ParseInLocation(payPeriodDateFormat, startDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+		}
+	}
+
+	if endDate != "" {
+		end, err = time.ParseInLocation(payPeriodDateFormat, endDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date must be on or after start date")
+	}
+
+	return start, end, nil
+}
+
+func FormatPayPeriodDate(t time.Time) string {
+	return t.Format(payPeriodDateFormat)
+}
+
+func SummarizePayroll(lines []types.PayrollLine) []types.PayrollSummary {
+	var summaries []types.PayrollSummary
+	index := make(map[int]int)
+
+	for _, line := range lines {
+		i, ok := index[line.UserID]
+		if !ok {
+			summaries = append(summaries, types.PayrollSummary{
+				UserID:      line.UserID,
+				StaffMember: line.StaffMember,
+			})
+			i = len(summaries) - 1
+			index[line.UserID] = i
+		}
+
+		summaries[i].Events++
+		summaries[i].Hours += line.Hours
+		summaries[i].Wages += line.Wages
+		summaries[i].Tips += line.TipShare
+		summaries[i].Total += line.Total
+		if !line.IsPaid {
+			summaries[i].Unpaid += line.Total
+		}
+	}
+
+	return summaries
+}
@@ -0,0 +1,50 @@
+package helpers
+
+import "github.com/davidalvarez305/yd_cocktails/constants"
+
+var roleCapabilities = map[int][]string{
+	constants.UserAdminRoleID: {
+		constants.ViewLeadsCapability,
+		constants.EditQuotesCapability,
+		constants.SendInvoicesCapability,
+		constants.ManageUsersCapability,
+		constants.ManageEventsCapability,
+		constants.ViewOwnEventsCapability,
+		constants.ManagePayrollCapability,
+		constants.ViewReportsCapability,
+		constants.ManageJobsCapability,
+	},
+	constants.UserBartenderRoleID: {
+		constants.ViewOwnEventsCapability,
+	},
+	constants.UserBarbackRoleID: {
+		constants.ViewOwnEventsCapability,
+	},
+	constants.UserSalesRoleID: {
+		constants.ViewLeadsCapability,
+		constants.EditQuotesCapability,
+		constants.SendInvoicesCapability,
+	},
+}
+
+func HasCapability(userRoleId int, capability string) bool {
+	for _, c := range roleCapabilities[userRoleId] {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func CanAccessCRM(userRoleId int) bool {
+	return len(roleCapabilities[userRoleId]) > 0
+}
+
+// GetCapabilities is passed to templates as .Can so they can hide actions the user cannot take.
+func GetCapabilities(userRoleId int) map[string]bool {
+	can := make(map[string]bool)
+	for _, c := range roleCapabilities[userRoleId] {
+		can[c] = true
+	}
+	return can
+}
@@ -0,0 +1,234 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// PriceQuote prices every requested service from its unit type and then adds the surcharge, travel and
+// minimum order lines, so the same inputs always produce the same quote regardless of what the browser sent.
+func PriceQuote(req types.PricingRequest, catalog []models.Service) (types.PricedQuote, error) {
+	var quote types.PricedQuote
+
+	if req.Guests <= 0 {
+		return quote, fmt.Errorf("guest count must be greater than zero")
+	}
+
+	if req.Hours <= 0 {
+		return quote, fmt.Errorf("hours must be greater than zero")
+	}
+
+	services := make(map[int]models.Service, len(catalog))
+	for _, service := range catalog {
+		services[service.ServiceID] = service
+	}
+
+	quote.BilledHours = BilledHours(req.Hours)
+
+	for _, requested := range req.Services {
+		service, ok := services[requested.ServiceID]
+		if !ok {
+			return quote, fmt.Errorf("service %d not found", requested.ServiceID)
+		}
+
+		line, err := priceService(service, requested.Units, req.Guests, quote.BilledHours)
+		if err != nil {
+			return quote, err
+		}
+
+		quote.Services = append(quote.Services, line)
+		quote.Subtotal += line.Total
+	}
+	quote.Subtotal = roundCents(quote.Subtotal)
+
+	adjustments, err := PriceQuoteAdjustments(req.EventDate, req.TravelMiles, quote.Subtotal, catalog)
+	if err != nil {
+		return quote, err
+	}
+	quote.Adjustments = adjustments
+
+	quote.Total = quote.Subtotal
+	for _, adjustment := range adjustments {
+		quote.Total += adjustment.Total
+	}
+	quote.Total = roundCents(quote.Total)
+
+	return quote, nil
+}
+
+// BilledHours never bills less than the minimum event length.
+func BilledHours(hours float64) float64 {
+	return math.Max(hours, constants.MinimumBillableHours)
+}
+
+// PriceQuoteAdjustments returns the lines a quote needs on top of its services: a holiday or weekend surcharge,
+// a travel fee past the included miles and a top up to the minimum order.
+func PriceQuoteAdjustments(eventDate int64, travelMiles, subtotal float64, catalog []models.Service) ([]types.PricedQuoteService, error) {
+	var adjustments []types.PricedQuoteService
+
+	if subtotal <= 0 {
+		return adjustments, nil
+	}
+
+	total := subtotal
+
+	if eventDate > 0 {
+		loc, err := time.LoadLocation(constants.TimeZone)
+		if err != nil {
+			return adjustments, fmt.Errorf("error loading time zone: %w", err)
+		}
+		date := time.Unix(eventDate, 0).In(loc)
+
+		name, rate, reason := "", 0.0, ""
+		if holiday := HolidayName(date); holiday != "" {
+			name, rate, reason = constants.HolidaySurchargeService, constants.HolidaySurchargeRate, holiday
+		} else if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			name, rate, reason = constants.WeekendSurchargeService, constants.WeekendSurchargeRate, date.Weekday().String()
+		}
+
+		if name != "" {
+			line, err := adjustmentLine(catalog, name, 1, subtotal*rate)
+			if err != nil {
+				return adjustments, err
+			}
+			line.PricingRule = fmt.Sprintf("%s: %g%% of $%.2f (%s)", name, rate*100, subtotal, reason)
+			adjustments = append(adjustments, line)
+			total += line.Total
+		}
+	}
+
+	if travelMiles > constants.IncludedTravelMiles {
+		extraMiles := travelMiles - constants.IncludedTravelMiles
+		line, err := adjustmentLine(catalog, constants.TravelFeeService, extraMiles, constants.TravelFeePerMile)
+		if err != nil {
+			return adjustments, err
+		}
+		line.PricingRule = fmt.Sprintf("Travel: %g mi beyond the first %g mi at $%.2f/mi", extraMiles, constants.IncludedTravelMiles, constants.TravelFeePerMile)
+		adjustments = append(adjustments, line)
+		total += line.Total
+	}
+
+	if total < constants.MinimumQuoteTotal {
+		line, err := adjustmentLine(catalog, constants.MinimumOrderAdjustmentService, 1, constants.MinimumQuoteTotal-total)
+		if err != nil {
+			return adjustments, err
+		}
+		line.PricingRule = fmt.Sprintf("Minimum order: $%.2f minimum, quote came to $%.2f", constants.MinimumQuoteTotal, total)
+		adjustments = append(adjustments, line)
+	}
+
+	return adjustments, nil
+}
+
+// HolidayName returns the holiday the date falls on, or an empty string for a regular day.
+func HolidayName(date time.Time) string {
+	year, month, day := date.Date()
+
+	switch {
+	case month == time.January && day == 1:
+		return "New Year's Day"
+	case month == time.May && day == lastWeekday(year, time.May, time.Monday):
+		return "Memorial Day"
+	case month == time.July && day == 4:
+		return "Independence Day"
+	case month == time.September && day == nthWeekday(year, time.September, time.Monday, 1):
+		return "Labor Day"
+	case month == time.November && day == nthWeekday(year, time.November, time.Thursday, 4):
+		return "Thanksgiving"
+	case month == time.December && day == 24:
+		return "Christmas Eve"
+	case month == time.December && day == 25:
+		return "Christmas Day"
+	case month == time.December && day == 31:
+		return "New Year's Eve"
+	}
+
+	return ""
+}
+
+func priceService(service models.Service, requestedUnits float64, guests int, hours float64) (types.PricedQuoteService, error) {
+	line := types.PricedQuoteService{
+		ServiceID:    service.ServiceID,
+		Service:      service.Service,
+		PricePerUnit: service.SuggestedPrice,
+	}
+
+	if service.ServiceTypeID == constants.PricingAdjustmentServiceTypeID {
+		return line, fmt.Errorf("%s is calculated automatically and cannot be added by hand", service.Service)
+	}
+
+	switch service.UnitTypeID {
+	case constants.PerPersonUnitTypeID:
+		line.Units = float64(guests)
+		line.PricePerUnit = service.SuggestedPrice * hours / constants.AssumedBaseHoursForPerPersonPricing
+		line.PricingRule = fmt.Sprintf("Per person: %d guests at $%.2f per %g hrs, prorated to %g hrs", guests, service.SuggestedPrice, constants.AssumedBaseHoursForPerPersonPricing, hours)
+	case constants.PerHourUnitTypeID:
+		line.Units = hours
+		line.PricingRule = fmt.Sprintf("Per hour: %g hrs at $%.2f/hr", hours, service.SuggestedPrice)
+	case constants.RatioUnitTypeID:
+		if service.GuestRatio <= 0 {
+			return line, fmt.Errorf("%s has no guest ratio", service.Service)
+		}
+		staff := math.Ceil(float64(guests) / float64(service.GuestRatio))
+		line.Units = staff
+		line.PricingRule = fmt.Sprintf("1 per %d guests: %g for %d guests", service.GuestRatio, staff, guests)
+		if service.ServiceTypeID == constants.BartendingServiceTypeID {
+			line.Units = staff * hours
+			line.PricingRule = fmt.Sprintf("1 bartender per %d guests: %g for %d guests x %g hrs at $%.2f/hr", service.GuestRatio, staff, guests, hours, service.SuggestedPrice)
+		}
+	case constants.AdHocUnitTypeID:
+		if requestedUnits <= 0 {
+			return line, fmt.Errorf("%s needs a quantity greater than zero", service.Service)
+		}
+		line.Units = requestedUnits
+		line.PricingRule = fmt.Sprintf("Ad hoc: %g at $%.2f", requestedUnits, service.SuggestedPrice)
+	case constants.FlatUnitTypeID, constants.FixedUnitTypeID:
+		line.Units = 1
+		line.PricingRule = fmt.Sprintf("Flat fee: $%.2f", service.SuggestedPrice)
+	default:
+		return line, fmt.Errorf("%s has no pricing rule for unit type %d", service.Service, service.UnitTypeID)
+	}
+
+	line.PricePerUnit = roundCents(line.PricePerUnit)
+	line.Total = roundCents(line.Units * line.PricePerUnit)
+
+	return line, nil
+}
+
+func adjustmentLine(catalog []models.Service, name string, units, pricePerUnit float64) (types.PricedQuoteService, error) {
+	for _, service := range catalog {
+		if service.ServiceTypeID == constants.PricingAdjustmentServiceTypeID && service.Service == name {
+			pricePerUnit = roundCents(pricePerUnit)
+			return types.PricedQuoteService{
+				ServiceID:    service.ServiceID,
+				Service:      service.Service,
+				Units:        units,
+				PricePerUnit: pricePerUnit,
+				Total:        roundCents(units * pricePerUnit),
+			}, nil
+		}
+	}
+
+	return types.PricedQuoteService{}, fmt.Errorf("pricing adjustment service %q is missing", name)
+}
+
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) int {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return 1 + offset + (n-1)*7
+}
+
+func lastWeekday(year int, month time.Month, weekday time.Weekday) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.Day() - offset
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+var testPricingCatalog = []models.Service{
+	{ServiceID: 1, ServiceTypeID: constants.AlcoholServiceTypeID, Service: "Open Bar", SuggestedPrice: 40, UnitTypeID: constants.PerPersonUnitTypeID},
+	{ServiceID: 2, ServiceTypeID: constants.BartendingServiceTypeID, Service: "Bartender", SuggestedPrice: 50, GuestRatio: 50, UnitTypeID: constants.RatioUnitTypeID},
+	{ServiceID: 3, ServiceTypeID: constants.ExtraServiceTypeID, Service: "Barback", SuggestedPrice: 100, GuestRatio: 75, UnitTypeID: constants.RatioUnitTypeID},
+	{ServiceID: 4, ServiceTypeID: constants.CoolerRentalServiceTypeID, Service: "Cooler", SuggestedPrice: 25, UnitTypeID: constants.PerHourUnitTypeID},
+	{ServiceID: 5, ServiceTypeID: constants.BarRentalServiceTypeID, Service: "Bar Rental", SuggestedPrice: 300, UnitTypeID: constants.FlatUnitTypeID},
+	{ServiceID: 6, ServiceTypeID: constants.ExtraServiceTypeID, Service: "Ice Sculpture", SuggestedPrice: 15, UnitTypeID: constants.AdHocUnitTypeID},
+	{ServiceID: 7, ServiceTypeID: constants.ExtraServiceTypeID, Service: "Server", SuggestedPrice: 80, UnitTypeID: constants.RatioUnitTypeID},
+	{ServiceID: 90, ServiceTypeID: constants.PricingAdjustmentServiceTypeID, Service: constants.WeekendSurchargeService, UnitTypeID: constants.FixedUnitTypeID},
+	{ServiceID: 91, ServiceTypeID: constants.PricingAdjustmentServiceTypeID, Service: constants.HolidaySurchargeService, UnitTypeID: constants.FixedUnitTypeID},
+	{ServiceID: 92, ServiceTypeID: constants.PricingAdjustmentServiceTypeID, Service: constants.TravelFeeService, UnitTypeID: constants.FixedUnitTypeID},
+	{ServiceID: 93, ServiceTypeID: constants.PricingAdjustmentServiceTypeID, Service: constants.MinimumOrderAdjustmentService, UnitTypeID: constants.FixedUnitTypeID},
+}
+
+func testPricingDate(t *testing.T, year int, month time.Month, day int) int64 {
+	t.Helper()
+
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return time.Date(year, month, day, 18, 0, 0, 0, loc).Unix()
+}
+
+func TestPriceQuoteServiceRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		guests       int
+		hours        float64
+		service      types.PricingRequestService
+		units        float64
+		pricePerUnit float64
+		total        float64
+	}{
+		{"per person prorated past the base hours", 100, 5, types.PricingRequestService{ServiceID: 1}, 100, 50, 5000},
+		{"per person prorated up to the minimum hours", 100, 2, types.PricingRequestService{ServiceID: 1}, 100, 30, 3000},
+		{"per hour", 80, 4, types.PricingRequestService{ServiceID: 4}, 4, 25, 100},
+		{"per hour bills the minimum hours", 80, 2, types.PricingRequestService{ServiceID: 4}, 3, 25, 75},
+		{"bartenders by ratio for every hour", 120, 4, types.PricingRequestService{ServiceID: 2}, 12, 50, 600},
+		{"bartender ratio rounds staff up", 101, 3, types.PricingRequestService{ServiceID: 2}, 9, 50, 450},
+		{"other staff by ratio once", 120, 4, types.PricingRequestService{ServiceID: 3}, 2, 100, 200},
+		{"flat", 120, 6, types.PricingRequestService{ServiceID: 5}, 1, 300, 300},
+		{"ad hoc", 120, 4, types.PricingRequestService{ServiceID: 6, Units: 4}, 4, 15, 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := PriceQuote(types.PricingRequest{
+				Guests:   tt.guests,
+				Hours:    tt.hours,
+				Services: []types.PricingRequestService{tt.service},
+			}, testPricingCatalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			line := quote.Services[0]
+			if line.Units != tt.units || line.PricePerUnit != tt.pricePerUnit || line.Total != tt.total {
+				t.Errorf("got %g x $%.2f = $%.2f, expected %g x $%.2f = $%.2f", line.Units, line.PricePerUnit, line.Total, tt.units, tt.pricePerUnit, tt.total)
+			}
+		})
+	}
+}
+
+func TestPriceQuoteRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		req  types.PricingRequest
+	}{
+		{"no guests", types.PricingRequest{Hours: 4, Services: []types.PricingRequestService{{ServiceID: 1}}}},
+		{"no hours", types.PricingRequest{Guests: 50, Services: []types.PricingRequestService{{ServiceID: 1}}}},
+		{"unknown service", types.PricingRequest{Guests: 50, Hours: 4, Services: []types.PricingRequestService{{ServiceID: 404}}}},
+		{"ad hoc without units", types.PricingRequest{Guests: 50, Hours: 4, Services: []types.PricingRequestService{{ServiceID: 6}}}},
+		{"ratio without a guest ratio", types.PricingRequest{Guests: 50, Hours: 4, Services: []types.PricingRequestService{{ServiceID: 7}}}},
+		{"adjustment added by hand", types.PricingRequest{Guests: 50, Hours: 4, Services: []types.PricingRequestService{{ServiceID: 92}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := PriceQuote(tt.req, testPricingCatalog); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPriceQuoteAdjustments(t *testing.T) {
+	weekday := testPricingDate(t, 2026, time.October, 14)
+	saturday := testPricingDate(t, 2026, time.October, 17)
+	independenceDay := testPricingDate(t, 2026, time.July, 4)
+	thanksgiving := testPricingDate(t, 2026, time.November, 26)
+
+	type line struct {
+		service string
+		units   float64
+		total   float64
+	}
+
+	tests := []struct {
+		name        string
+		eventDate   int64
+		travelMiles float64
+		subtotal    float64
+		want        []line
+	}{
+		{"weekday within the included miles", weekday, 25, 1000, nil},
+		{"no event date", 0, 0, 1000, nil},
+		{"nothing to adjust", saturday, 60, 0, nil},
+		{"weekend surcharge", saturday, 0, 1000, []line{{constants.WeekendSurchargeService, 1, 100}}},
+		{"holiday surcharge replaces the weekend one", independenceDay, 0, 1000, []line{{constants.HolidaySurchargeService, 1, 200}}},
+		{"holiday on a weekday", thanksgiving, 0, 1000, []line{{constants.HolidaySurchargeService, 1, 200}}},
+		{"travel past the included miles", weekday, 40, 1000, []line{{constants.TravelFeeService, 15, 22.5}}},
+		{"minimum order", weekday, 0, 300, []line{{constants.MinimumOrderAdjustmentService, 1, 200}}},
+		{"minimum order counts surcharge and travel", saturday, 45, 400, []line{
+			{constants.WeekendSurchargeService, 1, 40},
+			{constants.TravelFeeService, 20, 30},
+			{constants.MinimumOrderAdjustmentService, 1, 30},
+		}},
+		{"surcharge and travel lift the quote past the minimum", independenceDay, 55, 400, []line{
+			{constants.HolidaySurchargeService, 1, 80},
+			{constants.TravelFeeService, 30, 45},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustments, err := PriceQuoteAdjustments(tt.eventDate, tt.travelMiles, tt.subtotal, testPricingCatalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(adjustments) != len(tt.want) {
+				t.Fatalf("got %d adjustments %+v, expected %d", len(adjustments), adjustments, len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				got := adjustments[i]
+				if got.Service != want.service || got.Units != want.units || got.Total != want.total {
+					t.Errorf("adjustment %d: got %s %g = $%.2f, expected %s %g = $%.2f", i, got.Service, got.Units, got.Total, want.service, want.units, want.total)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceQuoteAdjustmentsNeedsAdjustmentServices(t *testing.T) {
+	_, err := PriceQuoteAdjustments(testPricingDate(t, 2026, time.October, 17), 0, 1000, testPricingCatalog[:7])
+	if err == nil {
+		t.Error("expected an error when the surcharge service is missing from the catalog")
+	}
+}
+
+func TestPriceQuoteTotals(t *testing.T) {
+	quote, err := PriceQuote(types.PricingRequest{
+		Guests:      100,
+		Hours:       5,
+		EventDate:   testPricingDate(t, 2026, time.October, 17),
+		TravelMiles: 35,
+		Services:    []types.PricingRequestService{{ServiceID: 1}, {ServiceID: 2}},
+	}, testPricingCatalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// $5,000 open bar + $500 for 2 bartenders x 5 hrs, then 10% weekend and 10 mi of travel
+	if quote.BilledHours != 5 || quote.Subtotal != 5500 || quote.Total != 6065 {
+		t.Errorf("got %g hrs, subtotal $%.2f, total $%.2f, expected 5 hrs, $5500.00 and $6065.00", quote.BilledHours, quote.Subtotal, quote.Total)
+	}
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+const scheduledMessageInputFormat = "2006-01-02T15:04"
+
+// NextAllowedSendTime returns t when it falls outside quiet hours, otherwise the end of the
+// current quiet period in the business time zone.
+func NextAllowedSendTime(t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return t, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	local := t.In(loc)
+	windowOpens := time.Date(local.Year(), local.Month(), local.Day(), constants.QuietHoursEndHour, 0, 0, 0, loc)
+
+	switch {
+	case local.Hour() < constants.QuietHoursEndHour:
+		return windowOpens, nil
+	case local.Hour() >= constants.QuietHoursStartHour:
+		return windowOpens.AddDate(0, 0, 1), nil
+	default:
+		return t, nil
+	}
+}
+
+// ParseScheduledMessageTime reads a datetime-local input value in the business time zone.
+func ParseScheduledMessageTime(value string) (time.Time, error) {
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	sendAt, err := time.ParseInLocation(scheduledMessageInputFormat, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid send time: %w", err)
+	}
+
+	return sendAt, nil
+}
@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// IsQuoteOption reports whether a line is still up to the customer: part of a package tier or an optional add-on.
+func IsQuoteOption(qs types.QuoteServiceList) bool {
+	return qs.IsOptional || qs.PackageTier != ""
+}
+
+// GroupQuoteOptions splits a quote into the lines every package includes, one group per package tier and the
+// optional add-ons, keeping tiers in the order their first line was added.
+func GroupQuoteOptions(quoteServices []types.QuoteServiceList) types.QuoteOptions {
+	var options types.QuoteOptions
+	tiers := make(map[string]int)
+
+	for _, qs := range quoteServices {
+		switch {
+		case qs.PackageTier != "":
+			i, ok := tiers[qs.PackageTier]
+			if !ok {
+				i = len(options.Packages)
+				tiers[qs.PackageTier] = i
+				options.Packages = append(options.Packages, types.QuotePackage{Tier: qs.PackageTier})
+			}
+			options.Packages[i].Services = append(options.Packages[i].Services, qs)
+			options.Packages[i].Total = roundCents(options.Packages[i].Total + qs.Total)
+		case qs.IsOptional:
+			options.AddOns = append(options.AddOns, qs)
+		default:
+			options.Included = append(options.Included, qs)
+		}
+	}
+
+	return options
+}
+
+// ResolveQuoteOptions checks the customer's choices against the quote and returns the option lines to keep,
+// along with the names of the add-ons they picked.
+func ResolveQuoteOptions(quoteServices []types.QuoteServiceList, packageTier string, addOnIds []int) ([]int, string, error) {
+	options := GroupQuoteOptions(quoteServices)
+
+	var keep []int
+
+	if len(options.Packages) > 0 {
+		var chosen *types.QuotePackage
+		for i := range options.Packages {
+			if options.Packages[i].Tier == packageTier {
+				chosen = &options.Packages[i]
+			}
+		}
+
+		if chosen == nil {
+			return keep, "", fmt.Errorf("please choose one of the packages")
+		}
+
+		for _, qs := range chosen.Services {
+			keep = append(keep, qs.QuoteServiceID)
+		}
+	} else if packageTier != "" {
+		return keep, "", fmt.Errorf("this quote has no packages to choose from")
+	}
+
+	addOns := make(map[int]types.QuoteServiceList, len(options.AddOns))
+	for _, qs := range options.AddOns {
+		addOns[qs.QuoteServiceID] = qs
+	}
+
+	var names []string
+	seen := make(map[int]bool)
+	for _, id := range addOnIds {
+		qs, ok := addOns[id]
+		if !ok {
+			return keep, "", fmt.Errorf("add-on %d is not part of this quote", id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		keep = append(keep, id)
+		names = append(names, qs.Service)
+	}
+
+	return keep, strings.Join(names, ", "), nil
+}
@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// GetReportPeriod works like GetPayPeriod but defaults to the trailing twelve months.
+func GetReportPeriod(startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	now = now.In(loc)
+	end := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, loc)
+
+	return parseDateRange(startDate, endDate, end.AddDate(-1, 0, 0), end, loc)
+}
+
+func CalculateProfitability(events []types.EventProfitability) {
+	for i := range events {
+		events[i].Margin = events[i].QuotedRevenue - events[i].LaborCost - events[i].ServiceCost
+		events[i].MarginPercent = marginPercent(events[i].Margin, events[i].QuotedRevenue)
+	}
+}
+
+// SummarizeProfitability groups events by the label returned from key, in the order each label first appears.
+func SummarizeProfitability(events []types.EventProfitability, key func(types.EventProfitability) string) []types.ProfitabilitySummary {
+	var summaries []types.ProfitabilitySummary
+	index := make(map[string]int)
+
+	for _, event := range events {
+		label := key(event)
+		i, ok := index[label]
+		if !ok {
+			summaries = append(summaries, types.ProfitabilitySummary{Label: label})
+			i = len(summaries) - 1
+			index[label] = i
+		}
+
+		summaries[i].Events++
+		summaries[i].QuotedRevenue += event.QuotedRevenue
+		summaries[i].AmountPaid += event.AmountPaid
+		summaries[i].LaborCost += event.LaborCost
+		summaries[i].ServiceCost += event.ServiceCost
+		summaries[i].Margin += event.Margin
+	}
+
+	for i := range summaries {
+		summaries[i].MarginPercent = marginPercent(summaries[i].Margin, summaries[i].QuotedRevenue)
+	}
+
+	return summaries
+}
+
+func SortProfitabilityByMargin(summaries []types.ProfitabilitySummary) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Margin > summaries[j].Margin
+	})
+}
+
+func marginPercent(margin, revenue float64) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return margin / revenue * 100
+}
+
+func CalculateMarketingAttribution(report []types.MarketingAttribution) {
+	for i := range report {
+		report[i].BookingRate = 0
+		if report[i].Leads > 0 {
+			report[i].BookingRate = float64(report[i].DepositPaid) / float64(report[i].Leads) * 100
+		}
+
+		report[i].CostPerLead = 0
+		if report[i].Leads > 0 {
+			report[i].CostPerLead = report[i].Spend / float64(report[i].Leads)
+		}
+
+		report[i].CostPerBooking = 0
+		if report[i].DepositPaid > 0 {
+			report[i].CostPerBooking = report[i].Spend / float64(report[i].DepositPaid)
+		}
+
+		report[i].ROAS = 0
+		if report[i].Spend > 0 {
+			report[i].ROAS = report[i].Revenue / report[i].Spend
+		}
+	}
+}
+
+func TotalMarketingAttribution(report []types.MarketingAttribution) types.MarketingAttribution {
+	total := types.MarketingAttribution{Label: "Total"}
+
+	for _, row := range report {
+		total.Leads += row.Leads
+		total.Quoted += row.Quoted
+		total.DepositPaid += row.DepositPaid
+		total.EventsCompleted += row.EventsCompleted
+		total.Revenue += row.Revenue
+		total.Spend += row.Spend
+	}
+
+	totals := []types.MarketingAttribution{total}
+	CalculateMarketingAttribution(totals)
+
+	return totals[0]
+}
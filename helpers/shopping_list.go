@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// Recipe units that can be poured, converted to fluid ounces. Anything else (pieces, leaves) is bought by the count.
+var unitOunces = map[string]float64{
+	"oz":     1,
+	"ounce":  1,
+	"dash":   1.0 / 32,
+	"bsp":    1.0 / 6,
+	"tsp":    1.0 / 6,
+	"tbsp":   0.5,
+	"splash": 0.25,
+	"ml":     1 / 29.5735,
+	"cl":     1 / 2.95735,
+	"cup":    8,
+}
+
+// BuildEventShoppingList splits the event's drinks evenly across its cocktails and totals every ingredient,
+// rounding up to the bottles, pieces and ice bags that actually have to be bought.
+func BuildEventShoppingList(guests int, startTime, endTime int64, drinksPerGuestPerHour float64, cocktails []types.ShoppingListCocktail) (types.EventShoppingList, error) {
+	var list types.EventShoppingList
+
+	if guests <= 0 {
+		return list, fmt.Errorf("event has no guest count")
+	}
+
+	if endTime <= startTime {
+		return list, fmt.Errorf("event end time must be after start time")
+	}
+
+	if drinksPerGuestPerHour <= 0 {
+		return list, fmt.Errorf("drinks per guest per hour must be greater than zero")
+	}
+
+	if len(cocktails) == 0 {
+		return list, fmt.Errorf("event has no cocktails")
+	}
+
+	list.Guests = guests
+	list.Hours = math.Round(float64(endTime-startTime)/3600*100) / 100
+	list.DrinksPerGuestPerHour = drinksPerGuestPerHour
+	list.TotalDrinks = int(math.Ceil(float64(guests) * list.Hours * drinksPerGuestPerHour))
+	list.DrinksPerCocktail = int(math.Ceil(float64(list.TotalDrinks) / float64(len(cocktails))))
+
+	type total struct {
+		category string
+		name     string
+		unit     string
+		amount   float64
+	}
+
+	totals := make(map[string]*total)
+	var keys []string
+
+	for _, cocktail := range cocktails {
+		if len(cocktail.Ingredients) == 0 {
+			list.MissingRecipes = append(list.MissingRecipes, cocktail.Name)
+			continue
+		}
+
+		for _, ingredient := range cocktail.Ingredients {
+			amount := ingredient.Amount * float64(list.DrinksPerCocktail)
+			unit := ingredient.Unit
+
+			if ounces, ok := unitOunces[strings.ToLower(unit)]; ok {
+				amount *= ounces
+				unit = "oz"
+			}
+
+			key := fmt.Sprintf("%d:%s", ingredient.IngredientID, unit)
+			if _, ok := totals[key]; !ok {
+				totals[key] = &total{category: ingredient.Category, name: ingredient.Ingredient, unit: unit}
+				keys = append(keys, key)
+			}
+			totals[key].amount += amount
+		}
+	}
+
+	for _, key := range keys {
+		t := totals[key]
+		item := types.ShoppingListItem{
+			Category: t.category,
+			Item:     t.name,
+			Needed:   math.Round(t.amount*100) / 100,
+			Unit:     t.unit,
+		}
+
+		switch {
+		case t.unit == "oz" && t.category == constants.LiquorIngredientCategory:
+			item.Quantity = int(math.Ceil(t.amount / constants.LiquorBottleOunces))
+			item.PurchaseUnit = "750 ml bottle"
+		case t.unit == "oz":
+			item.Quantity = int(math.Ceil(t.amount / constants.MixerBottleOunces))
+			item.PurchaseUnit = "1 L bottle"
+		default:
+			item.Quantity = int(math.Ceil(t.amount))
+			item.PurchaseUnit = t.unit
+		}
+
+		list.Items = append(list.Items, item)
+	}
+
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		a, b := categoryRank(list.Items[i].Category), categoryRank(list.Items[j].Category)
+		if a != b {
+			return a < b
+		}
+		return list.Items[i].Item < list.Items[j].Item
+	})
+
+	icePounds := float64(guests) * list.Hours * constants.IcePoundsPerGuestPerHour
+	list.Items = append(list.Items, types.ShoppingListItem{
+		Category:     "Ice",
+		Item:         "Ice",
+		Needed:       math.Round(icePounds*100) / 100,
+		Unit:         "lb",
+		Quantity:     int(math.Ceil(icePounds / constants.IceBagPounds)),
+		PurchaseUnit: fmt.Sprintf("%g lb bag", constants.IceBagPounds),
+	})
+
+	return list, nil
+}
+
+func categoryRank(category string) int {
+	for i, c := range constants.IngredientCategories {
+		if c == category {
+			return i
+		}
+	}
+	return len(constants.IngredientCategories)
+}
@@ -0,0 +1,118 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func TestBuildEventShoppingList(t *testing.T) {
+	margarita := types.ShoppingListCocktail{
+		Name: "Margarita",
+		Ingredients: []types.CocktailIngredientList{
+			{IngredientID: 1, Ingredient: "Tequila", Category: constants.LiquorIngredientCategory, Unit: "oz", Amount: 2},
+			{IngredientID: 2, Ingredient: "Lime Juice", Category: constants.MixerIngredientCategory, Unit: "oz", Amount: 1},
+			{IngredientID: 3, Ingredient: "Lime Wedge", Category: constants.GarnishIngredientCategory, Unit: "piece", Amount: 1},
+		},
+	}
+	mojito := types.ShoppingListCocktail{
+		Name: "Mojito",
+		Ingredients: []types.CocktailIngredientList{
+			{IngredientID: 4, Ingredient: "Rum", Category: constants.LiquorIngredientCategory, Unit: "oz", Amount: 2},
+			{IngredientID: 2, Ingredient: "Lime Juice", Category: constants.MixerIngredientCategory, Unit: "ML", Amount: 30},
+			{IngredientID: 5, Ingredient: "Mint", Category: constants.GarnishIngredientCategory, Unit: "leaf", Amount: 8},
+		},
+	}
+	oldFashioned := types.ShoppingListCocktail{Name: "Old Fashioned"}
+
+	start := int64(1790000000)
+
+	tests := []struct {
+		name              string
+		guests            int
+		hours             float64
+		drinksPerHour     float64
+		cocktails         []types.ShoppingListCocktail
+		totalDrinks       int
+		drinksPerCocktail int
+		items             []types.ShoppingListItem
+		missingRecipes    []string
+	}{
+		{
+			name: "totals shared ingredients across cocktails", guests: 50, hours: 4, drinksPerHour: 1,
+			cocktails:   []types.ShoppingListCocktail{margarita, mojito},
+			totalDrinks: 200, drinksPerCocktail: 100,
+			items: []types.ShoppingListItem{
+				{Category: constants.LiquorIngredientCategory, Item: "Rum", Needed: 200, Unit: "oz", Quantity: 8, PurchaseUnit: "750 ml bottle"},
+				{Category: constants.LiquorIngredientCategory, Item: "Tequila", Needed: 200, Unit: "oz", Quantity: 8, PurchaseUnit: "750 ml bottle"},
+				{Category: constants.MixerIngredientCategory, Item: "Lime Juice", Needed: 201.44, Unit: "oz", Quantity: 6, PurchaseUnit: "1 L bottle"},
+				{Category: constants.GarnishIngredientCategory, Item: "Lime Wedge", Needed: 100, Unit: "piece", Quantity: 100, PurchaseUnit: "piece"},
+				{Category: constants.GarnishIngredientCategory, Item: "Mint", Needed: 800, Unit: "leaf", Quantity: 800, PurchaseUnit: "leaf"},
+				{Category: "Ice", Item: "Ice", Needed: 100, Unit: "lb", Quantity: 5, PurchaseUnit: "20 lb bag"},
+			},
+		},
+		{
+			name: "rounds drinks up and still splits them across cocktails without recipes", guests: 45, hours: 3.5, drinksPerHour: 1.5,
+			cocktails:   []types.ShoppingListCocktail{margarita, oldFashioned},
+			totalDrinks: 237, drinksPerCocktail: 119,
+			items: []types.ShoppingListItem{
+				{Category: constants.LiquorIngredientCategory, Item: "Tequila", Needed: 238, Unit: "oz", Quantity: 10, PurchaseUnit: "750 ml bottle"},
+				{Category: constants.MixerIngredientCategory, Item: "Lime Juice", Needed: 119, Unit: "oz", Quantity: 4, PurchaseUnit: "1 L bottle"},
+				{Category: constants.GarnishIngredientCategory, Item: "Lime Wedge", Needed: 119, Unit: "piece", Quantity: 119, PurchaseUnit: "piece"},
+				{Category: "Ice", Item: "Ice", Needed: 78.75, Unit: "lb", Quantity: 4, PurchaseUnit: "20 lb bag"},
+			},
+			missingRecipes: []string{"Old Fashioned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := start + int64(tt.hours*3600)
+
+			list, err := BuildEventShoppingList(tt.guests, start, end, tt.drinksPerHour, tt.cocktails)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if list.TotalDrinks != tt.totalDrinks || list.DrinksPerCocktail != tt.drinksPerCocktail {
+				t.Errorf("got %d drinks, %d per cocktail, expected %d and %d", list.TotalDrinks, list.DrinksPerCocktail, tt.totalDrinks, tt.drinksPerCocktail)
+			}
+
+			if !reflect.DeepEqual(list.Items, tt.items) {
+				t.Errorf("got items %+v, expected %+v", list.Items, tt.items)
+			}
+
+			if !reflect.DeepEqual(list.MissingRecipes, tt.missingRecipes) {
+				t.Errorf("got missing recipes %v, expected %v", list.MissingRecipes, tt.missingRecipes)
+			}
+		})
+	}
+}
+
+func TestBuildEventShoppingListRejectsInvalidEvents(t *testing.T) {
+	cocktails := []types.ShoppingListCocktail{{Name: "Margarita"}}
+	start := int64(1790000000)
+
+	tests := []struct {
+		name          string
+		guests        int
+		end           int64
+		drinksPerHour float64
+		cocktails     []types.ShoppingListCocktail
+	}{
+		{"no guests", 0, start + 3600, 1, cocktails},
+		{"ends before it starts", 50, start, 1, cocktails},
+		{"no drinks per hour", 50, start + 3600, 0, cocktails},
+		{"no cocktails", 50, start + 3600, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildEventShoppingList(tt.guests, start, tt.end, tt.drinksPerHour, tt.cocktails); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+// ParseSMSConsentKeyword returns the normalized keyword and consent action for an inbound text
+// that is only a carrier keyword such as STOP or START. Regular messages return an empty action.
+func ParseSMSConsentKeyword(body string) (string, string) {
+	keyword := strings.ToUpper(strings.Trim(strings.TrimSpace(body), ".!"))
+
+	switch keyword {
+	case "STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "OPTOUT", "REVOKE":
+		return keyword, constants.OptOutSMSConsentAction
+	case "START", "UNSTOP", "YES", "OPTIN", "SUBSCRIBE":
+		return keyword, constants.OptInSMSConsentAction
+	case "HELP", "INFO":
+		return keyword, constants.HelpSMSConsentAction
+	default:
+		return keyword, ""
+	}
+}
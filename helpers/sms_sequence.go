@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+// RenderSMSSequenceMessage fills in the {first_name}, {full_name} and {company_name} placeholders of a saved template.
+func RenderSMSSequenceMessage(body, fullName string) string {
+	firstName := ""
+	if fields := strings.Fields(fullName); len(fields) > 0 {
+		firstName = fields[0]
+	}
+
+	replacer := strings.NewReplacer(
+		"{first_name}", firstName,
+		"{full_name}", fullName,
+		"{company_name}", constants.CompanyName,
+	)
+
+	return strings.TrimSpace(replacer.Replace(body))
+}
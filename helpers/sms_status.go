@@ -0,0 +1,21 @@
+package helpers
+
+var twilioMessageErrorDescriptions = map[string]string{
+	"21610": "Recipient has unsubscribed",
+	"21614": "Not a valid mobile number",
+	"30003": "Unreachable destination handset",
+	"30004": "Message blocked by recipient",
+	"30005": "Unknown or inactive number",
+	"30006": "Landline or unreachable carrier",
+	"30007": "Filtered by carrier",
+	"30008": "Unknown delivery error",
+}
+
+// GetTwilioMessageErrorDescription explains the common Twilio delivery error codes so staff don't have to look them up.
+func GetTwilioMessageErrorDescription(errorCode string) string {
+	if description, ok := twilioMessageErrorDescriptions[errorCode]; ok {
+		return description
+	}
+
+	return "Twilio error " + errorCode
+}
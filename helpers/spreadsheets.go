@@ -1,7 +1,9 @@
 package helpers
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
@@ -31,9 +33,10 @@ func GenerateExcelFile(report interface{}, sheetName, localFilePath string) (str
 		// Fill in the data
 		for i := 0; i < v.Len(); i++ {
 			row := v.Index(i).Interface()
-			for j, header := range headers {
+			for j := range headers {
 				cell := fmt.Sprintf("%s%d", string(rune('A'+j)), i+2)
-				value := reflect.ValueOf(row).FieldByName(header)
+				// Headers can be renamed with the spreadsheet_header tag, so look fields up by position.
+				value := reflect.ValueOf(row).Field(j)
 				if value.IsValid() {
 					f.SetCellValue(sheetName, cell, value.Interface())
 				} else {
@@ -52,6 +55,37 @@ func GenerateExcelFile(report interface{}, sheetName, localFilePath string) (str
 	return localFilePath, nil
 }
 
+func WriteCSV(w io.Writer, report interface{}) error {
+	v := reflect.ValueOf(report)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("provided report is not a slice")
+	}
+
+	writer := csv.NewWriter(w)
+
+	if v.Len() > 0 {
+		if err := writer.Write(getHeaders(v.Index(0).Interface())); err != nil {
+			return fmt.Errorf("error writing CSV headers: %w", err)
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			row := v.Index(i)
+			record := make([]string, row.NumField())
+			for j := range record {
+				record[j] = fmt.Sprint(row.Field(j).Interface())
+			}
+
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("error writing CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
 func getHeaders(data interface{}) []string {
 	val := reflect.TypeOf(data)
 	var headers []string
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/davidalvarez305/yd_cocktails/csrf"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/davidalvarez305/yd_cocktails/sessions"
 	"github.com/davidalvarez305/yd_cocktails/utils"
@@ -123,7 +122,7 @@ func GenerateTokenInHeader(w http.ResponseWriter, r *http.Request) (string, erro
 		IsUsed:     false,
 	}
 
-	err = database.InsertCSRFToken(csrfToken)
+	err = stores.Sessions.InsertCSRFToken(csrfToken)
 	if err != nil {
 		fmt.Printf("Error inserting CSRF token: %+v\n", err)
 		return token, err
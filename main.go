@@ -4,15 +4,20 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/handlers"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/middleware"
 	"github.com/davidalvarez305/yd_cocktails/router"
 	"github.com/davidalvarez305/yd_cocktails/services"
+	"github.com/davidalvarez305/yd_cocktails/sessions"
 )
 
 func init() {
@@ -25,6 +30,12 @@ func init() {
 
 	constants.Init()
 
+	providers, err := services.NewProviders(constants.ProvidersMode, constants.ProvidersDir)
+	if err != nil {
+		log.Fatalf("ERROR CONFIGURING PROVIDERS: %+v\n", err)
+	}
+	services.SetProviders(providers)
+
 	_, err = database.Connect()
 
 	if err != nil {
@@ -32,20 +43,74 @@ func init() {
 	}
 	fmt.Println("Database connected.")
 
-	services.StartLeadChecker()
-	fmt.Println("Lead checker started.")
+	// The job queue isn't needed when running a one-off command
+	if isMigrateCommand() {
+		return
+	}
+
+	services.StartJobQueue()
+	fmt.Println("Job queue started.")
+}
+
+func isMigrateCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == "migrate"
+}
+
+// Usage: go run . migrate [up | down [steps] | status]
+func runMigrateCommand(args []string) {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
 
-	services.StartSMSNotifcationChecker()
-	fmt.Println("Unread messages checker started.")
+	switch command {
+	case "up":
+		if err := database.MigrateUp(); err != nil {
+			log.Fatalf("ERROR RUNNING MIGRATIONS: %+v\n", err)
+		}
+		fmt.Println("Migrations applied.")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("INVALID NUMBER OF STEPS: %s\n", args[1])
+			}
+			steps = n
+		}
 
-	services.StartTranscriptionService()
-	fmt.Println("Transcription service started.")
+		if err := database.MigrateDown(steps); err != nil {
+			log.Fatalf("ERROR REVERTING MIGRATIONS: %+v\n", err)
+		}
+		fmt.Println("Migrations reverted.")
+	case "status":
+		status, err := database.MigrationStatus()
+		if err != nil {
+			log.Fatalf("ERROR GETTING MIGRATION STATUS: %+v\n", err)
+		}
+		for _, line := range status {
+			fmt.Println(line)
+		}
+	default:
+		log.Fatalf("UNKNOWN MIGRATE COMMAND: %s\n", command)
+	}
 }
 
 func main() {
+	if isMigrateCommand() {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
+	stores := database.NewPostgresStores()
+	services.SetStores(stores)
+	sessions.SetStores(stores)
+	middleware.SetStores(stores)
+	helpers.SetStores(stores)
+
 	s := &http.Server{
 		Addr:           ":" + constants.ServerPort,
-		Handler:        middleware.UserTracking(middleware.SecurityMiddleware(middleware.CSRFProtectMiddleware(router.Router()))),
+		Handler:        middleware.UserTracking(middleware.SecurityMiddleware(middleware.CSRFProtectMiddleware(router.Router(handlers.NewServer(stores))))),
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20,
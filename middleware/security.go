@@ -11,7 +11,6 @@ import (
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/csrf"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/davidalvarez305/yd_cocktails/sessions"
@@ -89,7 +88,7 @@ func CSRFProtectMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if strings.Contains(path, "/call/inbound") || strings.Contains(path, "/sms/inbound") {
+		if strings.Contains(path, "/call/inbound") || strings.Contains(path, "/sms/inbound") || path == constants.TwilioSMSStatusCallbackWebhook {
 			if err := validateTwilioWebhook(r); err != nil {
 				fmt.Printf("ERROR VALIDATING TWILIO SECURITY MIDDLEWARE: %+v\n", err)
 				http.Error(w, "Error validating Twilio webhook.", http.StatusInternalServerError)
@@ -99,7 +98,7 @@ func CSRFProtectMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		var csrfURLs = []string{"/terms-and-conditions", "/privacy-policy", "/about", "/contact", "/quote", "/login", "/crm", "/sms", "/call", "/planning", "/staffing"}
+		var csrfURLs = []string{"/terms-and-conditions", "/privacy-policy", "/about", "/contact", "/quote", "/login", "/crm", "/sms", "/call", "/planning", "/staffing", "/staff/", "/external/"}
 
 		if r.Method == http.MethodGet && (utils.UrlsListHasCurrentPath(csrfURLs, path) || path == "/") {
 			csrfSecret, ok := r.Context().Value("csrf_secret").(string)
@@ -131,7 +130,7 @@ func CSRFProtectMiddleware(next http.Handler) http.Handler {
 				IsUsed:     false,
 			}
 
-			err = database.InsertCSRFToken(csrfToken)
+			err = stores.Sessions.InsertCSRFToken(csrfToken)
 			if err != nil {
 				fmt.Printf("ERROR INSERTING TOKEN SECURITY MIDDLEWARE: %+v\n", err)
 				http.Error(w, "Error inserting CSRF token.", http.StatusBadRequest)
@@ -164,7 +163,7 @@ func CSRFProtectMiddleware(next http.Handler) http.Handler {
 				return
 			}
 
-			isUsed, err := database.CheckIsTokenUsed(csrfToken)
+			isUsed, err := stores.Sessions.CheckIsTokenUsed(csrfToken)
 			if err != nil {
 				fmt.Printf("%+v\n", err)
 				http.Error(w, "Token doesn't exist in DB.", http.StatusBadRequest)
@@ -178,7 +177,7 @@ func CSRFProtectMiddleware(next http.Handler) http.Handler {
 				return
 			}
 
-			err = database.MarkCSRFTokenAsUsed(csrfToken)
+			err = stores.Sessions.MarkCSRFTokenAsUsed(csrfToken)
 			if err != nil {
 				fmt.Printf("%+v\n", err)
 				http.Error(w, "Error marking token as used.", http.StatusBadRequest)
@@ -220,19 +219,22 @@ func AuthRequired(next http.Handler) http.Handler {
 			return
 		}
 
-		user, err := database.GetUserById(values.UserID)
+		user, err := stores.Users.GetUserById(values.UserID)
 		if err != nil {
 			fmt.Printf("CANNOT GET USER PERMISSION DENIED: %+v\n", err)
 			http.Error(w, "Permission denied", http.StatusUnauthorized)
 			return
 		}
 
-		if user.UserRoleID != constants.UserAdminRoleID {
-			fmt.Printf("IS NOT ADMIN PERMISSION DENIED: %+v\n", err)
-			http.Error(w, "Admins only.", http.StatusUnauthorized)
+		if !helpers.CanAccessCRM(user.UserRoleID) {
+			fmt.Printf("ROLE HAS NO CRM CAPABILITIES PERMISSION DENIED: %d\n", user.UserRoleID)
+			http.Error(w, "Permission denied", http.StatusUnauthorized)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		ctx := context.WithValue(r.Context(), "user_id", user.UserID)
+		ctx = context.WithValue(ctx, "user_role_id", user.UserRoleID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
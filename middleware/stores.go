@@ -0,0 +1,10 @@
+package middleware
+
+import "github.com/davidalvarez305/yd_cocktails/database"
+
+var stores = database.NewPostgresStores()
+
+// SetStores swaps the repositories used by the middleware package, e.g. for the in-memory fakes.
+func SetStores(s database.Stores) {
+	stores = s
+}
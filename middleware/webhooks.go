@@ -12,7 +12,8 @@ func validateTwilioWebhook(r *http.Request) error {
 	requestValidator := client.NewRequestValidator(constants.TwilioAuthToken)
 	twilioSignature := r.Header.Get("X-Twilio-Signature")
 
-	url := "https://" + r.Host + r.URL.Path
+	// Twilio signs the full URL, so call flow steps that carry state in the query string need it included
+	url := "https://" + r.Host + r.URL.RequestURI()
 
 	if r.Method == "POST" {
 		if err := r.ParseForm(); err != nil {
@@ -21,7 +22,7 @@ func validateTwilioWebhook(r *http.Request) error {
 	}
 
 	params := make(map[string]string)
-	for key, values := range r.Form {
+	for key, values := range r.PostForm {
 		if len(values) > 0 {
 			params[key] = values[0]
 		}
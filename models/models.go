@@ -63,6 +63,8 @@ type LeadMarketing struct {
 	InstantFormID     int64  `json:"instant_form_id" form:"instant_form_id" schema:"instant_form_id"`
 	InstantFormName   string `json:"instant_form_name" form:"instant_form_name" schema:"instant_form_name"`
 	ReferralLeadID    int    `json:"referral_lead_id" form:"referral_lead_id" schema:"referral_lead_id"`
+
+	TrackingPhoneNumber string `json:"tracking_phone_number" form:"tracking_phone_number" schema:"tracking_phone_number"`
 }
 
 type CSRFToken struct {
@@ -82,6 +84,19 @@ type Message struct {
 	IsInbound   bool   `json:"is_inbound"`
 	Status      string `json:"status" form:"status" schema:"status"`
 	IsRead      bool   `json:"is_read"`
+
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+
+	Media []MessageMedia `json:"media"`
+}
+
+type MessageMedia struct {
+	MessageMediaID int    `json:"message_media_id"`
+	MessageID      int    `json:"message_id"`
+	FilePath       string `json:"file_path"`
+	ContentType    string `json:"content_type"`
+	DateCreated    int64  `json:"date_created"`
 }
 
 type PhoneCall struct {
@@ -114,6 +129,7 @@ type Event struct {
 	EventID       int     `json:"event_id" form:"event_id" schema:"event_id"`
 	BartenderID   int     `json:"bartender_id" form:"bartender_id" schema:"bartender_id"`
 	LeadID        int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	QuoteID       int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
 	StreetAddress string  `json:"street_address" form:"street_address" schema:"street_address"`
 	City          string  `json:"city" form:"city" schema:"city"`
 	ZipCode       string  `json:"zip_code" form:"zip_code" schema:"zip_code"`
@@ -133,8 +149,9 @@ type EventCocktail struct {
 }
 
 type Cocktail struct {
-	CocktailID int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
-	Name       string `json:"name" form:"name" schema:"name"`
+	CocktailID   int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
+	Name         string `json:"name" form:"name" schema:"name"`
+	Instructions string `json:"instructions" form:"instructions" schema:"instructions"`
 }
 
 type Ingredient struct {
@@ -157,12 +174,13 @@ type CocktailIngredient struct {
 }
 
 type Quote struct {
-	QuoteID    int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
-	ExternalID string  `json:"external_id" form:"external_id" schema:"external_id"`
-	LeadID     int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
-	Guests     int     `json:"guests" form:"guests" schema:"guests"`
-	Hours      float64 `json:"hours" form:"hours" schema:"hours"`
-	EventDate  int64   `json:"event_date" form:"event_date" schema:"event_date"`
+	QuoteID     int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
+	ExternalID  string  `json:"external_id" form:"external_id" schema:"external_id"`
+	LeadID      int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	Guests      int     `json:"guests" form:"guests" schema:"guests"`
+	Hours       float64 `json:"hours" form:"hours" schema:"hours"`
+	EventDate   int64   `json:"event_date" form:"event_date" schema:"event_date"`
+	TravelMiles float64 `json:"travel_miles" form:"travel_miles" schema:"travel_miles"`
 }
 
 type InvoiceType struct {
@@ -211,6 +229,7 @@ type Service struct {
 	SuggestedPrice float64 `json:"suggested_price" form:"suggested_price" schema:"suggested_price"`
 	GuestRatio     int     `json:"guest_ratio" form:"guest_ratio" schema:"guest_ratio"`
 	UnitTypeID     int     `json:"unit_type_id" form:"unit_type_id" schema:"unit_type_id"`
+	CostBasis      float64 `json:"cost_basis" form:"cost_basis" schema:"cost_basis"`
 }
 
 type ServiceType struct {
@@ -224,6 +243,23 @@ type QuoteService struct {
 	QuoteID        int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
 	Units          float64 `json:"units" form:"units" schema:"units"`
 	PricePerUnit   float64 `json:"price_per_unit" form:"price_per_unit" schema:"price_per_unit"`
+	PricingRule    string  `json:"pricing_rule" form:"pricing_rule" schema:"pricing_rule"`
+	PackageTier    string  `json:"package_tier" form:"package_tier" schema:"package_tier"`
+	IsOptional     bool    `json:"is_optional" form:"is_optional" schema:"is_optional"`
+}
+
+type QuoteAcceptance struct {
+	QuoteAcceptanceID int    `json:"quote_acceptance_id"`
+	QuoteID           int    `json:"quote_id"`
+	SignatureName     string `json:"signature_name"`
+	PackageTier       string `json:"package_tier"`
+	SelectedAddOns    string `json:"selected_add_ons"`
+	IPAddress         string `json:"ip_address"`
+	UserAgent         string `json:"user_agent"`
+	DateAccepted      int64  `json:"date_accepted"`
+	DateInvoiced      int64  `json:"date_invoiced"`
+
+	DateInvoicingStarted int64 `json:"date_invoicing_started"`
 }
 
 type LeadNote struct {
@@ -254,9 +290,179 @@ type EventStaff struct {
 	EventID      int     `json:"event_id" form:"event_id" schema:"event_id"`
 	EventRoleID  int     `json:"event_role_id" form:"event_role_id" schema:"event_role_id"`
 	HourlyRate   float64 `json:"hourly_rate" form:"hourly_rate" schema:"hourly_rate"`
+
+	EventStaffStatusID int   `json:"event_staff_status_id" form:"event_staff_status_id" schema:"event_staff_status_id"`
+	DateResponded      int64 `json:"date_responded" form:"date_responded" schema:"date_responded"`
+
+	DatePaid   int64   `json:"date_paid" form:"date_paid" schema:"date_paid"`
+	AmountPaid float64 `json:"amount_paid" form:"amount_paid" schema:"amount_paid"`
+}
+
+type EventStaffStatus struct {
+	EventStaffStatusID int    `json:"event_staff_status_id" form:"event_staff_status_id" schema:"event_staff_status_id"`
+	Status             string `json:"status" form:"status" schema:"status"`
+}
+
+type AdSpend struct {
+	AdSpendID    int     `json:"ad_spend_id" form:"ad_spend_id" schema:"ad_spend_id"`
+	Date         int64   `json:"date" form:"date" schema:"date"`
+	Source       string  `json:"source" form:"source" schema:"source"`
+	CampaignID   int64   `json:"campaign_id" form:"campaign_id" schema:"campaign_id"`
+	CampaignName string  `json:"campaign_name" form:"campaign_name" schema:"campaign_name"`
+	AdSetID      int64   `json:"ad_set_id" form:"ad_set_id" schema:"ad_set_id"`
+	AdSetName    string  `json:"ad_set_name" form:"ad_set_name" schema:"ad_set_name"`
+	Spend        float64 `json:"spend" form:"spend" schema:"spend"`
+	DateImported int64   `json:"date_imported" form:"date_imported" schema:"date_imported"`
+}
+
+type Job struct {
+	JobID             int    `json:"job_id" form:"job_id" schema:"job_id"`
+	JobType           string `json:"job_type" form:"job_type" schema:"job_type"`
+	Payload           string `json:"payload" form:"payload" schema:"payload"`
+	Status            string `json:"status" form:"status" schema:"status"`
+	Attempts          int    `json:"attempts" form:"attempts" schema:"attempts"`
+	MaxAttempts       int    `json:"max_attempts" form:"max_attempts" schema:"max_attempts"`
+	RecurringInterval int    `json:"recurring_interval" form:"recurring_interval" schema:"recurring_interval"`
+	RunAt             int64  `json:"run_at" form:"run_at" schema:"run_at"`
+	LastError         string `json:"last_error" form:"last_error" schema:"last_error"`
+	LockedBy          string `json:"locked_by" form:"locked_by" schema:"locked_by"`
+	LockedAt          int64  `json:"locked_at" form:"locked_at" schema:"locked_at"`
+	DateCreated       int64  `json:"date_created" form:"date_created" schema:"date_created"`
+	DateCompleted     int64  `json:"date_completed" form:"date_completed" schema:"date_completed"`
+}
+
+type JobDeadLetter struct {
+	JobDeadLetterID int    `json:"job_dead_letter_id" form:"job_dead_letter_id" schema:"job_dead_letter_id"`
+	JobID           int    `json:"job_id" form:"job_id" schema:"job_id"`
+	JobType         string `json:"job_type" form:"job_type" schema:"job_type"`
+	Payload         string `json:"payload" form:"payload" schema:"payload"`
+	Attempts        int    `json:"attempts" form:"attempts" schema:"attempts"`
+	LastError       string `json:"last_error" form:"last_error" schema:"last_error"`
+	DateFailed      int64  `json:"date_failed" form:"date_failed" schema:"date_failed"`
+	DateRequeued    int64  `json:"date_requeued" form:"date_requeued" schema:"date_requeued"`
+}
+
+type SMSSequence struct {
+	SMSSequenceID       int    `json:"sms_sequence_id" form:"sms_sequence_id" schema:"sms_sequence_id"`
+	Name                string `json:"name" form:"name" schema:"name"`
+	IsActive            bool   `json:"is_active" form:"is_active" schema:"is_active"`
+	ArchiveOnCompletion bool   `json:"archive_on_completion" form:"archive_on_completion" schema:"archive_on_completion"`
+	DateCreated         int64  `json:"date_created" form:"date_created" schema:"date_created"`
+}
+
+type SMSSequenceStep struct {
+	SMSSequenceStepID int    `json:"sms_sequence_step_id" form:"sms_sequence_step_id" schema:"sms_sequence_step_id"`
+	SMSSequenceID     int    `json:"sms_sequence_id" form:"sms_sequence_id" schema:"sms_sequence_id"`
+	StepNumber        int    `json:"step_number" form:"step_number" schema:"step_number"`
+	DelayDays         int    `json:"delay_days" form:"delay_days" schema:"delay_days"`
+	Body              string `json:"body" form:"body" schema:"body"`
+	IsAIGenerated     bool   `json:"is_ai_generated" form:"is_ai_generated" schema:"is_ai_generated"`
+}
+
+type SMSSequenceEnrollment struct {
+	SMSSequenceEnrollmentID int    `json:"sms_sequence_enrollment_id" form:"sms_sequence_enrollment_id" schema:"sms_sequence_enrollment_id"`
+	SMSSequenceID           int    `json:"sms_sequence_id" form:"sms_sequence_id" schema:"sms_sequence_id"`
+	LeadID                  int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	LastStepNumber          int    `json:"last_step_number" form:"last_step_number" schema:"last_step_number"`
+	Status                  string `json:"status" form:"status" schema:"status"`
+	StopReason              string `json:"stop_reason" form:"stop_reason" schema:"stop_reason"`
+	DateEnrolled            int64  `json:"date_enrolled" form:"date_enrolled" schema:"date_enrolled"`
+	DateLastSent            int64  `json:"date_last_sent" form:"date_last_sent" schema:"date_last_sent"`
+	DateStopped             int64  `json:"date_stopped" form:"date_stopped" schema:"date_stopped"`
+}
+
+type SMSConsentEvent struct {
+	SMSConsentEventID int    `json:"sms_consent_event_id" form:"sms_consent_event_id" schema:"sms_consent_event_id"`
+	PhoneNumber       string `json:"phone_number" form:"phone_number" schema:"phone_number"`
+	LeadID            int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	IsOptedIn         bool   `json:"is_opted_in" form:"is_opted_in" schema:"is_opted_in"`
+	Source            string `json:"source" form:"source" schema:"source"`
+	Keyword           string `json:"keyword" form:"keyword" schema:"keyword"`
+	Message           string `json:"message" form:"message" schema:"message"`
+	ExternalID        string `json:"external_id" form:"external_id" schema:"external_id"`
+	DateCreated       int64  `json:"date_created" form:"date_created" schema:"date_created"`
+}
+
+type ScheduledMessage struct {
+	ScheduledMessageID int    `json:"scheduled_message_id" form:"scheduled_message_id" schema:"scheduled_message_id"`
+	LeadID             int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	UserID             int    `json:"user_id" form:"user_id" schema:"user_id"`
+	TextFrom           string `json:"text_from" form:"text_from" schema:"text_from"`
+	TextTo             string `json:"text_to" form:"text_to" schema:"text_to"`
+	Body               string `json:"body" form:"body" schema:"body"`
+	SendAt             int64  `json:"send_at" form:"send_at" schema:"send_at"`
+	Status             string `json:"status" form:"status" schema:"status"`
+	ExternalID         string `json:"external_id" form:"external_id" schema:"external_id"`
+	LastError          string `json:"last_error" form:"last_error" schema:"last_error"`
+	DateCreated        int64  `json:"date_created" form:"date_created" schema:"date_created"`
+	DateSent           int64  `json:"date_sent" form:"date_sent" schema:"date_sent"`
 }
 
 type UnitType struct {
 	UnitTypeID int    `json:"unit_type_id" form:"unit_type_id" schema:"unit_type_id"`
 	Type       string `json:"type" form:"type" schema:"type"`
 }
+
+type Conversation struct {
+	ConversationID int    `json:"conversation_id"`
+	LeadID         int    `json:"lead_id"`
+	AssignedUserID int    `json:"assigned_user_id"`
+	Status         string `json:"status"`
+	SnoozedUntil   int64  `json:"snoozed_until"`
+	DateAssigned   int64  `json:"date_assigned"`
+	DateCreated    int64  `json:"date_created"`
+}
+
+type ConversationNote struct {
+	ConversationNoteID int    `json:"conversation_note_id"`
+	ConversationID     int    `json:"conversation_id"`
+	UserID             int    `json:"user_id"`
+	Note               string `json:"note"`
+	DateCreated        int64  `json:"date_created"`
+}
+
+type CallFlow struct {
+	CallFlowID        int    `json:"call_flow_id"`
+	Greeting          string `json:"greeting"`
+	HasLanguageMenu   bool   `json:"has_language_menu"`
+	RingStrategy      string `json:"ring_strategy"`
+	RingTimeout       int    `json:"ring_timeout"`
+	VoicemailGreeting string `json:"voicemail_greeting"`
+	AfterHoursMessage string `json:"after_hours_message"`
+	DateUpdated       int64  `json:"date_updated"`
+}
+
+type CallFlowBusinessHours struct {
+	CallFlowBusinessHoursID int    `json:"call_flow_business_hours_id"`
+	CallFlowID              int    `json:"call_flow_id"`
+	DayOfWeek               int    `json:"day_of_week"`
+	OpenTime                string `json:"open_time"`
+	CloseTime               string `json:"close_time"`
+}
+
+type CallFlowRecipient struct {
+	CallFlowRecipientID int    `json:"call_flow_recipient_id"`
+	CallFlowID          int    `json:"call_flow_id"`
+	UserID              int    `json:"user_id"`
+	Language            string `json:"language"`
+	RingOrder           int    `json:"ring_order"`
+}
+
+type DiscountCode struct {
+	DiscountCodeID int     `json:"discount_code_id"`
+	Code           string  `json:"code"`
+	DiscountType   string  `json:"discount_type"`
+	Amount         float64 `json:"amount"`
+	ExpiresAt      int64   `json:"expires_at"`
+	MaxRedemptions int     `json:"max_redemptions"`
+	IsActive       bool    `json:"is_active"`
+	DateCreated    int64   `json:"date_created"`
+}
+
+type QuoteDiscount struct {
+	QuoteDiscountID int     `json:"quote_discount_id"`
+	QuoteID         int     `json:"quote_id"`
+	DiscountCodeID  int     `json:"discount_code_id"`
+	Amount          float64 `json:"amount"`
+	DateRedeemed    int64   `json:"date_redeemed"`
+}
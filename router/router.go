@@ -6,11 +6,13 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/handlers"
 	"github.com/davidalvarez305/yd_cocktails/middleware"
+	"github.com/davidalvarez305/yd_cocktails/services"
 )
 
-func Router() *http.ServeMux {
+func Router(srv *handlers.Server) *http.ServeMux {
 	router := http.NewServeMux()
 
 	currentDir, err := os.Getwd()
@@ -21,13 +23,17 @@ func Router() *http.ServeMux {
 
 	router.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(filepath.Join(currentDir, "static")))))
 
-	router.Handle("/crm/", middleware.AuthRequired(http.HandlerFunc(handlers.CRMHandler)))
-	router.HandleFunc("/webhooks/", handlers.WebhookHandler)
-	router.HandleFunc("/external/", handlers.ExternalHandler)
-	router.HandleFunc("/partials/", handlers.PartialsHandler)
-	router.HandleFunc("/sms/", handlers.PhoneServiceHandler)
-	router.HandleFunc("/call/", handlers.PhoneServiceHandler)
-	router.HandleFunc("/", handlers.WebsiteHandler)
+	router.Handle("/crm/", middleware.AuthRequired(http.HandlerFunc(srv.CRMHandler)))
+	router.Handle("/staff/", middleware.AuthRequired(http.HandlerFunc(srv.StaffHandler)))
+	router.HandleFunc("/webhooks/", srv.WebhookHandler)
+	if constants.ProvidersMode == services.FakeProviders {
+		router.HandleFunc("/webhooks/stripe/fake/pay/", srv.FakeInvoicePaymentHandler)
+	}
+	router.HandleFunc("/external/", srv.ExternalHandler)
+	router.HandleFunc("/partials/", srv.PartialsHandler)
+	router.HandleFunc("/sms/", srv.PhoneServiceHandler)
+	router.HandleFunc("/call/", srv.PhoneServiceHandler)
+	router.HandleFunc("/", srv.WebsiteHandler)
 
 	return router
 }
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+func GetAdSpendFromSheets() ([][]string, error) {
+	var rows [][]string
+
+	if constants.AdSpendSpreadsheetID == "" {
+		return rows, fmt.Errorf("ad spend spreadsheet is not configured")
+	}
+
+	resp, err := GetDataFromSheets(constants.AdSpendSpreadsheetID, constants.AdSpendSpreadsheetRange)
+	if err != nil {
+		return rows, fmt.Errorf("unable to retrieve data from sheet: %w", err)
+	}
+
+	for _, values := range resp.Values {
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = fmt.Sprintf("%v", value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
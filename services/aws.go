@@ -20,7 +20,15 @@ import (
 	"github.com/google/uuid"
 )
 
+type awsTranscriber struct{}
+
+type s3ObjectStore struct{}
+
 func UploadFileToS3(file multipart.File, fileSize int64, s3FilePath string) error {
+	return providers.ObjectStore.UploadFile(file, fileSize, s3FilePath)
+}
+
+func (s3ObjectStore) UploadFile(file multipart.File, fileSize int64, s3FilePath string) error {
 	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(constants.AWSRegion))
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
@@ -50,6 +58,10 @@ func UploadFileToS3(file multipart.File, fileSize int64, s3FilePath string) erro
 }
 
 func DownloadFileFromS3(s3FilePath, localFilePath string) (string, error) {
+	return providers.ObjectStore.DownloadFile(s3FilePath, localFilePath)
+}
+
+func (s3ObjectStore) DownloadFile(s3FilePath, localFilePath string) (string, error) {
 	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(constants.AWSRegion))
 	if err != nil {
 		return "", fmt.Errorf("failed to load AWS config: %w", err)
@@ -81,7 +93,34 @@ func DownloadFileFromS3(s3FilePath, localFilePath string) (string, error) {
 	return localFilePath, nil
 }
 
+func PresignS3URL(s3FilePath string, expires time.Duration) (string, error) {
+	return providers.ObjectStore.PresignURL(s3FilePath, expires)
+}
+
+func (s3ObjectStore) PresignURL(s3FilePath string, expires time.Duration) (string, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(constants.AWSRegion))
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewPresignClient(s3.NewFromConfig(cfg))
+
+	request, err := client.PresignGetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(constants.AWSS3BucketName),
+		Key:    aws.String(s3FilePath),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
 func TranscribeAudio(audioFileURL string) (string, string, error) {
+	return providers.Transcriber.TranscribeAudio(audioFileURL)
+}
+
+func (awsTranscriber) TranscribeAudio(audioFileURL string) (string, string, error) {
 	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(constants.AWSRegion))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to load AWS config: %w", err)
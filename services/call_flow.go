@@ -0,0 +1,169 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+const (
+	callFlowMenuPrompt        = "For English, press 1."
+	callFlowSpanishMenuPrompt = "Para español, oprima 2."
+
+	// Twilio stops recording voicemails after this many seconds
+	voicemailMaxLength = 120
+)
+
+func twimlText(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+func twimlResponse(verbs ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><Response>` + strings.Join(verbs, "") + `</Response>`
+}
+
+func twimlSay(text, language string) string {
+	if text == "" {
+		return ""
+	}
+	if language == constants.SpanishCallLanguage {
+		return fmt.Sprintf(`<Say language="es-US">%s</Say>`, twimlText(text))
+	}
+	return fmt.Sprintf(`<Say>%s</Say>`, twimlText(text))
+}
+
+// callFlowNumbers lists who should ring for a caller's language, falling back to the tracking number's
+// forwarding phone when nobody has been added to the call flow yet.
+func callFlowNumbers(flow types.CallFlowDetails, language, fallbackNumber string) []string {
+	var numbers []string
+	for _, recipient := range flow.Recipients {
+		if recipient.PhoneNumber == "" {
+			continue
+		}
+		if recipient.Language == "" || language == "" || recipient.Language == language {
+			numbers = append(numbers, "1"+recipient.PhoneNumber)
+		}
+	}
+
+	if len(numbers) == 0 && fallbackNumber != "" {
+		numbers = append(numbers, fallbackNumber)
+	}
+
+	return numbers
+}
+
+func callFlowDialAction(language string, next int) string {
+	query := url.Values{}
+	if language != "" {
+		query.Set("language", language)
+	}
+	if next > 0 {
+		query.Set("next", strconv.Itoa(next))
+	}
+
+	action := constants.RootDomain + constants.TwilioCallbackWebhook
+	if len(query) > 0 {
+		action += "?" + query.Encode()
+	}
+	return action
+}
+
+func callFlowDial(flow types.CallFlowDetails, numbers []string, language string, next int) string {
+	var ringing []string
+	nextAction := 0
+
+	if flow.RingStrategy == constants.SequentialRingStrategy {
+		ringing = numbers[next : next+1]
+		nextAction = next + 1
+	} else {
+		ringing = numbers
+	}
+
+	var b strings.Builder
+	for _, number := range ringing {
+		fmt.Fprintf(&b, `<Number>%s</Number>`, twimlText(number))
+	}
+
+	recordingCallbackURL := constants.RootDomain + constants.TwilioRecordingCallbackWebhook
+
+	return fmt.Sprintf(`<Dial record="true" timeout="%d" recordingStatusCallback="%s" recordingStatusCallbackEvent="completed" action="%s">%s</Dial>`,
+		flow.RingTimeout, twimlText(recordingCallbackURL), twimlText(callFlowDialAction(language, nextAction)), b.String())
+}
+
+func callFlowVoicemail(flow types.CallFlowDetails) string {
+	recordingCallbackURL := constants.RootDomain + constants.TwilioVoicemailRecordingWebhook
+	voicemailURL := constants.RootDomain + constants.TwilioVoicemailWebhook
+
+	return twimlSay(flow.VoicemailGreeting, "") + fmt.Sprintf(`<Record maxLength="%d" playBeep="true" action="%s" recordingStatusCallback="%s" recordingStatusCallbackEvent="completed" />`,
+		voicemailMaxLength, twimlText(voicemailURL), twimlText(recordingCallbackURL))
+}
+
+// InboundCallTwiML is the first step of the call flow: after hours go straight to voicemail, otherwise the caller
+// hears the greeting and either the language menu or the first ring.
+func InboundCallTwiML(flow types.CallFlowDetails, fallbackNumber string, now time.Time) (string, error) {
+	isOpen, err := helpers.IsWithinBusinessHours(flow.BusinessHours, now)
+	if err != nil {
+		return "", err
+	}
+
+	if !isOpen {
+		return twimlResponse(twimlSay(flow.AfterHoursMessage, ""), callFlowVoicemail(flow)), nil
+	}
+
+	greeting := twimlSay(flow.Greeting, "")
+
+	if flow.HasLanguageMenu {
+		menuURL := constants.RootDomain + constants.TwilioCallMenuWebhook
+		gather := fmt.Sprintf(`<Gather numDigits="1" timeout="6" action="%s">%s%s</Gather>`,
+			twimlText(menuURL), twimlSay(callFlowMenuPrompt, ""), twimlSay(callFlowSpanishMenuPrompt, constants.SpanishCallLanguage))
+
+		// Callers who don't press anything fall through to the English line
+		return twimlResponse(greeting, gather, CallFlowNextStep(flow, constants.EnglishCallLanguage, 0, fallbackNumber)), nil
+	}
+
+	return twimlResponse(greeting, CallFlowNextStep(flow, "", 0, fallbackNumber)), nil
+}
+
+// CallFlowMenuTwiML routes a keypad choice from the language menu.
+func CallFlowMenuTwiML(flow types.CallFlowDetails, digits, fallbackNumber string) string {
+	language := constants.EnglishCallLanguage
+	if digits == "2" {
+		language = constants.SpanishCallLanguage
+	}
+
+	return twimlResponse(CallFlowNextStep(flow, language, 0, fallbackNumber))
+}
+
+// CallFlowNextStep rings the next person in line, or takes a voicemail once everyone has been tried.
+func CallFlowNextStep(flow types.CallFlowDetails, language string, next int, fallbackNumber string) string {
+	numbers := callFlowNumbers(flow, language, fallbackNumber)
+
+	if next < 0 || next >= len(numbers) {
+		return callFlowVoicemail(flow)
+	}
+
+	return callFlowDial(flow, numbers, language, next)
+}
+
+// CallFlowNoAnswerTwiML continues the call flow after a Dial ends without anyone picking up.
+func CallFlowNoAnswerTwiML(flow types.CallFlowDetails, language, next, fallbackNumber string) string {
+	nextIndex, err := strconv.Atoi(next)
+	if err != nil || flow.RingStrategy != constants.SequentialRingStrategy {
+		return twimlResponse(callFlowVoicemail(flow))
+	}
+
+	return twimlResponse(CallFlowNextStep(flow, language, nextIndex, fallbackNumber))
+}
+
+func VoicemailCompleteTwiML() string {
+	return twimlResponse(`<Say>Thank you. Goodbye.</Say><Hangup />`)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+// Open CRM tabs each hold a subscription, so events only reach users connected to this process.
+var crmEventSubscribers = struct {
+	mu   sync.Mutex
+	subs map[chan types.CRMEvent]struct{}
+}{subs: make(map[chan types.CRMEvent]struct{})}
+
+func SubscribeCRMEvents() (<-chan types.CRMEvent, func()) {
+	events := make(chan types.CRMEvent, 16)
+
+	crmEventSubscribers.mu.Lock()
+	crmEventSubscribers.subs[events] = struct{}{}
+	crmEventSubscribers.mu.Unlock()
+
+	unsubscribe := func() {
+		crmEventSubscribers.mu.Lock()
+		delete(crmEventSubscribers.subs, events)
+		crmEventSubscribers.mu.Unlock()
+	}
+
+	return events, unsubscribe
+}
+
+func PublishCRMEvent(event types.CRMEvent) {
+	crmEventSubscribers.mu.Lock()
+	defer crmEventSubscribers.mu.Unlock()
+
+	for events := range crmEventSubscribers.subs {
+		// A slow tab misses the event rather than holding up the webhook
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+// GetRedeemableDiscountCode looks up a code and makes sure it can still be used on a new quote.
+func GetRedeemableDiscountCode(code string) (models.DiscountCode, error) {
+	discountCode, err := stores.Quotes.GetDiscountCodeByCode(helpers.NormalizeDiscountCode(code))
+	if err != nil {
+		return discountCode, fmt.Errorf("discount code %s not found", helpers.NormalizeDiscountCode(code))
+	}
+
+	redemptions, err := stores.Quotes.CountDiscountCodeRedemptions(discountCode.DiscountCodeID, 0)
+	if err != nil {
+		return discountCode, err
+	}
+
+	if err := helpers.ValidateDiscountCode(discountCode, redemptions, time.Now()); err != nil {
+		return discountCode, err
+	}
+
+	return discountCode, nil
+}
+
+// ApplyDiscountCode redeems a code on a quote, replacing any code the quote already had.
+func ApplyDiscountCode(quoteId int, code string) error {
+	discountCode, err := stores.Quotes.GetDiscountCodeByCode(helpers.NormalizeDiscountCode(code))
+	if err != nil {
+		return fmt.Errorf("discount code %s not found", helpers.NormalizeDiscountCode(code))
+	}
+
+	redemptions, err := stores.Quotes.CountDiscountCodeRedemptions(discountCode.DiscountCodeID, quoteId)
+	if err != nil {
+		return err
+	}
+
+	if err := helpers.ValidateDiscountCode(discountCode, redemptions, time.Now()); err != nil {
+		return err
+	}
+
+	amount, err := calculateQuoteDiscount(quoteId, discountCode)
+	if err != nil {
+		return err
+	}
+
+	// The count above only gives a friendly error early, the redemption itself enforces the limit
+	redeemed, err := stores.Quotes.RedeemQuoteDiscount(quoteId, discountCode.DiscountCodeID, amount)
+	if err != nil {
+		return err
+	}
+
+	if !redeemed {
+		return fmt.Errorf("discount code %s has reached its usage limit", discountCode.Code)
+	}
+
+	return nil
+}
+
+// SyncQuoteDiscount recalculates a redeemed discount after the quote's services change. A code that has since
+// expired or hit its limit stays on quotes that already redeemed it.
+func SyncQuoteDiscount(quoteId int) error {
+	discount, err := stores.Quotes.GetQuoteDiscount(quoteId)
+	if err != nil {
+		return fmt.Errorf("error getting quote discount: %w", err)
+	}
+
+	if discount.QuoteDiscountID == 0 {
+		return nil
+	}
+
+	discountCode, err := stores.Quotes.GetDiscountCodeByCode(discount.Code)
+	if err != nil {
+		return fmt.Errorf("error getting discount code: %w", err)
+	}
+
+	amount, err := calculateQuoteDiscount(quoteId, discountCode)
+	if err != nil {
+		amount = 0
+	}
+
+	return stores.Quotes.SetQuoteDiscount(quoteId, discountCode.DiscountCodeID, amount)
+}
+
+func calculateQuoteDiscount(quoteId int, discountCode models.DiscountCode) (float64, error) {
+	serviceTypeIds, err := stores.Quotes.GetDiscountCodeServiceTypeIDs(discountCode.DiscountCodeID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting discount code service types: %w", err)
+	}
+
+	quoteServices, err := stores.Quotes.GetQuoteServices(quoteId)
+	if err != nil {
+		return 0, fmt.Errorf("error getting quote services: %w", err)
+	}
+
+	catalog, err := stores.Quotes.GetServices()
+	if err != nil {
+		return 0, fmt.Errorf("error getting services: %w", err)
+	}
+
+	return helpers.CalculateDiscount(discountCode, serviceTypeIds, quoteServices, catalog)
+}
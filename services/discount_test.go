@@ -0,0 +1,61 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func TestApplyDiscountCodeEnforcesUsageLimitOnRedemption(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+	quoteId, _ := createTestQuote(t, stores, false)
+
+	code, discountType, amount, maxRedemptions := "SPRING", constants.FixedDiscountType, 50.0, 1
+	err := stores.Quotes.CreateDiscountCode(types.DiscountCodeForm{
+		Code:           &code,
+		DiscountType:   &discountType,
+		Amount:         &amount,
+		MaxRedemptions: &maxRedemptions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	discountCode, err := stores.Quotes.GetDiscountCodeByCode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyDiscountCode(quoteId, code); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reapplying to the same quote doesn't use up another redemption
+	if err := ApplyDiscountCode(quoteId, code); err != nil {
+		t.Fatalf("expected the quote to keep its redemption, got %v", err)
+	}
+
+	// A second quote that passed the early check still can't redeem past the limit
+	redeemed, err := stores.Quotes.RedeemQuoteDiscount(quoteId+1000, discountCode.DiscountCodeID, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redeemed {
+		t.Error("expected the redemption past the limit to be refused")
+	}
+
+	redemptions, err := stores.Quotes.CountDiscountCodeRedemptions(discountCode.DiscountCodeID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redemptions != 1 {
+		t.Errorf("got %d redemptions, expected 1", redemptions)
+	}
+
+	err = ApplyDiscountCode(quoteId+1000, code)
+	if err == nil || !strings.Contains(err.Error(), "usage limit") {
+		t.Errorf("expected a usage limit error, got %v", err)
+	}
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/types"
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/webhook"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
+	"google.golang.org/api/sheets/v4"
+)
+
+const cassetteFileName = "cassette.json"
+
+// cassette stores provider responses keyed by call and arguments. Repeated calls with the
+// same arguments are replayed in the order they were recorded.
+type cassette struct {
+	mu        sync.Mutex
+	dir       string
+	recording bool
+	entries   map[string][]json.RawMessage
+	played    map[string]int
+}
+
+func openCassette(dir string, recording bool) (*cassette, error) {
+	c := &cassette{
+		dir:       dir,
+		recording: recording,
+		entries:   make(map[string][]json.RawMessage),
+		played:    make(map[string]int),
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating providers directory: %w", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, cassetteFileName))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	if err := json.Unmarshal(contents, &c.entries); err != nil {
+		return nil, fmt.Errorf("error parsing cassette: %w", err)
+	}
+
+	return c, nil
+}
+
+func cassetteKey(call string, args ...any) string {
+	encoded, _ := json.Marshal(args)
+	sum := sha256.Sum256(encoded)
+	return call + ":" + hex.EncodeToString(sum[:8])
+}
+
+// play decodes the next recorded response for the call into out. When nothing has been
+// recorded, respond is used instead and, in record mode, its result is saved.
+func (c *cassette) play(key string, out any, respond func() (any, error)) error {
+	c.mu.Lock()
+	recorded := c.entries[key]
+	index := c.played[key]
+	c.played[key]++
+	c.mu.Unlock()
+
+	if !c.recording && index < len(recorded) {
+		return json.Unmarshal(recorded[index], out)
+	}
+
+	response, err := respond()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("error encoding provider response: %w", err)
+	}
+
+	if c.recording {
+		if err := c.record(key, encoded); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(encoded, out)
+}
+
+func (c *cassette) record(key string, response json.RawMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = append(c.entries[key], response)
+
+	contents, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cassette: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.dir, cassetteFileName), contents, 0644); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+
+	return nil
+}
+
+func newFakeProviders(c *cassette, live Providers) Providers {
+	return Providers{
+		Messenger:    fakeMessenger{cassette: c, live: live.Messenger},
+		Payments:     &fakePaymentProvider{cassette: c, live: live.Payments, invoices: make(map[string]stripe.Invoice)},
+		LLM:          fakeLLM{cassette: c, live: live.LLM},
+		ObjectStore:  fakeObjectStore{dir: filepath.Join(c.dir, "objects"), live: live.ObjectStore},
+		Transcriber:  fakeTranscriber{cassette: c, live: live.Transcriber},
+		Mailer:       fakeMailer{cassette: c, live: live.Mailer},
+		Spreadsheets: fakeSpreadsheets{cassette: c, live: live.Spreadsheets},
+	}
+}
+
+func fakeID(prefix string) string {
+	return prefix + strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+type fakeMessenger struct {
+	cassette *cassette
+	live     Messenger
+}
+
+func (f fakeMessenger) SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error) {
+	var text openapi.ApiV2010Message
+
+	err := f.cassette.play(cassetteKey("messenger.SendTextMessage", to, from, body), &text, func() (any, error) {
+		if f.live != nil {
+			return f.live.SendTextMessage(to, from, body)
+		}
+
+		fmt.Printf("FAKE SMS FROM %s TO %s: %s\n", from, to, body)
+
+		sid := fakeID("SM")
+		status := "delivered"
+		toNumber, fromNumber := "+1"+to, "+1"+from
+		return openapi.ApiV2010Message{Sid: &sid, Status: &status, To: &toNumber, From: &fromNumber, Body: &body}, nil
+	})
+
+	return text, err
+}
+
+func (f fakeMessenger) SendMediaMessage(to, from, body string, mediaURLs []string) (openapi.ApiV2010Message, error) {
+	var text openapi.ApiV2010Message
+
+	err := f.cassette.play(cassetteKey("messenger.SendMediaMessage", to, from, body, mediaURLs), &text, func() (any, error) {
+		if f.live != nil {
+			return f.live.SendMediaMessage(to, from, body, mediaURLs)
+		}
+
+		fmt.Printf("FAKE MMS FROM %s TO %s: %s %v\n", from, to, body, mediaURLs)
+
+		sid := fakeID("MM")
+		status := "delivered"
+		numMedia := fmt.Sprint(len(mediaURLs))
+		toNumber, fromNumber := "+1"+to, "+1"+from
+		return openapi.ApiV2010Message{Sid: &sid, Status: &status, To: &toNumber, From: &fromNumber, Body: &body, NumMedia: &numMedia}, nil
+	})
+
+	return text, err
+}
+
+func (f fakeMessenger) InitiateOutboundCall(from, twiML string) (openapi.ApiV2010Call, error) {
+	var call openapi.ApiV2010Call
+
+	err := f.cassette.play(cassetteKey("messenger.InitiateOutboundCall", from, twiML), &call, func() (any, error) {
+		if f.live != nil {
+			return f.live.InitiateOutboundCall(from, twiML)
+		}
+
+		fmt.Printf("FAKE OUTBOUND CALL FROM %s\n", from)
+
+		sid := fakeID("CA")
+		status := "queued"
+		return openapi.ApiV2010Call{Sid: &sid, Status: &status}, nil
+	})
+
+	return call, err
+}
+
+func (f fakeMessenger) DownloadFile(fileURL, localFilePath string) error {
+	if f.live != nil {
+		return f.live.DownloadFile(fileURL, localFilePath)
+	}
+
+	return os.WriteFile(localFilePath, []byte("fake media downloaded from "+fileURL), 0644)
+}
+
+func (f fakeMessenger) DeleteCallRecording(callRecordingSid string) error {
+	if f.live != nil {
+		return f.live.DeleteCallRecording(callRecordingSid)
+	}
+
+	fmt.Printf("FAKE RECORDING DELETED: %s\n", callRecordingSid)
+	return nil
+}
+
+// fakePaymentProvider keeps the invoices it issues so they can be looked up and paid later.
+type fakePaymentProvider struct {
+	cassette *cassette
+	live     PaymentProvider
+	mu       sync.Mutex
+	invoices map[string]stripe.Invoice
+}
+
+func (f *fakePaymentProvider) save(inv stripe.Invoice) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.invoices[inv.ID] = inv
+}
+
+func (f *fakePaymentProvider) newInvoice(customerId string, amount, discount float64, dueDate int64) stripe.Invoice {
+	if customerId == "" {
+		customerId = fakeID("cus_")
+	}
+
+	id := fakeID("in_")
+
+	var discounts []*stripe.InvoiceTotalDiscountAmount
+	amountOff := int64(math.Round(discount * 100))
+	if amountOff > 0 {
+		discounts = append(discounts, &stripe.InvoiceTotalDiscountAmount{Amount: amountOff})
+	}
+
+	return stripe.Invoice{
+		ID:                   id,
+		Customer:             &stripe.Customer{ID: customerId},
+		Status:               stripe.InvoiceStatusOpen,
+		AmountDue:            int64(math.Round(amount*100)) - amountOff,
+		TotalDiscountAmounts: discounts,
+		DueDate:              dueDate,
+		Currency:             stripe.CurrencyUSD,
+		HostedInvoiceURL:     constants.RootDomain + "/webhooks/stripe/fake/pay/" + id,
+		Created:              time.Now().Unix(),
+	}
+}
+
+func (f *fakePaymentProvider) CreateInvoice(params types.CreateInvoiceParams) (stripe.Invoice, error) {
+	var inv stripe.Invoice
+
+	err := f.cassette.play(cassetteKey("payments.CreateInvoice", params), &inv, func() (any, error) {
+		if f.live != nil {
+			return f.live.CreateInvoice(params)
+		}
+		return f.newInvoice(params.StripeCustomerID, params.Quote, params.Discount, params.DueDate), nil
+	})
+	if err != nil {
+		return inv, err
+	}
+
+	f.save(inv)
+	return inv, nil
+}
+
+func (f *fakePaymentProvider) UpdateInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoice, error) {
+	var inv stripe.Invoice
+
+	err := f.cassette.play(cassetteKey("payments.UpdateInvoice", leadQuoteInvoice), &inv, func() (any, error) {
+		if f.live != nil {
+			return f.live.UpdateInvoice(leadQuoteInvoice)
+		}
+
+		f.mu.Lock()
+		if original, ok := f.invoices[leadQuoteInvoice.StripeInvoiceID]; ok {
+			original.Status = stripe.InvoiceStatusVoid
+			f.invoices[original.ID] = original
+		}
+		f.mu.Unlock()
+
+		return f.newInvoice(leadQuoteInvoice.StripeCustomerID, leadQuoteInvoice.Amount*leadQuoteInvoice.InvoiceTypeMultiplier, leadQuoteInvoice.Discount*leadQuoteInvoice.InvoiceTypeMultiplier, leadQuoteInvoice.DueDate), nil
+	})
+	if err != nil {
+		return inv, err
+	}
+
+	f.save(inv)
+	return inv, nil
+}
+
+func (f *fakePaymentProvider) GetInvoice(stripeInvoiceId string) (stripe.Invoice, error) {
+	f.mu.Lock()
+	inv, ok := f.invoices[stripeInvoiceId]
+	f.mu.Unlock()
+
+	if ok {
+		return inv, nil
+	}
+
+	err := f.cassette.play(cassetteKey("payments.GetInvoice", stripeInvoiceId), &inv, func() (any, error) {
+		if f.live != nil {
+			return f.live.GetInvoice(stripeInvoiceId)
+		}
+		return nil, fmt.Errorf("invoice with stripeInvoiceId %s not found", stripeInvoiceId)
+	})
+
+	return inv, err
+}
+
+// SimulateInvoicePayment marks a fake invoice as paid and returns a signed
+// invoice.payment_succeeded webhook payload along with its Stripe-Signature header.
+func SimulateInvoicePayment(stripeInvoiceId string) ([]byte, string, error) {
+	payments, ok := providers.Payments.(*fakePaymentProvider)
+	if !ok || payments.live != nil {
+		return nil, "", fmt.Errorf("invoice payments can only be simulated with fake providers")
+	}
+
+	inv, err := payments.GetInvoice(stripeInvoiceId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	inv.Status = stripe.InvoiceStatusPaid
+	inv.Paid = true
+	inv.AmountPaid = inv.AmountDue
+	payments.save(inv)
+
+	raw, err := json.Marshal(inv)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding invoice: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"id":          fakeID("evt_"),
+		"object":      "event",
+		"type":        "invoice.payment_succeeded",
+		"api_version": stripe.APIVersion,
+		"created":     time.Now().Unix(),
+		"data":        map[string]json.RawMessage{"object": raw},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding event: %w", err)
+	}
+
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload: payload,
+		Secret:  constants.StripeWebhookSecret,
+	})
+
+	return signed.Payload, signed.Header, nil
+}
+
+type fakeLLM struct {
+	cassette *cassette
+	live     LLM
+}
+
+func (f fakeLLM) Complete(prompt string, maxTokens int) (string, error) {
+	var completion string
+
+	err := f.cassette.play(cassetteKey("llm.Complete", prompt, maxTokens), &completion, func() (any, error) {
+		if f.live != nil {
+			return f.live.Complete(prompt, maxTokens)
+		}
+
+		excerpt := prompt
+		if len(excerpt) > 120 {
+			excerpt = excerpt[:120] + "..."
+		}
+		return "Offline response for: " + excerpt, nil
+	})
+
+	return completion, err
+}
+
+// fakeObjectStore writes uploads to a local directory instead of the bucket.
+type fakeObjectStore struct {
+	dir  string
+	live ObjectStore
+}
+
+func (f fakeObjectStore) UploadFile(file multipart.File, fileSize int64, s3FilePath string) error {
+	if f.live != nil {
+		return f.live.UploadFile(file, fileSize, s3FilePath)
+	}
+
+	localPath := filepath.Join(f.dir, filepath.FromSlash(s3FilePath))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	outFile, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %w", err)
+	}
+	defer outFile.Close()
+
+	if _, err := io.CopyN(outFile, file, fileSize); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return nil
+}
+
+func (f fakeObjectStore) DownloadFile(s3FilePath, localFilePath string) (string, error) {
+	if f.live != nil {
+		return f.live.DownloadFile(s3FilePath, localFilePath)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(f.dir, filepath.FromSlash(s3FilePath)))
+	if err != nil {
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+
+	if err := os.WriteFile(localFilePath, contents, 0644); err != nil {
+		return "", fmt.Errorf("failed to save file locally: %w", err)
+	}
+
+	return localFilePath, nil
+}
+
+func (f fakeObjectStore) PresignURL(s3FilePath string, expires time.Duration) (string, error) {
+	if f.live != nil {
+		return f.live.PresignURL(s3FilePath, expires)
+	}
+
+	return "file://" + filepath.ToSlash(filepath.Join(f.dir, filepath.FromSlash(s3FilePath))), nil
+}
+
+type fakeTranscriber struct {
+	cassette *cassette
+	live     Transcriber
+}
+
+func (f fakeTranscriber) TranscribeAudio(audioFileURL string) (string, string, error) {
+	var transcription struct {
+		JobName string `json:"job_name"`
+		Text    string `json:"text"`
+	}
+
+	err := f.cassette.play(cassetteKey("transcriber.TranscribeAudio", audioFileURL), &transcription, func() (any, error) {
+		if f.live != nil {
+			jobName, text, err := f.live.TranscribeAudio(audioFileURL)
+			return map[string]string{"job_name": jobName, "text": text}, err
+		}
+		return map[string]string{"job_name": uuid.New().String(), "text": "Offline transcription of " + audioFileURL}, nil
+	})
+
+	return transcription.JobName, transcription.Text, err
+}
+
+type fakeMailer struct {
+	cassette *cassette
+	live     Mailer
+}
+
+func (f fakeMailer) SendMail(recipients []string, subject, sender, body string) error {
+	var sent bool
+
+	return f.cassette.play(cassetteKey("mailer.SendMail", recipients, subject, sender, body), &sent, func() (any, error) {
+		if f.live != nil {
+			return true, f.live.SendMail(recipients, subject, sender, body)
+		}
+
+		fmt.Printf("FAKE EMAIL TO %s: %s\n", strings.Join(recipients, ", "), subject)
+		return true, nil
+	})
+}
+
+type fakeSpreadsheets struct {
+	cassette *cassette
+	live     Spreadsheets
+}
+
+func (f fakeSpreadsheets) GetValues(spreadsheetId, spreadsheetRange string) (*sheets.ValueRange, error) {
+	var values sheets.ValueRange
+
+	err := f.cassette.play(cassetteKey("spreadsheets.GetValues", spreadsheetId, spreadsheetRange), &values, func() (any, error) {
+		if f.live != nil {
+			return f.live.GetValues(spreadsheetId, spreadsheetRange)
+		}
+		return sheets.ValueRange{}, nil
+	})
+
+	return &values, err
+}
@@ -18,6 +18,8 @@ import (
 	"google.golang.org/api/sheets/v4"
 )
 
+type gmailMailer struct{}
+
 func refreshAuthToken(config *oauth2.Config) (oauth2.Token, error) {
 	var token oauth2.Token
 
@@ -78,6 +80,10 @@ func initializeGoogleClient(scope string) (*http.Client, error) {
 }
 
 func SendGmail(recipients []string, subject, sender, body string) error {
+	return providers.Mailer.SendMail(recipients, subject, sender, body)
+}
+
+func (gmailMailer) SendMail(recipients []string, subject, sender, body string) error {
 	client, err := initializeGoogleClient(gmail.GmailSendScope)
 	if err != nil {
 		fmt.Printf("Unable to initialize Gmail client: %v", err)
@@ -106,7 +112,13 @@ func SendGmail(recipients []string, subject, sender, body string) error {
 	return nil
 }
 
+type googleSpreadsheets struct{}
+
 func GetDataFromSheets(spreadsheetId, spreadsheetRange string) (*sheets.ValueRange, error) {
+	return providers.Spreadsheets.GetValues(spreadsheetId, spreadsheetRange)
+}
+
+func (googleSpreadsheets) GetValues(spreadsheetId, spreadsheetRange string) (*sheets.ValueRange, error) {
 	var values sheets.ValueRange
 
 	client, err := initializeGoogleClient(sheets.SpreadsheetsScope)
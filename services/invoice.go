@@ -5,34 +5,39 @@ import (
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/types"
 )
 
 func UpdateInvoicesWorkflow(quoteId int, eventDate int64) error {
 	// Get stripe invoices
-	leadQuoteInvoices, err := database.GetLeadQuoteInvoices(quoteId)
+	leadQuoteInvoices, err := stores.Invoices.GetLeadQuoteInvoices(quoteId)
 	if err != nil {
 		fmt.Printf("ERROR GETTING QUOTE INVOICES: %+v\n", err)
 		return err
 	}
 
-	isDepositPaid, err := database.IsDepositPaid(quoteId)
+	isDepositPaid, err := stores.Invoices.IsDepositPaid(quoteId)
 	if err != nil {
 		fmt.Printf("ERROR CHECKING IF DEPOSIT IS PAID: %+v\n", err)
 		return err
 	}
 
+	discount, err := stores.Quotes.GetQuoteDiscount(quoteId)
+	if err != nil {
+		fmt.Printf("ERROR GETTING QUOTE DISCOUNT: %+v\n", err)
+		return err
+	}
+
 	var remainingInvoice types.LeadQuoteInvoice
 
 	if isDepositPaid {
-		remainingInvoice, err = database.GetRemainingInvoice(quoteId)
+		remainingInvoice, err = stores.Invoices.GetRemainingInvoice(quoteId)
 		if err != nil {
 			fmt.Printf("ERROR GETTING REMAINING INVOICE: %+v\n", err)
 			return err
 		}
 
-		depositStripeInvoiceId, err := database.GetDepositStripeInvoiceID(quoteId)
+		depositStripeInvoiceId, err := stores.Invoices.GetDepositStripeInvoiceID(quoteId)
 		if err != nil {
 			fmt.Printf("ERROR GETTING DEPOSIT STRIPE INVOICE ID: %+v\n", err)
 			return err
@@ -47,7 +52,12 @@ func UpdateInvoicesWorkflow(quoteId int, eventDate int64) error {
 		// Subtract from the invoice amount what was already deducted from the deposit
 		// This way the only thing that changes is the amount for which the invoice was updated by
 		// The customer now only has to pay the difference between the new invoice amount and the deposit that was paid
-		remainingInvoice.Amount = remainingInvoice.Amount - float64(depositInvoice.AmountPaid/100) // convert to cents before division
+		remainingInvoice.Amount = remainingInvoice.Amount - float64(depositInvoice.AmountPaid)/100
+
+		// The deposit's discount was already left out of what was paid, so subtracting the payment
+		// adds it back here and the full discount comes off the remaining invoice
+		remainingInvoice.Discount = discount.Amount
+		remainingInvoice.DiscountCode = discount.Code
 	}
 
 	for _, leadQuoteInvoice := range leadQuoteInvoices {
@@ -55,6 +65,9 @@ func UpdateInvoicesWorkflow(quoteId int, eventDate int64) error {
 		// In this way, we can continue this flow as usual, only changing the logic beforehand
 		if isDepositPaid {
 			leadQuoteInvoice = remainingInvoice
+		} else {
+			leadQuoteInvoice.Discount = discount.Amount
+			leadQuoteInvoice.DiscountCode = discount.Code
 		}
 
 		// Calculate new due date
@@ -79,14 +92,14 @@ func UpdateInvoicesWorkflow(quoteId int, eventDate int64) error {
 		}
 
 		// Set old invoice status to void
-		err = database.UpdateInvoiceStatus(leadQuoteInvoice.StripeInvoiceID, constants.VoidInvoiceStatusID)
+		err = stores.Invoices.UpdateInvoiceStatus(leadQuoteInvoice.StripeInvoiceID, constants.VoidInvoiceStatusID)
 		if err != nil {
 			fmt.Printf("ERROR UPDATING INVOICE STATUS: %+v\n", err)
 			return err
 		}
 
 		// Create new invoice with status open
-		err = database.CreateQuoteInvoice(invoice.ID, invoice.HostedInvoiceURL, quoteId, leadQuoteInvoice.InvoiceTypeID, invoice.DueDate)
+		err = stores.Invoices.CreateQuoteInvoice(invoice.ID, invoice.HostedInvoiceURL, quoteId, leadQuoteInvoice.InvoiceTypeID, invoice.DueDate)
 		if err != nil {
 			fmt.Printf("ERROR CREATING INVOICE: %+v\n", err)
 			return err
@@ -97,7 +110,13 @@ func UpdateInvoicesWorkflow(quoteId int, eventDate int64) error {
 }
 
 func CreateInvoiceWorkflow(quote types.QuoteDetails) error {
-	invoiceTypes, err := database.GetInvoiceTypes()
+	invoiceTypes, err := stores.Invoices.GetInvoiceTypes()
+	if err != nil {
+		fmt.Printf("ERROR DURING INVOICE WORKFLOW: %+v\n", err)
+		return err
+	}
+
+	discount, err := stores.Quotes.GetQuoteDiscount(quote.QuoteID)
 	if err != nil {
 		fmt.Printf("ERROR DURING INVOICE WORKFLOW: %+v\n", err)
 		return err
@@ -126,6 +145,8 @@ func CreateInvoiceWorkflow(quote types.QuoteDetails) error {
 			PhoneNumber:      quote.PhoneNumber,
 			DueDate:          invoiceDueDate,
 			Quote:            quote.Amount * invoiceType.AmountPercentage,
+			Discount:         discount.Amount * invoiceType.AmountPercentage,
+			DiscountCode:     discount.Code,
 		}
 
 		createdInvoice, err := CreateStripeInvoice(createInvoiceParams)
@@ -134,7 +155,7 @@ func CreateInvoiceWorkflow(quote types.QuoteDetails) error {
 			return err
 		}
 
-		err = database.CreateQuoteInvoice(createdInvoice.ID, createdInvoice.HostedInvoiceURL, quote.QuoteID, invoiceType.InvoiceTypeID, invoiceDueDate)
+		err = stores.Invoices.CreateQuoteInvoice(createdInvoice.ID, createdInvoice.HostedInvoiceURL, quote.QuoteID, invoiceType.InvoiceTypeID, invoiceDueDate)
 		if err != nil {
 			fmt.Printf("ERROR DURING INVOICE WORKFLOW: %+v\n", err)
 			return err
@@ -146,7 +167,7 @@ func CreateInvoiceWorkflow(quote types.QuoteDetails) error {
 		}
 	}
 
-	err = database.AssignStripeCustomerIDToLead(stripeCustomerId, quote.LeadID)
+	err = stores.Leads.AssignStripeCustomerIDToLead(stripeCustomerId, quote.LeadID)
 	if err != nil {
 		fmt.Printf("ERROR DURING INVOICE WORKFLOW: %+v\n", err)
 		return err
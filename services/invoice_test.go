@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+)
+
+func openDepositStripeInvoiceID(t *testing.T, stores database.Stores, quoteId int) string {
+	t.Helper()
+
+	invoices, err := stores.Invoices.GetLeadQuoteInvoices(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invoice := range invoices {
+		if invoice.InvoiceTypeID == constants.DepositInvoiceTypeID {
+			return invoice.StripeInvoiceID
+		}
+	}
+
+	t.Fatal("expected an open deposit invoice")
+	return ""
+}
+
+func TestUpdateInvoicesWorkflowAppliesFullDiscountAfterDepositIsPaid(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+	quoteId, _ := createTestQuote(t, stores, false)
+
+	// $800 quote with an $80.20 discount: the deposit bills $200 - $20.05 = $179.95
+	if err := stores.Quotes.SetQuoteDiscount(quoteId, 0, 80.20); err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := stores.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err := stores.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(quote.LeadID), fmt.Sprint(quoteId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateInvoiceWorkflow(details); err != nil {
+		t.Fatal(err)
+	}
+
+	depositStripeInvoiceId := openDepositStripeInvoiceID(t, stores, quoteId)
+	if _, _, err := SimulateInvoicePayment(depositStripeInvoiceId); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Invoices.SetInvoiceStatusToPaid(depositStripeInvoiceId, time.Now().Unix()); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores.Invoices.VoidFullInvoice(quoteId); err != nil {
+		t.Fatal(err)
+	}
+
+	deposit, err := GetStripeInvoice(depositStripeInvoiceId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deposit.AmountPaid != 17995 {
+		t.Fatalf("expected the deposit to be paid 17995 cents, got %d", deposit.AmountPaid)
+	}
+
+	if err := UpdateInvoicesWorkflow(quoteId, quote.EventDate); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := stores.Invoices.GetRemainingInvoice(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining.InvoiceTypeID != constants.RemainingInvoiceTypeID {
+		t.Fatal("expected an open remaining invoice")
+	}
+
+	reissued, err := GetStripeInvoice(remaining.StripeInvoiceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// $800 - $179.95 paid - $80.20 discount, so the customer pays $719.80 in all
+	if reissued.AmountDue != 53985 {
+		t.Errorf("expected the remaining invoice to be 53985 cents, got %d", reissued.AmountDue)
+	}
+}
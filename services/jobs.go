@@ -0,0 +1,164 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+type jobHandler func(payload []byte) error
+
+var jobHandlers map[string]jobHandler
+
+// Registered in init because handlers that queue follow-up jobs refer back to jobHandlers through EnqueueJob
+func init() {
+	jobHandlers = map[string]jobHandler{
+		constants.CheckLeadSpreadsheetsJob:      func([]byte) error { return checkSpreadsheets() },
+		constants.ArchiveUnresponsiveLeadsJob:   func([]byte) error { return archiveUnresponsiveLeads() },
+		constants.UnreadMessagesNotificationJob: func([]byte) error { return checkSMS() },
+		constants.PhoneCallTranscriptionJob:     func([]byte) error { return checkPhoneCallTranscription() },
+		constants.SMSSequenceJob:                func([]byte) error { return sendDueSMSSequenceSteps() },
+		constants.SendScheduledMessageJob:       sendScheduledMessage,
+		constants.VoicemailTranscriptionJob:     transcribeVoicemail,
+	}
+}
+
+// jobDeadLetterHandlers clean up after a job that ran out of attempts, given its payload and last error.
+var jobDeadLetterHandlers = map[string]func(payload []byte, lastError string) error{
+	constants.SendScheduledMessageJob: failScheduledMessage,
+}
+
+var recurringJobs = []struct {
+	jobType  string
+	interval time.Duration
+}{
+	{constants.CheckLeadSpreadsheetsJob, 1 * time.Minute},
+	{constants.ArchiveUnresponsiveLeadsJob, 24 * time.Hour},
+	{constants.UnreadMessagesNotificationJob, 5 * time.Minute},
+	{constants.PhoneCallTranscriptionJob, 5 * time.Minute},
+	{constants.SMSSequenceJob, 5 * time.Minute},
+}
+
+const (
+	jobPollInterval = 5 * time.Second
+	jobBaseBackoff  = 30 * time.Second
+	jobMaxBackoff   = 1 * time.Hour
+)
+
+// EnqueueJob schedules a one-off job. The payload is stored as JSON and handed back to the job's handler.
+func EnqueueJob(jobType string, payload any, runAt time.Time) error {
+	if _, ok := jobHandlers[jobType]; !ok {
+		return fmt.Errorf("no handler registered for job type: %s", jobType)
+	}
+
+	data := []byte("{}")
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error marshaling job payload: %w", err)
+		}
+		data = encoded
+	}
+
+	return stores.Jobs.EnqueueJob(models.Job{
+		JobType:     jobType,
+		Payload:     string(data),
+		MaxAttempts: constants.DefaultJobMaxAttempts,
+		RunAt:       runAt.Unix(),
+	})
+}
+
+// jobBackoff doubles the wait after every failed attempt, starting at 30 seconds and capped at an hour.
+func jobBackoff(attempts int) time.Duration {
+	backoff := jobBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= jobMaxBackoff {
+			return jobMaxBackoff
+		}
+	}
+	return backoff
+}
+
+func runJob(job models.Job) (err error) {
+	handler, ok := jobHandlers[job.JobType]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type: %s", job.JobType)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler([]byte(job.Payload))
+}
+
+// processNextJob runs a single due job and reports whether one was found.
+func processNextJob(workerID string) bool {
+	job, err := stores.Jobs.ClaimNextJob(workerID)
+	if err != nil {
+		fmt.Printf("ERROR CLAIMING JOB: %+v\n", err)
+		return false
+	}
+
+	if job == nil {
+		return false
+	}
+
+	err = runJob(*job)
+	if err == nil {
+		if err := stores.Jobs.CompleteJob(job.JobID); err != nil {
+			fmt.Printf("ERROR COMPLETING JOB %d: %+v\n", job.JobID, err)
+		}
+		return true
+	}
+
+	fmt.Printf("ERROR RUNNING JOB %d (%s): %+v\n", job.JobID, job.JobType, err)
+
+	if job.Attempts >= job.MaxAttempts {
+		if err := stores.Jobs.DeadLetterJob(job.JobID, err.Error()); err != nil {
+			fmt.Printf("ERROR DEAD LETTERING JOB %d: %+v\n", job.JobID, err)
+		}
+
+		if onDeadLetter, ok := jobDeadLetterHandlers[job.JobType]; ok {
+			if err := onDeadLetter([]byte(job.Payload), err.Error()); err != nil {
+				fmt.Printf("ERROR CLEANING UP DEAD LETTERED JOB %d: %+v\n", job.JobID, err)
+			}
+		}
+		return true
+	}
+
+	retryAt := time.Now().Add(jobBackoff(job.Attempts)).Unix()
+	if err := stores.Jobs.RescheduleJob(job.JobID, err.Error(), retryAt); err != nil {
+		fmt.Printf("ERROR RESCHEDULING JOB %d: %+v\n", job.JobID, err)
+	}
+	return true
+}
+
+// StartJobQueue registers the recurring jobs and starts polling for due jobs. Several instances can run
+// at once since every claim locks its row with SKIP LOCKED.
+func StartJobQueue() {
+	for _, job := range recurringJobs {
+		err := stores.Jobs.ScheduleRecurringJob(job.jobType, int(job.interval.Seconds()), constants.DefaultJobMaxAttempts)
+		if err != nil {
+			fmt.Printf("ERROR SCHEDULING RECURRING JOB %s: %+v\n", job.jobType, err)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	workerID := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+
+	go func() {
+		for {
+			if !processNextJob(workerID) {
+				time.Sleep(jobPollInterval)
+			}
+		}
+	}()
+}
@@ -1,20 +1,23 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/types"
+	"github.com/davidalvarez305/yd_cocktails/utils"
 )
 
-func checkSpreadsheets() {
+var ErrStaffPhoneNumber = errors.New("phone number belongs to a user")
+
+func checkSpreadsheets() error {
 	resp, err := GetDataFromSheets(constants.FacebookLeadsSpreadsheetID, constants.FacebookLeadsSpreadsheetRange)
 	if err != nil {
-		fmt.Printf("Unable to retrieve data from sheet: %v", err)
-		return
+		return fmt.Errorf("unable to retrieve data from sheet: %w", err)
 	}
 
 	var leads []types.FacebookInstantFormLead
@@ -62,14 +65,14 @@ func checkSpreadsheets() {
 			continue
 		}
 
-		exists, err := database.IsPhoneNumberInDB(phoneNumber)
+		exists, err := stores.Leads.IsPhoneNumberInDB(phoneNumber)
 
 		// Skip if lead has already been saved before
 		if exists || err != nil {
 			continue
 		}
 
-		_, err = database.CreateLeadAndMarketing(form)
+		_, err = stores.Leads.CreateLeadAndMarketing(form)
 
 		if err != nil {
 			fmt.Printf("ERROR CREATING FB LEAD FOR: %+v. MESSAGE: %+v\n", lead, err)
@@ -90,36 +93,110 @@ func checkSpreadsheets() {
 				Message: %s
 			`, lead.PhoneNumber, lead.FullName, lead.EventDescription)
 
+			// Staff notifications skip quiet hours and stay out of the lead's thread
 			_, err := SendTextMessage(phoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
-
-			fmt.Printf("ERROR SENDING FB LEAD AD NOTIFICATION MSG: %+v\n", err)
+			if err != nil {
+				fmt.Printf("ERROR SENDING FB LEAD AD NOTIFICATION MSG: %+v\n", err)
+			}
 		}
 	}
-}
 
-func archiveUnresponsiveLeads() {
-	err := database.ArchivedLeadsWithLastContactOverTwoWeeks()
+	return nil
+}
 
+func archiveUnresponsiveLeads() error {
+	err := stores.Leads.ArchivedLeadsWithLastContactOverTwoWeeks()
 	if err != nil {
-		fmt.Printf("ERROR ARCHIVING UNRESPONSIVE LEADS: %+v\n", err)
+		return fmt.Errorf("error archiving unresponsive leads: %w", err)
 	}
+
+	return nil
 }
 
-func StartLeadChecker() {
-	go func() {
-		for {
-			checkSpreadsheets()
+// NotifyNewLead emails the company and texts the notification subscribers about a lead that just came in.
+func NotifyNewLead(leadID int, form types.QuoteForm) error {
+	subject := "YD Cocktails: New Lead"
+	recipients := []string{constants.CompanyEmail}
+	templateFile := constants.PARTIAL_TEMPLATES_DIR + "new_lead_notification_email.html"
+
+	var notificationTemplateData = map[string]any{
+		"Name":           helpers.SafeString(form.FullName),
+		"PhoneNumber":    helpers.SafeString(form.PhoneNumber),
+		"DateCreated":    utils.FormatTimestampWithOptions(helpers.SafeInt64(form.CreatedAt), &types.TimestampFormatOptions{TimeZone: constants.TimeZone}),
+		"ButtonClicked":  helpers.SafeString(form.ButtonClicked),
+		"Message":        helpers.SafeString(form.Message),
+		"LeadDetailsURL": fmt.Sprintf("%s/crm/lead/%d", constants.RootDomain, leadID),
+		"Location":       "",
+	}
 
-			// Sleep for one minute before the next run
-			time.Sleep(1 * time.Minute)
-		}
-	}()
+	if helpers.SafeString(form.Longitude) != "0.0" && len(helpers.SafeString(form.Longitude)) > 0 || helpers.SafeString(form.Latitude) != "0.0" && len(helpers.SafeString(form.Latitude)) > 0 {
+		notificationTemplateData["Location"] = fmt.Sprintf("https://www.google.com/maps?q=%s,%s", helpers.SafeString(form.Latitude), helpers.SafeString(form.Longitude))
+	}
 
-	go func() {
-		for {
-			archiveUnresponsiveLeads()
+	template, err := helpers.BuildStringFromTemplate(templateFile, "email", notificationTemplateData)
+	if err != nil {
+		return fmt.Errorf("error building lead notification template: %w", err)
+	}
+
+	body := fmt.Sprintf("Content-Type: text/html; charset=UTF-8\r\n%s", template)
+	err = SendGmail(recipients, subject, constants.CompanyEmail, body)
+	if err != nil {
+		return fmt.Errorf("error sending lead notification email: %w", err)
+	}
 
-			time.Sleep(24 * time.Hour)
+	for _, phoneNumber := range constants.NotificationSubscribers {
+
+		var textMessageTemplateNotification = fmt.Sprintf(
+			`NEW LEAD:
+
+			Phone: %s,
+			Full Name: %s,
+			Message: %s
+		`, helpers.SafeString(form.PhoneNumber), helpers.SafeString(form.FullName), helpers.SafeString(form.Message))
+
+		_, err := SendTextMessage(phoneNumber, constants.CompanyPhoneNumber, textMessageTemplateNotification)
+		if err != nil {
+			fmt.Printf("ERROR SENDING NEW LEAD NOTIFICATION MSG: %+v\n", err)
 		}
-	}()
+	}
+
+	return nil
+}
+
+// GetOrCreateLeadFromPhoneNumber returns the lead behind a phone number, creating one for numbers that called or texted
+// a tracking number before ever filling out a form. Staff numbers never become leads.
+func GetOrCreateLeadFromPhoneNumber(phoneNumber, source, trackingPhoneNumber string) (int, error) {
+	_, err := stores.Users.GetUserIDFromPhoneNumber(phoneNumber)
+	if err == nil {
+		return 0, ErrStaffPhoneNumber
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("error checking user phone number: %w", err)
+	}
+
+	createdAt := time.Now().Unix()
+
+	form := types.QuoteForm{
+		FullName:            &phoneNumber,
+		PhoneNumber:         &phoneNumber,
+		CreatedAt:           &createdAt,
+		Source:              &source,
+		TrackingPhoneNumber: &trackingPhoneNumber,
+	}
+
+	leadId, created, err := stores.Leads.GetOrCreateLeadFromPhoneNumber(form)
+	if err != nil {
+		return 0, fmt.Errorf("error getting or creating lead from phone number: %w", err)
+	}
+
+	if created && constants.Production {
+		go func() {
+			if err := NotifyNewLead(leadId, form); err != nil {
+				fmt.Printf("ERROR SENDING LEAD NOTIFICATION: %+v\n", err)
+			}
+		}()
+	}
+
+	return leadId, nil
 }
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/types"
+	"google.golang.org/api/sheets/v4"
+)
+
+type stubSpreadsheets struct {
+	values [][]interface{}
+}
+
+func (s stubSpreadsheets) GetValues(spreadsheetId, spreadsheetRange string) (*sheets.ValueRange, error) {
+	return &sheets.ValueRange{Values: s.values}, nil
+}
+
+func newTestServices(t *testing.T, spreadsheets Spreadsheets) database.Stores {
+	t.Helper()
+
+	memoryStores := database.NewMemoryStores()
+	SetStores(memoryStores)
+
+	fakes, err := NewProviders(FakeProviders, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakes.Spreadsheets = spreadsheets
+	SetProviders(fakes)
+
+	return memoryStores
+}
+
+func instantFormRow(id, createdTime, fullName, phoneNumber string) []interface{} {
+	return []interface{}{
+		id, createdTime, "ad:1", "Summer Ad", "as:2", "Summer Ad Set", "c:3", "Summer Campaign",
+		"f:4", "Quote Form", "false", "fb", fullName, phoneNumber, "Wedding for 100", "jane@example.com",
+	}
+}
+
+func TestCheckSpreadsheets(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{values: [][]interface{}{
+		{"id", "created_time", "ad_id", "ad_name", "adset_id", "adset_name", "campaign_id", "campaign_name", "form_id", "form_name", "is_organic", "platform", "full_name", "phone_number", "event_description", "email"},
+		instantFormRow("l:100", "2030-01-10T15:04:05-05:00", "Jane Doe", "p:+1 (305) 555-0301"),
+		instantFormRow("l:101", "2030-01-10T16:04:05-05:00", "John Roe", "p:+13055550302"),
+		instantFormRow("l:102", "2030-01-10T17:04:05-05:00", "Already Known", "3055550303"),
+		instantFormRow("l:103", "not a date", "Bad Date", "3055550304"),
+		instantFormRow("l:104", "2030-01-10T18:04:05-05:00", "No Phone", ""),
+		{"l:105", "2030-01-10T19:04:05-05:00", "too short"},
+	}})
+
+	fullName, phoneNumber := "Existing Lead", "3055550303"
+	existingLeadId, err := stores.Leads.CreateLeadAndMarketing(types.QuoteForm{FullName: &fullName, PhoneNumber: &phoneNumber})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run must not create the same leads again
+	for i := 0; i < 2; i++ {
+		if err := checkSpreadsheets(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, phoneNumber := range []string{"13055550301", "13055550302"} {
+		exists, err := stores.Leads.IsPhoneNumberInDB(phoneNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Errorf("expected a lead for %s", phoneNumber)
+		}
+	}
+
+	for _, phoneNumber := range []string{"3055550304", ""} {
+		exists, err := stores.Leads.IsPhoneNumberInDB(phoneNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			t.Errorf("expected no lead for %q", phoneNumber)
+		}
+	}
+
+	leadId, err := stores.Leads.GetLeadIDFromPhoneNumber("3055550303")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leadId != existingLeadId {
+		t.Errorf("expected the existing lead %d to be kept, got %d", existingLeadId, leadId)
+	}
+
+	leads, _, err := stores.Leads.GetLeadList(types.GetLeadsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leads) != 3 {
+		t.Errorf("expected 3 leads, got %d", len(leads))
+	}
+}
+
+func TestGetOrCreateLeadFromPhoneNumber(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+
+	username, staffPhoneNumber, password := "staff", "3055550310", "password"
+	if err := stores.Users.CreateUser(types.UserForm{
+		Username:    &username,
+		PhoneNumber: &staffPhoneNumber,
+		Password:    &password,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GetOrCreateLeadFromPhoneNumber(staffPhoneNumber, constants.PhoneLeadSource, "3055550000"); !errors.Is(err, ErrStaffPhoneNumber) {
+		t.Errorf("expected ErrStaffPhoneNumber for a staff caller, got %v", err)
+	}
+
+	firstLeadId, err := GetOrCreateLeadFromPhoneNumber("3055550311", constants.PhoneLeadSource, "3055550000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondLeadId, err := GetOrCreateLeadFromPhoneNumber("3055550311", constants.SMSLeadSource, "3055550000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secondLeadId != firstLeadId {
+		t.Errorf("expected the caller's lead %d to be reused, got %d", firstLeadId, secondLeadId)
+	}
+
+	leads, _, err := stores.Leads.GetLeadList(types.GetLeadsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leads) != 1 {
+		t.Errorf("expected 1 lead, got %d", len(leads))
+	}
+}
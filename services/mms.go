@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/google/uuid"
+)
+
+const (
+	messageMediaS3Path = "uploads/mms/"
+
+	// Twilio rejects MMS images larger than 5MB
+	MaxMessageMediaSize int64 = 5 << 20
+
+	// Message media is private, so Twilio and the CRM get links that only work for a little while
+	messageMediaURLExpiry = time.Hour
+)
+
+var ErrInvalidMessageMedia = errors.New("text message attachments must be images under 5MB")
+
+// messageMediaExtensions is also the list of types staff can attach to a text.
+// mime.ExtensionsByType sorts alphabetically, which would turn JPEGs into .jfif
+var messageMediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func messageMediaFileName(contentType string) string {
+	fileName := uuid.New().String()
+
+	if extension, ok := messageMediaExtensions[contentType]; ok {
+		return fileName + extension
+	}
+
+	extensions, err := mime.ExtensionsByType(contentType)
+	if err == nil && len(extensions) > 0 {
+		fileName += extensions[0]
+	}
+
+	return fileName
+}
+
+// StoreInboundMessageMedia copies a Twilio MMS attachment into S3 since Twilio media URLs require account credentials.
+func StoreInboundMessageMedia(mediaURL, contentType string) (models.MessageMedia, error) {
+	var media models.MessageMedia
+
+	fileName := messageMediaFileName(contentType)
+	localFilePath := constants.LOCAL_FILES_DIR + fileName
+
+	err := DownloadFileFromTwilio(mediaURL, localFilePath)
+	if err != nil {
+		return media, fmt.Errorf("error downloading message media: %w", err)
+	}
+	defer os.Remove(localFilePath)
+
+	fileInfo, err := os.Stat(localFilePath)
+	if err != nil {
+		return media, fmt.Errorf("error getting message media info: %w", err)
+	}
+
+	file, err := os.Open(localFilePath)
+	if err != nil {
+		return media, fmt.Errorf("error opening message media: %w", err)
+	}
+	defer file.Close()
+
+	s3FilePath := messageMediaS3Path + fileName
+	err = UploadFileToS3(file, fileInfo.Size(), s3FilePath)
+	if err != nil {
+		return media, fmt.Errorf("error uploading message media: %w", err)
+	}
+
+	media = models.MessageMedia{
+		FilePath:    s3FilePath,
+		ContentType: contentType,
+		DateCreated: time.Now().Unix(),
+	}
+
+	return media, nil
+}
+
+func UploadOutboundMessageMedia(fileHeader *multipart.FileHeader) (models.MessageMedia, error) {
+	var media models.MessageMedia
+
+	if fileHeader.Size > MaxMessageMediaSize {
+		return media, ErrInvalidMessageMedia
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return media, fmt.Errorf("error opening message media: %w", err)
+	}
+	defer file.Close()
+
+	// The browser's Content-Type is whatever the file was named, so the type comes from the bytes
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return media, fmt.Errorf("error reading message media: %w", err)
+	}
+
+	contentType := http.DetectContentType(header[:n])
+	if _, ok := messageMediaExtensions[contentType]; !ok {
+		return media, ErrInvalidMessageMedia
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return media, fmt.Errorf("error reading message media: %w", err)
+	}
+
+	s3FilePath := messageMediaS3Path + messageMediaFileName(contentType)
+	err = UploadFileToS3(file, fileHeader.Size, s3FilePath)
+	if err != nil {
+		return media, fmt.Errorf("error uploading message media: %w", err)
+	}
+
+	media = models.MessageMedia{
+		FilePath:    s3FilePath,
+		ContentType: contentType,
+		DateCreated: time.Now().Unix(),
+	}
+
+	return media, nil
+}
+
+// GetMessageMediaURL returns a short-lived link to a stored attachment.
+func GetMessageMediaURL(filePath string) (string, error) {
+	return PresignS3URL(filePath, messageMediaURLExpiry)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func newTestMessageMediaHeader(t *testing.T, contentType string, contents []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="media"; filename="photo.png"`)
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(contents); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+
+	return form.File["media"][0]
+}
+
+func TestUploadOutboundMessageMediaChecksContentType(t *testing.T) {
+	newTestServices(t, stubSpreadsheets{})
+
+	var pngBytes bytes.Buffer
+	if err := png.Encode(&pngBytes, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		contents []byte
+		wantErr  bool
+	}{
+		{"png", pngBytes.Bytes(), false},
+		{"html labelled as png", []byte("<html><script>alert(1)</script></html>"), true},
+		{"pdf labelled as png", []byte("%PDF-1.4\n"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media, err := UploadOutboundMessageMedia(newTestMessageMediaHeader(t, "image/png", tt.contents))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMessageMedia) {
+					t.Fatalf("expected ErrInvalidMessageMedia, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if media.ContentType != "image/png" || !strings.HasSuffix(media.FilePath, ".png") {
+				t.Errorf("got %q at %q, expected a png", media.ContentType, media.FilePath)
+			}
+		})
+	}
+}
@@ -12,7 +12,13 @@ import (
 	"github.com/davidalvarez305/yd_cocktails/types"
 )
 
+type openAILLM struct{}
+
 func GetOpenAICompletionsResponse(prompt string, maxTokens int) (string, error) {
+	return providers.LLM.Complete(prompt, maxTokens)
+}
+
+func (openAILLM) Complete(prompt string, maxTokens int) (string, error) {
 	requestBody, err := json.Marshal(map[string]interface{}{
 		"model":       "gpt-4o-mini",
 		"messages":    []map[string]interface{}{{"role": "user", "content": prompt}},
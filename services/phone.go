@@ -1,22 +1,55 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"regexp"
-	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	twilio "github.com/twilio/twilio-go"
 	openapi "github.com/twilio/twilio-go/rest/api/v2010"
 )
 
+type twilioMessenger struct{}
+
+// ErrTextMessagingOptOut is returned instead of sending to a number whose latest consent is an opt out.
+var ErrTextMessagingOptOut = errors.New("phone number has opted out of text messages")
+
+func checkTextMessagingConsent(to string) error {
+	optedOut, err := stores.Messages.IsPhoneNumberOptedOut(helpers.RemoveCountryCode(to))
+	if err != nil {
+		return fmt.Errorf("error checking text message consent: %w", err)
+	}
+
+	if optedOut {
+		return ErrTextMessagingOptOut
+	}
+
+	return nil
+}
+
 func SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error) {
+	if err := checkTextMessagingConsent(to); err != nil {
+		return openapi.ApiV2010Message{}, err
+	}
+
+	return providers.Messenger.SendTextMessage(to, from, body)
+}
+
+func SendMediaMessage(to, from, body string, mediaURLs []string) (openapi.ApiV2010Message, error) {
+	if err := checkTextMessagingConsent(to); err != nil {
+		return openapi.ApiV2010Message{}, err
+	}
+
+	return providers.Messenger.SendMediaMessage(to, from, body, mediaURLs)
+}
+
+func (twilioMessenger) SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error) {
 	client := twilio.NewRestClient()
 
 	var params openapi.CreateMessageParams
@@ -25,6 +58,30 @@ func SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error) {
 	params.SetTo("+1" + to)
 	params.SetFrom("+1" + from)
 	params.SetBody(body)
+	params.SetStatusCallback(constants.RootDomain + constants.TwilioSMSStatusCallbackWebhook)
+
+	sentMessage, err := client.Api.CreateMessage(&params)
+
+	if err != nil || sentMessage == nil {
+		return text, err
+	}
+
+	text = *sentMessage
+
+	return text, nil
+}
+
+func (twilioMessenger) SendMediaMessage(to, from, body string, mediaURLs []string) (openapi.ApiV2010Message, error) {
+	client := twilio.NewRestClient()
+
+	var params openapi.CreateMessageParams
+	var text openapi.ApiV2010Message
+
+	params.SetTo("+1" + to)
+	params.SetFrom("+1" + from)
+	params.SetBody(body)
+	params.SetStatusCallback(constants.RootDomain + constants.TwilioSMSStatusCallbackWebhook)
+	params.SetMediaUrl(mediaURLs)
 
 	sentMessage, err := client.Api.CreateMessage(&params)
 
@@ -38,6 +95,10 @@ func SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error) {
 }
 
 func InitiateOutboundCall(from, twiML string) (openapi.ApiV2010Call, error) {
+	return providers.Messenger.InitiateOutboundCall(from, twiML)
+}
+
+func (twilioMessenger) InitiateOutboundCall(from, twiML string) (openapi.ApiV2010Call, error) {
 	client := twilio.NewRestClient()
 
 	var call openapi.ApiV2010Call
@@ -68,6 +129,10 @@ func InitiateOutboundCall(from, twiML string) (openapi.ApiV2010Call, error) {
 }
 
 func DownloadFileFromTwilio(fileURL, localFilePath string) error {
+	return providers.Messenger.DownloadFile(fileURL, localFilePath)
+}
+
+func (twilioMessenger) DownloadFile(fileURL, localFilePath string) error {
 	req, err := http.NewRequest("GET", fileURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -111,29 +176,14 @@ func MissedCallFollowUpText(phoneCall models.PhoneCall, user models.User) error
 		
 		Si prefiere español dejenos saber!`, user.FirstName)
 
-	sentMessage, err := SendTextMessage(phoneCall.CallTo, user.PhoneNumber, textMessageTemplateNotification)
-
+	leadId, err := stores.Leads.GetLeadIDFromPhoneNumber(phoneCall.CallTo)
 	if err != nil {
-		fmt.Printf("ERROR SENDING MISSED CALL NOTIFICATION MSG: %+v\n", err)
-		return err
+		leadId = 0
 	}
 
-	var externalID = helpers.SafeString(sentMessage.Sid)
-	var messageStatus = helpers.SafeString(sentMessage.Status)
-
-	msg := models.Message{
-		ExternalID:  externalID,
-		Text:        textMessageTemplateNotification,
-		TextFrom:    user.PhoneNumber,
-		TextTo:      phoneCall.CallTo,
-		IsInbound:   false,
-		DateCreated: time.Now().Unix(),
-		Status:      messageStatus,
-		IsRead:      true,
-	}
-
-	err = database.SaveSMS(msg)
+	_, err = SendAutomatedTextMessage(leadId, phoneCall.CallTo, user.PhoneNumber, textMessageTemplateNotification)
 	if err != nil {
+		fmt.Printf("ERROR SENDING MISSED CALL NOTIFICATION MSG: %+v\n", err)
 		return err
 	}
 
@@ -141,6 +191,10 @@ func MissedCallFollowUpText(phoneCall models.PhoneCall, user models.User) error
 }
 
 func DeleteCallRecording(callRecordingSid string) error {
+	return providers.Messenger.DeleteCallRecording(callRecordingSid)
+}
+
+func (twilioMessenger) DeleteCallRecording(callRecordingSid string) error {
 	client := twilio.NewRestClient()
 
 	err := client.Api.DeleteRecording(callRecordingSid, &openapi.DeleteRecordingParams{})
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func PriceQuote(req types.PricingRequest) (types.PricedQuote, error) {
+	catalog, err := stores.Quotes.GetServices()
+	if err != nil {
+		return types.PricedQuote{}, fmt.Errorf("error getting services: %w", err)
+	}
+
+	return helpers.PriceQuote(req, catalog)
+}
+
+// SyncQuoteAdjustments recalculates the surcharge, travel and minimum order lines from whatever services are
+// on the quote now, so hand edits to a line are kept and the adjustments follow them. Any redeemed discount is
+// recalculated along with them.
+func SyncQuoteAdjustments(quoteId int) error {
+	quote, err := stores.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+	if err != nil {
+		return fmt.Errorf("error getting quote: %w", err)
+	}
+
+	catalog, err := stores.Quotes.GetServices()
+	if err != nil {
+		return fmt.Errorf("error getting services: %w", err)
+	}
+
+	quoteServices, err := stores.Quotes.GetQuoteServices(quoteId)
+	if err != nil {
+		return fmt.Errorf("error getting quote services: %w", err)
+	}
+
+	isAdjustment := make(map[int]bool)
+	for _, service := range catalog {
+		if service.ServiceTypeID == constants.PricingAdjustmentServiceTypeID {
+			isAdjustment[service.ServiceID] = true
+		}
+	}
+
+	var subtotal float64
+	for _, qs := range quoteServices {
+		if !isAdjustment[qs.ServiceID] && !helpers.IsQuoteOption(qs) {
+			subtotal += qs.Total
+		}
+	}
+
+	adjustments, err := helpers.PriceQuoteAdjustments(quote.EventDate, quote.TravelMiles, subtotal, catalog)
+	if err != nil {
+		return err
+	}
+
+	if err := stores.Quotes.ReplaceQuoteAdjustments(quoteId, QuoteServiceForms(adjustments)); err != nil {
+		return err
+	}
+
+	return SyncQuoteDiscount(quoteId)
+}
+
+// QuoteServiceForms turns priced lines into the rows the quote stores insert.
+func QuoteServiceForms(lines []types.PricedQuoteService) []types.QuoteServiceForm {
+	var forms []types.QuoteServiceForm
+	for _, line := range lines {
+		forms = append(forms, types.QuoteServiceForm{
+			ServiceID:    &line.ServiceID,
+			Units:        &line.Units,
+			PricePerUnit: &line.PricePerUnit,
+			PricingRule:  &line.PricingRule,
+		})
+	}
+	return forms
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"mime/multipart"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/types"
+	"github.com/stripe/stripe-go/v81"
+	openapi "github.com/twilio/twilio-go/rest/api/v2010"
+	"google.golang.org/api/sheets/v4"
+)
+
+type Messenger interface {
+	SendTextMessage(to, from, body string) (openapi.ApiV2010Message, error)
+	SendMediaMessage(to, from, body string, mediaURLs []string) (openapi.ApiV2010Message, error)
+	InitiateOutboundCall(from, twiML string) (openapi.ApiV2010Call, error)
+	DownloadFile(fileURL, localFilePath string) error
+	DeleteCallRecording(callRecordingSid string) error
+}
+
+type PaymentProvider interface {
+	CreateInvoice(params types.CreateInvoiceParams) (stripe.Invoice, error)
+	UpdateInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoice, error)
+	GetInvoice(stripeInvoiceId string) (stripe.Invoice, error)
+}
+
+type LLM interface {
+	Complete(prompt string, maxTokens int) (string, error)
+}
+
+type ObjectStore interface {
+	UploadFile(file multipart.File, fileSize int64, s3FilePath string) error
+	DownloadFile(s3FilePath, localFilePath string) (string, error)
+	PresignURL(s3FilePath string, expires time.Duration) (string, error)
+}
+
+type Transcriber interface {
+	TranscribeAudio(audioFileURL string) (string, string, error)
+}
+
+type Spreadsheets interface {
+	GetValues(spreadsheetId, spreadsheetRange string) (*sheets.ValueRange, error)
+}
+
+type Mailer interface {
+	SendMail(recipients []string, subject, sender, body string) error
+}
+
+// Providers groups every third party the services talk to.
+type Providers struct {
+	Messenger    Messenger
+	Payments     PaymentProvider
+	LLM          LLM
+	ObjectStore  ObjectStore
+	Transcriber  Transcriber
+	Mailer       Mailer
+	Spreadsheets Spreadsheets
+}
+
+const (
+	LiveProviders   = "live"
+	FakeProviders   = "fake"
+	RecordProviders = "record"
+)
+
+var providers = NewLiveProviders()
+
+func NewLiveProviders() Providers {
+	return Providers{
+		Messenger:    twilioMessenger{},
+		Payments:     stripePaymentProvider{},
+		LLM:          openAILLM{},
+		ObjectStore:  s3ObjectStore{},
+		Transcriber:  awsTranscriber{},
+		Mailer:       gmailMailer{},
+		Spreadsheets: googleSpreadsheets{},
+	}
+}
+
+// NewProviders builds the providers for the PROVIDERS mode: live SDKs, offline fakes that
+// replay the cassette when possible, or live SDKs whose responses are recorded to the cassette.
+func NewProviders(mode, dir string) (Providers, error) {
+	switch mode {
+	case "", LiveProviders:
+		return NewLiveProviders(), nil
+	case FakeProviders:
+		c, err := openCassette(dir, false)
+		if err != nil {
+			return Providers{}, err
+		}
+		return newFakeProviders(c, Providers{}), nil
+	case RecordProviders:
+		c, err := openCassette(dir, true)
+		if err != nil {
+			return Providers{}, err
+		}
+		return newFakeProviders(c, NewLiveProviders()), nil
+	default:
+		return Providers{}, fmt.Errorf("unknown providers mode: %s", mode)
+	}
+}
+
+func SetProviders(p Providers) {
+	providers = p
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+var ErrQuoteAlreadyAccepted = errors.New("this quote has already been accepted")
+
+var ErrQuoteAcceptanceInProgress = errors.New("this quote is already being accepted, please refresh the page in a moment")
+
+// QuoteAcceptanceError is a problem with what the customer submitted, so it's safe to show them.
+type QuoteAcceptanceError struct {
+	Message string
+}
+
+func (e QuoteAcceptanceError) Error() string {
+	return e.Message
+}
+
+// AcceptQuote records the customer's signature and choices, reprices the quote around them and invoices it.
+// A quote stays open to retries until its invoices go out, so a failed Stripe call never strands an acceptance.
+func AcceptQuote(externalQuoteId string, form types.QuoteAcceptanceForm, ipAddress, userAgent string) error {
+	externalQuote, err := stores.Quotes.GetExternalQuoteDetails(externalQuoteId)
+	if err != nil {
+		return fmt.Errorf("error getting quote: %w", err)
+	}
+	quoteId := externalQuote.QuoteID
+
+	acceptance, err := stores.Quotes.GetQuoteAcceptance(quoteId)
+	if err != nil {
+		return fmt.Errorf("error getting quote acceptance: %w", err)
+	}
+
+	if acceptance.IsInvoiced {
+		return ErrQuoteAlreadyAccepted
+	}
+
+	// A retry keeps the signature and choices that were already recorded
+	if acceptance.QuoteAcceptanceID == 0 {
+		if err := recordQuoteAcceptance(quoteId, form, ipAddress, userAgent); err != nil {
+			return err
+		}
+
+		acceptance, err = stores.Quotes.GetQuoteAcceptance(quoteId)
+		if err != nil {
+			return fmt.Errorf("error getting quote acceptance: %w", err)
+		}
+	}
+
+	// Only the request that claims invoicing talks to Stripe, so a double submit can't invoice twice
+	claimed, err := stores.Quotes.ClaimQuoteAcceptanceInvoicing(quoteId)
+	if err != nil {
+		return fmt.Errorf("error claiming quote invoicing: %w", err)
+	}
+
+	if !claimed {
+		return ErrQuoteAcceptanceInProgress
+	}
+
+	leadId, err := invoiceAcceptedQuote(quoteId)
+	if err != nil {
+		if releaseErr := stores.Quotes.ReleaseQuoteAcceptanceInvoicing(quoteId); releaseErr != nil {
+			fmt.Printf("ERROR RELEASING QUOTE INVOICING: %+v\n", releaseErr)
+		}
+		return err
+	}
+
+	PublishCRMEvent(types.CRMEvent{
+		Type:    constants.QuoteAcceptedCRMEvent,
+		LeadID:  leadId,
+		Message: fmt.Sprintf("Quote #%d accepted by %s", quoteId, acceptance.SignatureName),
+	})
+
+	return nil
+}
+
+func invoiceAcceptedQuote(quoteId int) (int, error) {
+	if err := SyncQuoteAdjustments(quoteId); err != nil {
+		return 0, fmt.Errorf("error repricing accepted quote: %w", err)
+	}
+
+	quote, err := stores.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+	if err != nil {
+		return 0, fmt.Errorf("error getting quote: %w", err)
+	}
+
+	hasInvoice, err := stores.Invoices.CheckQuoteHasInvoiceID(quoteId)
+	if err != nil {
+		return 0, fmt.Errorf("error checking quote invoices: %w", err)
+	}
+
+	// Invoices sent before acceptance were billed for every option, so they're reissued for what was chosen
+	if hasInvoice {
+		if err := UpdateInvoicesWorkflow(quoteId, quote.EventDate); err != nil {
+			return 0, fmt.Errorf("error updating invoices: %w", err)
+		}
+	} else {
+		details, err := stores.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(quote.LeadID), fmt.Sprint(quoteId))
+		if err != nil {
+			return 0, fmt.Errorf("error getting invoice details: %w", err)
+		}
+
+		if err := CreateInvoiceWorkflow(details); err != nil {
+			return 0, fmt.Errorf("error creating invoices: %w", err)
+		}
+	}
+
+	if err := stores.Quotes.MarkQuoteAcceptanceInvoiced(quoteId, time.Now().Unix()); err != nil {
+		return 0, fmt.Errorf("error marking quote acceptance invoiced: %w", err)
+	}
+
+	return quote.LeadID, nil
+}
+
+func recordQuoteAcceptance(quoteId int, form types.QuoteAcceptanceForm, ipAddress, userAgent string) error {
+	signatureName := strings.TrimSpace(helpers.SafeString(form.SignatureName))
+	if signatureName == "" {
+		return QuoteAcceptanceError{Message: "please type your full name to sign"}
+	}
+
+	if !helpers.SafeBoolDefaultFalse(form.AgreeToTerms) {
+		return QuoteAcceptanceError{Message: "please agree to the terms and conditions"}
+	}
+
+	quoteServices, err := stores.Quotes.GetQuoteServices(quoteId)
+	if err != nil {
+		return fmt.Errorf("error getting quote services: %w", err)
+	}
+
+	packageTier := strings.TrimSpace(helpers.SafeString(form.PackageTier))
+	keep, addOns, err := helpers.ResolveQuoteOptions(quoteServices, packageTier, form.AddOnIDs)
+	if err != nil {
+		return QuoteAcceptanceError{Message: err.Error()}
+	}
+
+	err = stores.Quotes.AcceptQuote(models.QuoteAcceptance{
+		QuoteID:        quoteId,
+		SignatureName:  signatureName,
+		PackageTier:    packageTier,
+		SelectedAddOns: addOns,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		DateAccepted:   time.Now().Unix(),
+	}, keep)
+	if err != nil {
+		return fmt.Errorf("error accepting quote: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/database"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func createTestQuote(t *testing.T, stores database.Stores, withAddOn bool) (int, string) {
+	t.Helper()
+
+	fullName, phoneNumber, source, optIn := "Jane Doe", "3055550401", "google", true
+	leadId, err := stores.Leads.CreateLeadAndMarketing(types.QuoteForm{
+		FullName:           &fullName,
+		PhoneNumber:        &phoneNumber,
+		OptInTextMessaging: &optIn,
+		Source:             &source,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createService := func(serviceName string, price float64) int {
+		serviceTypeId, unitTypeId := constants.BartendingServiceTypeID, constants.FlatUnitTypeID
+		if err := stores.Quotes.CreateService(types.ServiceForm{
+			ServiceTypeID:  &serviceTypeId,
+			Service:        &serviceName,
+			SuggestedPrice: &price,
+			UnitTypeID:     &unitTypeId,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		catalog, err := stores.Quotes.GetServices()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, service := range catalog {
+			if service.Service == serviceName {
+				return service.ServiceID
+			}
+		}
+
+		t.Fatalf("service %s was not created", serviceName)
+		return 0
+	}
+
+	units, openBarId, openBarPrice := 1.0, createService("Open Bar", 800), 800.0
+	quoteServices := []types.QuoteServiceForm{{ServiceID: &openBarId, Units: &units, PricePerUnit: &openBarPrice}}
+	loc, err := time.LoadLocation(constants.TimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guests, hours, date := 50, 4.0, time.Date(2030, time.January, 16, 18, 0, 0, 0, loc).Unix()
+	quoteId, externalQuoteId, err := stores.Quotes.CreateQuickQuote(types.QuickQuoteForm{
+		LeadID:    &leadId,
+		Guests:    &guests,
+		Hours:     &hours,
+		EventDate: &date,
+	}, quoteServices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if withAddOn {
+		toastId, toastPrice, isOptional := createService("Champagne Toast", 200), 200.0, true
+		if err := stores.Quotes.CreateQuoteService(types.QuoteServiceForm{
+			QuoteID:      &quoteId,
+			ServiceID:    &toastId,
+			Units:        &units,
+			PricePerUnit: &toastPrice,
+			IsOptional:   &isOptional,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return quoteId, externalQuoteId
+}
+
+func TestAcceptQuoteRetriesUntilInvoiced(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+	quoteId, externalQuoteId := createTestQuote(t, stores, false)
+
+	// A previous attempt recorded the acceptance but failed before its invoices went out
+	err := stores.Quotes.AcceptQuote(models.QuoteAcceptance{
+		QuoteID:       quoteId,
+		SignatureName: "Jane Doe",
+		DateAccepted:  1,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AcceptQuote(externalQuoteId, types.QuoteAcceptanceForm{}, "127.0.0.1", "test"); err != nil {
+		t.Fatalf("expected the retry to invoice the quote, got %v", err)
+	}
+
+	hasInvoice, err := stores.Invoices.CheckQuoteHasInvoiceID(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasInvoice {
+		t.Error("expected the retry to create invoices")
+	}
+
+	acceptance, err := stores.Quotes.GetQuoteAcceptance(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acceptance.IsInvoiced {
+		t.Error("expected the acceptance to be marked invoiced")
+	}
+
+	err = AcceptQuote(externalQuoteId, types.QuoteAcceptanceForm{}, "127.0.0.1", "test")
+	if !errors.Is(err, ErrQuoteAlreadyAccepted) {
+		t.Errorf("expected ErrQuoteAlreadyAccepted once invoiced, got %v", err)
+	}
+}
+
+func TestAcceptQuoteReissuesExistingInvoicesForChosenOptions(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+	quoteId, externalQuoteId := createTestQuote(t, stores, true)
+
+	quote, err := stores.Quotes.GetLeadQuoteDetails(fmt.Sprint(quoteId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err := stores.Invoices.GetLeadQuoteInvoiceDetails(fmt.Sprint(quote.LeadID), fmt.Sprint(quoteId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateInvoiceWorkflow(details); err != nil {
+		t.Fatal(err)
+	}
+
+	sentDepositId := openDepositStripeInvoiceID(t, stores, quoteId)
+
+	signatureName, agreeToTerms := "Jane Doe", true
+	err = AcceptQuote(externalQuoteId, types.QuoteAcceptanceForm{
+		SignatureName: &signatureName,
+		AgreeToTerms:  &agreeToTerms,
+	}, "127.0.0.1", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if openDepositStripeInvoiceID(t, stores, quoteId) == sentDepositId {
+		t.Error("expected the deposit invoice to be reissued")
+	}
+
+	invoices, err := stores.Invoices.GetLeadQuoteInvoices(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoices) != 3 {
+		t.Fatalf("expected 3 open invoices, got %d", len(invoices))
+	}
+	for _, invoice := range invoices {
+		if invoice.Amount != 800 {
+			t.Errorf("expected invoice type %d to bill the $800 open bar without the declined add-on, got %.2f", invoice.InvoiceTypeID, invoice.Amount)
+		}
+	}
+}
+
+func TestAcceptQuoteOnlyInvoicesOnceWhileClaimed(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+	quoteId, externalQuoteId := createTestQuote(t, stores, false)
+
+	err := stores.Quotes.AcceptQuote(models.QuoteAcceptance{
+		QuoteID:       quoteId,
+		SignatureName: "Jane Doe",
+		DateAccepted:  1,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another submit of the same quote is in the middle of invoicing it
+	claimed, err := stores.Quotes.ClaimQuoteAcceptanceInvoicing(quoteId)
+	if err != nil || !claimed {
+		t.Fatalf("expected to claim invoicing, got %v, %v", claimed, err)
+	}
+
+	err = AcceptQuote(externalQuoteId, types.QuoteAcceptanceForm{}, "127.0.0.1", "test")
+	if !errors.Is(err, ErrQuoteAcceptanceInProgress) {
+		t.Fatalf("expected ErrQuoteAcceptanceInProgress, got %v", err)
+	}
+
+	hasInvoice, err := stores.Invoices.CheckQuoteHasInvoiceID(quoteId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasInvoice {
+		t.Error("expected the losing request not to create invoices")
+	}
+
+	// The first request failed and handed the claim back, so a retry goes through
+	if err := stores.Quotes.ReleaseQuoteAcceptanceInvoicing(quoteId); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AcceptQuote(externalQuoteId, types.QuoteAcceptanceForm{}, "127.0.0.1", "test"); err != nil {
+		t.Fatalf("expected the retry to invoice the quote, got %v", err)
+	}
+
+	claimed, err = stores.Quotes.ClaimQuoteAcceptanceInvoicing(quoteId)
+	if err != nil || claimed {
+		t.Errorf("expected an invoiced quote not to be claimable, got %v, %v", claimed, err)
+	}
+}
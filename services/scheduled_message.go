@@ -0,0 +1,145 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+type scheduledMessagePayload struct {
+	ScheduledMessageID int `json:"scheduled_message_id"`
+}
+
+// deliverTextMessage sends a text right away and logs it to the conversation.
+func deliverTextMessage(to, from, body string) (string, error) {
+	sentMessage, err := SendTextMessage(to, from, body)
+	if err != nil {
+		return "", err
+	}
+
+	externalId := helpers.SafeString(sentMessage.Sid)
+
+	msg := models.Message{
+		ExternalID:  externalId,
+		Text:        body,
+		TextFrom:    from,
+		TextTo:      to,
+		IsInbound:   false,
+		DateCreated: time.Now().Unix(),
+		Status:      helpers.SafeString(sentMessage.Status),
+		IsRead:      true,
+	}
+
+	if err := stores.Messages.SaveSMS(msg); err != nil {
+		return externalId, fmt.Errorf("error saving text message: %w", err)
+	}
+
+	return externalId, nil
+}
+
+// ScheduleTextMessage saves the message and queues it to go out at msg.SendAt, pushed past quiet hours.
+func ScheduleTextMessage(msg models.ScheduledMessage) (time.Time, error) {
+	sendAt, err := helpers.NextAllowedSendTime(time.Unix(msg.SendAt, 0))
+	if err != nil {
+		return sendAt, err
+	}
+
+	msg.SendAt = sendAt.Unix()
+	msg.DateCreated = time.Now().Unix()
+
+	scheduledMessageId, err := stores.Messages.CreateScheduledMessage(msg)
+	if err != nil {
+		return sendAt, fmt.Errorf("error creating scheduled message: %w", err)
+	}
+
+	err = EnqueueJob(constants.SendScheduledMessageJob, scheduledMessagePayload{ScheduledMessageID: scheduledMessageId}, sendAt)
+	if err != nil {
+		return sendAt, fmt.Errorf("error enqueuing scheduled message: %w", err)
+	}
+
+	return sendAt, nil
+}
+
+// SendAutomatedTextMessage sends a system generated text now, or queues it for the morning during quiet hours.
+// It reports whether the text was queued.
+func SendAutomatedTextMessage(leadId int, to, from, body string) (bool, error) {
+	now := time.Now()
+
+	sendAt, err := helpers.NextAllowedSendTime(now)
+	if err != nil {
+		return false, err
+	}
+
+	if sendAt.After(now) {
+		_, err := ScheduleTextMessage(models.ScheduledMessage{
+			LeadID:   leadId,
+			TextFrom: from,
+			TextTo:   to,
+			Body:     body,
+			SendAt:   sendAt.Unix(),
+		})
+		return true, err
+	}
+
+	_, err = deliverTextMessage(to, from, body)
+	return false, err
+}
+
+func sendScheduledMessage(payload []byte) error {
+	var data scheduledMessagePayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return fmt.Errorf("error decoding scheduled message payload: %w", err)
+	}
+
+	msg, err := stores.Messages.GetScheduledMessage(data.ScheduledMessageID)
+	if err != nil {
+		return fmt.Errorf("error getting scheduled message: %w", err)
+	}
+
+	// Cancelled from the CRM after it was queued
+	if msg.Status != constants.PendingScheduledMessageStatus {
+		return nil
+	}
+
+	externalId, err := deliverTextMessage(msg.TextTo, msg.TextFrom, msg.Body)
+	if errors.Is(err, ErrTextMessagingOptOut) {
+		return stores.Messages.MarkScheduledMessageFailed(msg.ScheduledMessageID, err.Error())
+	}
+
+	// Once the provider accepted it, never send it again even if logging failed
+	if externalId != "" {
+		if markErr := stores.Messages.MarkScheduledMessageSent(msg.ScheduledMessageID, externalId); markErr != nil {
+			return fmt.Errorf("error marking scheduled message as sent: %w", markErr)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("error sending scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+// failScheduledMessage marks the message failed once its job runs out of attempts, so it doesn't sit pending forever.
+func failScheduledMessage(payload []byte, lastError string) error {
+	var data scheduledMessagePayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return fmt.Errorf("error decoding scheduled message payload: %w", err)
+	}
+
+	msg, err := stores.Messages.GetScheduledMessage(data.ScheduledMessageID)
+	if err != nil {
+		return fmt.Errorf("error getting scheduled message: %w", err)
+	}
+
+	if msg.Status != constants.PendingScheduledMessageStatus {
+		return nil
+	}
+
+	return stores.Messages.MarkScheduledMessageFailed(msg.ScheduledMessageID, lastError)
+}
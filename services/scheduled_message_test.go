@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+func TestScheduledMessageFailsWhenItsJobIsDeadLettered(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+
+	// The provider keeps failing until the job runs out of attempts
+	sendHandler := jobHandlers[constants.SendScheduledMessageJob]
+	jobHandlers[constants.SendScheduledMessageJob] = func([]byte) error { return errors.New("provider unavailable") }
+	t.Cleanup(func() { jobHandlers[constants.SendScheduledMessageJob] = sendHandler })
+
+	scheduledMessageId, err := stores.Messages.CreateScheduledMessage(models.ScheduledMessage{
+		TextFrom:    constants.CompanyPhoneNumber,
+		TextTo:      "3055550901",
+		Body:        "See you Saturday!",
+		SendAt:      time.Now().Add(-time.Minute).Unix(),
+		Status:      constants.PendingScheduledMessageStatus,
+		DateCreated: time.Now().Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = stores.Jobs.EnqueueJob(models.Job{
+		JobType:     constants.SendScheduledMessageJob,
+		Payload:     fmt.Sprintf(`{"scheduled_message_id": %d}`, scheduledMessageId),
+		MaxAttempts: 1,
+		RunAt:       time.Now().Add(-time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !processNextJob("test") {
+		t.Fatal("expected a job to run")
+	}
+
+	msg, err := stores.Messages.GetScheduledMessage(scheduledMessageId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Status != constants.FailedScheduledMessageStatus || msg.LastError != "provider unavailable" {
+		t.Errorf("got status %q with error %q, expected it failed with the provider error", msg.Status, msg.LastError)
+	}
+}
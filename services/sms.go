@@ -2,17 +2,14 @@ package services
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 )
 
-func checkSMS() {
-	unreadMessages, err := database.GetUnreadMessagesInLast5Minutes()
+func checkSMS() error {
+	unreadMessages, err := stores.Messages.GetUnreadMessagesInLast5Minutes()
 	if err != nil {
-		fmt.Printf("ERROR TRYING TO GET UNREAD MESSAGES IN LAST 5 MINUTES: %+v\n", err)
-		return
+		return fmt.Errorf("error getting unread messages in last 5 minutes: %w", err)
 	}
 
 	if unreadMessages > 0 {
@@ -31,18 +28,9 @@ func checkSMS() {
 
 		err = SendGmail(recipients, subject, constants.CompanyEmail, body)
 		if err != nil {
-			fmt.Printf("ERROR SENDING UNREAD MESSAGES NOTIFICATION EMAIL: %+v\n", err)
-			return
+			return fmt.Errorf("error sending unread messages notification email: %w", err)
 		}
 	}
-}
-
-func StartSMSNotifcationChecker() {
-	go func() {
-		for {
-			checkSMS()
 
-			time.Sleep(5 * time.Minute)
-		}
-	}()
+	return nil
 }
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+// ProcessSMSConsentKeyword records the consent change carried by an inbound keyword text and
+// sends the matching reply. Carriers confirm STOP themselves, so opt outs get no reply.
+func ProcessSMSConsentKeyword(message models.Message) error {
+	keyword, action := helpers.ParseSMSConsentKeyword(message.Text)
+	if action == "" {
+		return nil
+	}
+
+	leadId, err := stores.Leads.GetLeadIDFromPhoneNumber(message.TextFrom)
+	if err != nil {
+		leadId = 0
+	}
+
+	var reply string
+
+	switch action {
+	case constants.OptOutSMSConsentAction, constants.OptInSMSConsentAction:
+		isOptedIn := action == constants.OptInSMSConsentAction
+
+		event := models.SMSConsentEvent{
+			PhoneNumber: message.TextFrom,
+			LeadID:      leadId,
+			IsOptedIn:   isOptedIn,
+			Source:      constants.KeywordSMSConsentSource,
+			Keyword:     keyword,
+			Message:     message.Text,
+			ExternalID:  message.ExternalID,
+			DateCreated: message.DateCreated,
+		}
+
+		if err := stores.Messages.CreateSMSConsentEvent(event); err != nil {
+			return fmt.Errorf("error saving sms consent event: %w", err)
+		}
+
+		if !isOptedIn {
+			if leadId > 0 {
+				if err := stores.Sequences.StopSMSSequenceEnrollments(leadId, constants.OptedOutSMSSequenceStopReason); err != nil {
+					return fmt.Errorf("error stopping sms sequences: %w", err)
+				}
+			}
+			return nil
+		}
+
+		reply = constants.SMSOptInMessage
+	case constants.HelpSMSConsentAction:
+		reply = constants.SMSHelpMessage
+	}
+
+	// Keyword replies answer the customer's own text, so they go out even to a number that has opted out
+	sentMessage, err := providers.Messenger.SendTextMessage(message.TextFrom, message.TextTo, reply)
+	if err != nil {
+		return fmt.Errorf("error sending sms consent reply: %w", err)
+	}
+
+	msg := models.Message{
+		ExternalID:  helpers.SafeString(sentMessage.Sid),
+		Text:        reply,
+		TextFrom:    message.TextTo,
+		TextTo:      message.TextFrom,
+		IsInbound:   false,
+		DateCreated: time.Now().Unix(),
+		Status:      helpers.SafeString(sentMessage.Status),
+		IsRead:      true,
+	}
+
+	if err := stores.Messages.SaveSMS(msg); err != nil {
+		return fmt.Errorf("error saving sms consent reply: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func TestProcessSMSConsentKeywordAnswersHelpAfterOptOut(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+
+	fullName, phoneNumber, optIn := "Jane Doe", "3055550501", true
+	leadId, err := stores.Leads.CreateLeadAndMarketing(types.QuoteForm{
+		FullName:           &fullName,
+		PhoneNumber:        &phoneNumber,
+		OptInTextMessaging: &optIn,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"STOP", "HELP"} {
+		err := ProcessSMSConsentKeyword(models.Message{
+			ExternalID:  "SM" + text,
+			Text:        text,
+			TextFrom:    phoneNumber,
+			TextTo:      constants.CompanyPhoneNumber,
+			IsInbound:   true,
+			DateCreated: time.Now().Unix(),
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+	}
+
+	optedOut, err := stores.Messages.IsPhoneNumberOptedOut(phoneNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !optedOut {
+		t.Error("expected HELP to leave the number opted out")
+	}
+
+	messages, err := stores.Messages.GetMessagesByLeadID(leadId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replies []string
+	for _, message := range messages {
+		if !message.IsInbound {
+			replies = append(replies, message.Message)
+		}
+	}
+	if len(replies) != 1 || replies[0] != constants.SMSHelpMessage {
+		t.Errorf("expected only the HELP reply to be sent, got %q", replies)
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+	"github.com/davidalvarez305/yd_cocktails/helpers"
+	"github.com/davidalvarez305/yd_cocktails/models"
+	"github.com/davidalvarez305/yd_cocktails/types"
+)
+
+func generateSMSSequenceMessage(step types.DueSMSSequenceStep) (string, error) {
+	previousConversations, err := stores.Messages.GetPreviousConversations(step.LeadID)
+	if err != nil {
+		return "", fmt.Errorf("error getting lead conversations: %w", err)
+	}
+
+	var conversationHistory string
+	for _, convo := range previousConversations {
+		conversationHistory += fmt.Sprintf("[%s] %s\n", convo.Type, convo.Content)
+	}
+
+	prompt := fmt.Sprintf(`I have a lead whose full name is: %s. This person's original inquiry was: %s. 
+	These are our past conversations with this person:
+	%s
+	This is follow-up message number %d. Responding only with the message I'm going to send, write a text message about our bartending services following these instructions:
+	%s
+	The message should read like two friends talking to each other through text.
+	`, step.FullName, step.Message, conversationHistory, step.StepNumber, helpers.RenderSMSSequenceMessage(step.Body, step.FullName))
+
+	return GetOpenAICompletionsResponse(prompt, 300)
+}
+
+func sendSMSSequenceStep(step types.DueSMSSequenceStep) error {
+	body := helpers.RenderSMSSequenceMessage(step.Body, step.FullName)
+
+	if step.IsAIGenerated {
+		generated, err := generateSMSSequenceMessage(step)
+		if err != nil {
+			return fmt.Errorf("error generating sms sequence message: %w", err)
+		}
+		body = generated
+	}
+
+	sentMessage, err := SendTextMessage(step.PhoneNumber, constants.CompanyPhoneNumber, body)
+	if err != nil {
+		return fmt.Errorf("error sending sms sequence message: %w", err)
+	}
+
+	// The text is out, so the step is sent even if saving it fails and the next run must not send it again
+	err = stores.Sequences.MarkSMSSequenceStepSent(step.SMSSequenceEnrollmentID, step.StepNumber, step.IsLastStep)
+	if err != nil {
+		return fmt.Errorf("error marking sms sequence step as sent: %w", err)
+	}
+
+	if step.IsLastStep && step.ArchiveOnCompletion {
+		err = stores.Leads.UpdateLeadStatus(step.LeadID, constants.ArchivedLeadStatusID)
+		if err != nil {
+			return fmt.Errorf("error archiving lead after sms sequence: %w", err)
+		}
+	}
+
+	msg := models.Message{
+		ExternalID:  helpers.SafeString(sentMessage.Sid),
+		Text:        body,
+		TextFrom:    constants.CompanyPhoneNumber,
+		TextTo:      step.PhoneNumber,
+		IsInbound:   false,
+		DateCreated: time.Now().Unix(),
+		Status:      helpers.SafeString(sentMessage.Status),
+		IsRead:      true,
+	}
+
+	err = stores.Messages.SaveSMS(msg)
+	if err != nil {
+		return fmt.Errorf("error saving sms sequence message: %w", err)
+	}
+
+	return nil
+}
+
+func sendDueSMSSequenceSteps() error {
+	now := time.Now()
+
+	// Due steps stay due, so anything held back overnight goes out on the first run after quiet hours
+	sendAt, err := helpers.NextAllowedSendTime(now)
+	if err != nil {
+		return err
+	}
+	if sendAt.After(now) {
+		return nil
+	}
+
+	steps, err := stores.Sequences.GetDueSMSSequenceSteps()
+	if err != nil {
+		return fmt.Errorf("error getting due sms sequence steps: %w", err)
+	}
+
+	var failed int
+	for _, step := range steps {
+		// Leads that booked without a payment webhook stopping them are caught here
+		if step.IsBooked {
+			err = stores.Sequences.StopSMSSequenceEnrollments(step.LeadID, constants.BookedSMSSequenceStopReason)
+			if err != nil {
+				fmt.Printf("ERROR STOPPING SMS SEQUENCE FOR LEAD %d: %+v\n", step.LeadID, err)
+			}
+			continue
+		}
+
+		err = sendSMSSequenceStep(step)
+		if errors.Is(err, ErrTextMessagingOptOut) {
+			err = stores.Sequences.StopSMSSequenceEnrollments(step.LeadID, constants.OptedOutSMSSequenceStopReason)
+			if err != nil {
+				fmt.Printf("ERROR STOPPING SMS SEQUENCE FOR LEAD %d: %+v\n", step.LeadID, err)
+			}
+			continue
+		}
+		if err != nil {
+			fmt.Printf("ERROR SENDING SMS SEQUENCE STEP FOR LEAD %d: %+v\n", step.LeadID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d of %d sms sequence steps", failed, len(steps))
+	}
+
+	return nil
+}
@@ -2,16 +2,24 @@ package services
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/types"
 	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/coupon"
 	"github.com/stripe/stripe-go/v81/customer"
 	"github.com/stripe/stripe-go/v81/invoice"
 	"github.com/stripe/stripe-go/v81/invoiceitem"
 )
 
+type stripePaymentProvider struct{}
+
 func CreateStripeInvoice(params types.CreateInvoiceParams) (stripe.Invoice, error) {
+	return providers.Payments.CreateInvoice(params)
+}
+
+func (stripePaymentProvider) CreateInvoice(params types.CreateInvoiceParams) (stripe.Invoice, error) {
 	stripe.Key = constants.StrikeAPIKey
 
 	// Create a new customer if needed
@@ -46,6 +54,12 @@ func CreateStripeInvoice(params types.CreateInvoiceParams) (stripe.Invoice, erro
 		Currency:         stripe.String("USD"),
 	}
 
+	discounts, err := stripeDiscounts(params.Discount, params.DiscountCode)
+	if err != nil {
+		return stripe.Invoice{}, err
+	}
+	invoiceParams.Discounts = discounts
+
 	inv, err := invoice.New(invoiceParams)
 	if err != nil {
 		return stripe.Invoice{}, fmt.Errorf("failed to create invoice: %v", err)
@@ -54,7 +68,7 @@ func CreateStripeInvoice(params types.CreateInvoiceParams) (stripe.Invoice, erro
 	// Add Invoice Item (Attaching to Invoice)
 	_, err = invoiceitem.New(&stripe.InvoiceItemParams{
 		Customer:    stripe.String(params.StripeCustomerID),
-		Amount:      stripe.Int64(int64(params.Quote) * 100),
+		Amount:      stripe.Int64(int64(math.Round(params.Quote * 100))),
 		Currency:    stripe.String(string(stripe.CurrencyUSD)),
 		Description: stripe.String("Bartending service."),
 		Invoice:     stripe.String(inv.ID), // Attach to invoice
@@ -72,6 +86,10 @@ func CreateStripeInvoice(params types.CreateInvoiceParams) (stripe.Invoice, erro
 }
 
 func UpdateStripeInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoice, error) {
+	return providers.Payments.UpdateInvoice(leadQuoteInvoice)
+}
+
+func (stripePaymentProvider) UpdateInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoice, error) {
 	stripe.Key = constants.StrikeAPIKey
 	var updatedInvoice stripe.Invoice
 
@@ -102,6 +120,12 @@ func UpdateStripeInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoic
 		Currency:         stripe.String(constants.DefaultCurrency),
 	}
 
+	discounts, err := stripeDiscounts(leadQuoteInvoice.Discount*leadQuoteInvoice.InvoiceTypeMultiplier, leadQuoteInvoice.DiscountCode)
+	if err != nil {
+		return updatedInvoice, err
+	}
+	invoiceParams.Discounts = discounts
+
 	newInvoice, err := invoice.New(invoiceParams)
 	if err != nil {
 		return stripe.Invoice{}, fmt.Errorf("failed to create invoice: %v", err)
@@ -110,7 +134,7 @@ func UpdateStripeInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoic
 	// Add Invoice Item (Attaching to Invoice)
 	_, err = invoiceitem.New(&stripe.InvoiceItemParams{
 		Customer:    stripe.String(leadQuoteInvoice.StripeCustomerID),
-		Amount:      stripe.Int64(int64(leadQuoteInvoice.Amount*leadQuoteInvoice.InvoiceTypeMultiplier) * 100),
+		Amount:      stripe.Int64(int64(math.Round(leadQuoteInvoice.Amount * leadQuoteInvoice.InvoiceTypeMultiplier * 100))),
 		Currency:    stripe.String(string(stripe.CurrencyUSD)),
 		Description: stripe.String("Bartending service."),
 		Invoice:     stripe.String(newInvoice.ID), // Attach to invoice
@@ -130,7 +154,31 @@ func UpdateStripeInvoice(leadQuoteInvoice types.LeadQuoteInvoice) (stripe.Invoic
 	return updatedInvoice, nil
 }
 
+// stripeDiscounts creates a one time coupon for the dollar amount a discount code took off this invoice.
+func stripeDiscounts(discount float64, code string) ([]*stripe.InvoiceDiscountParams, error) {
+	amountOff := int64(math.Round(discount * 100))
+	if amountOff <= 0 {
+		return nil, nil
+	}
+
+	c, err := coupon.New(&stripe.CouponParams{
+		AmountOff: stripe.Int64(amountOff),
+		Currency:  stripe.String(string(stripe.CurrencyUSD)),
+		Duration:  stripe.String(string(stripe.CouponDurationOnce)),
+		Name:      stripe.String(code),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create coupon: %v", err)
+	}
+
+	return []*stripe.InvoiceDiscountParams{{Coupon: stripe.String(c.ID)}}, nil
+}
+
 func GetStripeInvoice(stripeInvoiceId string) (stripe.Invoice, error) {
+	return providers.Payments.GetInvoice(stripeInvoiceId)
+}
+
+func (stripePaymentProvider) GetInvoice(stripeInvoiceId string) (stripe.Invoice, error) {
 	stripe.Key = constants.StrikeAPIKey
 	var stripeInvoice stripe.Invoice
 
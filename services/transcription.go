@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/helpers"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/google/uuid"
@@ -17,7 +16,30 @@ const (
 	textTranscriptionS3Path  = "uploads/transcription/"
 )
 
+// TranscribePhoneCall claims the call first, since the voicemail job and the recurring sweep can both pick it up.
 func TranscribePhoneCall(phoneCall models.PhoneCall) error {
+	claimed, err := stores.Messages.ClaimPhoneCallTranscription(phoneCall.PhoneCallID)
+	if err != nil {
+		return fmt.Errorf("error claiming phone call transcription: %w", err)
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	err = transcribePhoneCall(phoneCall)
+	if err != nil {
+		// Saving the transcription completes the claim, so this only hands back calls the next sweep should retry
+		if releaseErr := stores.Messages.ReleasePhoneCallTranscription(phoneCall.PhoneCallID); releaseErr != nil {
+			fmt.Printf("ERROR RELEASING TRANSCRIPTION: %+v\n", releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func transcribePhoneCall(phoneCall models.PhoneCall) error {
 	// Download the file from Twilio
 	audioFileName := uuid.New().String() + ".mp3"
 	localAudioFilePath := constants.LOCAL_FILES_DIR + audioFileName
@@ -114,7 +136,7 @@ func TranscribePhoneCall(phoneCall models.PhoneCall) error {
 		TextURL:     transcriptionFileName,
 	}
 
-	err = database.CreatePhoneCallTranscription(transcription)
+	err = stores.Messages.CreatePhoneCallTranscription(transcription)
 	if err != nil {
 		fmt.Printf("ERROR SAVING TRANSCRIPTION: %+v\n", err)
 		return err
@@ -185,7 +207,7 @@ func SummarizePhoneCall(phoneCall models.PhoneCall, transcriptionText string) er
 		crmUserPhoneNumber = phoneCall.CallTo
 	}
 
-	userId, err := database.GetUserIDFromPhoneNumber(crmUserPhoneNumber)
+	userId, err := stores.Users.GetUserIDFromPhoneNumber(crmUserPhoneNumber)
 	if err != nil {
 		fmt.Printf("ERROR GETTING USER ID FROM PHONE NUMBER: %+v\n", err)
 		return err
@@ -196,7 +218,13 @@ func SummarizePhoneCall(phoneCall models.PhoneCall, transcriptionText string) er
 		leadPhoneNumber = phoneCall.CallFrom
 	}
 
-	leadId, err := database.GetLeadIDFromPhoneNumber(leadPhoneNumber)
+	// Only inbound callers are new leads, an outbound call goes to a number staff already has
+	var leadId int
+	if phoneCall.IsInbound {
+		leadId, err = GetOrCreateLeadFromPhoneNumber(leadPhoneNumber, constants.PhoneLeadSource, phoneCall.CallTo)
+	} else {
+		leadId, err = stores.Leads.GetLeadIDFromPhoneNumber(leadPhoneNumber)
+	}
 	if err != nil {
 		fmt.Printf("ERROR GETTING LEAD ID FROM PHONE NUMBER: %+v\n", err)
 		return err
@@ -209,7 +237,7 @@ func SummarizePhoneCall(phoneCall models.PhoneCall, transcriptionText string) er
 		AddedByUserID: userId,
 	}
 
-	err = database.CreateLeadNote(leadNote)
+	err = stores.Leads.CreateLeadNote(leadNote)
 	if err != nil {
 		fmt.Printf("ERROR SAVING LEAD NOTE: %+v\n", err)
 		return err
@@ -218,11 +246,14 @@ func SummarizePhoneCall(phoneCall models.PhoneCall, transcriptionText string) er
 	return nil
 }
 
-func checkPhoneCallTranscription() {
-	phoneCalls, err := database.GetPhoneCallsWithoutTranscription()
+func checkPhoneCallTranscription() error {
+	if !constants.Production {
+		return nil
+	}
+
+	phoneCalls, err := stores.Messages.GetPhoneCallsWithoutTranscription()
 	if err != nil {
-		fmt.Printf("ERROR GETTING PHONE CALLS WITHOUT TRANSCRIPTION: %+v\n", err)
-		return
+		return fmt.Errorf("error getting phone calls without transcription: %w", err)
 	}
 
 	// Not sure if it would be a good idea to do this concurrently...
@@ -233,18 +264,6 @@ func checkPhoneCallTranscription() {
 			continue
 		}
 	}
-}
-func StartTranscriptionService() {
-	go func() {
-		for {
-			// Sleep for five minutes before the next run
-			time.Sleep(5 * time.Minute)
-
-			if !constants.Production {
-				continue
-			}
 
-			checkPhoneCallTranscription()
-		}
-	}()
+	return nil
 }
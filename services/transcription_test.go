@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/models"
+)
+
+func TestTranscribePhoneCallSkipsClaimedCalls(t *testing.T) {
+	stores := newTestServices(t, stubSpreadsheets{})
+
+	err := stores.Messages.SavePhoneCall(models.PhoneCall{
+		ExternalID:   "CAvoicemail",
+		DateCreated:  time.Now().Unix(),
+		CallFrom:     "3055550701",
+		CallTo:       "3055550100",
+		IsInbound:    true,
+		RecordingURL: "https://api.twilio.com/2010-04-01/Accounts/AC1/Recordings/RE1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phoneCall, err := stores.Messages.GetPhoneCallBySID("CAvoicemail")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := stores.Messages.ClaimPhoneCallTranscription(phoneCall.PhoneCallID)
+	if err != nil || !claimed {
+		t.Fatalf("expected first claim to win, got %v, %v", claimed, err)
+	}
+
+	claimed, err = stores.Messages.ClaimPhoneCallTranscription(phoneCall.PhoneCallID)
+	if err != nil || claimed {
+		t.Fatalf("expected second claim to lose, got %v, %v", claimed, err)
+	}
+
+	// The sweep finds nothing and the voicemail job backs off while the claim is held
+	pending, err := stores.Messages.GetPhoneCallsWithoutTranscription()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no calls awaiting transcription, got %d", len(pending))
+	}
+
+	if err := TranscribePhoneCall(phoneCall); err != nil {
+		t.Fatalf("expected a claimed call to be skipped, got %v", err)
+	}
+
+	if err := stores.Messages.ReleasePhoneCallTranscription(phoneCall.PhoneCallID); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err = stores.Messages.GetPhoneCallsWithoutTranscription()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("expected a released call to be retried, got %d awaiting transcription", len(pending))
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/davidalvarez305/yd_cocktails/constants"
+)
+
+type voicemailPayload struct {
+	CallSid string `json:"call_sid"`
+}
+
+// QueueVoicemailTranscription transcribes the voicemail right away instead of waiting for the next transcription sweep.
+func QueueVoicemailTranscription(callSid string) error {
+	return EnqueueJob(constants.VoicemailTranscriptionJob, voicemailPayload{CallSid: callSid}, time.Now())
+}
+
+func transcribeVoicemail(payload []byte) error {
+	if !constants.Production {
+		return nil
+	}
+
+	var data voicemailPayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return fmt.Errorf("error decoding voicemail payload: %w", err)
+	}
+
+	phoneCall, err := stores.Messages.GetPhoneCallBySID(data.CallSid)
+	if err != nil {
+		return fmt.Errorf("error getting voicemail phone call: %w", err)
+	}
+
+	return TranscribePhoneCall(phoneCall)
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/davidalvarez305/yd_cocktails/constants"
 	"github.com/davidalvarez305/yd_cocktails/csrf"
-	"github.com/davidalvarez305/yd_cocktails/database"
 	"github.com/davidalvarez305/yd_cocktails/models"
 	"github.com/davidalvarez305/yd_cocktails/utils"
 	"github.com/google/uuid"
@@ -41,7 +40,7 @@ func Get(r *http.Request) (models.Session, error) {
 		return sessions, err
 	}
 
-	sessions, err = database.GetSession(userSecret)
+	sessions, err = stores.Sessions.GetSession(userSecret)
 	if err != nil {
 		return sessions, err
 	}
@@ -64,7 +63,7 @@ func Create(r *http.Request, w http.ResponseWriter) (models.Session, error) {
 		DateExpires: utils.GetSessionExpirationTime().Unix(),
 	}
 
-	err = database.CreateSession(session)
+	err = stores.Sessions.CreateSession(session)
 	if err != nil {
 		fmt.Printf("FAILED TO CREATE SESSION: %+v\n", err)
 		return session, err
@@ -74,7 +73,7 @@ func Create(r *http.Request, w http.ResponseWriter) (models.Session, error) {
 }
 
 func Update(values models.Session) error {
-	err := database.UpdateSession(values)
+	err := stores.Sessions.UpdateSession(values)
 	if err != nil {
 		return err
 	}
@@ -88,7 +87,7 @@ func Destroy(r *http.Request, w http.ResponseWriter) error {
 		return err
 	}
 
-	err = database.DeleteSession(secret)
+	err = stores.Sessions.DeleteSession(secret)
 	if err != nil {
 		return err
 	}
@@ -45,6 +45,8 @@ type QuoteForm struct {
 	InstantFormID     *int64  `json:"instant_form_id" form:"instant_form_id" schema:"instant_form_id"`
 	InstantFormName   *string `json:"instant_form_name" form:"instant_form_name" schema:"instant_form_name"`
 	ReferralLeadID    *int    `json:"referral_lead_id" form:"referral_lead_id" schema:"referral_lead_id"`
+
+	TrackingPhoneNumber *string `json:"tracking_phone_number" form:"tracking_phone_number" schema:"tracking_phone_number"`
 }
 
 type ContactForm struct {
@@ -63,6 +65,25 @@ type OutboundMessageForm struct {
 	CSRFToken string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
 }
 
+type ScheduledMessageForm struct {
+	To        *string `json:"to" form:"to" schema:"to"`
+	Body      *string `json:"body" form:"body" schema:"body"`
+	From      *string `json:"from" form:"from" schema:"from"`
+	SendAt    *string `json:"send_at" form:"send_at" schema:"send_at"`
+	LeadID    *int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	CSRFToken *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+}
+
+type ScheduledMessageList struct {
+	ScheduledMessageID int    `json:"scheduled_message_id"`
+	LeadID             int    `json:"lead_id"`
+	Body               string `json:"body"`
+	SendAt             string `json:"send_at"`
+	Status             string `json:"status"`
+	LastError          string `json:"last_error"`
+	UserName           string `json:"user_name"`
+}
+
 type LeadDetails struct {
 	LeadID           int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
 	FullName         string `json:"full_name" form:"full_name" schema:"full_name"`
@@ -94,6 +115,8 @@ type LeadDetails struct {
 	InstantFormID     int64  `json:"instant_form_id" form:"instant_form_id" schema:"instant_form_id"`
 	InstantFormName   string `json:"instant_form_name" form:"instant_form_name" schema:"instant_form_name"`
 
+	TrackingPhoneNumber string `json:"tracking_phone_number" form:"tracking_phone_number" schema:"tracking_phone_number"`
+
 	NextActionID   int `json:"next_action_id" form:"next_action_id" schema:"next_action_id"`
 	LeadInterestID int `json:"lead_interest_id" form:"lead_interest_id" schema:"lead_interest_id"`
 	LeadStatusID   int `json:"lead_status_id" form:"lead_status_id" schema:"lead_status_id"`
@@ -112,6 +135,8 @@ type LeadList struct {
 	TotalRows       int    `json:"total_rows" form:"total_rows" schema:"total_rows"`
 	LastContactDate string `json:"last_contact_date" form:"last_contact_date" schema:"last_contact_date"`
 	EventDate       string `json:"event_date" form:"event_date" schema:"event_date"`
+
+	HasFailedMessage bool `json:"has_failed_message" form:"has_failed_message" schema:"has_failed_message"`
 }
 
 type Referral struct {
@@ -395,6 +420,7 @@ type EventForm struct {
 	CSRFToken *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
 	EventID   *int    `json:"event_id" form:"event_id" schema:"event_id"`
 	LeadID    *int    `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	QuoteID   *int    `json:"quote_id" form:"quote_id" schema:"quote_id"`
 
 	BartenderID *int `json:"bartender_id" form:"bartender_id" schema:"bartender_id"`
 
@@ -462,13 +488,14 @@ type LeadQuoteList struct {
 }
 
 type LeadQuoteForm struct {
-	CSRFToken  *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
-	QuoteID    *int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
-	LeadID     *int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
-	ExternalID *string  `json:"external_id" form:"external_id" schema:"external_id"`
-	Guests     *int     `json:"guests" form:"guests" schema:"guests"`
-	Hours      *float64 `json:"hours" form:"hours" schema:"hours"`
-	EventDate  *int64   `json:"event_date" form:"event_date" schema:"event_date"`
+	CSRFToken   *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	QuoteID     *int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
+	LeadID      *int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	ExternalID  *string  `json:"external_id" form:"external_id" schema:"external_id"`
+	Guests      *int     `json:"guests" form:"guests" schema:"guests"`
+	Hours       *float64 `json:"hours" form:"hours" schema:"hours"`
+	EventDate   *int64   `json:"event_date" form:"event_date" schema:"event_date"`
+	TravelMiles *float64 `json:"travel_miles" form:"travel_miles" schema:"travel_miles"`
 }
 
 type QuoteDetails struct {
@@ -510,6 +537,8 @@ type CreateInvoiceParams struct {
 	FullName         string
 	DueDate          int64
 	Quote            float64
+	Discount         float64
+	DiscountCode     string
 }
 
 type InvoiceQuoteDetails struct {
@@ -531,6 +560,8 @@ type LeadQuoteInvoice struct {
 	InvoiceTypeMultiplier float64 `json:"invoice_type_multiplier" form:"invoice_type_multiplier" schema:"invoice_type_multiplier"`
 	InvoiceTypeID         int     `json:"invoice_type_id" form:"invoice_type_id" schema:"invoice_type_id"`
 	InvoiceStatusID       int     `json:"invoice_status_id" form:"invoice_status_id" schema:"invoice_status_id"`
+	Discount              float64 `json:"discount" form:"discount" schema:"discount"`
+	DiscountCode          string  `json:"discount_code" form:"discount_code" schema:"discount_code"`
 }
 
 type QuoteServiceList struct {
@@ -541,6 +572,9 @@ type QuoteServiceList struct {
 	Units          float64 `json:"units" form:"units" schema:"units"`
 	PricePerUnit   float64 `json:"price_per_unit" form:"price_per_unit" schema:"price_per_unit"`
 	Total          float64 `json:"total" form:"total" schema:"total"`
+	PricingRule    string  `json:"pricing_rule" form:"pricing_rule" schema:"pricing_rule"`
+	PackageTier    string  `json:"package_tier" form:"package_tier" schema:"package_tier"`
+	IsOptional     bool    `json:"is_optional" form:"is_optional" schema:"is_optional"`
 }
 
 type QuickQuoteServiceList struct {
@@ -559,6 +593,9 @@ type QuoteServiceForm struct {
 	QuoteID        *int     `json:"quote_id" form:"quote_id" schema:"quote_id"`
 	Units          *float64 `json:"units" form:"units" schema:"units"`
 	PricePerUnit   *float64 `json:"price_per_unit" form:"price_per_unit" schema:"price_per_unit"`
+	PricingRule    *string  `json:"pricing_rule" form:"pricing_rule" schema:"pricing_rule"`
+	PackageTier    *string  `json:"package_tier" form:"package_tier" schema:"package_tier"`
+	IsOptional     *bool    `json:"is_optional" form:"is_optional" schema:"is_optional"`
 }
 
 type ServiceForm struct {
@@ -569,6 +606,7 @@ type ServiceForm struct {
 	SuggestedPrice *float64 `json:"suggested_price" form:"suggested_price" schema:"suggested_price"`
 	GuestRatio     *int     `json:"guest_ratio" form:"guest_ratio" schema:"guest_ratio"`
 	UnitTypeID     *int     `json:"unit_type_id" form:"unit_type_id" schema:"unit_type_id"`
+	CostBasis      *float64 `json:"cost_basis" form:"cost_basis" schema:"cost_basis"`
 }
 
 type FrontendMessage struct {
@@ -580,13 +618,55 @@ type FrontendMessage struct {
 	MessageID   int    `json:"message_id"`
 	LeadID      int    `json:"lead_id"`
 	IsRead      bool   `json:"is_read"`
+
+	Status       string `json:"status"`
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+	IsFailed     bool   `json:"is_failed"`
+	IsNote       bool   `json:"is_note"`
+
+	Media []FrontendMessageMedia `json:"media"`
+}
+
+type FrontendMessageMedia struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	IsImage     bool   `json:"is_image"`
 }
 
 type LeadsWithMessages struct {
-	LeadName        string `json:"lead_name"`
-	LeadID          int    `json:"lead_id"`
-	UnreadMessages  int    `json:"unread_messages"`
-	LeadPhoneNumber string `json:"lead_phone_number"`
+	LeadName         string `json:"lead_name"`
+	LeadID           int    `json:"lead_id"`
+	UnreadMessages   int    `json:"unread_messages"`
+	LeadPhoneNumber  string `json:"lead_phone_number"`
+	AssignedUserName string `json:"assigned_user_name"`
+	Status           string `json:"status"`
+}
+
+type ConversationFilters struct {
+	AssignedUserID *int
+	Unassigned     bool
+	Status         *string
+}
+
+type FrontendConversation struct {
+	LeadID           int    `json:"lead_id"`
+	AssignedUserID   int    `json:"assigned_user_id"`
+	AssignedUserName string `json:"assigned_user_name"`
+	Status           string `json:"status"`
+	SnoozedUntil     string `json:"snoozed_until"`
+}
+
+type ConversationForm struct {
+	CSRFToken      *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	AssignedUserID *int    `json:"assigned_user_id" form:"assigned_user_id" schema:"assigned_user_id"`
+	Status         *string `json:"status" form:"status" schema:"status"`
+	SnoozedUntil   *string `json:"snoozed_until" form:"snoozed_until" schema:"snoozed_until"`
+}
+
+type ConversationNoteForm struct {
+	CSRFToken *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	Note      *string `json:"note" form:"note" schema:"note"`
 }
 
 type FrontendNote struct {
@@ -640,11 +720,13 @@ type LeadConversation struct {
 }
 
 type QuickQuoteForm struct {
-	CSRFToken *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
-	LeadID    *int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
-	EventDate *int64   `json:"event_date_service" form:"event_date_service" schema:"event_date_service"`
-	Hours     *float64 `json:"hours_service" form:"hours_service" schema:"hours_service"`
-	Guests    *int     `json:"guests_service" form:"guests_service" schema:"guests_service"`
+	CSRFToken    *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	LeadID       *int     `json:"lead_id" form:"lead_id" schema:"lead_id"`
+	EventDate    *int64   `json:"event_date_service" form:"event_date_service" schema:"event_date_service"`
+	Hours        *float64 `json:"hours_service" form:"hours_service" schema:"hours_service"`
+	Guests       *int     `json:"guests_service" form:"guests_service" schema:"guests_service"`
+	TravelMiles  *float64 `json:"travel_miles_service" form:"travel_miles_service" schema:"travel_miles_service"`
+	DiscountCode *string  `json:"discount_code_service" form:"discount_code_service" schema:"discount_code_service"`
 
 	// Must be parsed from JSON
 	QuoteServices *string `json:"quote_services" form:"quote_services" schema:"quote_services"`
@@ -663,30 +745,225 @@ type EventListView struct {
 }
 
 type EventStaffList struct {
-	EventStaffID int    `json:"event_staff_id" form:"event_staff_id" schema:"event_staff_id"`
-	FirstName    string `json:"first_name" form:"first_name" schema:"first_name"`
-	LastName     string `json:"last_name" form:"last_name" schema:"last_name"`
-	Role         string `json:"role" form:"role" schema:"role"`
+	EventStaffID int     `json:"event_staff_id" form:"event_staff_id" schema:"event_staff_id"`
+	FirstName    string  `json:"first_name" form:"first_name" schema:"first_name"`
+	LastName     string  `json:"last_name" form:"last_name" schema:"last_name"`
+	Role         string  `json:"role" form:"role" schema:"role"`
+	HourlyRate   float64 `json:"hourly_rate" form:"hourly_rate" schema:"hourly_rate"`
+	Status       string  `json:"status" form:"status" schema:"status"`
 }
 
 type EventStaffForm struct {
-	CSRFToken  *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
-	EventID    *int    `json:"event_id" form:"event_id" schema:"event_id"`
-	UserID     *int    `json:"user_id"`
-	UserRoleID *int    `json:"user_role_id" form:"user_role_id" schema:"user_role_id"`
+	CSRFToken  *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	EventID    *int     `json:"event_id" form:"event_id" schema:"event_id"`
+	UserID     *int     `json:"user_id"`
+	UserRoleID *int     `json:"user_role_id" form:"user_role_id" schema:"user_role_id"`
+	HourlyRate *float64 `json:"hourly_rate" form:"hourly_rate" schema:"hourly_rate"`
+}
+
+type StaffAssignment struct {
+	EventStaffID       int                 `json:"event_staff_id" form:"event_staff_id" schema:"event_staff_id"`
+	EventID            int                 `json:"event_id" form:"event_id" schema:"event_id"`
+	Role               string              `json:"role" form:"role" schema:"role"`
+	StreetAddress      string              `json:"street_address" form:"street_address" schema:"street_address"`
+	City               string              `json:"city" form:"city" schema:"city"`
+	ZipCode            string              `json:"zip_code" form:"zip_code" schema:"zip_code"`
+	EventTime          string              `json:"event_time" form:"event_time" schema:"event_time"`
+	Guests             int                 `json:"guests" form:"guests" schema:"guests"`
+	HourlyRate         float64             `json:"hourly_rate" form:"hourly_rate" schema:"hourly_rate"`
+	Hours              float64             `json:"hours" form:"hours" schema:"hours"`
+	ExpectedPay        float64             `json:"expected_pay" form:"expected_pay" schema:"expected_pay"`
+	EventStaffStatusID int                 `json:"event_staff_status_id" form:"event_staff_status_id" schema:"event_staff_status_id"`
+	Status             string              `json:"status" form:"status" schema:"status"`
+	IsUpcoming         bool                `json:"is_upcoming" form:"is_upcoming" schema:"is_upcoming"`
+	Cocktails          []EventCocktailList `json:"cocktails" form:"cocktails" schema:"cocktails"`
 }
 
 type EventCocktailList struct {
 	EventCocktailID int    `json:"event_cocktail_id" form:"event_cocktail_id" schema:"event_cocktail_id"`
+	CocktailID      int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
 	Name            string `json:"name" form:"name" schema:"name"`
 }
 
+type ShoppingListCocktail struct {
+	Name        string                   `json:"name"`
+	Ingredients []CocktailIngredientList `json:"ingredients"`
+}
+
+type ShoppingListItem struct {
+	Category     string  `json:"category" spreadsheet_header:"Category"`
+	Item         string  `json:"item" spreadsheet_header:"Item"`
+	Needed       float64 `json:"needed" spreadsheet_header:"Needed"`
+	Unit         string  `json:"unit" spreadsheet_header:"Unit"`
+	Quantity     int     `json:"quantity" spreadsheet_header:"Buy"`
+	PurchaseUnit string  `json:"purchase_unit" spreadsheet_header:"Purchase Unit"`
+}
+
+type EventShoppingList struct {
+	Guests                int                `json:"guests"`
+	Hours                 float64            `json:"hours"`
+	DrinksPerGuestPerHour float64            `json:"drinks_per_guest_per_hour"`
+	TotalDrinks           int                `json:"total_drinks"`
+	DrinksPerCocktail     int                `json:"drinks_per_cocktail"`
+	Items                 []ShoppingListItem `json:"items"`
+	MissingRecipes        []string           `json:"missing_recipes"`
+}
+
 type EventCocktailForm struct {
 	CSRFToken  *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
 	EventID    *int    `json:"event_id" form:"event_id" schema:"event_id"`
 	CocktailID *int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
 }
 
+type PayrollLine struct {
+	EventStaffID int     `json:"event_staff_id" spreadsheet_header:"Line ID"`
+	EventID      int     `json:"event_id" spreadsheet_header:"Event ID"`
+	UserID       int     `json:"user_id" spreadsheet_header:"User ID"`
+	StaffMember  string  `json:"staff_member" spreadsheet_header:"Staff Member"`
+	Role         string  `json:"role" spreadsheet_header:"Role"`
+	EventDate    string  `json:"event_date" spreadsheet_header:"Event Date"`
+	Hours        float64 `json:"hours" spreadsheet_header:"Hours"`
+	HourlyRate   float64 `json:"hourly_rate" spreadsheet_header:"Hourly Rate"`
+	Wages        float64 `json:"wages" spreadsheet_header:"Wages"`
+	TipShare     float64 `json:"tip_share" spreadsheet_header:"Tips"`
+	Total        float64 `json:"total" spreadsheet_header:"Total"`
+	IsPaid       bool    `json:"is_paid" spreadsheet_header:"Paid"`
+	DatePaid     string  `json:"date_paid" spreadsheet_header:"Date Paid"`
+	AmountPaid   float64 `json:"amount_paid" spreadsheet_header:"Amount Paid"`
+}
+
+type PayrollSummary struct {
+	UserID      int     `json:"user_id"`
+	StaffMember string  `json:"staff_member"`
+	Events      int     `json:"events"`
+	Hours       float64 `json:"hours"`
+	Wages       float64 `json:"wages"`
+	Tips        float64 `json:"tips"`
+	Total       float64 `json:"total"`
+	Unpaid      float64 `json:"unpaid"`
+}
+
+type EventProfitability struct {
+	EventID       int     `json:"event_id"`
+	LeadID        int     `json:"lead_id"`
+	FullName      string  `json:"full_name"`
+	EventDate     string  `json:"event_date"`
+	Month         string  `json:"month"`
+	PackageType   string  `json:"package_type"`
+	LeadSource    string  `json:"lead_source"`
+	QuotedRevenue float64 `json:"quoted_revenue"`
+	AmountPaid    float64 `json:"amount_paid"`
+	LaborCost     float64 `json:"labor_cost"`
+	ServiceCost   float64 `json:"service_cost"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+type ProfitabilitySummary struct {
+	Label         string  `json:"label"`
+	Events        int     `json:"events"`
+	QuotedRevenue float64 `json:"quoted_revenue"`
+	AmountPaid    float64 `json:"amount_paid"`
+	LaborCost     float64 `json:"labor_cost"`
+	ServiceCost   float64 `json:"service_cost"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+type MarketingAttribution struct {
+	Label           string  `json:"label" spreadsheet_header:"Group"`
+	Leads           int     `json:"leads" spreadsheet_header:"Leads"`
+	Quoted          int     `json:"quoted" spreadsheet_header:"Quoted"`
+	DepositPaid     int     `json:"deposit_paid" spreadsheet_header:"Deposit Paid"`
+	EventsCompleted int     `json:"events_completed" spreadsheet_header:"Events Completed"`
+	BookingRate     float64 `json:"booking_rate" spreadsheet_header:"Booking Rate %"`
+	Revenue         float64 `json:"revenue" spreadsheet_header:"Revenue"`
+	Spend           float64 `json:"spend" spreadsheet_header:"Spend"`
+	CostPerLead     float64 `json:"cost_per_lead" spreadsheet_header:"Cost Per Lead"`
+	CostPerBooking  float64 `json:"cost_per_booking" spreadsheet_header:"Cost Per Booking"`
+	ROAS            float64 `json:"roas" spreadsheet_header:"ROAS"`
+}
+
+type SMSSequenceForm struct {
+	CSRFToken           *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	SMSSequenceID       *int    `json:"sms_sequence_id" form:"sms_sequence_id" schema:"sms_sequence_id"`
+	Name                *string `json:"name" form:"name" schema:"name"`
+	IsActive            *bool   `json:"is_active" form:"is_active" schema:"is_active"`
+	ArchiveOnCompletion *bool   `json:"archive_on_completion" form:"archive_on_completion" schema:"archive_on_completion"`
+}
+
+type SMSSequenceStepForm struct {
+	CSRFToken     *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	SMSSequenceID *int    `json:"sms_sequence_id" form:"sms_sequence_id" schema:"sms_sequence_id"`
+	DelayDays     *int    `json:"delay_days" form:"delay_days" schema:"delay_days"`
+	Body          *string `json:"body" form:"body" schema:"body"`
+	IsAIGenerated *bool   `json:"is_ai_generated" form:"is_ai_generated" schema:"is_ai_generated"`
+}
+
+type SMSSequenceDetails struct {
+	SMSSequenceID       int                   `json:"sms_sequence_id"`
+	Name                string                `json:"name"`
+	IsActive            bool                  `json:"is_active"`
+	ArchiveOnCompletion bool                  `json:"archive_on_completion"`
+	ActiveEnrollments   int                   `json:"active_enrollments"`
+	Steps               []SMSSequenceStepList `json:"steps"`
+}
+
+type SMSSequenceStepList struct {
+	SMSSequenceStepID int    `json:"sms_sequence_step_id"`
+	StepNumber        int    `json:"step_number"`
+	DelayDays         int    `json:"delay_days"`
+	Body              string `json:"body"`
+	IsAIGenerated     bool   `json:"is_ai_generated"`
+}
+
+type SMSConsentEventList struct {
+	SMSConsentEventID int    `json:"sms_consent_event_id"`
+	IsOptedIn         bool   `json:"is_opted_in"`
+	Source            string `json:"source"`
+	Keyword           string `json:"keyword"`
+	Message           string `json:"message"`
+	DateCreated       string `json:"date_created"`
+}
+
+// DueSMSSequenceStep is the next unsent step of an active enrollment whose delay has passed.
+type DueSMSSequenceStep struct {
+	SMSSequenceEnrollmentID int    `json:"sms_sequence_enrollment_id"`
+	LeadID                  int    `json:"lead_id"`
+	FullName                string `json:"full_name"`
+	PhoneNumber             string `json:"phone_number"`
+	Message                 string `json:"message"`
+	StepNumber              int    `json:"step_number"`
+	Body                    string `json:"body"`
+	IsAIGenerated           bool   `json:"is_ai_generated"`
+	IsLastStep              bool   `json:"is_last_step"`
+	ArchiveOnCompletion     bool   `json:"archive_on_completion"`
+	IsBooked                bool   `json:"is_booked"`
+}
+
+type JobList struct {
+	JobID             int    `json:"job_id"`
+	JobType           string `json:"job_type"`
+	Status            string `json:"status"`
+	Attempts          int    `json:"attempts"`
+	MaxAttempts       int    `json:"max_attempts"`
+	RecurringInterval int    `json:"recurring_interval"`
+	RunAt             string `json:"run_at"`
+	LastError         string `json:"last_error"`
+	LockedBy          string `json:"locked_by"`
+	DateCompleted     string `json:"date_completed"`
+}
+
+type JobDeadLetterList struct {
+	JobDeadLetterID int    `json:"job_dead_letter_id"`
+	JobID           int    `json:"job_id"`
+	JobType         string `json:"job_type"`
+	Payload         string `json:"payload"`
+	Attempts        int    `json:"attempts"`
+	LastError       string `json:"last_error"`
+	DateFailed      string `json:"date_failed"`
+}
+
 type UserList struct {
 	UserID      int    `json:"user_id" form:"user_id" schema:"user_id"`
 	Username    string `json:"username" form:"username" schema:"username"`
@@ -715,9 +992,44 @@ type CreateCocktailForm struct {
 }
 
 type CocktailForm struct {
-	CSRFToken  *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
-	CocktailID *int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
-	Name       *string `json:"name" form:"name" schema:"name"`
+	CSRFToken    *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	CocktailID   *int    `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
+	Name         *string `json:"name" form:"name" schema:"name"`
+	Instructions *string `json:"instructions" form:"instructions" schema:"instructions"`
+}
+
+type IngredientForm struct {
+	CSRFToken *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	Name      *string `json:"name" form:"name" schema:"name"`
+	Category  *string `json:"category" form:"category" schema:"category"`
+}
+
+type UnitForm struct {
+	CSRFToken    *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	Name         *string `json:"name" form:"name" schema:"name"`
+	Abbreviation *string `json:"abbreviation" form:"abbreviation" schema:"abbreviation"`
+}
+
+type CocktailIngredientForm struct {
+	CSRFToken    *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	CocktailID   *int     `json:"cocktail_id" form:"cocktail_id" schema:"cocktail_id"`
+	IngredientID *int     `json:"ingredient_id" form:"ingredient_id" schema:"ingredient_id"`
+	UnitID       *int     `json:"unit_id" form:"unit_id" schema:"unit_id"`
+	Amount       *float64 `json:"amount" form:"amount" schema:"amount"`
+}
+
+type CocktailIngredientList struct {
+	IngredientID int     `json:"ingredient_id" form:"ingredient_id" schema:"ingredient_id"`
+	Ingredient   string  `json:"ingredient" form:"ingredient" schema:"ingredient"`
+	Category     string  `json:"category" form:"category" schema:"category"`
+	UnitID       int     `json:"unit_id" form:"unit_id" schema:"unit_id"`
+	Unit         string  `json:"unit" form:"unit" schema:"unit"`
+	Amount       float64 `json:"amount" form:"amount" schema:"amount"`
+}
+
+type CocktailRecipeSection struct {
+	Category    string                   `json:"category"`
+	Ingredients []CocktailIngredientList `json:"ingredients"`
 }
 
 type TimestampFormatOptions struct {
@@ -729,3 +1041,168 @@ type TimestampFormatOptions struct {
 	// constants.TimeZone
 	TimeZone string
 }
+
+type CRMEvent struct {
+	Type           string `json:"type"`
+	LeadID         int    `json:"lead_id"`
+	Message        string `json:"message"`
+	UnreadMessages int    `json:"unread_messages"`
+}
+
+type CallFlowDetails struct {
+	CallFlowID        int                         `json:"call_flow_id"`
+	Greeting          string                      `json:"greeting"`
+	HasLanguageMenu   bool                        `json:"has_language_menu"`
+	RingStrategy      string                      `json:"ring_strategy"`
+	RingTimeout       int                         `json:"ring_timeout"`
+	VoicemailGreeting string                      `json:"voicemail_greeting"`
+	AfterHoursMessage string                      `json:"after_hours_message"`
+	BusinessHours     []CallFlowBusinessHoursList `json:"business_hours"`
+	Recipients        []CallFlowRecipientList     `json:"recipients"`
+}
+
+type CallFlowBusinessHoursList struct {
+	DayOfWeek int    `json:"day_of_week"`
+	DayName   string `json:"day_name"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+type CallFlowRecipientList struct {
+	CallFlowRecipientID int    `json:"call_flow_recipient_id"`
+	UserID              int    `json:"user_id"`
+	UserName            string `json:"user_name"`
+	PhoneNumber         string `json:"phone_number"`
+	Language            string `json:"language"`
+	RingOrder           int    `json:"ring_order"`
+}
+
+type CallFlowForm struct {
+	CSRFToken         *string                     `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	CallFlowID        *int                        `json:"call_flow_id" form:"call_flow_id" schema:"call_flow_id"`
+	Greeting          *string                     `json:"greeting" form:"greeting" schema:"greeting"`
+	HasLanguageMenu   *bool                       `json:"has_language_menu" form:"has_language_menu" schema:"has_language_menu"`
+	RingStrategy      *string                     `json:"ring_strategy" form:"ring_strategy" schema:"ring_strategy"`
+	RingTimeout       *int                        `json:"ring_timeout" form:"ring_timeout" schema:"ring_timeout"`
+	VoicemailGreeting *string                     `json:"voicemail_greeting" form:"voicemail_greeting" schema:"voicemail_greeting"`
+	AfterHoursMessage *string                     `json:"after_hours_message" form:"after_hours_message" schema:"after_hours_message"`
+	BusinessHours     []CallFlowBusinessHoursForm `json:"business_hours" form:"business_hours" schema:"business_hours"`
+}
+
+type CallFlowBusinessHoursForm struct {
+	OpenTime  *string `json:"open_time" form:"open_time" schema:"open_time"`
+	CloseTime *string `json:"close_time" form:"close_time" schema:"close_time"`
+}
+
+type CallFlowRecipientForm struct {
+	CSRFToken  *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	CallFlowID *int    `json:"call_flow_id" form:"call_flow_id" schema:"call_flow_id"`
+	UserID     *int    `json:"user_id" form:"user_id" schema:"user_id"`
+	Language   *string `json:"language" form:"language" schema:"language"`
+	RingOrder  *int    `json:"ring_order" form:"ring_order" schema:"ring_order"`
+}
+
+type PricingRequest struct {
+	Guests      int                     `json:"guests"`
+	Hours       float64                 `json:"hours"`
+	EventDate   int64                   `json:"event_date"`
+	TravelMiles float64                 `json:"travel_miles"`
+	Services    []PricingRequestService `json:"services"`
+}
+
+type PricingRequestService struct {
+	ServiceID int     `json:"service_id"`
+	Units     float64 `json:"units"`
+}
+
+type PricedQuoteService struct {
+	ServiceID    int     `json:"service_id"`
+	Service      string  `json:"service"`
+	Units        float64 `json:"units"`
+	PricePerUnit float64 `json:"price_per_unit"`
+	Total        float64 `json:"total"`
+	PricingRule  string  `json:"pricing_rule"`
+}
+
+type PricedQuote struct {
+	BilledHours float64              `json:"billed_hours"`
+	Services    []PricedQuoteService `json:"services"`
+	Adjustments []PricedQuoteService `json:"adjustments"`
+	Subtotal    float64              `json:"subtotal"`
+	Total       float64              `json:"total"`
+}
+
+type DiscountCodeForm struct {
+	CSRFToken      *string  `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	Code           *string  `json:"code" form:"code" schema:"code"`
+	DiscountType   *string  `json:"discount_type" form:"discount_type" schema:"discount_type"`
+	Amount         *float64 `json:"amount" form:"amount" schema:"amount"`
+	ExpiresAt      *int64   `json:"expires_at" form:"expires_at" schema:"expires_at"`
+	MaxRedemptions *int     `json:"max_redemptions" form:"max_redemptions" schema:"max_redemptions"`
+	ServiceTypeIDs []int    `json:"service_type_id" form:"service_type_id" schema:"service_type_id"`
+}
+
+type DiscountCodeList struct {
+	DiscountCodeID int     `json:"discount_code_id"`
+	Code           string  `json:"code"`
+	DiscountType   string  `json:"discount_type"`
+	Amount         float64 `json:"amount"`
+	ExpiresAt      string  `json:"expires_at"`
+	MaxRedemptions int     `json:"max_redemptions"`
+	Redemptions    int     `json:"redemptions"`
+	IsActive       bool    `json:"is_active"`
+	ServiceTypes   string  `json:"service_types"`
+}
+
+type QuoteDiscountForm struct {
+	CSRFToken *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	Code      *string `json:"code" form:"code" schema:"code"`
+}
+
+type QuoteDiscountDetails struct {
+	QuoteDiscountID int     `json:"quote_discount_id"`
+	QuoteID         int     `json:"quote_id"`
+	DiscountCodeID  int     `json:"discount_code_id"`
+	Code            string  `json:"code"`
+	DiscountType    string  `json:"discount_type"`
+	Value           float64 `json:"value"`
+	Amount          float64 `json:"amount"`
+}
+
+type DiscountRedemptionReport struct {
+	Code          string  `json:"code"`
+	Source        string  `json:"source"`
+	Redemptions   int     `json:"redemptions"`
+	TotalDiscount float64 `json:"total_discount"`
+}
+
+type QuoteAcceptanceForm struct {
+	CSRFToken     *string `json:"csrf_token" form:"csrf_token" schema:"csrf_token"`
+	SignatureName *string `json:"signature_name" form:"signature_name" schema:"signature_name"`
+	AgreeToTerms  *bool   `json:"agree_to_terms" form:"agree_to_terms" schema:"agree_to_terms"`
+	PackageTier   *string `json:"package_tier" form:"package_tier" schema:"package_tier"`
+	AddOnIDs      []int   `json:"add_on_id" form:"add_on_id" schema:"add_on_id"`
+}
+
+type QuoteAcceptanceDetails struct {
+	QuoteAcceptanceID int    `json:"quote_acceptance_id"`
+	SignatureName     string `json:"signature_name"`
+	PackageTier       string `json:"package_tier"`
+	SelectedAddOns    string `json:"selected_add_ons"`
+	IPAddress         string `json:"ip_address"`
+	UserAgent         string `json:"user_agent"`
+	DateAccepted      string `json:"date_accepted"`
+	IsInvoiced        bool   `json:"is_invoiced"`
+}
+
+type QuotePackage struct {
+	Tier     string             `json:"tier"`
+	Services []QuoteServiceList `json:"services"`
+	Total    float64            `json:"total"`
+}
+
+type QuoteOptions struct {
+	Included []QuoteServiceList `json:"included"`
+	Packages []QuotePackage     `json:"packages"`
+	AddOns   []QuoteServiceList `json:"add_ons"`
+}